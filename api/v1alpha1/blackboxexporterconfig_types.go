@@ -0,0 +1,76 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionRolledOut reports whether every pod of a blackbox exporter runs its current image and configuration and
+// passes its readiness probe
+const ConditionRolledOut = "RolledOut"
+
+// BlackBoxExporterConfigStatus defines the image and configuration the available pods of the exporter run
+type BlackBoxExporterConfigStatus struct {
+	// Image is the image the exporter was last rolled out with
+	Image string `json:"image,omitempty"`
+
+	// ConfigHash is the hash of the configuration the exporter was last rolled out with
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// +listType=set
+	// +optional
+
+	// Modules are the modules of that configuration. The ServiceMonitors probing with another module wait for the
+	// exporter to roll it out
+	Modules []string `json:"modules,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the progress of the rollout of the exporter
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.status.image`
+// +kubebuilder:printcolumn:name="Rolled Out",type=string,JSONPath=`.status.conditions[?(@.type=="RolledOut")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// BlackBoxExporterConfig reports the rollout of a blackbox exporter deployed by the operator. It's maintained by the
+// operator, next to the exporter and named like it
+type BlackBoxExporterConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status BlackBoxExporterConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BlackBoxExporterConfigList contains a list of BlackBoxExporterConfig
+type BlackBoxExporterConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BlackBoxExporterConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BlackBoxExporterConfig{}, &BlackBoxExporterConfigList{})
+}
@@ -0,0 +1,110 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateMonitorSpec defines the desired state of CertificateMonitor
+type CertificateMonitorSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[^:/]+:[0-9]+$`
+
+	// Target is the host:port whose certificate chain is checked in a TLS handshake, e.g. a database or an ingress
+	Target string `json:"target"`
+
+	// +kubebuilder:validation:Optional
+
+	// ServerName is sent in the handshake and has to be covered by the SANs of the certificate. When unset, the
+	// host of the target is used
+	ServerName string `json:"serverName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+
+	// ExpiryWarningDays is how many days before the expiry of the first expiring certificate of the chain a
+	// warning fires
+	ExpiryWarningDays int `json:"expiryWarningDays,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+
+	// ExpiryCriticalDays is how many days before the expiry of the first expiring certificate of the chain a
+	// critical alert fires
+	ExpiryCriticalDays int `json:"expiryCriticalDays,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Issuer is a regular expression the issuer of the certificate has to match, e.g. 'CN=R3,O=Let's Encrypt' to
+	// detect a certificate accidentally issued by another CA
+	Issuer string `json:"issuer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricLabels are added to every probe series scraped for the monitor, e.g. 'tier: frontend' or 'telemetry: fleet', so dashboards
+	// can slice the probes and remote-write filtering can decide which series leave the cluster. Labels set by the operator take precedence
+	MetricLabels map[string]string `json:"metricLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// AlertLabels are added to every alert generated for the monitor, so they can be routed to the team
+	// owning it. Labels set by the operator take precedence
+	AlertLabels map[string]string `json:"alertLabels,omitempty"`
+}
+
+// CertificateMonitorStatus defines the observed state of CertificateMonitor
+type CertificateMonitorStatus struct {
+	ServiceMonitorRef NamespacedName `json:"serviceMonitorRef,omitempty"`
+	PrometheusRuleRef NamespacedName `json:"prometheusRuleRef,omitempty"`
+	ErrorStatus       string         `json:"errorStatus,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the state of each resource generated for the CertificateMonitor, so a failure points
+	// at the part of the pipeline that needs attention
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// CertificateMonitor is the Schema for the certificatemonitors API
+type CertificateMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateMonitorSpec   `json:"spec,omitempty"`
+	Status CertificateMonitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CertificateMonitorList contains a list of CertificateMonitor
+type CertificateMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateMonitor{}, &CertificateMonitorList{})
+}
@@ -38,12 +38,99 @@ type ClusterUrlMonitorSpec struct {
 	// +optional
 	DomainRef ClusterDomainRef `json:"domainRef,omitempty"`
 
+	// +kubebuilder:validation:Enum=api;api-int
+	// +optional
+
+	// Target probes an endpoint of the cluster's API server instead of the url made up of prefix, port and suffix.
+	// The suffix is appended to the endpoint. 'api-int' probes the internal endpoint through the cluster network
+	Target ClusterUrlTarget `json:"target,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// RawURL probes the absolute url instead of one of the cluster, e.g. an externally hosted dependency like the
+	// OCM API or an identity provider. Prefix, port, suffix and target are ignored if it's set
+	RawURL string `json:"rawURL,omitempty"`
+
 	// +kubebuilder:default:false
 	// +kubebuilder:validation:Optional
 
 	// SkipPrometheusRule instructs the controller to skip the creation of PrometheusRule CRs.
 	// One common use-case for is for alerts that are defined separately, such as for hosted clusters.
 	SkipPrometheusRule bool `json:"skipPrometheusRule"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// SkipServiceMonitor instructs the controller to skip the creation of ServiceMonitor CRs.
+	// One common use-case is a url that is already scraped elsewhere, with the ClusterUrlMonitor only defining the alerts.
+	SkipServiceMonitor bool `json:"skipServiceMonitor"`
+
+	// +kubebuilder:validation:Optional
+
+	// MaintenanceWindows are periods of planned downtime, during which the generated alerts don't fire
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricLabels are added to every probe series scraped for the monitor, e.g. 'tier: frontend' or 'telemetry: fleet', so dashboards
+	// can slice the probes and remote-write filtering can decide which series leave the cluster. Labels set by the operator take precedence
+	MetricLabels map[string]string `json:"metricLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricRelabelings are applied to the probe series after the relabelings managed by the operator, for
+	// rewrites the other settings don't cover. Edits of the generated ServiceMonitor are reverted, these persist
+	MetricRelabelings []RelabelConfig `json:"metricRelabelings,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// SampleLimit is the number of samples a scrape of a probe may return before it fails, protecting Prometheus from
+	// a misbehaving target generating unexpected series. Zero is unlimited
+	SampleLimit uint64 `json:"sampleLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TargetLimit is the number of probed urls the generated ServiceMonitor may have before its scrapes fail. Zero is unlimited
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// HTTPProbe optionally customizes the blackbox exporter module used to probe the cluster url
+	HTTPProbe *HTTPProbeSpec `json:"httpProbe,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Hypershift overrides whether the ClusterUrlMonitor is treated as monitoring a hosted control plane,
+	// which otherwise follows the domainRef. E.g. monitors of the management cluster's own endpoints opt out
+	Hypershift *HypershiftSpec `json:"hypershift,omitempty"`
+}
+
+// HypershiftSpec defines how a monitor relates to hosted control planes
+type HypershiftSpec struct {
+	// Enabled instructs the controller to probe the url from the RHOBS ServiceMonitor of a hosted control plane,
+	// identified by its hosted cluster. ClusterUrlMonitors leave alerting to the RHOBS tenant then
+	Enabled bool `json:"enabled"`
+}
+
+// IsHCP returns whether the ClusterUrlMonitor monitors a hosted control plane. Without an explicit
+// override this is inferred from the domainRef
+func (s ClusterUrlMonitorSpec) IsHCP() bool {
+	if s.Hypershift != nil {
+		return s.Hypershift.Enabled
+	}
+	return s.DomainRef == ClusterDomainRefHCP
+}
+
+// ProbedTarget returns the API endpoint the ClusterUrlMonitor probes, empty if it probes its rawURL, which
+// takes precedence over the target
+func (s ClusterUrlMonitorSpec) ProbedTarget() ClusterUrlTarget {
+	if s.RawURL != "" {
+		return ""
+	}
+	return s.Target
 }
 
 // ClusterDomainRef defines the object used determine the cluster's domain
@@ -58,17 +145,53 @@ var (
 	ClusterDomainRefHCP ClusterDomainRef = "hcp"
 )
 
+// ClusterUrlTarget defines an endpoint of the cluster's API server to probe
+type ClusterUrlTarget string
+
+var (
+	// ClusterUrlTargetAPI indicates the external API endpoint, as published in the 'infrastructures/cluster' object
+	ClusterUrlTargetAPI ClusterUrlTarget = "api"
+
+	// ClusterUrlTargetAPIInternal indicates the internal API endpoint (api-int), as published in the 'infrastructures/cluster' object
+	ClusterUrlTargetAPIInternal ClusterUrlTarget = "api-int"
+)
+
 // ClusterUrlMonitorStatus defines the observed state of ClusterUrlMonitor
 type ClusterUrlMonitorStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// ClusterURL is the url probed for the ClusterUrlMonitor
+	ClusterURL        string         `json:"clusterURL,omitempty"`
 	ServiceMonitorRef NamespacedName `json:"serviceMonitorRef,omitempty"`
 	PrometheusRuleRef NamespacedName `json:"prometheusRuleRef,omitempty"`
 	ErrorStatus       string         `json:"errorStatus,omitempty"`
+	// SloTarget is the availability target, in percent, last observed by the operator
+	SloTarget string `json:"sloTarget,omitempty"`
+	// LastSloChange records the last change of the availability target
+	LastSloChange *SloChange `json:"lastSloChange,omitempty"`
+	// ProbeHealth summarizes the recent probes, if the operator queries Prometheus for them
+	ProbeHealth *ProbeHealth `json:"probeHealth,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the state of each resource generated for the ClusterUrlMonitor, so a failure points
+	// at the part of the pipeline that needs attention
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=cum
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.clusterURL`
+// +kubebuilder:printcolumn:name="SLO",type=string,JSONPath=`.spec.slo.targetAvailabilityPercent`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Availability",type=string,JSONPath=`.status.probeHealth.availabilityPercent`
+// +kubebuilder:printcolumn:name="Budget Left",type=string,JSONPath=`.status.probeHealth.errorBudgetRemainingPercent`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.probeHealth.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ClusterUrlMonitor is the Schema for the clusterurlmonitors API
 type ClusterUrlMonitor struct {
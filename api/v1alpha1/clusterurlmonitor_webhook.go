@@ -0,0 +1,48 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:webhook:path=/validate-monitoring-openshift-io-v1alpha1-clusterurlmonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.openshift.io,resources=clusterurlmonitors,verbs=create;update,versions=v1alpha1,name=vclusterurlmonitor.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the webhook validating ClusterUrlMonitors
+func (r *ClusterUrlMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&monitorValidator{kind: "ClusterUrlMonitor", validate: validateClusterUrlMonitor}).
+		Complete()
+}
+
+// validateClusterUrlMonitor checks the availability target, urls and probe settings of a ClusterUrlMonitor
+func validateClusterUrlMonitor(obj runtime.Object) (interface{}, field.ErrorList) {
+	monitor, ok := obj.(*ClusterUrlMonitor)
+	if !ok {
+		return nil, field.ErrorList{field.InternalError(nil, fmt.Errorf("expected a ClusterUrlMonitor but got a %T", obj))}
+	}
+	spec := field.NewPath("spec")
+	errs := validateSlo(spec.Child("slo"), monitor.Spec.Slo)
+	errs = append(errs, validateURL(spec.Child("rawURL"), monitor.Spec.RawURL)...)
+	errs = append(errs, validateHTTPProbe(spec.Child("httpProbe"), monitor.Spec.HTTPProbe)...)
+	return monitor.Spec, errs
+}
@@ -1,19 +1,408 @@
 package v1alpha1
 
-import "gopkg.in/inf.v0"
+import (
+	"time"
+
+	"gopkg.in/inf.v0"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
 
 // NamespacedName contains the name of a object and its namespace
 type NamespacedName struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
+
+	// +optional
+
+	// UID of the referenced object, recorded for generated ServiceMonitors so an object recreated with the
+	// same name, e.g. in a recycled namespace, isn't mistaken for it
+	UID types.UID `json:"uid,omitempty"`
+
+	// +optional
+
+	// Kind of the referenced object qualified with its API group, e.g. ServiceMonitor.monitoring.rhobs, recorded for
+	// generated ServiceMonitors as the kind they're generated as depends on the monitor and the installed monitoring APIs
+	Kind string `json:"kind,omitempty"`
 }
 
 // SloSpec defines what is the percentage
 type SloSpec struct {
 	// TargetAvailabilityPercent defines the percent number to be used
 	TargetAvailabilityPercent string `json:"targetAvailabilityPercent"`
+
+	// +kubebuilder:validation:Optional
+
+	// Latency optionally defines a latency objective, alerted on alongside availability, so
+	// severe slowdowns of probes that still succeed burn an error budget too
+	Latency *LatencySloSpec `json:"latency,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// AlertLabels are added to every alert generated for the monitor, so they can be routed to the team
+	// owning it, e.g. by team, service or escalation tier. Labels set by the operator take precedence
+	AlertLabels map[string]string `json:"alertLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// AlertAnnotations are added to every alert generated for the monitor, e.g. a 'runbook_url' linking to the
+	// remediation docs or a 'summary'. Values may use Prometheus templating, e.g. '{{ $labels.probe_url }}'.
+	// Annotations set by the operator take precedence
+	AlertAnnotations map[string]string `json:"alertAnnotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Severities overrides the severities of the burn rate alerts, e.g. so monitors of non-production
+	// endpoints never page
+	Severities *AlertSeveritySpec `json:"severities,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// CountClientErrorsAsFailure controls whether probes answered with a 4xx status code burn the error budget.
+	// Defaults to true
+	CountClientErrorsAsFailure *bool `json:"countClientErrorsAsFailure,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$`
+
+	// GracePeriod suppresses the alerts of a newly created monitor for the given duration, e.g. 30m,
+	// while its target warms up
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+// ClientErrorsFail returns whether probes answered with a 4xx status code count as failures
+func (s SloSpec) ClientErrorsFail() bool {
+	return s.CountClientErrorsAsFailure == nil || *s.CountClientErrorsAsFailure
+}
+
+// AlertSeveritySpec defines the severities of the burn rate alerts. Critical alerts page, all others open a ticket
+type AlertSeveritySpec struct {
+	// +kubebuilder:validation:Enum=critical;warning;info
+	// +optional
+
+	// FastBurn is the severity of the alert on 2% of the error budget spent within 1h, 'critical' by default
+	FastBurn string `json:"fastBurn,omitempty"`
+
+	// +kubebuilder:validation:Enum=critical;warning;info
+	// +optional
+
+	// MediumBurn is the severity of the alert on 5% of the error budget spent within 6h, 'critical' by default
+	MediumBurn string `json:"mediumBurn,omitempty"`
+
+	// +kubebuilder:validation:Enum=critical;warning;info
+	// +optional
+
+	// SlowBurn is the severity of the alerts on 10% of the error budget spent within 1d or 3d, 'warning' by default
+	SlowBurn string `json:"slowBurn,omitempty"`
+}
+
+// LatencySloSpec defines the percentage of probes that have to complete within a threshold
+type LatencySloSpec struct {
+	// +kubebuilder:validation:Minimum:=1
+
+	// ThresholdMilliseconds is the duration a probe has to complete within
+	ThresholdMilliseconds int64 `json:"thresholdMilliseconds"`
+
+	// TargetPercent defines the percent number of probes that have to complete within the threshold, e.g. "95"
+	TargetPercent string `json:"targetPercent"`
+}
+
+const (
+	// ConditionReady sums up the other conditions: a monitor is ready once its ServiceMonitor is, as long as its
+	// last reconcile didn't fail and none of its other generated resources did
+	ConditionReady = "Ready"
+	// ConditionServiceMonitorReady reports whether the ServiceMonitor of a monitor is up to date
+	ConditionServiceMonitorReady = "ServiceMonitorReady"
+	// ConditionPrometheusRuleReady reports whether the PrometheusRule of a monitor is up to date.
+	// It's absent if the monitor doesn't need a PrometheusRule
+	ConditionPrometheusRuleReady = "PrometheusRuleReady"
+	// ConditionBlackBoxExporterReady reports whether the blackbox exporter runs its current configuration, so the
+	// monitor is probed with the module it requires
+	ConditionBlackBoxExporterReady = "BlackBoxExporterReady"
+	// ConditionTargetConflict warns that other monitors probe the same url with a different availability target.
+	// It's absent if there are none
+	ConditionTargetConflict = "TargetConflict"
+	// ConditionDryRun lists the writes of the generated resources the operator skipped in dry-run mode.
+	// It's absent outside of dry-run mode
+	ConditionDryRun = "DryRun"
+)
+
+// SloChange records a change of the availability target of a monitor
+type SloChange struct {
+	// From is the previous target, in percent
+	From string `json:"from,omitempty"`
+	// To is the new target, in percent. It's empty if the target was removed
+	To string `json:"to,omitempty"`
+	// ChangedBy is the field manager that last wrote the target
+	ChangedBy string `json:"changedBy,omitempty"`
+	// ChangedAt is when the target was last written
+	ChangedAt metav1.Time `json:"changedAt,omitempty"`
+}
+
+// ProbeHealth summarizes the probes of a monitor over the SLO period of 30 days, as queried from Prometheus
+type ProbeHealth struct {
+	// LastSuccessTime is when a probe last succeeded, unset if none did within the SLO period
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// AvailabilityPercent is the share of successful probes, in percent
+	AvailabilityPercent string `json:"availabilityPercent,omitempty"`
+	// ErrorBudgetRemainingPercent is the share of the error budget that's left, in percent. It's negative once
+	// the budget is spent, and unset if the monitor has no availability target
+	ErrorBudgetRemainingPercent string `json:"errorBudgetRemainingPercent,omitempty"`
+	// ObservedTime is when Prometheus was queried
+	ObservedTime metav1.Time `json:"observedTime"`
+}
+
+// MaxDebugDuration is how far ahead the debugUntil of a monitor may be. The debug probes block a worker of the
+// operator, so a debug mode that's never turned off isn't allowed
+const MaxDebugDuration = 24 * time.Hour
+
+// ProbeTranscript is the debug output of a probe of the blackbox exporter
+type ProbeTranscript struct {
+	// URL is the probed url
+	URL string `json:"url"`
+	// Module is the module of the exporter the url was probed with
+	Module string `json:"module"`
+	// Transcript is the exporter's log of the probe, cut to its last lines if it's long
+	Transcript string `json:"transcript"`
+	// Time is when the probe failed
+	Time metav1.Time `json:"time"`
+}
+
+// MaintenanceWindow is a period of planned downtime, during which the alerts of a monitor don't fire.
+// It either recurs on a schedule or is a one-off window between start and end
+type MaintenanceWindow struct {
+	// +kubebuilder:validation:Optional
+
+	// Schedule is a cron expression in UTC, made up of minute, hour, day of month, month and day of week,
+	// at which a recurring window starts. Each window lasts for the duration
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$`
+
+	// Duration of a recurring window, e.g. 2h
+	Duration string `json:"duration,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Start of a one-off window
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// End of a one-off window
+	End *metav1.Time `json:"end,omitempty"`
+}
+
+// HTTPProbeSpec customizes the blackbox exporter module used to probe a monitor's target
+type HTTPProbeSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+
+	// FollowRedirects defines whether the probe follows HTTP redirects. Disabling it makes the
+	// probe assert on the redirect response itself instead of on the page it redirects to
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ip4;ip6
+
+	// PreferredIPProtocol defines the IP protocol the target is resolved to. Dual-stack clusters
+	// can use it to verify the IPv4 and IPv6 paths separately. When unset, ip6 is preferred
+	PreferredIPProtocol IPProtocol `json:"preferredIPProtocol,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+
+	// IPProtocolFallback defines whether the probe falls back to the other IP protocol when the
+	// target can't be resolved to the preferred one. Disable it to only probe the preferred path
+	IPProtocolFallback *bool `json:"ipProtocolFallback,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// ProxyURL defines the HTTP proxy the probe is sent through, for targets that are only
+	// reachable via an egress proxy. When unset, the proxy settings of the exporter's environment apply
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ValidHTTPVersions defines the HTTP versions the probe accepts, e.g. only HTTP/2.0 to detect
+	// a route silently falling back to HTTP/1.1. When unset, any version is accepted
+	ValidHTTPVersions []HTTPVersion `json:"validHTTPVersions,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=TLS10;TLS11;TLS12;TLS13
+
+	// TLSMinVersion defines the lowest TLS version the probe negotiates. When unset, the exporter's default
+	// applies. Versions below TLS12 are rejected if the operator runs in FIPS mode
+	TLSMinVersion TLSVersion `json:"tlsMinVersion,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(B|KB|MB|GB)$`
+
+	// BodySizeLimit defines the size of the response body the probe reads, e.g. 1MB, so endpoints streaming large
+	// bodies don't exhaust the exporter's memory. Probes of larger bodies fail. When unset, the body isn't limited
+	BodySizeLimit string `json:"bodySizeLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=identity;gzip;br;deflate
+
+	// Compression defines the compression the probe requests and decompresses the response body with
+	Compression string `json:"compression,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Headers are sent with every probe, e.g. Accept: application/json for an API answering html by default
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// FailIfHeaderMatches fails the probe if a response header matches, e.g. X-Cache matching MISS to detect
+	// a CDN that stopped caching
+	FailIfHeaderMatches []HeaderAssertion `json:"failIfHeaderMatches,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// FailIfHeaderNotMatches fails the probe if a response header doesn't match, e.g. Strict-Transport-Security
+	// matching max-age to detect a route that lost its security headers
+	FailIfHeaderNotMatches []HeaderAssertion `json:"failIfHeaderNotMatches,omitempty"`
+}
+
+// HeaderAssertion matches a response header of the probe against a regular expression
+type HeaderAssertion struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Header is the name of the response header
+	Header string `json:"header"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Regexp is the RE2 regular expression the value of the header is matched against
+	Regexp string `json:"regexp"`
+
+	// +kubebuilder:validation:Optional
+
+	// AllowMissing defines whether a missing header passes the assertion. By default it fails the probe
+	AllowMissing bool `json:"allowMissing,omitempty"`
+}
+
+// ExternalExporterSpec references a blackbox exporter run by the user, which probes a monitor instead of the operator's
+// exporter. ServiceMonitors select its service, Probes and ScrapeConfigs address its url
+type ExternalExporterSpec struct {
+	// +kubebuilder:validation:Optional
+
+	// Service is the Service of the exporter, selected by the ServiceMonitor of the monitor
+	Service *ExternalExporterServiceSpec `json:"service,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// URL is the base url of the exporter, e.g. http://blackbox.example.com:9115, addressed by the Probe or the ScrapeConfig
+	// of the monitor
+	URL string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="http_2xx"
+
+	// Module is the module of the exporter the monitor is probed with. The exporter's configuration isn't managed by the
+	// operator, so the probe settings of the monitor don't apply
+	Module string `json:"module,omitempty"`
+}
+
+// ExternalExporterServiceSpec references the Service of a blackbox exporter
+type ExternalExporterServiceSpec struct {
+	// +kubebuilder:validation:Required
+
+	// Name of the Service
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+
+	// Namespace of the Service, which has to be the namespace of the monitor
+	Namespace string `json:"namespace"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="http"
+
+	// Port is the name of the port of the Service the exporter listens on
+	Port string `json:"port,omitempty"`
+}
+
+// RelabelConfig rewrites the labels of the probe series, as the metricRelabelings of a ServiceMonitor's endpoint do.
+// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#metric_relabel_configs
+type RelabelConfig struct {
+	// +kubebuilder:validation:Optional
+
+	// SourceLabels select the values of existing labels. They're joined with the separator and matched against
+	// the regex for the replace, keep and drop actions
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Separator is placed between the joined values of the source labels, ';' by default
+	Separator string `json:"separator,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// TargetLabel is the label the result of a replace or hashmod action is written to
+	TargetLabel string `json:"targetLabel,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Regex is the regular expression the joined values are matched against, '(.*)' by default
+	Regex string `json:"regex,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Modulus is taken of the hash of the joined values by the hashmod action
+	Modulus uint64 `json:"modulus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Replacement is the value written by a replace action, capture groups of the regex are available. '$1' by default
+	Replacement string `json:"replacement,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep;lowercase;uppercase
+
+	// Action is performed based on the match of the regex, replace by default
+	Action string `json:"action,omitempty"`
 }
 
+// HTTPVersion is an HTTP version a probe accepts
+// +kubebuilder:validation:Enum="HTTP/1.0";"HTTP/1.1";"HTTP/2.0"
+type HTTPVersion string
+
+// TLSVersion is a TLS version as named in the exporter's configuration
+type TLSVersion string
+
+const (
+	// The following values should match the kubebuilder-enumerated values for tlsMinVersion above
+	TLSVersion10 TLSVersion = "TLS10"
+	TLSVersion11 TLSVersion = "TLS11"
+	TLSVersion12 TLSVersion = "TLS12"
+	TLSVersion13 TLSVersion = "TLS13"
+)
+
+// IPProtocol defines the IP protocol used by a probe
+type IPProtocol string
+
+const (
+	// The following values should match the kubebuilder-enumerated values for preferredIPProtocol above
+	IPProtocolIP4 IPProtocol = "ip4"
+	IPProtocolIP6 IPProtocol = "ip6"
+)
+
+// Bounds and scale of the availability target, they are only read so they can be shared
+var (
+	// will be 90
+	sloLowerBound = inf.NewDec(9, -1)
+	// will be 100
+	sloUpperBound = inf.NewDec(1, -2)
+	// will be 1/100
+	oneHundredth = inf.NewDec(1, 2)
+)
+
 func (s SloSpec) IsValid() (bool, string) {
 	if s.TargetAvailabilityPercent == "" {
 		return false, ""
@@ -25,24 +414,33 @@ func (s SloSpec) IsValid() (bool, string) {
 		return false, ""
 	}
 
-	// will be 90
-	ninty := inf.NewDec(9, -1)
 	// is lower than lower bound
-	if d.Cmp(ninty) <= 0 {
+	if d.Cmp(sloLowerBound) <= 0 {
 		return false, ""
 	}
 
-	// will be 100
-	hundred := inf.NewDec(1, -2)
 	// is higher than upper bound
-	if d.Cmp(hundred) >= 0 {
+	if d.Cmp(sloUpperBound) >= 0 {
 		return false, ""
 	}
 
-	// will be 1/100
-	oneHundredth := inf.NewDec(1, 2)
-
 	res := d.Mul(d, oneHundredth).String()
 
 	return true, res
 }
+
+// IsValid returns whether the latency objective can be alerted on and, if so, its target as a ratio
+func (l LatencySloSpec) IsValid() (bool, string) {
+	if l.ThresholdMilliseconds <= 0 {
+		return false, ""
+	}
+	d, success := new(inf.Dec).SetString(l.TargetPercent)
+	if !success {
+		return false, ""
+	}
+	// Latency targets are commonly lower than availability targets, any share of probes is accepted
+	if d.Sign() <= 0 || d.Cmp(sloUpperBound) >= 0 {
+		return false, ""
+	}
+	return true, d.Mul(d, oneHundredth).String()
+}
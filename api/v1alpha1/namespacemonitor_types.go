@@ -0,0 +1,134 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceMonitorSpec defines the desired state of NamespaceMonitor
+type NamespaceMonitorSpec struct {
+	// +kubebuilder:validation:Optional
+
+	// RouteSelector optionally restricts the monitored Routes of the namespace. When unset, every Route is
+	RouteSelector *metav1.LabelSelector `json:"routeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^/`
+
+	// Suffix optionally defines the path probed on every Route (/livez /readyz etc)
+	Suffix string `json:"suffix,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;https
+
+	// Scheme optionally forces the scheme the Routes are probed with. When unset, https is probed for
+	// the Routes that have TLS configured
+	Scheme RouteScheme `json:"scheme,omitempty"`
+
+	// Slo is shared by all Routes of the namespace: their failed probes burn the same error budget
+	Slo SloSpec `json:"slo,omitempty"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// SkipPrometheusRule instructs the controller to skip the creation of PrometheusRule CRs.
+	// One common use-case for is for alerts that are defined separately.
+	SkipPrometheusRule bool `json:"skipPrometheusRule"`
+
+	// +kubebuilder:validation:Optional
+
+	// MaintenanceWindows are periods of planned downtime, during which the generated alerts don't fire
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricLabels are added to every probe series scraped for the monitor, e.g. 'tier: frontend' or 'telemetry: fleet', so dashboards
+	// can slice the probes and remote-write filtering can decide which series leave the cluster. Labels set by the operator take precedence
+	MetricLabels map[string]string `json:"metricLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TargetLimit is the number of Routes the generated ServiceMonitor may probe before its scrapes fail. Zero is unlimited
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// InsecureSkipTLSVerify probes the Routes without verifying their certificates
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify"`
+
+	// +kubebuilder:validation:Optional
+
+	// HTTPProbe optionally customizes the blackbox exporter module used to probe the Routes
+	HTTPProbe *HTTPProbeSpec `json:"httpProbe,omitempty"`
+}
+
+// NamespaceMonitorStatus defines the observed state of NamespaceMonitor
+type NamespaceMonitorStatus struct {
+	// RouteURLs are the probed urls, one per admitted Route of the namespace
+	RouteURLs         []string       `json:"routeURLs,omitempty"`
+	ServiceMonitorRef NamespacedName `json:"serviceMonitorRef,omitempty"`
+	PrometheusRuleRef NamespacedName `json:"prometheusRuleRef,omitempty"`
+	ErrorStatus       string         `json:"errorStatus,omitempty"`
+	// SloTarget is the availability target, in percent, last observed by the operator
+	SloTarget string `json:"sloTarget,omitempty"`
+	// LastSloChange records the last change of the availability target
+	LastSloChange *SloChange `json:"lastSloChange,omitempty"`
+	// ProbeHealth summarizes the recent probes, if the operator queries Prometheus for them
+	ProbeHealth *ProbeHealth `json:"probeHealth,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the state of each resource generated for the NamespaceMonitor, so a failure points
+	// at the part of the pipeline that needs attention
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SLO",type=string,JSONPath=`.spec.slo.targetAvailabilityPercent`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Availability",type=string,JSONPath=`.status.probeHealth.availabilityPercent`
+// +kubebuilder:printcolumn:name="Budget Left",type=string,JSONPath=`.status.probeHealth.errorBudgetRemainingPercent`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.probeHealth.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NamespaceMonitor is the Schema for the namespacemonitors API
+type NamespaceMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceMonitorSpec   `json:"spec,omitempty"`
+	Status NamespaceMonitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceMonitorList contains a list of NamespaceMonitor
+type NamespaceMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceMonitor{}, &NamespaceMonitorList{})
+}
@@ -0,0 +1,53 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:webhook:path=/validate-monitoring-openshift-io-v1alpha1-namespacemonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.openshift.io,resources=namespacemonitors,verbs=create;update,versions=v1alpha1,name=vnamespacemonitor.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the webhook validating NamespaceMonitors
+func (r *NamespaceMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&monitorValidator{kind: "NamespaceMonitor", validate: validateNamespaceMonitor}).
+		Complete()
+}
+
+// validateNamespaceMonitor checks the availability target, urls and probe settings of a NamespaceMonitor
+func validateNamespaceMonitor(obj runtime.Object) (interface{}, field.ErrorList) {
+	monitor, ok := obj.(*NamespaceMonitor)
+	if !ok {
+		return nil, field.ErrorList{field.InternalError(nil, fmt.Errorf("expected a NamespaceMonitor but got a %T", obj))}
+	}
+	spec := field.NewPath("spec")
+	errs := validateSlo(spec.Child("slo"), monitor.Spec.Slo)
+	errs = append(errs, validateHTTPProbe(spec.Child("httpProbe"), monitor.Spec.HTTPProbe)...)
+	if monitor.Spec.RouteSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(monitor.Spec.RouteSelector); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("routeSelector"), monitor.Spec.RouteSelector, err.Error()))
+		}
+	}
+	return monitor.Spec, errs
+}
@@ -0,0 +1,59 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProbeTemplateSpec defines the probe settings shared by the monitors referencing the ProbeTemplate
+type ProbeTemplateSpec struct {
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// InsecureSkipTLSVerify probes the targets without verifying their certificates
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify"`
+
+	// +kubebuilder:validation:Optional
+
+	// HTTPProbe customizes the blackbox exporter module used to probe the targets
+	HTTPProbe *HTTPProbeSpec `json:"httpProbe,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ProbeTemplate is the Schema for the probetemplates API
+type ProbeTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProbeTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProbeTemplateList contains a list of ProbeTemplate
+type ProbeTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProbeTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProbeTemplate{}, &ProbeTemplateList{})
+}
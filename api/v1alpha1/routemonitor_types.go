@@ -35,16 +35,96 @@ type RouteMonitorSpec struct {
 	// +kubebuilder:default:false
 	// +kubebuilder:validation:Optional
 
+	// SkipServiceMonitor instructs the controller to skip the creation of ServiceMonitor CRs.
+	// One common use-case is a url that is already scraped elsewhere, with the RouteMonitor only defining the alerts.
+	SkipServiceMonitor bool `json:"skipServiceMonitor"`
+
+	// +kubebuilder:validation:Optional
+
+	// MaintenanceWindows are periods of planned downtime, during which the generated alerts don't fire
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricLabels are added to every probe series scraped for the monitor, e.g. 'tier: frontend' or 'telemetry: fleet', so dashboards
+	// can slice the probes and remote-write filtering can decide which series leave the cluster. Labels set by the operator take precedence
+	MetricLabels map[string]string `json:"metricLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricRelabelings are applied to the probe series after the relabelings managed by the operator, for
+	// rewrites the other settings don't cover. Edits of the generated ServiceMonitor are reverted, these persist
+	MetricRelabelings []RelabelConfig `json:"metricRelabelings,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// SampleLimit is the number of samples a scrape of a probe may return before it fails, protecting Prometheus from
+	// a misbehaving target generating unexpected series. Zero is unlimited
+	SampleLimit uint64 `json:"sampleLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TargetLimit is the number of probed urls the generated ServiceMonitor may have before its scrapes fail. Zero is unlimited
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
 	// InsecureSkipTLSVerify indicates that the blackbox exporter module used to probe this route
 	// should *not* use https
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify"`
 
+	// +kubebuilder:validation:Optional
+
+	// HTTPProbe optionally customizes the blackbox exporter module used to probe the route
+	HTTPProbe *HTTPProbeSpec `json:"httpProbe,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ProbeTemplate names a ProbeTemplate whose probe settings are used instead of insecureSkipTLSVerify and httpProbe,
+	// so the monitors of similar targets share one probe profile
+	ProbeTemplate string `json:"probeTemplate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// DedicatedExporter probes the route from a blackbox exporter deployed into its own namespace instead of the shared one,
+	// so its probes cross the NetworkPolicies of the namespace like the requests of its clients, and other tenants can't
+	// starve them. The exporter is shared by the monitors of the namespace asking for one
+	DedicatedExporter bool `json:"dedicatedExporter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ExternalExporter probes the route from a blackbox exporter run by the user instead of one deployed by the operator
+	ExternalExporter *ExternalExporterSpec `json:"externalExporter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// RouteURLOverride optionally defines the url to probe instead of the one extracted from the Route,
+	// e.g. the public hostname of a CDN or WAF fronting the Route. The Route isn't read if it's set
+	RouteURLOverride string `json:"routeURLOverride,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=monitoring.coreos.com;monitoring.rhobs
 	// +kubebuilder:default=monitoring.coreos.com
 
 	// ServiceMonitorType dictates the type of ServiceMonitor the RouteMonitor should create
 	ServiceMonitorType string `json:"serviceMonitorType,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Hypershift overrides whether the RouteMonitor is treated as monitoring a hosted control plane, which otherwise
+	// follows the serviceMonitorType and, with Hypershift enabled, the HostedControlPlane of its namespace
+	Hypershift *HypershiftSpec `json:"hypershift,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// DebugUntil turns on the debug mode of the monitor until then: every minute, the exporter probes the route with its
+	// debug output, and the transcript of a failing probe is recorded in status.lastFailedProbe and an Event. It can be
+	// at most 24 hours ahead
+	DebugUntil *metav1.Time `json:"debugUntil,omitempty"`
 }
 
 const (
@@ -67,22 +147,77 @@ type RouteMonitorRouteSpec struct {
 	Port int64 `json:"port,omitempty"`
 
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^/`
 
 	// Suffix optionally defines the path we should probe (/livez /readyz etc)
 	Suffix string `json:"suffix,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// RouterName optionally selects the ingress of the Route whose host is probed, by the name of the
+	// router that admitted it. When unset, the first ingress is probed
+	RouterName string `json:"routerName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ProbeAllIngresses probes the host of every ingress of the Route, each as its own target, so
+	// every router admitting the Route is validated. RouterName is ignored if it's set
+	ProbeAllIngresses bool `json:"probeAllIngresses,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;https
+
+	// Scheme optionally forces the scheme the Route is probed with, e.g. http for an edge-terminated Route that is
+	// reachable on plain http internally. When unset, https is probed if the Route has TLS configured
+	Scheme RouteScheme `json:"scheme,omitempty"`
 }
 
+// RouteScheme is the scheme a Route is probed with
+type RouteScheme string
+
+const (
+	// The following values should match the kubebuilder-enumerated values for scheme above
+	RouteSchemeHTTP  RouteScheme = "http"
+	RouteSchemeHTTPS RouteScheme = "https"
+)
+
 // RouteMonitorStatus defines the observed state of RouteMonitor
 type RouteMonitorStatus struct {
 	// RouteURL is the url extracted from the Route resource
-	RouteURL          string         `json:"routeURL,omitempty"`
+	RouteURL string `json:"routeURL,omitempty"`
+	// IngressURLs are the urls of every ingress of the Route, when spec.route.probeAllIngresses is set
+	IngressURLs       []string       `json:"ingressURLs,omitempty"`
 	ServiceMonitorRef NamespacedName `json:"serviceMonitorRef,omitempty"`
 	PrometheusRuleRef NamespacedName `json:"prometheusRuleRef,omitempty"`
 	ErrorStatus       string         `json:"errorStatus,omitempty"`
+	// SloTarget is the availability target, in percent, last observed by the operator
+	SloTarget string `json:"sloTarget,omitempty"`
+	// LastSloChange records the last change of the availability target
+	LastSloChange *SloChange `json:"lastSloChange,omitempty"`
+	// ProbeHealth summarizes the recent probes, if the operator queries Prometheus for them
+	ProbeHealth *ProbeHealth `json:"probeHealth,omitempty"`
+	// LastFailedProbe is the transcript of the last probe that failed while the monitor was in debug mode
+	LastFailedProbe *ProbeTranscript `json:"lastFailedProbe,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the state of each resource generated for the RouteMonitor, so a failure points
+	// at the part of the pipeline that needs attention
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rmo
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.routeURL`
+// +kubebuilder:printcolumn:name="SLO",type=string,JSONPath=`.spec.slo.targetAvailabilityPercent`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Availability",type=string,JSONPath=`.status.probeHealth.availabilityPercent`
+// +kubebuilder:printcolumn:name="Budget Left",type=string,JSONPath=`.status.probeHealth.errorBudgetRemainingPercent`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.probeHealth.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // RouteMonitor is the Schema for the routemonitors API
 type RouteMonitor struct {
@@ -0,0 +1,59 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:webhook:path=/validate-monitoring-openshift-io-v1alpha1-routemonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.openshift.io,resources=routemonitors,verbs=create;update,versions=v1alpha1,name=vroutemonitor.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the webhook validating RouteMonitors
+func (r *RouteMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor}).
+		Complete()
+}
+
+// validateRouteMonitor checks the availability target, urls, probe settings, external exporter and debug mode of a RouteMonitor
+func validateRouteMonitor(obj runtime.Object) (interface{}, field.ErrorList) {
+	monitor, ok := obj.(*RouteMonitor)
+	if !ok {
+		return nil, field.ErrorList{field.InternalError(nil, fmt.Errorf("expected a RouteMonitor but got a %T", obj))}
+	}
+	spec := field.NewPath("spec")
+	errs := validateSlo(spec.Child("slo"), monitor.Spec.Slo)
+	errs = append(errs, validateURL(spec.Child("routeURLOverride"), monitor.Spec.RouteURLOverride)...)
+	errs = append(errs, validateHTTPProbe(spec.Child("httpProbe"), monitor.Spec.HTTPProbe)...)
+	errs = append(errs, validateExternalExporter(spec.Child("externalExporter"), monitor.Spec.ExternalExporter, monitor.Namespace)...)
+	errs = append(errs, validateDebugUntil(spec.Child("debugUntil"), monitor.Spec.DebugUntil)...)
+	// The Route isn't read if the url is overridden
+	if monitor.Spec.RouteURLOverride == "" {
+		if monitor.Spec.Route.Name == "" {
+			errs = append(errs, field.Required(spec.Child("route", "name"), "the Route to probe must be named unless routeURLOverride is set"))
+		}
+		if monitor.Spec.Route.Namespace == "" {
+			errs = append(errs, field.Required(spec.Child("route", "namespace"), "the namespace of the Route must be set unless routeURLOverride is set"))
+		}
+	}
+	return monitor.Spec, errs
+}
@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RouteMonitorSetSpec defines the desired state of RouteMonitorSet
+type RouteMonitorSetSpec struct {
+	// +kubebuilder:validation:Optional
+
+	// NamespaceSelector selects the namespaces whose Routes are monitored. When unset, Routes of every namespace are
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// RouteSelector selects the Routes a RouteMonitor is generated for
+	RouteSelector metav1.LabelSelector `json:"routeSelector"`
+
+	// Template is the spec shared by every generated RouteMonitor. The name and namespace of its route are
+	// set to the ones of each selected Route
+	Template RouteMonitorSpec `json:"template,omitempty"`
+}
+
+// RouteMonitorSetStatus defines the observed state of RouteMonitorSet
+type RouteMonitorSetStatus struct {
+	// RouteMonitors is the number of RouteMonitors generated for the selected Routes
+	RouteMonitors int    `json:"routeMonitors,omitempty"`
+	ErrorStatus   string `json:"errorStatus,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// RouteMonitorSet is the Schema for the routemonitorsets API
+type RouteMonitorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RouteMonitorSetSpec   `json:"spec,omitempty"`
+	Status RouteMonitorSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RouteMonitorSetList contains a list of RouteMonitorSet
+type RouteMonitorSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RouteMonitorSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RouteMonitorSet{}, &RouteMonitorSetList{})
+}
@@ -0,0 +1,162 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UrlMonitorSpec defines the desired state of UrlMonitor
+type UrlMonitorSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// URL is the absolute url to probe, e.g. an in-cluster Service or an external dependency of the application
+	URL string `json:"url"`
+
+	Slo SloSpec `json:"slo,omitempty"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// SkipPrometheusRule instructs the controller to skip the creation of PrometheusRule CRs.
+	// One common use-case for is for alerts that are defined separately.
+	SkipPrometheusRule bool `json:"skipPrometheusRule"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// SkipServiceMonitor instructs the controller to skip the creation of ServiceMonitor CRs.
+	// One common use-case is a url that is already scraped elsewhere, with the UrlMonitor only defining the alerts.
+	SkipServiceMonitor bool `json:"skipServiceMonitor"`
+
+	// +kubebuilder:validation:Optional
+
+	// MaintenanceWindows are periods of planned downtime, during which the generated alerts don't fire
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricLabels are added to every probe series scraped for the monitor, e.g. 'tier: frontend' or 'telemetry: fleet', so dashboards
+	// can slice the probes and remote-write filtering can decide which series leave the cluster. Labels set by the operator take precedence
+	MetricLabels map[string]string `json:"metricLabels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MetricRelabelings are applied to the probe series after the relabelings managed by the operator, for
+	// rewrites the other settings don't cover. Edits of the generated ServiceMonitor are reverted, these persist
+	MetricRelabelings []RelabelConfig `json:"metricRelabelings,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// SampleLimit is the number of samples a scrape of a probe may return before it fails, protecting Prometheus from
+	// a misbehaving target generating unexpected series. Zero is unlimited
+	SampleLimit uint64 `json:"sampleLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TargetLimit is the number of probed urls the generated ServiceMonitor may have before its scrapes fail. Zero is unlimited
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+
+	// +kubebuilder:default:false
+	// +kubebuilder:validation:Optional
+
+	// InsecureSkipTLSVerify probes the url without verifying its certificate, e.g. a Service serving a
+	// certificate signed by the service CA
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify"`
+
+	// +kubebuilder:validation:Optional
+
+	// HTTPProbe optionally customizes the blackbox exporter module used to probe the url
+	HTTPProbe *HTTPProbeSpec `json:"httpProbe,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// DedicatedExporter probes the url from a blackbox exporter deployed into its own namespace instead of the shared one,
+	// so its probes cross the NetworkPolicies of the namespace like the requests of its clients, and other tenants can't
+	// starve them. The exporter is shared by the monitors of the namespace asking for one
+	DedicatedExporter bool `json:"dedicatedExporter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ExternalExporter probes the url from a blackbox exporter run by the user instead of one deployed by the operator
+	ExternalExporter *ExternalExporterSpec `json:"externalExporter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// DebugUntil turns on the debug mode of the monitor until then: every minute, the exporter probes the url with its
+	// debug output, and the transcript of a failing probe is recorded in status.lastFailedProbe and an Event. It can be
+	// at most 24 hours ahead
+	DebugUntil *metav1.Time `json:"debugUntil,omitempty"`
+}
+
+// UrlMonitorStatus defines the observed state of UrlMonitor
+type UrlMonitorStatus struct {
+	ServiceMonitorRef NamespacedName `json:"serviceMonitorRef,omitempty"`
+	PrometheusRuleRef NamespacedName `json:"prometheusRuleRef,omitempty"`
+	ErrorStatus       string         `json:"errorStatus,omitempty"`
+	// SloTarget is the availability target, in percent, last observed by the operator
+	SloTarget string `json:"sloTarget,omitempty"`
+	// LastSloChange records the last change of the availability target
+	LastSloChange *SloChange `json:"lastSloChange,omitempty"`
+	// ProbeHealth summarizes the recent probes, if the operator queries Prometheus for them
+	ProbeHealth *ProbeHealth `json:"probeHealth,omitempty"`
+	// LastFailedProbe is the transcript of the last probe that failed while the monitor was in debug mode
+	LastFailedProbe *ProbeTranscript `json:"lastFailedProbe,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+
+	// Conditions report the state of each resource generated for the UrlMonitor, so a failure points
+	// at the part of the pipeline that needs attention
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="SLO",type=string,JSONPath=`.spec.slo.targetAvailabilityPercent`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Availability",type=string,JSONPath=`.status.probeHealth.availabilityPercent`
+// +kubebuilder:printcolumn:name="Budget Left",type=string,JSONPath=`.status.probeHealth.errorBudgetRemainingPercent`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.probeHealth.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// UrlMonitor is the Schema for the urlmonitors API
+type UrlMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UrlMonitorSpec   `json:"spec,omitempty"`
+	Status UrlMonitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UrlMonitorList contains a list of UrlMonitor
+type UrlMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UrlMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UrlMonitor{}, &UrlMonitorList{})
+}
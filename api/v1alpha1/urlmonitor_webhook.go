@@ -0,0 +1,50 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:webhook:path=/validate-monitoring-openshift-io-v1alpha1-urlmonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.openshift.io,resources=urlmonitors,verbs=create;update,versions=v1alpha1,name=vurlmonitor.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the webhook validating UrlMonitors
+func (r *UrlMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&monitorValidator{kind: "UrlMonitor", validate: validateUrlMonitor}).
+		Complete()
+}
+
+// validateUrlMonitor checks the availability target, urls, probe settings, external exporter and debug mode of a UrlMonitor
+func validateUrlMonitor(obj runtime.Object) (interface{}, field.ErrorList) {
+	monitor, ok := obj.(*UrlMonitor)
+	if !ok {
+		return nil, field.ErrorList{field.InternalError(nil, fmt.Errorf("expected a UrlMonitor but got a %T", obj))}
+	}
+	spec := field.NewPath("spec")
+	errs := validateSlo(spec.Child("slo"), monitor.Spec.Slo)
+	errs = append(errs, validateURL(spec.Child("url"), monitor.Spec.URL)...)
+	errs = append(errs, validateHTTPProbe(spec.Child("httpProbe"), monitor.Spec.HTTPProbe)...)
+	errs = append(errs, validateExternalExporter(spec.Child("externalExporter"), monitor.Spec.ExternalExporter, monitor.Namespace)...)
+	errs = append(errs, validateDebugUntil(spec.Child("debugUntil"), monitor.Spec.DebugUntil)...)
+	return monitor.Spec, errs
+}
@@ -0,0 +1,160 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// monitorValidator rejects monitors of a kind whose spec the operator would only report as invalid in the
+// ErrorStatus after reconciling them
+type monitorValidator struct {
+	kind string
+	// validate returns the spec of the monitor and what's invalid about it
+	validate func(obj runtime.Object) (interface{}, field.ErrorList)
+}
+
+var _ admission.CustomValidator = &monitorValidator{}
+
+// ValidateCreate rejects invalid monitors
+func (v *monitorValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	_, errs := v.validate(obj)
+	return nil, v.invalidIfAny(obj, errs)
+}
+
+// ValidateUpdate rejects changes making the spec invalid. Updates that don't change the spec, such as of
+// the finalizers, are admitted, so monitors created before the webhook can still be deleted
+func (v *monitorValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	if monitor, ok := newObj.(metav1.Object); ok && monitor.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	oldSpec, _ := v.validate(oldObj)
+	newSpec, errs := v.validate(newObj)
+	if reflect.DeepEqual(oldSpec, newSpec) {
+		return nil, nil
+	}
+	return nil, v.invalidIfAny(newObj, errs)
+}
+
+// ValidateDelete admits every deletion
+func (v *monitorValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// invalidIfAny returns the errors as the Invalid error of the monitor, or nil if there are none
+func (v *monitorValidator) invalidIfAny(obj runtime.Object, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	monitor, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("expected a %s but got a %T", v.kind, obj)
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: v.kind}, monitor.GetName(), errs)
+}
+
+// validateSlo rejects availability and latency targets the operator can't alert on. An empty SloSpec is valid,
+// the monitor falls back to the cluster-wide default
+func validateSlo(path *field.Path, slo SloSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	if reflect.DeepEqual(slo, SloSpec{}) {
+		return errs
+	}
+	if isValid, _ := slo.IsValid(); !isValid {
+		errs = append(errs, field.Invalid(path.Child("targetAvailabilityPercent"), slo.TargetAvailabilityPercent,
+			"must be a number between 0 and 100, exclusive"))
+	}
+	if slo.Latency != nil {
+		if isValid, _ := slo.Latency.IsValid(); !isValid {
+			errs = append(errs, field.Invalid(path.Child("latency"), *slo.Latency,
+				"thresholdMilliseconds must be positive and targetPercent a number between 0 and 100, exclusive"))
+		}
+	}
+	return errs
+}
+
+// validateURL rejects urls that can't be parsed or don't name a host. An empty url is valid, required urls
+// are enforced by the CRD
+func validateURL(path *field.Path, rawURL string) field.ErrorList {
+	errs := field.ErrorList{}
+	if rawURL == "" {
+		return errs
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return append(errs, field.Invalid(path, rawURL, err.Error()))
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		errs = append(errs, field.Invalid(path, rawURL, "must be an http or https url"))
+	}
+	if parsed.Hostname() == "" {
+		errs = append(errs, field.Invalid(path, rawURL, "must name a host"))
+	}
+	return errs
+}
+
+// validateHTTPProbe rejects a malformed proxy url of the probe and header assertions the exporter can't compile
+func validateHTTPProbe(path *field.Path, probe *HTTPProbeSpec) field.ErrorList {
+	if probe == nil {
+		return field.ErrorList{}
+	}
+	errs := validateURL(path.Child("proxyURL"), probe.ProxyURL)
+	errs = append(errs, validateHeaderAssertions(path.Child("failIfHeaderMatches"), probe.FailIfHeaderMatches)...)
+	return append(errs, validateHeaderAssertions(path.Child("failIfHeaderNotMatches"), probe.FailIfHeaderNotMatches)...)
+}
+
+// validateExternalExporter rejects the Service of an external exporter outside of the namespace of the monitor, so
+// editing a namespace's monitors doesn't let Prometheus scrape the Services of other namespaces
+func validateExternalExporter(path *field.Path, exporter *ExternalExporterSpec, namespace string) field.ErrorList {
+	errs := field.ErrorList{}
+	if exporter == nil || exporter.Service == nil || exporter.Service.Namespace == namespace {
+		return errs
+	}
+	return append(errs, field.Invalid(path.Child("service", "namespace"), exporter.Service.Namespace, "must be the namespace of the monitor"))
+}
+
+// validateDebugUntil rejects a debug mode turned on for longer than the MaxDebugDuration
+func validateDebugUntil(path *field.Path, debugUntil *metav1.Time) field.ErrorList {
+	errs := field.ErrorList{}
+	if debugUntil != nil && debugUntil.After(time.Now().Add(MaxDebugDuration)) {
+		errs = append(errs, field.Invalid(path, debugUntil.UTC().Format(time.RFC3339), fmt.Sprintf("must be at most %s ahead", MaxDebugDuration)))
+	}
+	return errs
+}
+
+// validateHeaderAssertions rejects the regular expressions Go's regexp package, which the exporter uses, can't compile
+func validateHeaderAssertions(path *field.Path, assertions []HeaderAssertion) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, assertion := range assertions {
+		if _, err := regexp.Compile(assertion.Regexp); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("regexp"), assertion.Regexp, err.Error()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,146 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMonitorValidator_ValidateCreate(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"}
+	tests := []struct {
+		name      string
+		validator *monitorValidator
+		obj       runtime.Object
+		wantErr   string
+	}{
+		{
+			name:      "a valid RouteMonitor",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj: &RouteMonitor{ObjectMeta: meta, Spec: RouteMonitorSpec{
+				Route: RouteMonitorRouteSpec{Name: "fake-route", Namespace: "fake-namespace"},
+				Slo:   SloSpec{TargetAvailabilityPercent: "99.5"},
+			}},
+		},
+		{
+			name:      "a RouteMonitor without Route",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj:       &RouteMonitor{ObjectMeta: meta},
+			wantErr:   "spec.route.name: Required value",
+		},
+		{
+			name:      "a RouteMonitor overriding the url instead of naming a Route",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj:       &RouteMonitor{ObjectMeta: meta, Spec: RouteMonitorSpec{RouteURLOverride: "https://shop.example.com"}},
+		},
+		{
+			name:      "a RouteMonitor with an out of range target",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj: &RouteMonitor{ObjectMeta: meta, Spec: RouteMonitorSpec{
+				RouteURLOverride: "https://shop.example.com",
+				Slo:              SloSpec{TargetAvailabilityPercent: "100"},
+			}},
+			wantErr: "spec.slo.targetAvailabilityPercent: Invalid value",
+		},
+		{
+			name:      "a UrlMonitor without host",
+			validator: &monitorValidator{kind: "UrlMonitor", validate: validateUrlMonitor},
+			obj:       &UrlMonitor{ObjectMeta: meta, Spec: UrlMonitorSpec{URL: "https:///healthz"}},
+			wantErr:   "spec.url: Invalid value: \"https:///healthz\": must name a host",
+		},
+		{
+			name:      "a UrlMonitor probed by the exporter Service of its namespace",
+			validator: &monitorValidator{kind: "UrlMonitor", validate: validateUrlMonitor},
+			obj: &UrlMonitor{ObjectMeta: meta, Spec: UrlMonitorSpec{
+				URL:              "https://shop.example.com",
+				ExternalExporter: &ExternalExporterSpec{Service: &ExternalExporterServiceSpec{Name: "blackbox", Namespace: "fake-namespace"}},
+			}},
+		},
+		{
+			name:      "a RouteMonitor probed by the exporter Service of another namespace",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj: &RouteMonitor{ObjectMeta: meta, Spec: RouteMonitorSpec{
+				RouteURLOverride: "https://shop.example.com",
+				ExternalExporter: &ExternalExporterSpec{Service: &ExternalExporterServiceSpec{Name: "blackbox", Namespace: "kube-system"}},
+			}},
+			wantErr: "spec.externalExporter.service.namespace: Invalid value: \"kube-system\": must be the namespace of the monitor",
+		},
+		{
+			name:      "a UrlMonitor debugged for more than a day",
+			validator: &monitorValidator{kind: "UrlMonitor", validate: validateUrlMonitor},
+			obj: &UrlMonitor{ObjectMeta: meta, Spec: UrlMonitorSpec{
+				URL:        "https://shop.example.com",
+				DebugUntil: &metav1.Time{Time: time.Now().Add(MaxDebugDuration + time.Hour)},
+			}},
+			wantErr: "spec.debugUntil: Invalid value",
+		},
+		{
+			name:      "a ClusterUrlMonitor with a malformed proxy url",
+			validator: &monitorValidator{kind: "ClusterUrlMonitor", validate: validateClusterUrlMonitor},
+			obj:       &ClusterUrlMonitor{ObjectMeta: meta, Spec: ClusterUrlMonitorSpec{HTTPProbe: &HTTPProbeSpec{ProxyURL: "http://proxy:port"}}},
+			wantErr:   "spec.httpProbe.proxyURL: Invalid value",
+		},
+		{
+			name:      "a RouteMonitor with a header assertion that doesn't compile",
+			validator: &monitorValidator{kind: "RouteMonitor", validate: validateRouteMonitor},
+			obj: &RouteMonitor{ObjectMeta: meta, Spec: RouteMonitorSpec{HTTPProbe: &HTTPProbeSpec{
+				FailIfHeaderNotMatches: []HeaderAssertion{{Header: "Strict-Transport-Security", Regexp: "max-age"}, {Header: "X-Cache", Regexp: "("}},
+			}}},
+			wantErr: "spec.httpProbe.failIfHeaderNotMatches[1].regexp: Invalid value: \"(\"",
+		},
+		{
+			name:      "a NamespaceMonitor with an invalid latency objective",
+			validator: &monitorValidator{kind: "NamespaceMonitor", validate: validateNamespaceMonitor},
+			obj: &NamespaceMonitor{ObjectMeta: meta, Spec: NamespaceMonitorSpec{
+				Slo: SloSpec{TargetAvailabilityPercent: "99", Latency: &LatencySloSpec{TargetPercent: "99"}},
+			}},
+			wantErr: "spec.slo.latency: Invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.validator.ValidateCreate(context.TODO(), tt.obj)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateCreate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateCreate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMonitorValidator_ValidateUpdate(t *testing.T) {
+	validator := &monitorValidator{kind: "UrlMonitor", validate: validateUrlMonitor}
+	invalid := &UrlMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"},
+		Spec:       UrlMonitorSpec{URL: "https://shop.example.com", Slo: SloSpec{TargetAvailabilityPercent: "200"}},
+	}
+
+	withFinalizer := invalid.DeepCopy()
+	withFinalizer.Finalizers = []string{"fake-finalizer"}
+	if _, err := validator.ValidateUpdate(context.TODO(), invalid, withFinalizer); err != nil {
+		t.Errorf("ValidateUpdate() = %v, want updates keeping the spec admitted", err)
+	}
+
+	changed := invalid.DeepCopy()
+	changed.Spec.URL = "https://other.example.com"
+	if _, err := validator.ValidateUpdate(context.TODO(), invalid, changed); err == nil {
+		t.Errorf("ValidateUpdate() = nil, want changes of an invalid spec rejected")
+	}
+
+	deleted := changed.DeepCopy()
+	now := metav1.Now()
+	deleted.DeletionTimestamp = &now
+	if _, err := validator.ValidateUpdate(context.TODO(), invalid, deleted); err != nil {
+		t.Errorf("ValidateUpdate() = %v, want updates of deleted monitors admitted", err)
+	}
+}
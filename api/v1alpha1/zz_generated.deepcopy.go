@@ -21,30 +21,705 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSeveritySpec) DeepCopyInto(out *AlertSeveritySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSeveritySpec.
+func (in *AlertSeveritySpec) DeepCopy() *AlertSeveritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSeveritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackBoxExporterConfig) DeepCopyInto(out *BlackBoxExporterConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackBoxExporterConfig.
+func (in *BlackBoxExporterConfig) DeepCopy() *BlackBoxExporterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackBoxExporterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlackBoxExporterConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackBoxExporterConfigList) DeepCopyInto(out *BlackBoxExporterConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BlackBoxExporterConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackBoxExporterConfigList.
+func (in *BlackBoxExporterConfigList) DeepCopy() *BlackBoxExporterConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackBoxExporterConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlackBoxExporterConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackBoxExporterConfigStatus) DeepCopyInto(out *BlackBoxExporterConfigStatus) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackBoxExporterConfigStatus.
+func (in *BlackBoxExporterConfigStatus) DeepCopy() *BlackBoxExporterConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackBoxExporterConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMonitor) DeepCopyInto(out *CertificateMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMonitor.
+func (in *CertificateMonitor) DeepCopy() *CertificateMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMonitorList) DeepCopyInto(out *CertificateMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMonitorList.
+func (in *CertificateMonitorList) DeepCopy() *CertificateMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMonitorSpec) DeepCopyInto(out *CertificateMonitorSpec) {
+	*out = *in
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertLabels != nil {
+		in, out := &in.AlertLabels, &out.AlertLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMonitorSpec.
+func (in *CertificateMonitorSpec) DeepCopy() *CertificateMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMonitorStatus) DeepCopyInto(out *CertificateMonitorStatus) {
+	*out = *in
+	out.ServiceMonitorRef = in.ServiceMonitorRef
+	out.PrometheusRuleRef = in.PrometheusRuleRef
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMonitorStatus.
+func (in *CertificateMonitorStatus) DeepCopy() *CertificateMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterUrlMonitor) DeepCopyInto(out *ClusterUrlMonitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitor.
+func (in *ClusterUrlMonitor) DeepCopy() *ClusterUrlMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUrlMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUrlMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUrlMonitorList) DeepCopyInto(out *ClusterUrlMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterUrlMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorList.
+func (in *ClusterUrlMonitorList) DeepCopy() *ClusterUrlMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUrlMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUrlMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUrlMonitorSpec) DeepCopyInto(out *ClusterUrlMonitorSpec) {
+	*out = *in
+	in.Slo.DeepCopyInto(&out.Slo)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hypershift != nil {
+		in, out := &in.Hypershift, &out.Hypershift
+		*out = new(HypershiftSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorSpec.
+func (in *ClusterUrlMonitorSpec) DeepCopy() *ClusterUrlMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUrlMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUrlMonitorStatus) DeepCopyInto(out *ClusterUrlMonitorStatus) {
+	*out = *in
+	out.ServiceMonitorRef = in.ServiceMonitorRef
+	out.PrometheusRuleRef = in.PrometheusRuleRef
+	if in.LastSloChange != nil {
+		in, out := &in.LastSloChange, &out.LastSloChange
+		*out = new(SloChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeHealth != nil {
+		in, out := &in.ProbeHealth, &out.ProbeHealth
+		*out = new(ProbeHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorStatus.
+func (in *ClusterUrlMonitorStatus) DeepCopy() *ClusterUrlMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUrlMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalExporterServiceSpec) DeepCopyInto(out *ExternalExporterServiceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalExporterServiceSpec.
+func (in *ExternalExporterServiceSpec) DeepCopy() *ExternalExporterServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalExporterServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalExporterSpec) DeepCopyInto(out *ExternalExporterSpec) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ExternalExporterServiceSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalExporterSpec.
+func (in *ExternalExporterSpec) DeepCopy() *ExternalExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbeSpec) DeepCopyInto(out *HTTPProbeSpec) {
+	*out = *in
+	if in.FollowRedirects != nil {
+		in, out := &in.FollowRedirects, &out.FollowRedirects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPProtocolFallback != nil {
+		in, out := &in.IPProtocolFallback, &out.IPProtocolFallback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ValidHTTPVersions != nil {
+		in, out := &in.ValidHTTPVersions, &out.ValidHTTPVersions
+		*out = make([]HTTPVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailIfHeaderMatches != nil {
+		in, out := &in.FailIfHeaderMatches, &out.FailIfHeaderMatches
+		*out = make([]HeaderAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailIfHeaderNotMatches != nil {
+		in, out := &in.FailIfHeaderNotMatches, &out.FailIfHeaderNotMatches
+		*out = make([]HeaderAssertion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbeSpec.
+func (in *HTTPProbeSpec) DeepCopy() *HTTPProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderAssertion) DeepCopyInto(out *HeaderAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderAssertion.
+func (in *HeaderAssertion) DeepCopy() *HeaderAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HypershiftSpec) DeepCopyInto(out *HypershiftSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HypershiftSpec.
+func (in *HypershiftSpec) DeepCopy() *HypershiftSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HypershiftSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencySloSpec) DeepCopyInto(out *LatencySloSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencySloSpec.
+func (in *LatencySloSpec) DeepCopy() *LatencySloSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencySloSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMonitor) DeepCopyInto(out *NamespaceMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMonitor.
+func (in *NamespaceMonitor) DeepCopy() *NamespaceMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMonitorList) DeepCopyInto(out *NamespaceMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMonitorList.
+func (in *NamespaceMonitorList) DeepCopy() *NamespaceMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMonitorSpec) DeepCopyInto(out *NamespaceMonitorSpec) {
+	*out = *in
+	if in.RouteSelector != nil {
+		in, out := &in.RouteSelector, &out.RouteSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Slo.DeepCopyInto(&out.Slo)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMonitorSpec.
+func (in *NamespaceMonitorSpec) DeepCopy() *NamespaceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMonitorStatus) DeepCopyInto(out *NamespaceMonitorStatus) {
+	*out = *in
+	if in.RouteURLs != nil {
+		in, out := &in.RouteURLs, &out.RouteURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.ServiceMonitorRef = in.ServiceMonitorRef
+	out.PrometheusRuleRef = in.PrometheusRuleRef
+	if in.LastSloChange != nil {
+		in, out := &in.LastSloChange, &out.LastSloChange
+		*out = new(SloChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeHealth != nil {
+		in, out := &in.ProbeHealth, &out.ProbeHealth
+		*out = new(ProbeHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMonitorStatus.
+func (in *NamespaceMonitorStatus) DeepCopy() *NamespaceMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
+func (in *NamespacedName) DeepCopy() *NamespacedName {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeHealth) DeepCopyInto(out *ProbeHealth) {
+	*out = *in
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	in.ObservedTime.DeepCopyInto(&out.ObservedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeHealth.
+func (in *ProbeHealth) DeepCopy() *ProbeHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTemplate) DeepCopyInto(out *ProbeTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitor.
-func (in *ClusterUrlMonitor) DeepCopy() *ClusterUrlMonitor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplate.
+func (in *ProbeTemplate) DeepCopy() *ProbeTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterUrlMonitor)
+	out := new(ProbeTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterUrlMonitor) DeepCopyObject() runtime.Object {
+func (in *ProbeTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -52,31 +727,31 @@ func (in *ClusterUrlMonitor) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterUrlMonitorList) DeepCopyInto(out *ClusterUrlMonitorList) {
+func (in *ProbeTemplateList) DeepCopyInto(out *ProbeTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterUrlMonitor, len(*in))
+		*out = make([]ProbeTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorList.
-func (in *ClusterUrlMonitorList) DeepCopy() *ClusterUrlMonitorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplateList.
+func (in *ProbeTemplateList) DeepCopy() *ProbeTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterUrlMonitorList)
+	out := new(ProbeTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterUrlMonitorList) DeepCopyObject() runtime.Object {
+func (in *ProbeTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -84,49 +759,57 @@ func (in *ClusterUrlMonitorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterUrlMonitorSpec) DeepCopyInto(out *ClusterUrlMonitorSpec) {
+func (in *ProbeTemplateSpec) DeepCopyInto(out *ProbeTemplateSpec) {
 	*out = *in
-	out.Slo = in.Slo
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorSpec.
-func (in *ClusterUrlMonitorSpec) DeepCopy() *ClusterUrlMonitorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplateSpec.
+func (in *ProbeTemplateSpec) DeepCopy() *ProbeTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterUrlMonitorSpec)
+	out := new(ProbeTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterUrlMonitorStatus) DeepCopyInto(out *ClusterUrlMonitorStatus) {
+func (in *ProbeTranscript) DeepCopyInto(out *ProbeTranscript) {
 	*out = *in
-	out.ServiceMonitorRef = in.ServiceMonitorRef
-	out.PrometheusRuleRef = in.PrometheusRuleRef
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUrlMonitorStatus.
-func (in *ClusterUrlMonitorStatus) DeepCopy() *ClusterUrlMonitorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTranscript.
+func (in *ProbeTranscript) DeepCopy() *ProbeTranscript {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterUrlMonitorStatus)
+	out := new(ProbeTranscript)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
 	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
-func (in *NamespacedName) DeepCopy() *NamespacedName {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespacedName)
+	out := new(RelabelConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -136,8 +819,8 @@ func (in *RouteMonitor) DeepCopyInto(out *RouteMonitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitor.
@@ -205,11 +888,147 @@ func (in *RouteMonitorRouteSpec) DeepCopy() *RouteMonitorRouteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteMonitorSet) DeepCopyInto(out *RouteMonitorSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorSet.
+func (in *RouteMonitorSet) DeepCopy() *RouteMonitorSet {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteMonitorSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RouteMonitorSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteMonitorSetList) DeepCopyInto(out *RouteMonitorSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RouteMonitorSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorSetList.
+func (in *RouteMonitorSetList) DeepCopy() *RouteMonitorSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteMonitorSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RouteMonitorSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteMonitorSetSpec) DeepCopyInto(out *RouteMonitorSetSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.RouteSelector.DeepCopyInto(&out.RouteSelector)
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorSetSpec.
+func (in *RouteMonitorSetSpec) DeepCopy() *RouteMonitorSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteMonitorSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteMonitorSetStatus) DeepCopyInto(out *RouteMonitorSetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorSetStatus.
+func (in *RouteMonitorSetStatus) DeepCopy() *RouteMonitorSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteMonitorSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteMonitorSpec) DeepCopyInto(out *RouteMonitorSpec) {
 	*out = *in
 	out.Route = in.Route
-	out.Slo = in.Slo
+	in.Slo.DeepCopyInto(&out.Slo)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalExporter != nil {
+		in, out := &in.ExternalExporter, &out.ExternalExporter
+		*out = new(ExternalExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hypershift != nil {
+		in, out := &in.Hypershift, &out.Hypershift
+		*out = new(HypershiftSpec)
+		**out = **in
+	}
+	if in.DebugUntil != nil {
+		in, out := &in.DebugUntil, &out.DebugUntil
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorSpec.
@@ -225,8 +1044,35 @@ func (in *RouteMonitorSpec) DeepCopy() *RouteMonitorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteMonitorStatus) DeepCopyInto(out *RouteMonitorStatus) {
 	*out = *in
+	if in.IngressURLs != nil {
+		in, out := &in.IngressURLs, &out.IngressURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.ServiceMonitorRef = in.ServiceMonitorRef
 	out.PrometheusRuleRef = in.PrometheusRuleRef
+	if in.LastSloChange != nil {
+		in, out := &in.LastSloChange, &out.LastSloChange
+		*out = new(SloChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeHealth != nil {
+		in, out := &in.ProbeHealth, &out.ProbeHealth
+		*out = new(ProbeHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastFailedProbe != nil {
+		in, out := &in.LastFailedProbe, &out.LastFailedProbe
+		*out = new(ProbeTranscript)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMonitorStatus.
@@ -239,9 +1085,54 @@ func (in *RouteMonitorStatus) DeepCopy() *RouteMonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SloChange) DeepCopyInto(out *SloChange) {
+	*out = *in
+	in.ChangedAt.DeepCopyInto(&out.ChangedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SloChange.
+func (in *SloChange) DeepCopy() *SloChange {
+	if in == nil {
+		return nil
+	}
+	out := new(SloChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SloSpec) DeepCopyInto(out *SloSpec) {
 	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencySloSpec)
+		**out = **in
+	}
+	if in.AlertLabels != nil {
+		in, out := &in.AlertLabels, &out.AlertLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertAnnotations != nil {
+		in, out := &in.AlertAnnotations, &out.AlertAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Severities != nil {
+		in, out := &in.Severities, &out.Severities
+		*out = new(AlertSeveritySpec)
+		**out = **in
+	}
+	if in.CountClientErrorsAsFailure != nil {
+		in, out := &in.CountClientErrorsAsFailure, &out.CountClientErrorsAsFailure
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SloSpec.
@@ -253,3 +1144,152 @@ func (in *SloSpec) DeepCopy() *SloSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UrlMonitor) DeepCopyInto(out *UrlMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UrlMonitor.
+func (in *UrlMonitor) DeepCopy() *UrlMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(UrlMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UrlMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UrlMonitorList) DeepCopyInto(out *UrlMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UrlMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UrlMonitorList.
+func (in *UrlMonitorList) DeepCopy() *UrlMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(UrlMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UrlMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UrlMonitorSpec) DeepCopyInto(out *UrlMonitorSpec) {
+	*out = *in
+	in.Slo.DeepCopyInto(&out.Slo)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalExporter != nil {
+		in, out := &in.ExternalExporter, &out.ExternalExporter
+		*out = new(ExternalExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DebugUntil != nil {
+		in, out := &in.DebugUntil, &out.DebugUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UrlMonitorSpec.
+func (in *UrlMonitorSpec) DeepCopy() *UrlMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UrlMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UrlMonitorStatus) DeepCopyInto(out *UrlMonitorStatus) {
+	*out = *in
+	out.ServiceMonitorRef = in.ServiceMonitorRef
+	out.PrometheusRuleRef = in.PrometheusRuleRef
+	if in.LastSloChange != nil {
+		in, out := &in.LastSloChange, &out.LastSloChange
+		*out = new(SloChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeHealth != nil {
+		in, out := &in.ProbeHealth, &out.ProbeHealth
+		*out = new(ProbeHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastFailedProbe != nil {
+		in, out := &in.LastFailedProbe, &out.LastFailedProbe
+		*out = new(ProbeTranscript)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UrlMonitorStatus.
+func (in *UrlMonitorStatus) DeepCopy() *UrlMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UrlMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
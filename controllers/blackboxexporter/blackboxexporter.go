@@ -0,0 +1,172 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blackboxexporter
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	consts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// BlackBoxExporterReconciler corrects the drift of the exporters' Deployments, DaemonSets and Services, e.g. a hand
+// edited image or a removed port, as soon as it happens rather than with the next reconcile of a monitor
+type BlackBoxExporterReconciler struct {
+	client.Client
+	Log logr.Logger
+	// BlackBoxExporter is the shared exporter, the exporters of the other namespaces are dedicated to their monitors
+	BlackBoxExporter *blackboxexporter.BlackBoxExporter
+	// RuntimeConfig, while set, reconciles all exporters whenever their settings change
+	RuntimeConfig *runtimeconfig.Config
+}
+
+// NewReconciler creates a BlackBoxExporterReconciler managing the exporters like the monitor reconcilers do
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *BlackBoxExporterReconciler {
+	log := ctrl.Log.WithName("controllers").WithName("BlackBoxExporter")
+	exporter := blackboxexporter.New(mgr.GetClient(), log, context.Background(), opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	exporter.FIPS = opts.FIPSMode
+	exporter.Deployment = opts.BlackBoxExporterDeployment
+	exporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	exporter.RuntimeConfig = opts.RuntimeConfig
+	exporter.FieldOwner = opts.FieldOwner
+	return &BlackBoxExporterReconciler{
+		Client:           mgr.GetClient(),
+		Log:              log,
+		BlackBoxExporter: exporter,
+		RuntimeConfig:    opts.RuntimeConfig,
+	}
+}
+
+// exporterIn returns the exporter of the namespace
+func (r *BlackBoxExporterReconciler) exporterIn(namespace string) *blackboxexporter.BlackBoxExporter {
+	if namespace == r.BlackBoxExporter.GetBlackBoxExporterNamespace() {
+		return r.BlackBoxExporter
+	}
+	return r.BlackBoxExporter.DedicatedTo(namespace)
+}
+
+// Reconcile restores the resources of the exporter while a monitor is probed by it. The exporters no monitor uses
+// anymore are left to the monitor reconcilers, which delete them
+func (r *BlackBoxExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithName("Reconcile").WithValues("namespace", req.Namespace)
+	exporter := r.exporterIn(req.Namespace)
+	inUse, err := exporter.InUse()
+	if err != nil {
+		return utilreconcile.RequeueWith(err)
+	}
+	if !inUse {
+		return utilreconcile.Stop()
+	}
+	if err := exporter.EnsureBlackBoxExporterResourcesExist(); err != nil {
+		return utilreconcile.RequeueWith(err)
+	}
+	log.V(logging.DebugVerbosity).Info("Reconciled the BlackBoxExporter")
+	return utilreconcile.Stop()
+}
+
+// isExporter keeps the resources of the exporters, which share their name and labels
+var isExporter = predicate.NewPredicateFuncs(func(o client.Object) bool {
+	return o.GetName() == consts.BlackBoxExporterName && o.GetLabels()["app"] == consts.BlackBoxExporterName
+})
+
+// specChanged keeps the changes of the resources' specs or metadata and their deletions, leaving out the frequent
+// updates of the status of the exporter pods. Services don't bump their generation, so their spec is compared
+var specChanged = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+			!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) {
+			return true
+		}
+		oldService, isService := e.ObjectOld.(*corev1.Service)
+		newService, _ := e.ObjectNew.(*corev1.Service)
+		return isService && newService != nil && !reflect.DeepEqual(oldService.Spec, newService.Spec)
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// rolloutProgressed keeps the updates of the exporter pods' rollout, so it's recorded in the BlackBoxExporterConfig as
+// soon as it completes
+var rolloutProgressed = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		switch old := e.ObjectOld.(type) {
+		case *appsv1.Deployment:
+			updated, ok := e.ObjectNew.(*appsv1.Deployment)
+			return ok && (old.Status.UpdatedReplicas != updated.Status.UpdatedReplicas ||
+				old.Status.AvailableReplicas != updated.Status.AvailableReplicas ||
+				old.Status.Replicas != updated.Status.Replicas)
+		case *appsv1.DaemonSet:
+			updated, ok := e.ObjectNew.(*appsv1.DaemonSet)
+			return ok && (old.Status.UpdatedNumberScheduled != updated.Status.UpdatedNumberScheduled ||
+				old.Status.NumberAvailable != updated.Status.NumberAvailable ||
+				old.Status.DesiredNumberScheduled != updated.Status.DesiredNumberScheduled)
+		}
+		return false
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BlackBoxExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	predicates := builder.WithPredicates(isExporter, predicate.Or(specChanged, rolloutProgressed))
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		Named("blackboxexporter").
+		For(&appsv1.Deployment{}, predicates).
+		Watches(&appsv1.DaemonSet{}, &handler.EnqueueRequestForObject{}, predicates).
+		Watches(&corev1.Service{}, &handler.EnqueueRequestForObject{}, predicates)
+	if r.RuntimeConfig != nil {
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listExporters, r.Log))
+	}
+	return bldr.Complete(r)
+}
+
+// listExporters returns the Services of the exporters, the shared one's even if it's missing, so the exporter of
+// each namespace is reconciled once
+func (r *BlackBoxExporterReconciler) listExporters(ctx context.Context) ([]client.Object, error) {
+	list := &corev1.ServiceList{}
+	if err := r.Client.List(ctx, list, client.MatchingLabels{"app": consts.BlackBoxExporterName}); err != nil {
+		return nil, err
+	}
+	shared := &corev1.Service{}
+	shared.Name = consts.BlackBoxExporterName
+	shared.Namespace = r.BlackBoxExporter.GetBlackBoxExporterNamespace()
+	exporters := []client.Object{shared}
+	for i := range list.Items {
+		if list.Items[i].Name == consts.BlackBoxExporterName && list.Items[i].Namespace != shared.Namespace {
+			exporters = append(exporters, &list.Items[i])
+		}
+	}
+	return exporters, nil
+}
@@ -0,0 +1,85 @@
+package blackboxexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	consts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+)
+
+func TestBlackBoxExporterReconciler_Reconcile(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name           string
+		monitors       []client.Object
+		namespace      string
+		wantDeployment bool
+	}{
+		{
+			name:      "no monitor uses the exporter",
+			namespace: "exporter-namespace",
+		},
+		{
+			name: "a monitor uses the shared exporter",
+			monitors: []client.Object{&v1alpha1.UrlMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop-namespace"},
+			}},
+			namespace:      "exporter-namespace",
+			wantDeployment: true,
+		},
+		{
+			name: "the only monitor using the exporter is being deleted",
+			monitors: []client.Object{&v1alpha1.UrlMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop-namespace", DeletionTimestamp: &now, Finalizers: []string{"fake-finalizer"}},
+			}},
+			namespace: "exporter-namespace",
+		},
+		{
+			name: "a monitor uses the dedicated exporter of its namespace",
+			monitors: []client.Object{&v1alpha1.UrlMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop-namespace"},
+				Spec:       v1alpha1.UrlMonitorSpec{DedicatedExporter: true},
+			}},
+			namespace:      "shop-namespace",
+			wantDeployment: true,
+		},
+		{
+			name: "the monitor of the namespace uses the shared exporter",
+			monitors: []client.Object{&v1alpha1.UrlMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop-namespace"},
+			}},
+			namespace: "shop-namespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kclient := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(tt.monitors...).WithStatusSubresource(&v1alpha1.BlackBoxExporterConfig{}).Build()
+			r := &BlackBoxExporterReconciler{
+				Client:           kclient,
+				Log:              logr.Discard(),
+				BlackBoxExporter: blackboxexporter.New(kclient, logr.Discard(), context.TODO(), "fake-image", "exporter-namespace"),
+			}
+
+			exporter := types.NamespacedName{Name: consts.BlackBoxExporterName, Namespace: tt.namespace}
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: exporter})
+			if err != nil || result.Requeue || result.RequeueAfter != 0 {
+				t.Fatalf("Reconcile() = %v, %v, want no requeue", result, err)
+			}
+			err = kclient.Get(context.TODO(), exporter, &appsv1.Deployment{})
+			if gotDeployment := err == nil; gotDeployment != tt.wantDeployment {
+				t.Errorf("deployment exists = %v, want %v (%v)", gotDeployment, tt.wantDeployment, err)
+			}
+		})
+	}
+}
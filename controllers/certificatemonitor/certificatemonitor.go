@@ -0,0 +1,299 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatemonitor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// CertificateMonitorReconciler reconciles a CertificateMonitor object
+type CertificateMonitorReconciler struct {
+	Client client.Client
+	Ctx    context.Context
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	BlackBoxExporter controllers.BlackBoxExporterHandler
+	ServiceMonitor   controllers.ServiceMonitorHandler
+	Prom             controllers.PrometheusRuleHandler
+	Common           controllers.MonitorResourceHandler
+
+	controllers.ReconcilerOptions
+
+	// Recorder emits Events about the monitors
+	Recorder record.EventRecorder
+
+	// DryRunClient, if set, dry-runs the writes of the generated resources and the CertificateMonitors, except their status
+	DryRunClient *reconcileCommon.DryRunClient
+}
+
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *CertificateMonitorReconciler {
+	log := ctrl.Log.WithName("controllers").WithName("CertificateMonitor")
+	client := mgr.GetClient()
+	ctx := context.Background()
+	recorder := mgr.GetEventRecorderFor(config.OperatorName)
+	var dryRun *reconcileCommon.DryRunClient
+	if opts.DryRun {
+		dryRun = reconcileCommon.NewDryRunClient(client, recorder, log.WithName("DryRun"))
+		client = dryRun
+	}
+	common := reconcileCommon.NewMonitorResourceCommon(ctx, client)
+	common.DryRun = opts.DryRun
+	if opts.ClusterIdentity != nil {
+		common.ClusterIdentity = opts.ClusterIdentity
+	}
+	blackBoxExporter := blackboxexporter.New(client, log, ctx, opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	blackBoxExporter.FIPS = opts.FIPSMode
+	blackBoxExporter.Deployment = opts.BlackBoxExporterDeployment
+	blackBoxExporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	blackBoxExporter.RuntimeConfig = opts.RuntimeConfig
+	blackBoxExporter.FieldOwner = opts.FieldOwner
+	return &CertificateMonitorReconciler{
+		Client:           client,
+		Ctx:              ctx,
+		Log:              log,
+		Scheme:           mgr.GetScheme(),
+		BlackBoxExporter: blackBoxExporter,
+		ServiceMonitor: &servicemonitor.ServiceMonitor{
+			Client:                client,
+			Ctx:                   ctx,
+			Comparer:              &reconcileCommon.ResourceComparer{},
+			LabelOwnedResources:   opts.LabelOwnedResources,
+			Scrubber:              opts.ScrubbedMetadata,
+			FieldOwner:            opts.FieldOwner,
+			ProbeResources:        opts.ProbeResources,
+			ScrapeConfigResources: opts.ScrapeConfigResources,
+			ZoneLabels:            opts.ZoneLabels,
+			NodeLabels:            opts.BlackBoxExporterDeployment.DaemonSet,
+			TLS:                   opts.BlackBoxExporterDeployment.ServingCert,
+			MeshTLS:               opts.BlackBoxExporterDeployment.MeshSidecar(),
+			Recorder:              recorder,
+		},
+		Prom: &alert.PrometheusRule{
+			Client:     client,
+			Ctx:        ctx,
+			Comparer:   &reconcileCommon.ResourceComparer{},
+			Scrubber:   opts.ScrubbedMetadata,
+			FieldOwner: opts.FieldOwner,
+		},
+		Common:            common,
+		ReconcilerOptions: opts,
+		Recorder:          recorder,
+		DryRunClient:      dryRun,
+	}
+}
+
+const (
+	FinalizerKey string = "certificatemonitor.routemonitoroperator.monitoring.openshift.io/finalizer"
+)
+
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=certificatemonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=certificatemonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
+
+func (r *CertificateMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Ctx = ctx
+	reconcileLog := logging.ForReconcile(r.Log.WithName("Reconcile"), "CertificateMonitor", req.NamespacedName)
+
+	log := logging.EnterStep(reconcileLog, "GetCertificateMonitor")
+	certificateMonitor, res, err := r.GetCertificateMonitor(req)
+	if err != nil {
+		log.Error(err, "Failed to retrieve CertificateMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		return res.ReturnWith(nil)
+	}
+	if !r.Scope.Matches(&certificateMonitor) {
+		log.V(logging.DebugVerbosity).Info("Skipping the CertificateMonitor: it's outside of the operator's scope")
+		return utilreconcile.Stop()
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureMonitorAndDependenciesAbsent")
+	res, err = r.EnsureMonitorAndDependenciesAbsent(certificateMonitor)
+	if err != nil {
+		log.Error(err, "Failed to delete CertificateMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully deleted CertificateMonitor. Finished Reconcile")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureFinalizerSet")
+	res, err = r.EnsureFinalizerSet(certificateMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set CertificateMonitor's Finalizer. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully set CertificateMonitor finalizers. Stopping...")
+		return res.ReturnWith(nil)
+	}
+
+	// The remaining steps record their outcome in the status, which is written once they're done
+	return controllers.ReconcileStatus(r.Common, r.DryRunClient, reconcileLog, &certificateMonitor, &certificateMonitor.Status.Conditions, func() (ctrl.Result, error) {
+		return r.ensureMonitorResources(reconcileLog, &certificateMonitor)
+	})
+}
+
+// ensureMonitorResources deploys the blackbox exporter and the resources generated for the CertificateMonitor
+func (r *CertificateMonitorReconciler) ensureMonitorResources(reconcileLog logr.Logger, certificateMonitor *monitoringv1alpha1.CertificateMonitor) (ctrl.Result, error) {
+	log := logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterResourcesExist")
+	err := r.BlackBoxExporter.EnsureBlackBoxExporterResourcesExist()
+	if err != nil {
+		log.Error(err, "Failed to create BlackBoxExporter. Requeueing...")
+		blackboxexporter.ReportInvalidConfig(r.Recorder, certificateMonitor, &certificateMonitor.Status.Conditions, err)
+		return utilreconcile.RequeueWith(err)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureServiceMonitorExists")
+	res, err := r.EnsureServiceMonitorExists(certificateMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set ServiceMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsurePrometheusRuleExists")
+	res, err = r.EnsurePrometheusRuleExists(certificateMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set PrometheusRule. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterRolledOut")
+	res, err = controllers.EnsureBlackBoxExporterRolledOut(r.BlackBoxExporter, &certificateMonitor.Status.Conditions, certificateMonitor.Generation)
+	if err != nil {
+		log.Error(err, "Failed to get the rollout of the BlackBoxExporter. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	reconcileLog.Info("All operations for CertificateMonitor completed. Finished Reconcile.")
+	return utilreconcile.Stop()
+}
+
+func (r *CertificateMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1alpha1.CertificateMonitor{}, builder.WithPredicates(controllers.MonitorChanged, r.Scope.Predicate())).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter()}).
+		Watches(
+			&monitoringv1.ServiceMonitor{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.CertificateMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	if r.ProbeResources {
+		bldr = bldr.Watches(
+			&monitoringv1.Probe{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.CertificateMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.ScrapeConfigResources {
+		bldr = bldr.Watches(
+			&scrapeconfig.ScrapeConfig{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.CertificateMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.VerifyInterval > 0 {
+		verifier := reconcileCommon.NewResourceVerifier(r.Client, r.Recorder, r.Log.WithName("Verify"), r.VerifyInterval, r.listCertificateMonitors, r.generatedResources)
+		if err := mgr.Add(verifier); err != nil {
+			return err
+		}
+		bldr = bldr.WatchesRawSource(verifier.Source(), &handler.EnqueueRequestForObject{})
+	}
+	if r.RuntimeConfig != nil {
+		// Puts changed exporter settings and SLO defaults into effect without waiting for the next reconcile
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listCertificateMonitors, r.Log))
+	}
+	return bldr.Complete(r)
+}
+
+// listCertificateMonitors returns the CertificateMonitors that aren't being deleted
+func (r *CertificateMonitorReconciler) listCertificateMonitors(ctx context.Context) ([]client.Object, error) {
+	list := &monitoringv1alpha1.CertificateMonitorList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	monitors := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp == nil && r.Scope.Matches(&list.Items[i]) {
+			monitors = append(monitors, &list.Items[i])
+		}
+	}
+	return monitors, nil
+}
+
+// generatedResources returns the resources referenced by the status of a CertificateMonitor
+func (r *CertificateMonitorReconciler) generatedResources(o client.Object) []client.Object {
+	certificateMonitor := o.(*monitoringv1alpha1.CertificateMonitor)
+	resources := []client.Object{}
+	if ref := certificateMonitor.Status.ServiceMonitorRef; ref.Name != "" {
+		meta := metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}
+		switch {
+		case r.ScrapeConfigResources:
+			resources = append(resources, &scrapeconfig.ScrapeConfig{ObjectMeta: meta})
+		case r.ProbeResources:
+			resources = append(resources, &monitoringv1.Probe{ObjectMeta: meta})
+		default:
+			resources = append(resources, &monitoringv1.ServiceMonitor{ObjectMeta: meta})
+		}
+	}
+	if ref := certificateMonitor.Status.PrometheusRuleRef; ref.Name != "" {
+		resources = append(resources, &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return resources
+}
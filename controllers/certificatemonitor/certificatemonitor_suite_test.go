@@ -0,0 +1,13 @@
+package certificatemonitor_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificatemonitor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certificatemonitor Suite")
+}
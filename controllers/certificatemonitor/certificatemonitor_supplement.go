@@ -0,0 +1,169 @@
+package certificatemonitor
+
+import (
+	"reflect"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Takes care that right PrometheusRules for the defined CertificateMonitor are in place. The outcome is recorded
+// in the CertificateMonitor's status, which is written by the caller
+func (s *CertificateMonitorReconciler) EnsurePrometheusRuleExists(certificateMonitor *v1alpha1.CertificateMonitor) (utilreconcile.Result, error) {
+	spec := certificateMonitor.Spec
+	namespacedName := types.NamespacedName{Namespace: certificateMonitor.Namespace, Name: certificateMonitor.Name}
+	ruleName := reconcileCommon.GeneratedResourceName(certificateMonitor.Status.PrometheusRuleRef, "CertificateMonitor", namespacedName)
+	template := alert.TemplateForCertificatePrometheusRule(alert.CertificateRuleOptions{
+		Target:         spec.Target,
+		WarningDays:    spec.ExpiryWarningDays,
+		CriticalDays:   spec.ExpiryCriticalDays,
+		Issuer:         spec.Issuer,
+		NamespacedName: namespacedName,
+		AlertLabels:    spec.AlertLabels,
+		Owner:          metav1.NewControllerRef(&certificateMonitor.ObjectMeta, certificateMonitor.GroupVersionKind()),
+	})
+	if s.LabelOwnedResources {
+		template.Labels = consts.OwnerLabels("CertificateMonitor", certificateMonitor.Name)
+	}
+	// Alerts keep the name of the monitor, only the resource is named uniquely
+	template.Name = ruleName.Name
+	if err := s.Prom.UpdatePrometheusRuleDeployment(template); err != nil {
+		return s.artifactFailed(certificateMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+
+	_, _ = s.Common.SetResourceReference(&certificateMonitor.Status.PrometheusRuleRef, ruleName)
+	reconcileCommon.SetArtifactCondition(&certificateMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, certificateMonitor.Generation, nil)
+	return utilreconcile.ContinueReconcile()
+}
+
+// Takes care that right ServiceMonitor for the defined CertificateMonitor are in place. The outcome is recorded
+// in the CertificateMonitor's status, which is written by the caller
+func (s *CertificateMonitorReconciler) EnsureServiceMonitorExists(certificateMonitor *v1alpha1.CertificateMonitor) (utilreconcile.Result, error) {
+	id, err := s.Common.GetOSDClusterID()
+	if err != nil {
+		return s.artifactFailed(certificateMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	namespacedName := reconcileCommon.GeneratedResourceName(certificateMonitor.Status.ServiceMonitorRef, "CertificateMonitor", types.NamespacedName{Name: certificateMonitor.Name, Namespace: certificateMonitor.Namespace})
+	spec := certificateMonitor.Spec
+	owner := metav1.NewControllerRef(&certificateMonitor.ObjectMeta, certificateMonitor.GroupVersionKind())
+	module, definition := blackboxexporter.CertificateModuleFor(spec.ServerName)
+	if err := definition.ValidateRequired(s.FIPSMode); err != nil {
+		return s.artifactFailed(certificateMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	res, err := controllers.EnsureBlackBoxExporterServesModule(s.BlackBoxExporter, s.DryRunClient, module, &certificateMonitor.Status.Conditions, certificateMonitor.Generation)
+	if err != nil || res.ShouldStop() {
+		return res, err
+	}
+	uid, err := s.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment([]string{spec.Target}, servicemonitor.Exporter{Namespace: s.BlackBoxExporter.GetBlackBoxExporterNamespace()}, namespacedName, id, false, module, spec.MetricLabels, nil, 0, 0, owner, certificateMonitor.Status.ServiceMonitorRef.UID)
+	if err != nil {
+		return s.artifactFailed(certificateMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	if _, err := s.Common.SetResourceReference(&certificateMonitor.Status.ServiceMonitorRef, namespacedName); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	// Record the UID, so a ServiceMonitor recreated by someone else isn't adopted
+	if uid != "" {
+		certificateMonitor.Status.ServiceMonitorRef.UID = uid
+	}
+	reconcileCommon.SetArtifactCondition(&certificateMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady, certificateMonitor.Generation, nil)
+	return utilreconcile.ContinueReconcile()
+}
+
+// artifactFailed reports the failure of a generated resource in the CertificateMonitor's status, so it's
+// visible which one needs attention. It requeues with the failure unless retrying can't fix it
+func (s *CertificateMonitorReconciler) artifactFailed(certificateMonitor *v1alpha1.CertificateMonitor, conditionType string, err error) (utilreconcile.Result, error) {
+	reconcileCommon.SetArtifactCondition(&certificateMonitor.Status.Conditions, conditionType, certificateMonitor.Generation, err)
+	metrics.ReconcileErrors.WithLabelValues("CertificateMonitor", string(customerrors.ClassOf(err))).Inc()
+	return utilreconcile.FailReconcileWith(err)
+}
+
+// Ensures that all dependencies related to a CertificateMonitor are deleted
+func (s *CertificateMonitorReconciler) EnsureMonitorAndDependenciesAbsent(certificateMonitor v1alpha1.CertificateMonitor) (utilreconcile.Result, error) {
+	if certificateMonitor.DeletionTimestamp == nil {
+		return utilreconcile.ContinueReconcile()
+	}
+
+	reason, err := reconcileCommon.CleanupSkipReason(s.Ctx, s.Client, &certificateMonitor)
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if reason != "" {
+		reconcileCommon.Eventf(s.Recorder, &certificateMonitor, corev1.EventTypeWarning, reconcileCommon.CleanupSkippedReason,
+			"Dropping the finalizer without deleting the generated resources: %s", reason)
+		return s.ensureFinalizerAbsent(certificateMonitor)
+	}
+
+	// The resources controlled by the CertificateMonitor are deleted with it by the garbage collector
+	resources := s.generatedResources(&certificateMonitor)
+	if err := reconcileCommon.DeleteUncollected(s.Ctx, s.Client, &certificateMonitor, resources...); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+
+	shouldDelete, err := s.BlackBoxExporter.ShouldDeleteBlackBoxExporterResources()
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if shouldDelete == blackboxexporterconsts.DeleteBlackBoxExporter {
+		err := s.BlackBoxExporter.EnsureBlackBoxExporterResourcesAbsent()
+		if err != nil {
+			return utilreconcile.RequeueReconcileWith(err)
+		}
+	}
+
+	return s.ensureFinalizerAbsent(certificateMonitor)
+}
+
+// ensureFinalizerAbsent drops the finalizer of the CertificateMonitor, so its deletion completes
+func (s *CertificateMonitorReconciler) ensureFinalizerAbsent(certificateMonitor v1alpha1.CertificateMonitor) (utilreconcile.Result, error) {
+	if s.Common.DeleteFinalizer(&certificateMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&certificateMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+func (s *CertificateMonitorReconciler) EnsureFinalizerSet(certificateMonitor v1alpha1.CertificateMonitor) (utilreconcile.Result, error) {
+	if s.Common.SetFinalizer(&certificateMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&certificateMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+// GetCertificateMonitor return the CertificateMonitor that is tested
+func (s *CertificateMonitorReconciler) GetCertificateMonitor(req ctrl.Request) (v1alpha1.CertificateMonitor, utilreconcile.Result, error) {
+	certificateMonitor := v1alpha1.CertificateMonitor{}
+	err := s.Client.Get(s.Ctx, req.NamespacedName, &certificateMonitor)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			res, err := utilreconcile.RequeueReconcileWith(err)
+			return v1alpha1.CertificateMonitor{}, res, err
+		}
+		s.Log.V(logging.DebugVerbosity).Info("Stopping the reconcile: the CertificateMonitor wasn't found")
+
+		return v1alpha1.CertificateMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	// if the resource is empty, we should terminate
+	if reflect.DeepEqual(certificateMonitor, v1alpha1.CertificateMonitor{}) {
+		return v1alpha1.CertificateMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	return certificateMonitor, utilreconcile.ContinueOperation(), nil
+}
@@ -0,0 +1,163 @@
+package certificatemonitor_test
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers/certificatemonitor"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
+	controllermocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/controllers"
+)
+
+var _ = Describe("Certificatemonitor", func() {
+	var (
+		certificateMonitor   v1alpha1.CertificateMonitor
+		reconciler           certificatemonitor.CertificateMonitorReconciler
+		mockClient           *clientmocks.MockClient
+		mockBlackBoxExporter *controllermocks.MockBlackBoxExporterHandler
+		mockCommon           *controllermocks.MockMonitorResourceHandler
+		mockPrometheusRule   *controllermocks.MockPrometheusRuleHandler
+		mockServiceMonitor   *controllermocks.MockServiceMonitorHandler
+
+		mockCtrl *gomock.Controller
+
+		res utilreconcile.Result
+		err error
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockBlackBoxExporter = controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+		mockServiceMonitor = controllermocks.NewMockServiceMonitorHandler(mockCtrl)
+		mockPrometheusRule = controllermocks.NewMockPrometheusRuleHandler(mockCtrl)
+		mockCommon = controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+		certificateMonitor = v1alpha1.CertificateMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-certificatemonitor",
+				Namespace: "fake-namespace",
+			},
+			Spec: v1alpha1.CertificateMonitorSpec{
+				Target:             "db.example.com:5432",
+				ServerName:         "db.example.com",
+				ExpiryWarningDays:  30,
+				ExpiryCriticalDays: 7,
+			},
+			Status: v1alpha1.CertificateMonitorStatus{
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+					{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		reconciler = certificatemonitor.CertificateMonitorReconciler{
+			Log:              logr.Discard(),
+			Client:           mockClient,
+			Scheme:           constinit.Scheme,
+			BlackBoxExporter: mockBlackBoxExporter,
+			Common:           mockCommon,
+			ServiceMonitor:   mockServiceMonitor,
+			Prom:             mockPrometheusRule,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("EnsureServiceMonitorExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureServiceMonitorExists(&certificateMonitor)
+		})
+		When("the ServiceMonitor doesn't exist", func() {
+			BeforeEach(func() {
+				module, _ := blackboxexporter.CertificateModuleFor("db.example.com")
+				ns := reconcileCommon.GeneratedResourceName(certificateMonitor.Status.ServiceMonitorRef, "CertificateMonitor", types.NamespacedName{Name: certificateMonitor.Name, Namespace: certificateMonitor.Namespace})
+				mockBlackBoxExporter.EXPECT().GetBlackBoxExporterNamespace().Times(1).Return("")
+				mockBlackBoxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
+				mockCommon.EXPECT().GetOSDClusterID().Times(1).Return("fake-cluster-id", nil)
+				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment([]string{"db.example.com:5432"}, servicemonitor.Exporter{}, ns, "fake-cluster-id", false, module, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&certificateMonitor.Status.ServiceMonitorRef, ns).Times(1).Return(true, nil)
+			})
+			It("probes the target with the TLS module of the server name and updates the ServiceRef", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+
+	Describe("EnsurePrometheusRuleExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsurePrometheusRuleExists(&certificateMonitor)
+		})
+		When("the PrometheusRule doesn't exist", func() {
+			BeforeEach(func() {
+				ns := reconcileCommon.GeneratedResourceName(certificateMonitor.Status.PrometheusRuleRef, "CertificateMonitor", types.NamespacedName{Name: certificateMonitor.Name, Namespace: certificateMonitor.Namespace})
+				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any()).Times(1).DoAndReturn(func(template monitoringv1.PrometheusRule) error {
+					Expect(template.Name).To(Equal(ns.Name))
+					Expect(template.Spec.Groups[0].Rules[0].Alert).To(Equal("fake-certificatemonitor-CertificateExpiring"))
+					Expect(template.Spec.Groups[0].Rules[0].Expr.String()).To(ContainSubstring(`probe_url="db.example.com:5432"`))
+					return nil
+				})
+				mockCommon.EXPECT().SetResourceReference(&certificateMonitor.Status.PrometheusRuleRef, ns).Times(1).Return(true, nil)
+			})
+			It("creates one alerting on the certificate and updates the CertificateMonitor", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+
+	Describe("EnsureMonitorAndDependenciesAbsent", func() {
+		BeforeEach(func() {
+			// The namespace of the monitor isn't terminating, so its generated resources are cleaned up
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Namespace{})).AnyTimes()
+			certificateMonitor.Finalizers = []string{certificatemonitor.FinalizerKey}
+		})
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureMonitorAndDependenciesAbsent(certificateMonitor)
+		})
+		When("the CertificateMonitor is not being deleted", func() {
+			It("does nothing", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+		When("the CertificateMonitor is being deleted", func() {
+			BeforeEach(func() {
+				certificateMonitor.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
+				certificateMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-servicemonitor", Namespace: "fake-namespace"}
+				certificateMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "fake-namespace"}
+				// Neither is controlled by the CertificateMonitor, e.g. because they were created before it controlled them
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(2)
+				mockBlackBoxExporter.EXPECT().ShouldDeleteBlackBoxExporterResources().Return(blackboxexporterconsts.DeleteBlackBoxExporter, nil)
+				mockBlackBoxExporter.EXPECT().EnsureBlackBoxExporterResourcesAbsent().Times(1)
+				mockCommon.EXPECT().DeleteFinalizer(&certificateMonitor, certificatemonitor.FinalizerKey).Times(1).Return(true)
+				mockCommon.EXPECT().UpdateMonitorResource(&certificateMonitor).Return(utilreconcile.StopOperation(), nil)
+			})
+			It("removes the generated resources, the blackbox exporter and the finalizer", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+			})
+		})
+	})
+})
@@ -18,19 +18,30 @@ package clusterurlmonitor
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/config"
 	"github.com/openshift/route-monitor-operator/controllers"
 	"github.com/openshift/route-monitor-operator/pkg/alert"
 	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
 	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
 	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -46,21 +57,85 @@ type ClusterUrlMonitorReconciler struct {
 	ServiceMonitor   controllers.ServiceMonitorHandler
 	Prom             controllers.PrometheusRuleHandler
 	Common           controllers.MonitorResourceHandler
+
+	controllers.ReconcilerOptions
+
+	// Recorder emits Events about the monitors
+	Recorder record.EventRecorder
+
+	// DryRunClient, if set, dry-runs the writes of the generated resources and the ClusterUrlMonitors, except their status
+	DryRunClient *reconcileCommon.DryRunClient
+}
+
+// sloChangeAlertDuration returns how long the alert on a lowered availability target fires
+func (r *ClusterUrlMonitorReconciler) sloChangeAlertDuration() time.Duration {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloChangeAlertDuration
+	}
+	return r.SloChangeAlertDuration
 }
 
-func NewReconciler(mgr manager.Manager, blackboxExporterImage, blackboxExporterNamespace string, enablehypershift bool) *ClusterUrlMonitorReconciler {
+// slo returns the SLO the monitor is held to, which is the default SLO if it doesn't set one
+func (r *ClusterUrlMonitorReconciler) slo(slo monitoringv1alpha1.SloSpec) monitoringv1alpha1.SloSpec {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloFor(slo)
+	}
+	return slo
+}
+
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *ClusterUrlMonitorReconciler {
 	log := ctrl.Log.WithName("controllers").WithName("ClusterUrlMonitor")
 	client := mgr.GetClient()
 	ctx := context.Background()
+	recorder := mgr.GetEventRecorderFor(config.OperatorName)
+	var dryRun *reconcileCommon.DryRunClient
+	if opts.DryRun {
+		dryRun = reconcileCommon.NewDryRunClient(client, recorder, log.WithName("DryRun"))
+		client = dryRun
+	}
+	common := reconcileCommon.NewMonitorResourceCommon(ctx, client)
+	common.DryRun = opts.DryRun
+	if opts.ClusterIdentity != nil {
+		common.ClusterIdentity = opts.ClusterIdentity
+	}
+	blackBoxExporter := blackboxexporter.New(client, log, ctx, opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	blackBoxExporter.FIPS = opts.FIPSMode
+	blackBoxExporter.Deployment = opts.BlackBoxExporterDeployment
+	blackBoxExporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	blackBoxExporter.RuntimeConfig = opts.RuntimeConfig
+	blackBoxExporter.FieldOwner = opts.FieldOwner
 	return &ClusterUrlMonitorReconciler{
 		Client:           client,
 		Ctx:              ctx,
 		Log:              log,
 		Scheme:           mgr.GetScheme(),
-		BlackBoxExporter: blackboxexporter.New(client, log, ctx, blackboxExporterImage, blackboxExporterNamespace),
-		ServiceMonitor:   servicemonitor.NewServiceMonitor(ctx, client),
-		Prom:             alert.NewPrometheusRule(ctx, client),
-		Common:           reconcileCommon.NewMonitorResourceCommon(ctx, client),
+		BlackBoxExporter: blackBoxExporter,
+		ServiceMonitor: &servicemonitor.ServiceMonitor{
+			Client:                client,
+			Ctx:                   ctx,
+			Comparer:              &reconcileCommon.ResourceComparer{},
+			LabelOwnedResources:   opts.LabelOwnedResources,
+			Scrubber:              opts.ScrubbedMetadata,
+			FieldOwner:            opts.FieldOwner,
+			ProbeResources:        opts.ProbeResources,
+			ScrapeConfigResources: opts.ScrapeConfigResources,
+			ZoneLabels:            opts.ZoneLabels,
+			NodeLabels:            opts.BlackBoxExporterDeployment.DaemonSet,
+			TLS:                   opts.BlackBoxExporterDeployment.ServingCert,
+			MeshTLS:               opts.BlackBoxExporterDeployment.MeshSidecar(),
+			Recorder:              recorder,
+		},
+		Prom: &alert.PrometheusRule{
+			Client:     client,
+			Ctx:        ctx,
+			Comparer:   &reconcileCommon.ResourceComparer{},
+			Scrubber:   opts.ScrubbedMetadata,
+			FieldOwner: opts.FieldOwner,
+		},
+		Common:            common,
+		ReconcilerOptions: opts,
+		Recorder:          recorder,
+		DryRunClient:      dryRun,
 	}
 }
 
@@ -73,8 +148,11 @@ const (
 // +kubebuilder:rbac:groups=monitoring.openshift.io,resources=clusterurlmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.openshift.io,resources=clusterurlmonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=dnses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;delete;update;patch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=ingresscontrollers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedcontrolplanes,verbs=get;list;watch
@@ -82,18 +160,23 @@ const (
 
 func (r *ClusterUrlMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.Ctx = ctx
-	log := r.Log.WithName("Reconcile").WithValues("name", req.Name, "namespace", req.Namespace)
+	reconcileLog := logging.ForReconcile(r.Log.WithName("Reconcile"), "ClusterUrlMonitor", req.NamespacedName)
 
-	log.V(2).Info("Entering GetClusterUrlMonitor")
+	log := logging.EnterStep(reconcileLog, "GetClusterUrlMonitor")
 	clusterUrlMonitor, res, err := r.GetClusterUrlMonitor(req)
 	if err != nil {
 		log.Error(err, "Failed to retreive ClusterUrlMonitor. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
+		return res.ReturnWith(nil)
+	}
+	if !r.Scope.Matches(&clusterUrlMonitor) {
+		log.V(logging.DebugVerbosity).Info("Skipping the ClusterUrlMonitor: it's outside of the operator's scope")
 		return utilreconcile.Stop()
 	}
 
+	log = logging.EnterStep(reconcileLog, "EnsureMonitorAndDependenciesAbsent")
 	res, err = r.EnsureMonitorAndDependenciesAbsent(clusterUrlMonitor)
 	if err != nil {
 		log.Error(err, "Failed to delete ClusterUrlMontior. Requeueing...")
@@ -101,10 +184,10 @@ func (r *ClusterUrlMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	}
 	if res.ShouldStop() {
 		log.Info("Successfully deleted ClusterUrlMonitor. Finished Reconcile")
-		return utilreconcile.Stop()
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsureFinalizerSet")
+	log = logging.EnterStep(reconcileLog, "EnsureFinalizerSet")
 	res, err = r.EnsureFinalizerSet(clusterUrlMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set ClusterUrlMonitor's Finalizer. Requeueing...")
@@ -112,48 +195,169 @@ func (r *ClusterUrlMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	}
 	if res.ShouldStop() {
 		log.Info("Successfully set ClusterUrlMonitor finalizers. Stopping...")
-		return utilreconcile.Stop()
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsureBlackBoxExporterResourcesExist")
-	err = r.BlackBoxExporter.EnsureBlackBoxExporterResourcesExist()
+	// The remaining steps record their outcome in the status, which is written once they're done
+	return controllers.ReconcileStatus(r.Common, r.DryRunClient, reconcileLog, &clusterUrlMonitor, &clusterUrlMonitor.Status.Conditions, func() (ctrl.Result, error) {
+		return r.ensureMonitorResources(reconcileLog, &clusterUrlMonitor)
+	})
+}
+
+// ensureMonitorResources deploys the blackbox exporter and the resources generated for the ClusterUrlMonitor
+func (r *ClusterUrlMonitorReconciler) ensureMonitorResources(reconcileLog logr.Logger, clusterUrlMonitor *monitoringv1alpha1.ClusterUrlMonitor) (ctrl.Result, error) {
+	log := logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterResourcesExist")
+	err := r.BlackBoxExporter.EnsureBlackBoxExporterResourcesExist()
 	if err != nil {
 		log.Error(err, "Failed to create BlackBoxExporter. Requeueing...")
+		blackboxexporter.ReportInvalidConfig(r.Recorder, clusterUrlMonitor, &clusterUrlMonitor.Status.Conditions, err)
 		return utilreconcile.RequeueWith(err)
 	}
 
-	log.V(2).Info("Entering EnsureServiceMonitorExists")
-	res, err = r.EnsureServiceMonitorExists(clusterUrlMonitor)
+	log = logging.EnterStep(reconcileLog, "EnsureServiceMonitorExists")
+	res, err := r.EnsureServiceMonitorExists(clusterUrlMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set ServiceMonitor. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
-		log.Info("Successfully patched ClusterUrlMonitor with ServiceMonitorRef. Stopping...")
-		return utilreconcile.Stop()
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsurePrometheusRuleResourceExists")
+	log = logging.EnterStep(reconcileLog, "EnsurePrometheusRuleExists")
 	res, err = r.EnsurePrometheusRuleExists(clusterUrlMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set PrometheusRule. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
-		log.Info("Successfully patched ClusterUrlMonitor with PrometheusRuleRef. Stopping...")
-		return utilreconcile.Stop()
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
 	}
 
-	log.Info("All operations for ClusterUrlMonitor completed. Finished Reconcile.")
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterRolledOut")
+	res, err = controllers.EnsureBlackBoxExporterRolledOut(r.BlackBoxExporter, &clusterUrlMonitor.Status.Conditions, clusterUrlMonitor.Generation)
+	if err != nil {
+		log.Error(err, "Failed to get the rollout of the BlackBoxExporter. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	reconcileLog.Info("All operations for ClusterUrlMonitor completed. Finished Reconcile.")
 	return utilreconcile.Stop()
 }
 
 func (r *ClusterUrlMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringv1alpha1.ClusterUrlMonitor{}).
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1alpha1.ClusterUrlMonitor{}, builder.WithPredicates(controllers.MonitorChanged, r.Scope.Predicate())).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter()}).
 		Watches(
 			&monitoringv1.ServiceMonitor{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.ClusterUrlMonitor{}, handler.OnlyControllerOwner()),
-		).
-		Complete(r)
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	if r.ProbeResources {
+		bldr = bldr.Watches(
+			&monitoringv1.Probe{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.ClusterUrlMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.ScrapeConfigResources {
+		bldr = bldr.Watches(
+			&scrapeconfig.ScrapeConfig{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.ClusterUrlMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.EnableHypershift {
+		bldr = bldr.Watches(
+			&hypershiftv1beta1.HostedControlPlane{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterUrlMonitorsOfNamespace),
+			builder.WithPredicates(controllers.CreatedOrDeleted),
+		)
+	}
+	if r.VerifyInterval > 0 {
+		verifier := reconcileCommon.NewResourceVerifier(r.Client, r.Recorder, r.Log.WithName("Verify"), r.VerifyInterval, r.listClusterUrlMonitors, r.generatedResources)
+		if err := mgr.Add(verifier); err != nil {
+			return err
+		}
+		bldr = bldr.WatchesRawSource(verifier.Source(), &handler.EnqueueRequestForObject{})
+	}
+	if r.RuntimeConfig != nil {
+		// Puts changed exporter settings and SLO defaults into effect without waiting for the next reconcile
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listClusterUrlMonitors, r.Log))
+	}
+	if r.ProbeHealth != nil {
+		reporter := probehealth.NewReporter(r.Client, r.ProbeHealth, r.Log.WithName("ProbeHealth"), r.ProbeHealthInterval, r.listClusterUrlMonitors, r.probeHealthTarget)
+		if err := mgr.Add(reporter); err != nil {
+			return err
+		}
+	}
+	return bldr.Complete(r)
+}
+
+// listClusterUrlMonitors returns the ClusterUrlMonitors that aren't being deleted
+func (r *ClusterUrlMonitorReconciler) listClusterUrlMonitors(ctx context.Context) ([]client.Object, error) {
+	list := &monitoringv1alpha1.ClusterUrlMonitorList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	monitors := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp == nil && r.Scope.Matches(&list.Items[i]) {
+			monitors = append(monitors, &list.Items[i])
+		}
+	}
+	return monitors, nil
+}
+
+// generatedResources returns the resources referenced by the status of a ClusterUrlMonitor
+func (r *ClusterUrlMonitorReconciler) generatedResources(o client.Object) []client.Object {
+	clusterUrlMonitor := o.(*monitoringv1alpha1.ClusterUrlMonitor)
+	resources := []client.Object{}
+	if ref := clusterUrlMonitor.Status.ServiceMonitorRef; ref.Name != "" {
+		meta := metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}
+		// The kind the ServiceMonitor was generated as is recorded, it's derived from the ClusterUrlMonitor for ones
+		// reconciled before
+		if resource := servicemonitor.GeneratedResource(ref.Kind); resource != nil {
+			resource.SetName(ref.Name)
+			resource.SetNamespace(ref.Namespace)
+			resources = append(resources, resource)
+		} else if clusterUrlMonitor.Spec.IsHCP() {
+			resources = append(resources, &rhobsv1.ServiceMonitor{ObjectMeta: meta})
+		} else if r.ScrapeConfigResources {
+			resources = append(resources, &scrapeconfig.ScrapeConfig{ObjectMeta: meta})
+		} else if r.ProbeResources {
+			resources = append(resources, &monitoringv1.Probe{ObjectMeta: meta})
+		} else {
+			resources = append(resources, &monitoringv1.ServiceMonitor{ObjectMeta: meta})
+		}
+	}
+	if ref := clusterUrlMonitor.Status.PrometheusRuleRef; ref.Name != "" {
+		resources = append(resources, &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return resources
+}
+
+// probeHealthTarget returns the probes of a ClusterUrlMonitor whose health is reported
+func (r *ClusterUrlMonitorReconciler) probeHealthTarget(o client.Object) (probehealth.Target, bool) {
+	clusterUrlMonitor := o.(*monitoringv1alpha1.ClusterUrlMonitor)
+	// The probes of HCP ClusterUrlMonitors are evaluated in the upstream RHOBS tenant
+	if clusterUrlMonitor.Spec.IsHCP() {
+		return probehealth.Target{}, false
+	}
+	clusterUrl, err := r.GetClusterUrl(*clusterUrlMonitor)
+	if err != nil {
+		return probehealth.Target{}, false
+	}
+	return probehealth.Target{
+		URLs:   []string{clusterUrl},
+		Slo:    r.slo(clusterUrlMonitor.Spec.Slo),
+		Health: &clusterUrlMonitor.Status.ProbeHealth,
+	}, true
 }
@@ -1,6 +1,8 @@
 package clusterurlmonitor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
@@ -9,77 +11,121 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
 	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
 	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	hcpClusterAnnotation = "hypershift.openshift.io/cluster"
+
+	// ControlPlaneEndpointLabel distinguishes the alerts of the internal and external API endpoints
+	ControlPlaneEndpointLabel = "control_plane_endpoint"
 )
 
-// Takes care that right PrometheusRules for the defined ClusterURLMonitor are in place
-func (s *ClusterUrlMonitorReconciler) EnsurePrometheusRuleExists(clusterUrlMonitor v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
-	// If .spec.skipPrometheusRule is true, ensure that the PrometheusRule does NOT exist
-	if clusterUrlMonitor.Spec.SkipPrometheusRule {
+// controlPlaneEndpoints maps targets to the value of their ControlPlaneEndpointLabel
+var controlPlaneEndpoints = map[v1alpha1.ClusterUrlTarget]string{
+	v1alpha1.ClusterUrlTargetAPI:         "external",
+	v1alpha1.ClusterUrlTargetAPIInternal: "internal",
+}
+
+// Takes care that right PrometheusRules for the defined ClusterURLMonitor are in place. The outcome is recorded
+// in the ClusterUrlMonitor's status, which is written by the caller
+func (s *ClusterUrlMonitorReconciler) EnsurePrometheusRuleExists(clusterUrlMonitor *v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
+	slo := s.slo(clusterUrlMonitor.Spec.Slo)
+
+	// Keep track of changes of the availability target before acting on them
+	alert.ObserveSloChange(s.Recorder, "ClusterUrlMonitor", clusterUrlMonitor, slo, &clusterUrlMonitor.Status.SloTarget, &clusterUrlMonitor.Status.LastSloChange)
+
+	// If .spec.skipPrometheusRule is true, ensure that the PrometheusRule does NOT exist. Neither should HCP
+	// clusterUrlMonitors have one, since alerting is implemented in the upstream RHOBS tenant: a rule generated before
+	// the monitor became one is deleted
+	if clusterUrlMonitor.Spec.SkipPrometheusRule || clusterUrlMonitor.Spec.IsHCP() {
 		// Cleanup any existing PrometheusRules and update the status
 		if err := s.Prom.DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef); err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
 		}
-		updated, _ := s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
-		if updated {
-			return s.Common.UpdateMonitorResourceStatus(&clusterUrlMonitor)
-		}
-
-		return utilreconcile.ContinueReconcile()
-	}
+		_, _ = s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
 
-	// We shouldn't create prometheusrules for HCP clusterUrlMonitors, since alerting is implemented in the upstream RHOBS tenant
-	if clusterUrlMonitor.Spec.DomainRef == v1alpha1.ClusterDomainRefHCP {
 		return utilreconcile.ContinueReconcile()
 	}
 
-	clusterDomain, err := s.GetClusterDomain(clusterUrlMonitor)
+	clusterUrl, err := s.GetClusterUrl(*clusterUrlMonitor)
 	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
 	}
 
-	spec := clusterUrlMonitor.Spec
-	clusterUrl := spec.Prefix + clusterDomain + ":" + spec.Port + spec.Suffix
-	parsedSlo, err := s.Common.ParseMonitorSLOSpecs(clusterUrl, clusterUrlMonitor.Spec.Slo)
+	parsedSlo, err := s.Common.ParseMonitorSLOSpecs(clusterUrl, slo)
 
-	if s.Common.SetErrorStatus(&clusterUrlMonitor.Status.ErrorStatus, err) {
-		return s.Common.UpdateMonitorResourceStatus(&clusterUrlMonitor)
+	if s.Common.SetErrorStatus(&clusterUrlMonitor.Status.ErrorStatus, err) && errors.Is(err, customerrors.InvalidSLO) {
+		reconcileCommon.Eventf(s.Recorder, clusterUrlMonitor, corev1.EventTypeWarning, reconcileCommon.InvalidSloReason,
+			"No PrometheusRule is generated: the targetAvailabilityPercent '%s' or the latency objective is invalid", slo.TargetAvailabilityPercent)
 	}
 	if parsedSlo == "" {
-		err = s.Prom.DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef)
-		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+		deleteErr := s.Prom.DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef)
+		if deleteErr != nil {
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionPrometheusRuleReady, deleteErr)
 		}
-		updated, _ := s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
-		if updated {
-			return s.Common.UpdateMonitorResourceStatus(&clusterUrlMonitor)
+		_, _ = s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		// An invalid SLO fails the PrometheusRule, without an SLO none is needed
+		if err != nil {
+			reconcileCommon.SetArtifactCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, clusterUrlMonitor.Generation, err)
+		} else {
+			meta.RemoveStatusCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
 		}
 		return utilreconcile.StopReconcile()
 	}
 
 	namespacedName := types.NamespacedName{Namespace: clusterUrlMonitor.Namespace, Name: clusterUrlMonitor.Name}
-	template := alert.TemplateForPrometheusRuleResource(clusterUrl, parsedSlo, namespacedName)
+	ruleName := reconcileCommon.GeneratedResourceName(clusterUrlMonitor.Status.PrometheusRuleRef, "ClusterUrlMonitor", namespacedName)
+	var ruleLabels map[string]string
+	if endpoint, ok := controlPlaneEndpoints[clusterUrlMonitor.Spec.ProbedTarget()]; ok {
+		ruleLabels = map[string]string{ControlPlaneEndpointLabel: endpoint}
+	}
+	template, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+		URL:                    clusterUrl,
+		Percent:                parsedSlo,
+		Slo:                    slo,
+		NamespacedName:         namespacedName,
+		Created:                clusterUrlMonitor.CreationTimestamp.Time,
+		MaintenanceWindows:     clusterUrlMonitor.Spec.MaintenanceWindows,
+		LastSloChange:          clusterUrlMonitor.Status.LastSloChange,
+		SloChangeAlertDuration: s.sloChangeAlertDuration(),
+		RuleLabels:             ruleLabels,
+		Owner:                  metav1.NewControllerRef(&clusterUrlMonitor.ObjectMeta, clusterUrlMonitor.GroupVersionKind()),
+	})
+	if err != nil {
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+	if s.LabelOwnedResources {
+		template.Labels = consts.OwnerLabels("ClusterUrlMonitor", clusterUrlMonitor.Name)
+	}
+	// Alerts keep the name of the monitor, only the resource is named uniquely
+	template.Name = ruleName.Name
 	err = s.Prom.UpdatePrometheusRuleDeployment(template)
 	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
 	}
 
 	// Update PrometheusRuleReference in ClusterUrlMonitor if necessary
-	updated, _ := s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, namespacedName)
-	if updated {
-		return s.Common.UpdateMonitorResourceStatus(&clusterUrlMonitor)
-	}
+	_, _ = s.Common.SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, ruleName)
+	reconcileCommon.SetArtifactCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, clusterUrlMonitor.Generation, nil)
 	return utilreconcile.ContinueReconcile()
 }
 func isClusterVersionAvailable(hcp hypershiftv1beta1.HostedControlPlane) error {
@@ -90,66 +136,165 @@ func isClusterVersionAvailable(hcp hypershiftv1beta1.HostedControlPlane) error {
 	return nil
 }
 
-// Takes care that right ServiceMonitor for the defined ClusterURLMonitor are in place
-func (s *ClusterUrlMonitorReconciler) EnsureServiceMonitorExists(clusterUrlMonitor v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
-	clusterDomain, err := s.GetClusterDomain(clusterUrlMonitor)
+// Takes care that right ServiceMonitor for the defined ClusterURLMonitor are in place. The outcome is recorded
+// in the ClusterUrlMonitor's status, which is written by the caller
+func (s *ClusterUrlMonitorReconciler) EnsureServiceMonitorExists(clusterUrlMonitor *v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
+	isHCP := clusterUrlMonitor.Spec.IsHCP()
+
+	// If .spec.skipServiceMonitor is true, ensure that the ServiceMonitor does NOT exist
+	if clusterUrlMonitor.Spec.SkipServiceMonitor {
+		// Cleanup any existing ServiceMonitor and update the status
+		if err := s.ServiceMonitor.DeleteServiceMonitorDeployment(clusterUrlMonitor.Status.ServiceMonitorRef); err != nil {
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+		}
+		_, _ = s.Common.SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+
+		return utilreconcile.ContinueReconcile()
+	}
+
+	var hcp hypershiftv1beta1.HostedControlPlane
+	if isHCP {
+		var err error
+		hcp, err = s.Common.GetHCP(clusterUrlMonitor.Namespace)
+		if errors.Is(err, customerrors.NoHostedControlPlane) {
+			return s.hostedControlPlaneDeleted(clusterUrlMonitor)
+		}
+		if err != nil {
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+		}
+	}
+
+	clusterUrl, err := s.GetClusterUrl(*clusterUrlMonitor)
 	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 	}
 
-	namespacedName := types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace}
+	namespacedName := reconcileCommon.GeneratedResourceName(clusterUrlMonitor.Status.ServiceMonitorRef, "ClusterUrlMonitor", types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace})
 	spec := clusterUrlMonitor.Spec
-	clusterUrl := spec.Prefix + clusterDomain + ":" + spec.Port + spec.Suffix
-	isHCP := (clusterUrlMonitor.Spec.DomainRef == v1alpha1.ClusterDomainRefHCP)
 	var id string
 	if isHCP {
-		var hcp hypershiftv1beta1.HostedControlPlane
 		id, err = s.Common.GetHypershiftClusterID(clusterUrlMonitor.Namespace)
 		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
-		}
-		hcp, err = s.Common.GetHCP(clusterUrlMonitor.Namespace)
-		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 		}
 		err = isClusterVersionAvailable(hcp)
 		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 		}
 	} else {
 		id, err = s.Common.GetOSDClusterID()
 		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 		}
 	}
 
+	// Hosted control planes are scraped through RHOBS and the others through the cluster's Prometheus, unless only the
+	// other API is installed
+	useRHOBS, err := s.MonitoringAPIs.ServiceMonitorAPI(isHCP)
+	if errors.Is(err, customerrors.NoMonitoringAPI) {
+		return s.monitoringAPIUnavailable(clusterUrlMonitor)
+	}
+	if err != nil {
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
 	owner := metav1.NewControllerRef(&clusterUrlMonitor.ObjectMeta, clusterUrlMonitor.GroupVersionKind())
-	if err := s.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment(clusterUrl, s.BlackBoxExporter.GetBlackBoxExporterNamespace(), namespacedName, id, isHCP, false, owner); err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+	// The internal API endpoint is served with the cluster's internal CA, which the blackbox exporter doesn't trust
+	insecureSkipTLSVerify := spec.ProbedTarget() == v1alpha1.ClusterUrlTargetAPIInternal
+	module, definition := blackboxexporter.ModuleFor(insecureSkipTLSVerify, spec.HTTPProbe)
+	if err := definition.ValidateRequired(s.FIPSMode); err != nil {
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	res, err := controllers.EnsureBlackBoxExporterServesModule(s.BlackBoxExporter, s.DryRunClient, module, &clusterUrlMonitor.Status.Conditions, clusterUrlMonitor.Generation)
+	if err != nil || res.ShouldStop() {
+		return res, err
+	}
+	uid, err := s.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment([]string{clusterUrl}, servicemonitor.Exporter{Namespace: s.BlackBoxExporter.GetBlackBoxExporterNamespace()}, namespacedName, id, useRHOBS, module, clusterUrlMonitor.Spec.MetricLabels, clusterUrlMonitor.Spec.MetricRelabelings, clusterUrlMonitor.Spec.SampleLimit, clusterUrlMonitor.Spec.TargetLimit, owner, clusterUrlMonitor.Status.ServiceMonitorRef.UID)
+	if err != nil {
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 	}
 
 	// Update RouteMonitor ServiceMonitorRef if required
-	updated, err := s.Common.SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, namespacedName)
-	if err != nil {
+	if _, err := s.Common.SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, namespacedName); err != nil {
 		return utilreconcile.RequeueReconcileWith(err)
 	}
-	if updated {
-		return s.Common.UpdateMonitorResourceStatus(&clusterUrlMonitor)
+	// Record the UID, so a ServiceMonitor recreated by someone else isn't adopted
+	if uid != "" {
+		clusterUrlMonitor.Status.ServiceMonitorRef.UID = uid
 	}
+	// Record the kind, which depends on the installed monitoring APIs, so it's the one verified and deleted
+	clusterUrlMonitor.Status.ServiceMonitorRef.Kind = servicemonitor.GeneratedKind(useRHOBS, s.ScrapeConfigResources, s.ProbeResources)
+	clusterUrlMonitor.Status.ClusterURL = clusterUrl
+	reconcileCommon.SetArtifactCondition(&clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady, clusterUrlMonitor.Generation, nil)
 	return utilreconcile.ContinueReconcile()
 }
 
+// artifactFailed reports the failure of a generated resource in the ClusterUrlMonitor's status, so it's
+// visible which one needs attention. It requeues with the failure unless retrying can't fix it
+func (s *ClusterUrlMonitorReconciler) artifactFailed(clusterUrlMonitor *v1alpha1.ClusterUrlMonitor, conditionType string, err error) (utilreconcile.Result, error) {
+	reconcileCommon.SetArtifactCondition(&clusterUrlMonitor.Status.Conditions, conditionType, clusterUrlMonitor.Generation, err)
+	metrics.ReconcileErrors.WithLabelValues("ClusterUrlMonitor", string(customerrors.ClassOf(err))).Inc()
+	return utilreconcile.FailReconcileWith(err)
+}
+
+// hostedControlPlaneDeleted orphans a ClusterUrlMonitor whose HostedControlPlane was deleted: its RHOBS ServiceMonitor
+// is deleted and it isn't requeued, until a HostedControlPlane created in its namespace triggers a reconcile
+func (s *ClusterUrlMonitorReconciler) hostedControlPlaneDeleted(clusterUrlMonitor *v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
+	if err := s.ServiceMonitor.DeleteServiceMonitorDeployment(clusterUrlMonitor.Status.ServiceMonitorRef); err != nil {
+		return s.artifactFailed(clusterUrlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	_, _ = s.Common.SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+	reconcileCommon.SetHostedControlPlaneDeletedCondition(&clusterUrlMonitor.Status.Conditions, clusterUrlMonitor.Generation, clusterUrlMonitor.Namespace)
+	return utilreconcile.StopReconcile()
+}
+
+// monitoringAPIUnavailable reports that the ClusterUrlMonitor's ServiceMonitor can't be generated, as no monitoring API
+// is installed, and checks again once the discovered APIs expire
+func (s *ClusterUrlMonitorReconciler) monitoringAPIUnavailable(clusterUrlMonitor *v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
+	reconcileCommon.SetMonitoringAPIUnavailableCondition(&clusterUrlMonitor.Status.Conditions, clusterUrlMonitor.Generation)
+	return utilreconcile.RequeueAfter(servicemonitor.APIDiscoveryInterval).Because("no monitoring API is installed"), nil
+}
+
+// clusterUrlMonitorsOfNamespace returns the requests of the ClusterUrlMonitors in the namespace of a created or deleted
+// HostedControlPlane, so they are orphaned or resumed with it
+func (s *ClusterUrlMonitorReconciler) clusterUrlMonitorsOfNamespace(ctx context.Context, o client.Object) []ctrl.Request {
+	list := v1alpha1.ClusterUrlMonitorList{}
+	if err := s.Client.List(ctx, &list, client.InNamespace(o.GetNamespace())); err != nil {
+		s.Log.Error(err, "Failed to list the ClusterUrlMonitors of the namespace", "namespace", o.GetNamespace())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, clusterUrlMonitor := range list.Items {
+		if !s.Scope.Matches(&clusterUrlMonitor) {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace}})
+	}
+	return requests
+}
+
 // Ensures that all dependencies related to a ClusterUrlMonitor are deleted
 func (s *ClusterUrlMonitorReconciler) EnsureMonitorAndDependenciesAbsent(clusterUrlMonitor v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
 	if clusterUrlMonitor.DeletionTimestamp == nil {
 		return utilreconcile.ContinueReconcile()
 	}
 
-	isHCP := (clusterUrlMonitor.Spec.DomainRef == v1alpha1.ClusterDomainRefHCP)
-	err := s.ServiceMonitor.DeleteServiceMonitorDeployment(clusterUrlMonitor.Status.ServiceMonitorRef, isHCP)
+	reason, err := reconcileCommon.CleanupSkipReason(s.Ctx, s.Client, &clusterUrlMonitor)
 	if err != nil {
 		return utilreconcile.RequeueReconcileWith(err)
 	}
+	if reason != "" {
+		reconcileCommon.Eventf(s.Recorder, &clusterUrlMonitor, corev1.EventTypeWarning, reconcileCommon.CleanupSkippedReason,
+			"Dropping the finalizer without deleting the generated resources: %s", reason)
+		return s.ensureFinalizerAbsent(clusterUrlMonitor)
+	}
+
+	// The resources controlled by the ClusterUrlMonitor are deleted with it by the garbage collector
+	resources := s.generatedResources(&clusterUrlMonitor)
+	if err := reconcileCommon.DeleteUncollected(s.Ctx, s.Client, &clusterUrlMonitor, resources...); err != nil {
+		return s.deletionBlocked(clusterUrlMonitor, err)
+	}
 
 	shouldDelete, err := s.BlackBoxExporter.ShouldDeleteBlackBoxExporterResources()
 	if err != nil {
@@ -162,20 +307,27 @@ func (s *ClusterUrlMonitorReconciler) EnsureMonitorAndDependenciesAbsent(cluster
 		}
 	}
 
-	err = s.Prom.DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef)
-	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
-	}
+	return s.ensureFinalizerAbsent(clusterUrlMonitor)
+}
 
+// ensureFinalizerAbsent drops the finalizer of the ClusterUrlMonitor, so its deletion completes
+func (s *ClusterUrlMonitorReconciler) ensureFinalizerAbsent(clusterUrlMonitor v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
 	if s.Common.DeleteFinalizer(&clusterUrlMonitor, FinalizerKey) {
 		// ignore the output as we want to remove the PrevFinalizerKey anyways
 		s.Common.DeleteFinalizer(&clusterUrlMonitor, PrevFinalizerKey)
 		return s.Common.UpdateMonitorResource(&clusterUrlMonitor)
 	}
-
 	return utilreconcile.ContinueReconcile()
 }
 
+// deletionBlocked warns that the ClusterUrlMonitor keeps its finalizer because its generated resources couldn't be
+// deleted, and requeues with the failure
+func (s *ClusterUrlMonitorReconciler) deletionBlocked(clusterUrlMonitor v1alpha1.ClusterUrlMonitor, err error) (utilreconcile.Result, error) {
+	reconcileCommon.Eventf(s.Recorder, &clusterUrlMonitor, corev1.EventTypeWarning, reconcileCommon.DeletionBlockedReason,
+		"Keeping the finalizer until the generated resources are deleted: %v", err)
+	return utilreconcile.RequeueReconcileWith(err)
+}
+
 func (s *ClusterUrlMonitorReconciler) EnsureFinalizerSet(clusterUrlMonitor v1alpha1.ClusterUrlMonitor) (utilreconcile.Result, error) {
 	if s.Common.SetFinalizer(&clusterUrlMonitor, FinalizerKey) {
 		// ignore the output as we want to remove the PrevFinalizerKey anyways
@@ -195,7 +347,7 @@ func (s *ClusterUrlMonitorReconciler) GetClusterUrlMonitor(req ctrl.Request) (v1
 			res, err := utilreconcile.RequeueReconcileWith(err)
 			return v1alpha1.ClusterUrlMonitor{}, res, err
 		}
-		s.Log.V(2).Info("StopRequeue", "As ClusterUrlMonitor is 'NotFound', stopping requeue", nil)
+		s.Log.V(logging.DebugVerbosity).Info("Stopping the reconcile: the ClusterUrlMonitor wasn't found")
 
 		return v1alpha1.ClusterUrlMonitor{}, utilreconcile.StopOperation(), nil
 	}
@@ -209,6 +361,43 @@ func (s *ClusterUrlMonitorReconciler) GetClusterUrlMonitor(req ctrl.Request) (v1
 	return ClusterUrlMonitor, utilreconcile.ContinueOperation(), nil
 }
 
+// GetClusterUrl returns the url probed for the ClusterUrlMonitor
+func (s *ClusterUrlMonitorReconciler) GetClusterUrl(monitor v1alpha1.ClusterUrlMonitor) (string, error) {
+	if monitor.Spec.RawURL != "" {
+		return monitor.Spec.RawURL, nil
+	}
+	if monitor.Spec.Target != "" {
+		return s.getAPIServerUrl(monitor)
+	}
+	clusterDomain, err := s.GetClusterDomain(monitor)
+	if err != nil {
+		return "", err
+	}
+	spec := monitor.Spec
+	return spec.Prefix + clusterDomain + ":" + spec.Port + spec.Suffix, nil
+}
+
+// getAPIServerUrl returns the API server endpoint the target of the ClusterUrlMonitor refers to, based on
+// the cluster's infrastructure object
+func (s *ClusterUrlMonitorReconciler) getAPIServerUrl(monitor v1alpha1.ClusterUrlMonitor) (string, error) {
+	if monitor.Spec.DomainRef == v1alpha1.ClusterDomainRefHCP {
+		return "", fmt.Errorf("target '%s' is not supported for hosted control planes", monitor.Spec.Target)
+	}
+	clusterInfra := configv1.Infrastructure{}
+	err := s.Client.Get(s.Ctx, types.NamespacedName{Name: "cluster"}, &clusterInfra)
+	if err != nil {
+		return "", err
+	}
+	endpoint := clusterInfra.Status.APIServerURL
+	if monitor.Spec.Target == v1alpha1.ClusterUrlTargetAPIInternal {
+		endpoint = clusterInfra.Status.APIServerInternalURL
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("the infrastructure object publishes no endpoint for target '%s'", monitor.Spec.Target)
+	}
+	return strings.TrimSuffix(endpoint, "/") + monitor.Spec.Suffix, nil
+}
+
 // GetClusterDomain returns the baseDomain for a cluster, using the correct method based on it's type
 func (s *ClusterUrlMonitorReconciler) GetClusterDomain(monitor v1alpha1.ClusterUrlMonitor) (string, error) {
 	if monitor.Spec.DomainRef == v1alpha1.ClusterDomainRefHCP {
@@ -118,6 +118,99 @@ var _ = Describe("ClusterUrlMonitorSupplement", func() {
 			})
 		})
 	})
+	Describe("IsHCP()", func() {
+		It("should follow the domainRef without an override", func() {
+			clusterUrlMonitor.Spec.DomainRef = v1alpha1.ClusterDomainRefHCP
+			Expect(clusterUrlMonitor.Spec.IsHCP()).To(BeTrue())
+			clusterUrlMonitor.Spec.DomainRef = v1alpha1.ClusterDomainRefInfra
+			Expect(clusterUrlMonitor.Spec.IsHCP()).To(BeFalse())
+		})
+		It("should follow the override", func() {
+			clusterUrlMonitor.Spec.DomainRef = v1alpha1.ClusterDomainRefHCP
+			clusterUrlMonitor.Spec.Hypershift = &v1alpha1.HypershiftSpec{Enabled: false}
+			Expect(clusterUrlMonitor.Spec.IsHCP()).To(BeFalse())
+		})
+	})
+	Describe("ProbedTarget()", func() {
+		It("should return the target", func() {
+			clusterUrlMonitor.Spec.Target = v1alpha1.ClusterUrlTargetAPIInternal
+			Expect(clusterUrlMonitor.Spec.ProbedTarget()).To(Equal(v1alpha1.ClusterUrlTargetAPIInternal))
+		})
+		It("should ignore the target of a raw url", func() {
+			clusterUrlMonitor.Spec.Target = v1alpha1.ClusterUrlTargetAPIInternal
+			clusterUrlMonitor.Spec.RawURL = "https://api.openshift.com/api/clusters_mgmt/v1"
+			Expect(clusterUrlMonitor.Spec.ProbedTarget()).To(BeEmpty())
+		})
+	})
+	Describe("GetClusterUrl()", func() {
+		var infra configv1.Infrastructure
+		BeforeEach(func() {
+			clusterUrlMonitor.Spec.Suffix = "/livez"
+			infra = configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			}
+			testObjs = append(testObjs, &infra)
+		})
+		JustBeforeEach(func() {
+			infra.Status.APIServerURL = "https://api.testdomain.devshift.org:6443"
+			infra.Status.APIServerInternalURL = "https://api-int.testdomain.devshift.org:6443"
+			err := reconciler.Client.Update(context.TODO(), &infra)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		When("no target is set", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.Prefix = "console."
+				clusterUrlMonitor.Spec.Port = "443"
+			})
+			It("should build the url from prefix, port and suffix", func() {
+				url, err := reconciler.GetClusterUrl(clusterUrlMonitor)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(url).To(Equal("console.testdomain.devshift.org:443/livez"))
+			})
+		})
+		When("the internal API endpoint is targeted", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.Target = v1alpha1.ClusterUrlTargetAPIInternal
+			})
+			It("should probe api-int", func() {
+				url, err := reconciler.GetClusterUrl(clusterUrlMonitor)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(url).To(Equal("https://api-int.testdomain.devshift.org:6443/livez"))
+			})
+			When("the cluster is a hosted control plane", func() {
+				BeforeEach(func() {
+					clusterUrlMonitor.Spec.DomainRef = v1alpha1.ClusterDomainRefHCP
+				})
+				It("should return an error", func() {
+					_, err := reconciler.GetClusterUrl(clusterUrlMonitor)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		When("the external API endpoint is targeted", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.Target = v1alpha1.ClusterUrlTargetAPI
+			})
+			It("should probe api", func() {
+				url, err := reconciler.GetClusterUrl(clusterUrlMonitor)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(url).To(Equal("https://api.testdomain.devshift.org:6443/livez"))
+			})
+		})
+		When("a raw url is set", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.RawURL = "https://api.openshift.com/api/clusters_mgmt/v1"
+				clusterUrlMonitor.Spec.Target = v1alpha1.ClusterUrlTargetAPI
+			})
+			It("should probe it as is", func() {
+				url, err := reconciler.GetClusterUrl(clusterUrlMonitor)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(url).To(Equal("https://api.openshift.com/api/clusters_mgmt/v1"))
+			})
+		})
+	})
 })
 
 func buildClient(objs ...client.Object) client.Client {
@@ -1,20 +1,27 @@
 package clusterurlmonitor_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/controllers/clusterurlmonitor"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
 	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	"github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
@@ -53,6 +60,12 @@ var _ = Describe("Clusterurlmonitor", func() {
 				Name:      "fake-clusterurlmonitor",
 				Namespace: "fake-namespace",
 			},
+			Status: v1alpha1.ClusterUrlMonitorStatus{
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+					{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+				},
+			},
 		}
 	})
 
@@ -82,21 +95,36 @@ var _ = Describe("Clusterurlmonitor", func() {
 			suffix = "/suffix"
 		})
 		JustBeforeEach(func() {
-			res, err = reconciler.EnsureServiceMonitorExists(clusterUrlMonitor)
+			res, err = reconciler.EnsureServiceMonitorExists(&clusterUrlMonitor)
+		})
+		When("the ServiceMonitor is skipped", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.SkipServiceMonitor = true
+				mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(clusterUrlMonitor.Status.ServiceMonitorRef).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, types.NamespacedName{}).Times(1).Return(false, nil)
+			})
+			It("deletes the existing ServiceMonitor and removes its condition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.FindStatusCondition(clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)).To(BeNil())
+			})
 		})
 		When("the ServiceMonitor doesn't exist", func() {
 			BeforeEach(func() {
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1) // fetching domain
-				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 				mockBlackBoxExporter.EXPECT().GetBlackBoxExporterNamespace().Times(1).Return("")
-				ns := types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace}
+				mockBlackBoxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
+				ns := reconcileCommon.GeneratedResourceName(clusterUrlMonitor.Status.ServiceMonitorRef, "ClusterUrlMonitor", types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace})
+				Expect(ns.Name).NotTo(Equal(clusterUrlMonitor.Name))
 				mockCommon.EXPECT().GetOSDClusterID().Times(1)
 				mockCommon.EXPECT().SetResourceReference(&clusterUrlMonitor.Status.ServiceMonitorRef, ns).Times(1).Return(true, nil)
-				mockCommon.EXPECT().UpdateMonitorResourceStatus(&clusterUrlMonitor).Times(1)
 			})
-			It("creates a ServiceMonitor and updates the ServiceRef", func() {
+			It("creates a ServiceMonitor and records the ServiceRef and the probed url", func() {
 				Expect(err).NotTo(HaveOccurred())
-				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(clusterUrlMonitor.Status.ClusterURL).To(Equal("prefix..:1337/suffix"))
+				Expect(clusterUrlMonitor.Status.ServiceMonitorRef.Kind).To(Equal(servicemonitor.ServiceMonitorKind))
 			})
 		})
 	})
@@ -107,7 +135,7 @@ var _ = Describe("Clusterurlmonitor", func() {
 			err error
 		)
 		JustBeforeEach(func() {
-			res, err = reconciler.EnsurePrometheusRuleExists(clusterUrlMonitor)
+			res, err = reconciler.EnsurePrometheusRuleExists(&clusterUrlMonitor)
 		})
 		When("the ClusterUrlMonitor has an invalid slo value", func() {
 			BeforeEach(func() {
@@ -123,6 +151,9 @@ var _ = Describe("Clusterurlmonitor", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res).NotTo(BeNil())
 				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				condition := meta.FindStatusCondition(clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Message).To(Equal(customerrors.InvalidSLO.Error()))
 			})
 		})
 		When("the resource Exists but not the same as the generated template", func() {
@@ -139,21 +170,34 @@ var _ = Describe("Clusterurlmonitor", func() {
 				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
 			})
 		})
+		When("the ClusterUrlMonitor is explicitly marked as monitoring a hosted control plane", func() {
+			BeforeEach(func() {
+				clusterUrlMonitor.Spec.Hypershift = &v1alpha1.HypershiftSpec{Enabled: true}
+				// The monitor had a PrometheusRule before the override was set
+				clusterUrlMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "fake-namespace"}
+				mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, types.NamespacedName{}).Times(1).Return(true, nil)
+			})
+			It("deletes its PrometheusRule, leaves alerting to the RHOBS tenant and continues reconciling", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.FindStatusCondition(clusterUrlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)).To(BeNil())
+			})
+		})
 		When("the resource doesn't exists", func() {
 			BeforeEach(func() {
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 				mockCommon.EXPECT().ParseMonitorSLOSpecs(gomock.Any(), clusterUrlMonitor.Spec.Slo).Times(1).Return("99.5", nil)
 				mockCommon.EXPECT().SetErrorStatus(&clusterUrlMonitor.Status.ErrorStatus, nil)
 				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any()).Times(1)
-				ns := types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace}
+				ns := reconcileCommon.GeneratedResourceName(clusterUrlMonitor.Status.PrometheusRuleRef, "ClusterUrlMonitor", types.NamespacedName{Name: clusterUrlMonitor.Name, Namespace: clusterUrlMonitor.Namespace})
 				mockCommon.EXPECT().SetResourceReference(&clusterUrlMonitor.Status.PrometheusRuleRef, ns).Times(1).Return(true, nil)
-				mockCommon.EXPECT().UpdateMonitorResourceStatus(&clusterUrlMonitor).Times(1).Return(utilreconcile.StopOperation(), nil)
 			})
 
 			It("should create one and update the clusterURLMonitor", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res).NotTo(BeNil())
-				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
 			})
 		})
 	})
@@ -164,6 +208,8 @@ var _ = Describe("Clusterurlmonitor", func() {
 			err error
 		)
 		BeforeEach(func() {
+			// The namespace of the monitor isn't terminating, so its generated resources are cleaned up
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Namespace{})).AnyTimes()
 			clusterUrlMonitor.Finalizers = []string{clusterurlmonitor.FinalizerKey}
 		})
 		JustBeforeEach(func() {
@@ -180,10 +226,20 @@ var _ = Describe("Clusterurlmonitor", func() {
 			BeforeEach(func() {
 				clusterUrlMonitor.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
 			})
-			When("the ServiceMonitor still exists", func() {
+			When("the generated resources still exist", func() {
 				BeforeEach(func() {
-					mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(clusterUrlMonitor.Status.PrometheusRuleRef).Times(1)
-					mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(clusterUrlMonitor.Status.ServiceMonitorRef, gomock.Any()).Times(1)
+					clusterUrlMonitor.UID = "fake-uid"
+					clusterUrlMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-servicemonitor", Namespace: "fake-namespace"}
+					clusterUrlMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "fake-namespace"}
+					// The ServiceMonitor is controlled by the ClusterUrlMonitor and left to the garbage collector,
+					// the PrometheusRule was created before it was controlled
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.ServiceMonitor{})).
+						DoAndReturn(func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+							obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(&clusterUrlMonitor, v1alpha1.GroupVersion.WithKind("ClusterUrlMonitor"))})
+							return nil
+						})
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.PrometheusRule{}))
+					mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.PrometheusRule{}))
 					gomock.InOrder(
 						mockCommon.EXPECT().DeleteFinalizer(&clusterUrlMonitor, clusterurlmonitor.FinalizerKey).Times(1).Return(true),
 						mockCommon.EXPECT().DeleteFinalizer(&clusterUrlMonitor, clusterurlmonitor.PrevFinalizerKey).Times(1),
@@ -196,7 +252,7 @@ var _ = Describe("Clusterurlmonitor", func() {
 						mockBlackBoxExporter.EXPECT().ShouldDeleteBlackBoxExporterResources().Return(blackboxexporter.DeleteBlackBoxExporter, nil)
 						mockBlackBoxExporter.EXPECT().EnsureBlackBoxExporterResourcesAbsent().Times(1)
 					})
-					It("removes the servicemonitor, the blackbox exporter and cleans up the finalizer", func() {
+					It("removes the prometheusrule, the blackbox exporter and cleans up the finalizer", func() {
 						Expect(err).NotTo(HaveOccurred())
 						Expect(res).To(Equal(reconcile.StopOperation()))
 					})
@@ -206,10 +262,20 @@ var _ = Describe("Clusterurlmonitor", func() {
 					BeforeEach(func() {
 						mockBlackBoxExporter.EXPECT().ShouldDeleteBlackBoxExporterResources().Return(blackboxexporter.KeepBlackBoxExporter, nil)
 					})
-					It("removes the servicemonitor and cleans up the finalizer", func() {
+					It("removes the prometheusrule and cleans up the finalizer", func() {
 						Expect(err).NotTo(HaveOccurred())
 						Expect(res).To(Equal(reconcile.StopOperation()))
 					})
+					When("the ServiceMonitor of a hosted control plane was generated with the cluster's monitoring API", func() {
+						BeforeEach(func() {
+							clusterUrlMonitor.Spec.DomainRef = v1alpha1.ClusterDomainRefHCP
+							clusterUrlMonitor.Status.ServiceMonitorRef.Kind = servicemonitor.ServiceMonitorKind
+						})
+						It("looks up the recorded kind instead of a RHOBS ServiceMonitor", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(res).To(Equal(reconcile.StopOperation()))
+						})
+					})
 				})
 			})
 		})
@@ -0,0 +1,174 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consoledashboard
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/dashboard"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var logger logr.Logger = ctrl.Log.WithName("controllers").WithName("ConsoleDashboard")
+
+// dashboardName is the ConfigMap holding the console dashboard
+var dashboardName = types.NamespacedName{Name: dashboard.Name, Namespace: dashboard.Namespace}
+
+// ConsoleDashboardReconciler keeps the console dashboard of the RouteMonitors and ClusterUrlMonitors up to date
+type ConsoleDashboardReconciler struct {
+	client.Client
+}
+
+// NewConsoleDashboardReconciler creates a ConsoleDashboardReconciler
+func NewConsoleDashboardReconciler(mgr manager.Manager) *ConsoleDashboardReconciler {
+	return &ConsoleDashboardReconciler{
+		Client: mgr.GetClient(),
+	}
+}
+
+// +kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitors,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=clusterurlmonitors,verbs=get;list;watch
+
+// Reconcile renders the dashboard from the current monitors and creates or updates its ConfigMap, reverting changes
+// made to it by others
+func (r *ConsoleDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logger.WithName("Reconcile").WithValues("name", req.Name, "namespace", req.Namespace)
+
+	monitors, err := r.dashboardMonitors(ctx)
+	if err != nil {
+		return utilreconcile.RequeueWith(err)
+	}
+	expected, err := dashboard.TemplateForDashboardConfigMap(monitors)
+	if err != nil {
+		return utilreconcile.RequeueWith(err)
+	}
+
+	actual := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, dashboardName, actual)
+	if kerr.IsNotFound(err) {
+		log.Info("Creating the console dashboard", "monitors", len(monitors))
+		return utilreconcile.RequeueWith(r.Client.Create(ctx, &expected))
+	}
+	if err != nil {
+		return utilreconcile.RequeueWith(err)
+	}
+	if reflect.DeepEqual(actual.Data, expected.Data) && actual.Labels[dashboard.ConsoleLabel] == "true" {
+		return utilreconcile.Stop()
+	}
+	if actual.Labels == nil {
+		actual.Labels = map[string]string{}
+	}
+	actual.Labels[dashboard.ConsoleLabel] = "true"
+	actual.Data = expected.Data
+	log.Info("Updating the console dashboard", "monitors", len(monitors))
+	return utilreconcile.RequeueWith(r.Client.Update(ctx, actual))
+}
+
+// dashboardMonitors returns the RouteMonitors and ClusterUrlMonitors that aren't being deleted, ordered by kind,
+// namespace and name so the dashboard doesn't change with the order they're listed in
+func (r *ConsoleDashboardReconciler) dashboardMonitors(ctx context.Context) ([]dashboard.Monitor, error) {
+	monitors := []dashboard.Monitor{}
+
+	routeMonitors := v1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, &routeMonitors); err != nil {
+		return nil, err
+	}
+	for _, routeMonitor := range routeMonitors.Items {
+		if routeMonitor.DeletionTimestamp != nil {
+			continue
+		}
+		urls := routeMonitor.Status.IngressURLs
+		if len(urls) == 0 && routeMonitor.Status.RouteURL != "" {
+			urls = []string{routeMonitor.Status.RouteURL}
+		}
+		monitors = append(monitors, dashboardMonitor("RouteMonitor", &routeMonitor, urls, routeMonitor.Status.SloTarget, routeMonitor.Spec.Slo))
+	}
+
+	clusterUrlMonitors := v1alpha1.ClusterUrlMonitorList{}
+	if err := r.Client.List(ctx, &clusterUrlMonitors); err != nil {
+		return nil, err
+	}
+	for _, clusterUrlMonitor := range clusterUrlMonitors.Items {
+		if clusterUrlMonitor.DeletionTimestamp != nil {
+			continue
+		}
+		var urls []string
+		if clusterUrlMonitor.Status.ClusterURL != "" {
+			urls = []string{clusterUrlMonitor.Status.ClusterURL}
+		}
+		monitors = append(monitors, dashboardMonitor("ClusterUrlMonitor", &clusterUrlMonitor, urls, clusterUrlMonitor.Status.SloTarget, clusterUrlMonitor.Spec.Slo))
+	}
+
+	sort.SliceStable(monitors, func(i, j int) bool {
+		if monitors[i].Kind != monitors[j].Kind {
+			return monitors[i].Kind < monitors[j].Kind
+		}
+		if monitors[i].NamespacedName.Namespace != monitors[j].NamespacedName.Namespace {
+			return monitors[i].NamespacedName.Namespace < monitors[j].NamespacedName.Namespace
+		}
+		return monitors[i].NamespacedName.Name < monitors[j].NamespacedName.Name
+	})
+	return monitors, nil
+}
+
+// dashboardMonitor describes a monitor for the dashboard. The availability target is the one last observed by the
+// operator, which includes the cluster-wide default
+func dashboardMonitor(kind string, monitor client.Object, urls []string, sloTarget string, slo v1alpha1.SloSpec) dashboard.Monitor {
+	_, percent := v1alpha1.SloSpec{TargetAvailabilityPercent: sloTarget}.IsValid()
+	return dashboard.Monitor{
+		Kind:             kind,
+		NamespacedName:   types.NamespacedName{Name: monitor.GetName(), Namespace: monitor.GetNamespace()},
+		URLs:             urls,
+		Percent:          percent,
+		ClientErrorsFail: slo.ClientErrorsFail(),
+	}
+}
+
+// enqueueDashboard requeues the dashboard, as any change of a monitor may change it
+func (r *ConsoleDashboardReconciler) enqueueDashboard(_ context.Context, _ client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: dashboardName}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ConsoleDashboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isDashboard := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == dashboardName.Name && o.GetNamespace() == dashboardName.Namespace
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("consoledashboard").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isDashboard)).
+		Watches(&v1alpha1.RouteMonitor{}, handler.EnqueueRequestsFromMapFunc(r.enqueueDashboard)).
+		Watches(&v1alpha1.ClusterUrlMonitor{}, handler.EnqueueRequestsFromMapFunc(r.enqueueDashboard)).
+		Complete(r)
+}
@@ -0,0 +1,89 @@
+package consoledashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/dashboard"
+)
+
+func TestConsoleDashboardReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	routeMonitor := &v1alpha1.RouteMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop-namespace"},
+		Status:     v1alpha1.RouteMonitorStatus{RouteURL: "https://shop.apps.example.com", SloTarget: "99.5"},
+	}
+	clusterUrlMonitor := &v1alpha1.ClusterUrlMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "console", Namespace: "openshift-monitoring"},
+		Status:     v1alpha1.ClusterUrlMonitorStatus{ClusterURL: "https://console.apps.example.com/health"},
+	}
+	tests := []struct {
+		name      string
+		existing  *corev1.ConfigMap
+		wantExprs []string
+	}{
+		{
+			name: "the dashboard doesn't exist",
+			wantExprs: []string{
+				`probe_success{probe_url=\"https://console.apps.example.com/health\"}`,
+				`/(1-0.995)`,
+			},
+		},
+		{
+			name: "the dashboard was changed",
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: dashboard.Name, Namespace: dashboard.Namespace, Labels: map[string]string{"team": "ops"}},
+				Data:       map[string]string{dashboard.DataKey: "{}"},
+			},
+			wantExprs: []string{`probe_success{probe_url=\"https://shop.apps.example.com\"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{routeMonitor.DeepCopy(), clusterUrlMonitor.DeepCopy()}
+			if tt.existing != nil {
+				objs = append(objs, tt.existing)
+			}
+			r := &ConsoleDashboardReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			}
+
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: dashboardName})
+			if err != nil || result.Requeue || result.RequeueAfter != 0 {
+				t.Fatalf("Reconcile() = %v, %v, want no requeue", result, err)
+			}
+			configMap := &corev1.ConfigMap{}
+			if err := r.Client.Get(context.TODO(), dashboardName, configMap); err != nil {
+				t.Fatalf("failed to get the dashboard: %v", err)
+			}
+			if configMap.Labels[dashboard.ConsoleLabel] != "true" {
+				t.Errorf("labels = %v, want %s=true", configMap.Labels, dashboard.ConsoleLabel)
+			}
+			if tt.existing != nil && configMap.Labels["team"] != "ops" {
+				t.Errorf("labels = %v, want the existing labels kept", configMap.Labels)
+			}
+			for _, expr := range tt.wantExprs {
+				if !strings.Contains(configMap.Data[dashboard.DataKey], expr) {
+					t.Errorf("dashboard doesn't contain %s", expr)
+				}
+			}
+			if !strings.Contains(configMap.Data[dashboard.DataKey], "ClusterUrlMonitor openshift-monitoring/console") {
+				t.Errorf("dashboard doesn't name the ClusterUrlMonitor")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DebugProbeInterval is how often a monitor in debug mode is requeued to probe its urls with the exporter's debug
+	// output
+	DebugProbeInterval = time.Minute
+
+	// maxTranscriptLength bounds the transcript recorded in the status, and maxEventTranscriptLength the one of the
+	// Event. A probe logs its failure last, so the last lines are kept
+	maxTranscriptLength      = 4096
+	maxEventTranscriptLength = 1024
+)
+
+// Debugging reports whether the debug mode of a monitor, turned on until debugUntil, is on at the time. A debugUntil
+// further ahead than the MaxDebugDuration doesn't turn it on
+func Debugging(debugUntil *metav1.Time, now time.Time) bool {
+	return debugUntil != nil && now.Before(debugUntil.Time) && !debugUntil.After(now.Add(v1alpha1.MaxDebugDuration))
+}
+
+// DebugProbes runs the debug probes of the monitors in debug mode, at most once per DebugProbeInterval for each of
+// them: the reconciles triggered in between, e.g. by changes of the generated resources, don't probe again. The zero
+// value is ready to use
+type DebugProbes struct {
+	mu   sync.Mutex
+	last map[types.UID]time.Time
+}
+
+// due reports whether the debug probes of the monitor are due at the time, and records them as run if they are.
+// The monitors out of debug mode are forgotten
+func (d *DebugProbes) due(monitor client.Object, debugging bool, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !debugging {
+		delete(d.last, monitor.GetUID())
+		return false
+	}
+	if last, ok := d.last[monitor.GetUID()]; ok && now.Sub(last) < DebugProbeInterval {
+		return false
+	}
+	if d.last == nil {
+		d.last = map[types.UID]time.Time{}
+	}
+	d.last[monitor.GetUID()] = now
+	return true
+}
+
+// CaptureFailingProbe probes the urls of a monitor in debug mode with the exporter's debug output, if they weren't
+// probed within the DebugProbeInterval, and records the transcript of the first failing probe in lastFailedProbe and a
+// ProbeFailed Event. The last failed probe is kept once the debug mode is over. A debug probe that couldn't be run is
+// reported in a DebugProbeError Event, without failing the reconcile. In dry-run mode, if dryRun is set, the urls
+// aren't probed
+func (d *DebugProbes) CaptureFailingProbe(exporter BlackBoxExporterHandler, dryRun *reconcileCommon.DryRunClient, recorder record.EventRecorder, monitor client.Object,
+	debugUntil *metav1.Time, urls []string, module string, lastFailedProbe **v1alpha1.ProbeTranscript) {
+	now := time.Now()
+	if dryRun != nil || !d.due(monitor, Debugging(debugUntil, now), now) {
+		return
+	}
+	for _, url := range urls {
+		success, transcript, err := exporter.DebugBlackBoxExporterProbe(url, module)
+		if err != nil {
+			reconcileCommon.Eventf(recorder, monitor, corev1.EventTypeWarning, reconcileCommon.DebugProbeErrorReason,
+				"Failed to probe %s with the debug output of the blackbox exporter: %v", url, err)
+			continue
+		}
+		if success {
+			continue
+		}
+		*lastFailedProbe = &v1alpha1.ProbeTranscript{
+			URL:        url,
+			Module:     module,
+			Transcript: lastLines(transcript, maxTranscriptLength),
+			Time:       metav1.NewTime(now.Truncate(time.Second)),
+		}
+		reconcileCommon.Eventf(recorder, monitor, corev1.EventTypeWarning, reconcileCommon.ProbeFailedReason,
+			"The probe of %s with the module %s failed:\n%s", url, module, lastLines(transcript, maxEventTranscriptLength))
+		return
+	}
+}
+
+// lastLines returns the last whole lines of the text fitting into length bytes
+func lastLines(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	text = text[len(text)-length:]
+	if _, rest, found := strings.Cut(text, "\n"); found {
+		return rest
+	}
+	return text
+}
@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	controllermocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/controllers"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCaptureFailingProbe(t *testing.T) {
+	later := metav1.NewTime(time.Now().Add(time.Hour))
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+	tooLate := metav1.NewTime(time.Now().Add(v1alpha1.MaxDebugDuration + time.Hour))
+	previous := &v1alpha1.ProbeTranscript{URL: "https://previous.example.com", Module: "http_2xx", Transcript: "msg=\"Probe failed\""}
+	for name, tc := range map[string]struct {
+		debugUntil     *metav1.Time
+		success        bool
+		transcript     string
+		probeErr       error
+		wantProbe      bool
+		wantTranscript string
+		wantEvent      string
+	}{
+		"debug mode off":        {},
+		"debug mode over":       {debugUntil: &earlier},
+		"debug mode too long":   {debugUntil: &tooLate},
+		"successful probe":      {debugUntil: &later, success: true, transcript: "msg=\"Probe succeeded\"", wantProbe: true},
+		"failing probe":         {debugUntil: &later, transcript: "msg=\"Beginning probe\"\nmsg=\"Probe failed\"", wantProbe: true, wantTranscript: "msg=\"Beginning probe\"\nmsg=\"Probe failed\"", wantEvent: "Warning ProbeFailed"},
+		"long failing probe":    {debugUntil: &later, transcript: strings.Repeat("msg=\"Resolving target address\"\n", 200) + "msg=\"Probe failed\"", wantProbe: true, wantTranscript: "msg=\"Probe failed\"", wantEvent: "Warning ProbeFailed"},
+		"probe couldn't be run": {debugUntil: &later, probeErr: errors.New("connection refused"), wantProbe: true, wantEvent: "Warning DebugProbeError"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			exporter := controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+			if tc.wantProbe {
+				exporter.EXPECT().DebugBlackBoxExporterProbe("https://shop.example.com", "http_2xx").Times(1).Return(tc.success, tc.transcript, tc.probeErr)
+			}
+			recorder := record.NewFakeRecorder(1)
+			monitor := &v1alpha1.UrlMonitor{Spec: v1alpha1.UrlMonitorSpec{DebugUntil: tc.debugUntil}}
+			monitor.Status.LastFailedProbe = previous
+
+			debugProbes := &controllers.DebugProbes{}
+			debugProbes.CaptureFailingProbe(exporter, nil, recorder, monitor, monitor.Spec.DebugUntil, []string{"https://shop.example.com"}, "http_2xx", &monitor.Status.LastFailedProbe)
+
+			lastFailedProbe := monitor.Status.LastFailedProbe
+			if tc.wantTranscript == "" {
+				if lastFailedProbe != previous {
+					t.Errorf("lastFailedProbe = %+v, want the previous one", lastFailedProbe)
+				}
+			} else {
+				if lastFailedProbe.URL != "https://shop.example.com" || lastFailedProbe.Module != "http_2xx" || lastFailedProbe.Time.IsZero() {
+					t.Errorf("lastFailedProbe = %+v, want the probe of https://shop.example.com", lastFailedProbe)
+				}
+				if !strings.HasSuffix(lastFailedProbe.Transcript, tc.wantTranscript) || len(lastFailedProbe.Transcript) > 4096 {
+					t.Errorf("transcript = %q, want at most 4096 bytes ending with %q", lastFailedProbe.Transcript, tc.wantTranscript)
+				}
+				if strings.HasPrefix(lastFailedProbe.Transcript, "\n") || !strings.HasPrefix(lastFailedProbe.Transcript, "msg=") {
+					t.Errorf("transcript = %q, want whole lines", lastFailedProbe.Transcript)
+				}
+			}
+			select {
+			case event := <-recorder.Events:
+				if tc.wantEvent == "" || !strings.HasPrefix(event, tc.wantEvent) {
+					t.Errorf("event = %q, want %q", event, tc.wantEvent)
+				}
+			default:
+				if tc.wantEvent != "" {
+					t.Errorf("no event, want %q", tc.wantEvent)
+				}
+			}
+		})
+	}
+}
+
+func TestCaptureFailingProbe_OncePerInterval(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	exporter := controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+	exporter.EXPECT().DebugBlackBoxExporterProbe("https://shop.example.com", "http_2xx").Times(2).Return(true, "msg=\"Probe succeeded\"", nil)
+	recorder := record.NewFakeRecorder(1)
+	later := metav1.NewTime(time.Now().Add(time.Hour))
+	monitor := &v1alpha1.UrlMonitor{Spec: v1alpha1.UrlMonitorSpec{DebugUntil: &later}}
+	monitor.UID = "fake-uid"
+	other := monitor.DeepCopy()
+	other.UID = "other-uid"
+	debugProbes := &controllers.DebugProbes{}
+
+	for _, m := range []*v1alpha1.UrlMonitor{monitor, monitor, other} {
+		debugProbes.CaptureFailingProbe(exporter, nil, recorder, m, m.Spec.DebugUntil, []string{"https://shop.example.com"}, "http_2xx", &m.Status.LastFailedProbe)
+	}
+}
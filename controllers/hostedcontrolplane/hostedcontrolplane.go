@@ -19,6 +19,11 @@ package hostedcontrolplane
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/go-logr/logr"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -55,13 +60,21 @@ var logger logr.Logger = ctrl.Log.WithName("controllers").WithName("HostedContro
 type HostedControlPlaneReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// DedicatedExporters probes the kube-apiserver of each HostedControlPlane from a blackbox exporter deployed into its
+	// namespace, near the control plane, instead of the shared exporter of the management cluster
+	DedicatedExporters bool
+	// EndpointMonitors monitors the public kube-apiserver, oauth and console endpoints of each hosted cluster with a
+	// ClusterUrlMonitor in the namespace of its HostedControlPlane
+	EndpointMonitors bool
 }
 
 // NewHostedControlPlaneReconciler creates a HostedControlPlaneReconciler
-func NewHostedControlPlaneReconciler(mgr manager.Manager) *HostedControlPlaneReconciler {
+func NewHostedControlPlaneReconciler(mgr manager.Manager, dedicatedExporters, endpointMonitors bool) *HostedControlPlaneReconciler {
 	return &HostedControlPlaneReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		DedicatedExporters: dedicatedExporters,
+		EndpointMonitors:   endpointMonitors,
 	}
 }
 
@@ -124,6 +137,17 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return utilreconcile.RequeueWith(err)
 	}
 
+	if r.EndpointMonitors {
+		log.Info("Deploying endpoint monitors")
+		err = r.deployEndpointMonitors(ctx, log, hostedcontrolplane)
+	} else {
+		err = r.deleteEndpointMonitors(ctx, log, hostedcontrolplane)
+	}
+	if err != nil {
+		log.Error(err, "failed to reconcile the endpoint monitors")
+		return utilreconcile.RequeueWith(err)
+	}
+
 	return ctrl.Result{}, err
 }
 
@@ -244,11 +268,164 @@ func (r *HostedControlPlaneReconciler) buildInternalMonitoringRouteMonitor(route
 			},
 			InsecureSkipTLSVerify: true,
 			ServiceMonitorType:    v1alpha1.ServiceMonitorTypeRHOBS,
+			DedicatedExporter:     r.DedicatedExporters,
 		},
 	}
 	return routemonitor
 }
 
+// hostedClusterEndpoints are the endpoints of a hosted cluster monitored by a ClusterUrlMonitor each
+var hostedClusterEndpoints = []string{"kube-apiserver", "oauth", "console"}
+
+// endpointMonitorName names the ClusterUrlMonitor of an endpoint of the HostedControlPlane's hosted cluster
+func endpointMonitorName(hostedcontrolplane *hypershiftv1beta1.HostedControlPlane, endpoint string) string {
+	return fmt.Sprintf("%s-%s", hostedcontrolplane.Name, endpoint)
+}
+
+// hostedClusterEndpointURL returns the url the endpoint of the HostedControlPlane's hosted cluster is probed at, based on
+// what the HostedControlPlane publishes. It's empty while the HostedControlPlane doesn't publish the endpoint
+func hostedClusterEndpointURL(hostedcontrolplane *hypershiftv1beta1.HostedControlPlane, endpoint string) (string, error) {
+	switch endpoint {
+	case "kube-apiserver":
+		apiServer := hostedcontrolplane.Status.ControlPlaneEndpoint
+		if apiServer.Host == "" {
+			return "", nil
+		}
+		return fmt.Sprintf("https://%s/livez", net.JoinHostPort(apiServer.Host, strconv.Itoa(int(apiServer.Port)))), nil
+	case "oauth":
+		if hostedcontrolplane.Status.OAuthCallbackURLTemplate == "" {
+			return "", nil
+		}
+		// The callback is served by the oauth server, e.g. https://oauth.example.com:443/oauth2callback/[identity-provider-name]
+		callback, err := url.Parse(hostedcontrolplane.Status.OAuthCallbackURLTemplate)
+		if err != nil {
+			return "", fmt.Errorf("invalid oauth callback url template of HostedControlPlane '%s': %w", hostedcontrolplane.Name, err)
+		}
+		return fmt.Sprintf("%s://%s/healthz", callback.Scheme, callback.Host), nil
+	case "console":
+		dns := hostedcontrolplane.Spec.DNS
+		if dns.BaseDomain == "" {
+			return "", nil
+		}
+		// The ingress domain of the hosted cluster is prefixed with the cluster's name by default
+		domain := hostedcontrolplane.Name + "." + dns.BaseDomain
+		if dns.BaseDomainPrefix != nil {
+			domain = strings.TrimPrefix(*dns.BaseDomainPrefix+"."+dns.BaseDomain, ".")
+		}
+		return "https://console-openshift-console.apps." + domain, nil
+	}
+	return "", fmt.Errorf("unknown hosted cluster endpoint '%s'", endpoint)
+}
+
+// buildEndpointMonitor constructs the ClusterUrlMonitor probing the url of an endpoint of the HostedControlPlane's hosted
+// cluster. It's scraped through RHOBS with the hosted cluster's ID, alerting is left to the RHOBS tenant
+func (r *HostedControlPlaneReconciler) buildEndpointMonitor(hostedcontrolplane *hypershiftv1beta1.HostedControlPlane, endpoint, endpointURL string) v1alpha1.ClusterUrlMonitor {
+	return v1alpha1.ClusterUrlMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            endpointMonitorName(hostedcontrolplane, endpoint),
+			Namespace:       hostedcontrolplane.Namespace,
+			OwnerReferences: buildOwnerReferences(hostedcontrolplane),
+			Labels: map[string]string{
+				watchResourceLabel: "true",
+			},
+		},
+		Spec: v1alpha1.ClusterUrlMonitorSpec{
+			RawURL:    endpointURL,
+			DomainRef: v1alpha1.ClusterDomainRefHCP,
+			Slo: v1alpha1.SloSpec{
+				TargetAvailabilityPercent: "99.5",
+			},
+		},
+	}
+}
+
+// deployEndpointMonitors creates or updates the ClusterUrlMonitors of the endpoints the HostedControlPlane publishes, and
+// deletes the ones of the endpoints it stopped publishing. Monitors of the same name which weren't generated for this
+// HostedControlPlane, e.g. by the automation this controller replaces, are left alone
+func (r *HostedControlPlaneReconciler) deployEndpointMonitors(ctx context.Context, log logr.Logger, hostedcontrolplane *hypershiftv1beta1.HostedControlPlane) error {
+	for _, endpoint := range hostedClusterEndpoints {
+		endpointURL, err := hostedClusterEndpointURL(hostedcontrolplane, endpoint)
+		if err != nil {
+			return err
+		}
+		if endpointURL == "" {
+			log.Info(fmt.Sprintf("Skipped monitoring the %s endpoint: not published by the HostedControlPlane", endpoint))
+			// A monitor generated while it was published would keep probing the stale url
+			err = r.deleteEndpointMonitor(ctx, log, hostedcontrolplane, endpoint)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		expectedMonitor := r.buildEndpointMonitor(hostedcontrolplane, endpoint, endpointURL)
+		err = r.Client.Create(ctx, &expectedMonitor)
+		if err == nil {
+			continue
+		}
+		if !kerr.IsAlreadyExists(err) {
+			return err
+		}
+		// Object already exists: update it
+		actualMonitor := v1alpha1.ClusterUrlMonitor{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: expectedMonitor.Name, Namespace: expectedMonitor.Namespace}, &actualMonitor)
+		if err != nil {
+			return err
+		}
+		if !isManagedEndpointMonitor(&actualMonitor, hostedcontrolplane) {
+			log.Info(fmt.Sprintf("Skipped updating ClusterUrlMonitor %s/%s: not managed for the HostedControlPlane", actualMonitor.Namespace, actualMonitor.Name))
+			continue
+		}
+		expectedMonitor.ObjectMeta = buildMetadataForUpdate(expectedMonitor.ObjectMeta, actualMonitor.ObjectMeta)
+		err = r.Client.Update(ctx, &expectedMonitor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEndpointMonitors removes the ClusterUrlMonitors of the endpoints of the HostedControlPlane. Monitors of the
+// same name which weren't generated for this HostedControlPlane, e.g. by the automation this controller replaces, are
+// left alone
+func (r *HostedControlPlaneReconciler) deleteEndpointMonitors(ctx context.Context, log logr.Logger, hostedcontrolplane *hypershiftv1beta1.HostedControlPlane) error {
+	for _, endpoint := range hostedClusterEndpoints {
+		err := r.deleteEndpointMonitor(ctx, log, hostedcontrolplane, endpoint)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEndpointMonitor removes the ClusterUrlMonitor of an endpoint of the HostedControlPlane, if it was generated for it
+func (r *HostedControlPlaneReconciler) deleteEndpointMonitor(ctx context.Context, log logr.Logger, hostedcontrolplane *hypershiftv1beta1.HostedControlPlane, endpoint string) error {
+	monitor := v1alpha1.ClusterUrlMonitor{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: endpointMonitorName(hostedcontrolplane, endpoint), Namespace: hostedcontrolplane.Namespace}, &monitor)
+	if err != nil {
+		if !kerr.IsNotFound(err) {
+			return err
+		}
+		log.V(1).Info(fmt.Sprintf("Skipped deleting ClusterUrlMonitor %s/%s: already deleted", hostedcontrolplane.Namespace, endpointMonitorName(hostedcontrolplane, endpoint)))
+		return nil
+	}
+	if !isManagedEndpointMonitor(&monitor, hostedcontrolplane) {
+		log.V(1).Info(fmt.Sprintf("Skipped deleting ClusterUrlMonitor %s/%s: not managed for the HostedControlPlane", monitor.Namespace, monitor.Name))
+		return nil
+	}
+	err = r.Client.Delete(ctx, &monitor)
+	if err != nil && !kerr.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isManagedEndpointMonitor reports whether the ClusterUrlMonitor was generated for the HostedControlPlane: it carries the
+// watchResourceLabel and the HostedControlPlane is its controller
+func isManagedEndpointMonitor(monitor *v1alpha1.ClusterUrlMonitor, hostedcontrolplane *hypershiftv1beta1.HostedControlPlane) bool {
+	_, managed := monitor.Labels[watchResourceLabel]
+	return managed && metav1.IsControlledBy(monitor, hostedcontrolplane)
+}
+
 // buildOwnerReferences generates a set OwnerReferences indicating the HostedControlPlane is the owner+controller of the object. This is used
 // to trigger reconciles against non-HCP objects (ie - the route & routemonitor generated by this controller)
 func buildOwnerReferences(hostedcontrolplane *hypershiftv1beta1.HostedControlPlane) []metav1.OwnerReference {
@@ -261,6 +438,10 @@ func (r *HostedControlPlaneReconciler) finalizeHostedControlPlane(ctx context.Co
 	if err != nil {
 		return fmt.Errorf("failed to cleanup internal monitoring resources: %w", err)
 	}
+	err = r.deleteEndpointMonitors(ctx, log, hostedcontrolplane)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup endpoint monitors: %w", err)
+	}
 	return nil
 }
 
@@ -306,7 +487,7 @@ func (r *HostedControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error
 
 	// The following:
 	// - Reconciles against all HostedControlPlane objects
-	// - Additionally watches against route & routemonitor objects with the 'watchResourceLabel' present.
+	// - Additionally watches against route, routemonitor & clusterurlmonitor objects with the 'watchResourceLabel' present.
 	//   When these objects are modified, the HCP specified in the objects' .metadata.OwnerReferences is
 	//   reconciled
 	return ctrl.NewControllerManagedBy(mgr).
@@ -321,5 +502,10 @@ func (r *HostedControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &hypershiftv1beta1.HostedControlPlane{}, handler.OnlyControllerOwner()),
 			builder.WithPredicates(selectorPredicate),
 		).
+		Watches(
+			&v1alpha1.ClusterUrlMonitor{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &hypershiftv1beta1.HostedControlPlane{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(selectorPredicate),
+		).
 		Complete(r)
 }
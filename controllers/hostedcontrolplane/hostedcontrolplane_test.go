@@ -219,9 +219,10 @@ func TestHostedControlPlaneReconciler_buildInternalMonitoringRouteMonitor(t *tes
 
 	// Testing
 	tests := []struct {
-		name string
-		args args
-		eval func(routemonitor v1alpha1.RouteMonitor) (passed bool, reason string)
+		name               string
+		args               args
+		dedicatedExporters bool
+		eval               func(routemonitor v1alpha1.RouteMonitor) (passed bool, reason string)
 	}{
 		// Cases
 		{
@@ -272,6 +273,37 @@ func TestHostedControlPlaneReconciler_buildInternalMonitoringRouteMonitor(t *tes
 				}
 				return true, ""
 			},
+		}, {
+			name: "routemonitor is probed by the shared exporter by default",
+			args: args{
+				route:              route,
+				hostedcontrolplane: &hcp,
+				apiServerPort:      6443,
+			},
+			eval: func(routemonitor v1alpha1.RouteMonitor) (passed bool, reason string) {
+				if routemonitor.Spec.DedicatedExporter {
+					return false, ".spec.dedicatedExporter is set without dedicated exporters"
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "routemonitor is probed by an exporter in the HostedControlPlane's namespace with dedicated exporters",
+			args: args{
+				route:              route,
+				hostedcontrolplane: &hcp,
+				apiServerPort:      6443,
+			},
+			dedicatedExporters: true,
+			eval: func(routemonitor v1alpha1.RouteMonitor) (passed bool, reason string) {
+				if !routemonitor.Spec.DedicatedExporter {
+					return false, ".spec.dedicatedExporter isn't set with dedicated exporters"
+				}
+				if routemonitor.Spec.ServiceMonitorType != v1alpha1.ServiceMonitorTypeRHOBS {
+					return false, ".spec.serviceMonitorType isn't RHOBS"
+				}
+				return true, ""
+			},
 		},
 	}
 
@@ -279,6 +311,7 @@ func TestHostedControlPlaneReconciler_buildInternalMonitoringRouteMonitor(t *tes
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := newTestReconciler(t)
+			r.DedicatedExporters = tt.dedicatedExporters
 			routemonitor := r.buildInternalMonitoringRouteMonitor(tt.args.route, tt.args.hostedcontrolplane, tt.args.apiServerPort)
 			passed, reason := tt.eval(routemonitor)
 			if !passed {
@@ -715,6 +748,366 @@ func TestHostedControlPlaneReconciler_deleteInternalMonitoringObjects(t *testing
 	}
 }
 
+func TestHostedControlPlaneReconciler_hostedClusterEndpointURL(t *testing.T) {
+	// Test-specific definitions
+	var (
+		prefix      = "api-prefix"
+		emptyPrefix = ""
+
+		hcp = hypershiftv1beta1.HostedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+				UID:       "test-uid",
+			},
+			Spec: hypershiftv1beta1.HostedControlPlaneSpec{
+				DNS: hypershiftv1beta1.DNSSpec{
+					BaseDomain: "example.com",
+				},
+			},
+			Status: hypershiftv1beta1.HostedControlPlaneStatus{
+				ControlPlaneEndpoint: hypershiftv1beta1.APIEndpoint{
+					Host: "api.test.example.com",
+					Port: 443,
+				},
+				OAuthCallbackURLTemplate: "https://oauth.test.example.com:443/oauth2callback/[identity-provider-name]",
+			},
+		}
+	)
+
+	// Testing
+	tests := []struct {
+		name     string
+		endpoint string
+		mutate   func(hcp *hypershiftv1beta1.HostedControlPlane)
+		want     string
+		wantErr  bool
+	}{
+		// Cases
+		{
+			name:     "kube-apiserver is probed at its livez endpoint",
+			endpoint: "kube-apiserver",
+			want:     "https://api.test.example.com:443/livez",
+		},
+		{
+			name:     "kube-apiserver isn't probed before its endpoint is published",
+			endpoint: "kube-apiserver",
+			mutate: func(hcp *hypershiftv1beta1.HostedControlPlane) {
+				hcp.Status.ControlPlaneEndpoint = hypershiftv1beta1.APIEndpoint{}
+			},
+			want: "",
+		},
+		{
+			name:     "oauth is probed at the healthz endpoint of the oauth server",
+			endpoint: "oauth",
+			want:     "https://oauth.test.example.com:443/healthz",
+		},
+		{
+			name:     "oauth isn't probed before its callback is published",
+			endpoint: "oauth",
+			mutate: func(hcp *hypershiftv1beta1.HostedControlPlane) {
+				hcp.Status.OAuthCallbackURLTemplate = ""
+			},
+			want: "",
+		},
+		{
+			name:     "console is probed under the ingress domain prefixed with the cluster's name",
+			endpoint: "console",
+			want:     "https://console-openshift-console.apps.test.example.com",
+		},
+		{
+			name:     "console is probed under the ingress domain prefixed with the base domain prefix",
+			endpoint: "console",
+			mutate: func(hcp *hypershiftv1beta1.HostedControlPlane) {
+				hcp.Spec.DNS.BaseDomainPrefix = &prefix
+			},
+			want: "https://console-openshift-console.apps.api-prefix.example.com",
+		},
+		{
+			name:     "console is probed under the base domain with an empty base domain prefix",
+			endpoint: "console",
+			mutate: func(hcp *hypershiftv1beta1.HostedControlPlane) {
+				hcp.Spec.DNS.BaseDomainPrefix = &emptyPrefix
+			},
+			want: "https://console-openshift-console.apps.example.com",
+		},
+		{
+			name:     "unknown endpoints are rejected",
+			endpoint: "ingress",
+			wantErr:  true,
+		},
+	}
+
+	// Execution
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostedcontrolplane := hcp.DeepCopy()
+			if tt.mutate != nil {
+				tt.mutate(hostedcontrolplane)
+			}
+			got, err := hostedClusterEndpointURL(hostedcontrolplane, tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("hostedClusterEndpointURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("hostedClusterEndpointURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostedControlPlaneReconciler_deployEndpointMonitors(t *testing.T) {
+	// Test-specific definitions
+	var (
+		ctx = context.TODO()
+		log = log.FromContext(ctx)
+
+		hcp = hypershiftv1beta1.HostedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: hypershiftv1beta1.HostedControlPlaneSpec{
+				DNS: hypershiftv1beta1.DNSSpec{
+					BaseDomain: "example.com",
+				},
+			},
+			Status: hypershiftv1beta1.HostedControlPlaneStatus{
+				ControlPlaneEndpoint: hypershiftv1beta1.APIEndpoint{
+					Host: "api.test.example.com",
+					Port: 443,
+				},
+			},
+		}
+	)
+
+	// Testing
+	tests := []struct {
+		name string
+		objs []client.Object
+		eval func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string)
+	}{
+		// Cases
+		{
+			name: "a clusterurlmonitor is created for each published endpoint",
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				clusterurlmonitors := v1alpha1.ClusterUrlMonitorList{}
+				err = r.List(ctx, &clusterurlmonitors)
+				if err != nil {
+					return false, fmt.Sprintf("failed to retrieve clusterurlmonitors from test client: %v", err)
+				}
+				// The oauth endpoint isn't published by the HostedControlPlane
+				if len(clusterurlmonitors.Items) != 2 {
+					return false, fmt.Sprintf("unexpected number of clusterurlmonitors found: expected 2, got %d. Clusterurlmonitors: %#v", len(clusterurlmonitors.Items), clusterurlmonitors)
+				}
+				for _, clusterurlmonitor := range clusterurlmonitors.Items {
+					if !clusterurlmonitor.Spec.IsHCP() {
+						return false, fmt.Sprintf("clusterurlmonitor %s isn't scraped as a hosted control plane's", clusterurlmonitor.Name)
+					}
+					if clusterurlmonitor.Spec.Slo.TargetAvailabilityPercent == "" {
+						return false, fmt.Sprintf("clusterurlmonitor %s has no slo", clusterurlmonitor.Name)
+					}
+					if len(clusterurlmonitor.OwnerReferences) != 1 {
+						return false, fmt.Sprintf("clusterurlmonitor %s isn't owned by the hostedcontrolplane", clusterurlmonitor.Name)
+					}
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "the clusterurlmonitor is updated when it already exists",
+			objs: []client.Object{
+				&v1alpha1.ClusterUrlMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-kube-apiserver",
+						Namespace:       "test",
+						OwnerReferences: buildOwnerReferences(&hcp),
+						Labels:          map[string]string{watchResourceLabel: "true", "labelWeExpectToBeRemoved": "true"},
+					},
+				},
+			},
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				result := v1alpha1.ClusterUrlMonitor{}
+				err = r.Get(ctx, types.NamespacedName{Name: "test-kube-apiserver", Namespace: "test"}, &result)
+				if err != nil {
+					return false, fmt.Sprintf("failed to retrieve clusterurlmonitor from test client: %v", err)
+				}
+				if _, found := result.Labels["labelWeExpectToBeRemoved"]; found {
+					return false, fmt.Sprintf("clusterurlmonitor was not updated; expected labels to not contain key 'labelWeExpectToBeRemoved', clusterurlmonitor has the following labels: %#v", result.Labels)
+				}
+				if result.Spec.RawURL != "https://api.test.example.com:443/livez" {
+					return false, fmt.Sprintf("clusterurlmonitor was not updated; unexpected url %s", result.Spec.RawURL)
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "a clusterurlmonitor of the same name not managed for the hostedcontrolplane is left alone",
+			objs: []client.Object{
+				&v1alpha1.ClusterUrlMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-kube-apiserver",
+						Namespace: "test",
+					},
+					Spec: v1alpha1.ClusterUrlMonitorSpec{
+						RawURL: "https://external.example.com",
+					},
+				},
+			},
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				result := v1alpha1.ClusterUrlMonitor{}
+				err = r.Get(ctx, types.NamespacedName{Name: "test-kube-apiserver", Namespace: "test"}, &result)
+				if err != nil {
+					return false, fmt.Sprintf("failed to retrieve clusterurlmonitor from test client: %v", err)
+				}
+				if result.Spec.RawURL != "https://external.example.com" || len(result.OwnerReferences) != 0 {
+					return false, fmt.Sprintf("clusterurlmonitor was taken over: %#v", result)
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "the clusterurlmonitor of an endpoint that isn't published anymore is deleted",
+			objs: []client.Object{
+				&v1alpha1.ClusterUrlMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-oauth",
+						Namespace:       "test",
+						OwnerReferences: buildOwnerReferences(&hcp),
+						Labels:          map[string]string{watchResourceLabel: "true"},
+					},
+				},
+			},
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				err = r.Get(ctx, types.NamespacedName{Name: "test-oauth", Namespace: "test"}, &v1alpha1.ClusterUrlMonitor{})
+				if !errors.IsNotFound(err) {
+					return false, fmt.Sprintf("expected clusterurlmonitor to be deleted, instead got err: %v", err)
+				}
+				return true, ""
+			},
+		},
+	}
+
+	// Execution
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, tt.objs...)
+			err := r.deployEndpointMonitors(ctx, log, &hcp)
+			passed, reason := tt.eval(err, r)
+			if !passed {
+				t.Errorf("HostedControlPlaneReconciler.deployEndpointMonitors() did not pass due to = %s", reason)
+			}
+		})
+	}
+}
+
+func TestHostedControlPlaneReconciler_deleteEndpointMonitors(t *testing.T) {
+	// Test-specific definitions
+	var (
+		ctx = context.TODO()
+		log = log.FromContext(ctx)
+		hcp = hypershiftv1beta1.HostedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+				UID:       "test-uid",
+			},
+		}
+
+		clusterurlmonitor = v1alpha1.ClusterUrlMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				// Name must match the expected clusterurlmonitor created by the deployEndpointMonitors() function
+				Name:            "test-console",
+				Namespace:       "test",
+				OwnerReferences: buildOwnerReferences(&hcp),
+				Labels:          map[string]string{watchResourceLabel: "true"},
+			},
+		}
+
+		// A clusterurlmonitor of the same name created by someone else
+		unmanagedClusterUrlMonitor = v1alpha1.ClusterUrlMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-console",
+				Namespace: "test",
+			},
+		}
+	)
+
+	// Testing
+	tests := []struct {
+		name string
+		objs []client.Object
+		eval func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string)
+	}{
+		// Cases
+		{
+			name: "the clusterurlmonitors present are deleted",
+			objs: []client.Object{
+				&clusterurlmonitor,
+			},
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				err = r.Client.Get(ctx, types.NamespacedName{Name: clusterurlmonitor.Name, Namespace: clusterurlmonitor.Namespace}, &v1alpha1.ClusterUrlMonitor{})
+				if !errors.IsNotFound(err) {
+					return false, fmt.Sprintf("expected clusterurlmonitor to be deleted, instead got err: %v", err)
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "clusterurlmonitors not managed for the hostedcontrolplane are kept",
+			objs: []client.Object{
+				&unmanagedClusterUrlMonitor,
+			},
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				err = r.Client.Get(ctx, types.NamespacedName{Name: unmanagedClusterUrlMonitor.Name, Namespace: unmanagedClusterUrlMonitor.Namespace}, &v1alpha1.ClusterUrlMonitor{})
+				if err != nil {
+					return false, fmt.Sprintf("expected clusterurlmonitor to be kept, instead got err: %v", err)
+				}
+				return true, ""
+			},
+		},
+		{
+			name: "no error is returned when no clusterurlmonitor exists",
+			eval: func(err error, r *HostedControlPlaneReconciler) (passed bool, reason string) {
+				if err != nil {
+					return false, fmt.Sprintf("unexpected error returned: %v", err)
+				}
+				return true, ""
+			},
+		},
+	}
+
+	// Execution
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, tt.objs...)
+			err := r.deleteEndpointMonitors(ctx, log, &hcp)
+			passed, reason := tt.eval(err, r)
+			if !passed {
+				t.Errorf("HostedControlPlaneReconciler.deleteEndpointMonitors() error = %v, did not pass due to = %s", err, reason)
+			}
+		})
+	}
+}
+
 // newTestReconciler creates a test client containing the following objects
 func newTestReconciler(t *testing.T, objs ...client.Object) *HostedControlPlaneReconciler {
 	var err error
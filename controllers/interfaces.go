@@ -4,6 +4,7 @@ import (
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
@@ -59,19 +60,25 @@ type MonitorResourceHandler interface {
 type ServiceMonitorHandler interface {
 	// UpdateServiceMonitorDeployment ensures that a ServiceMonitor deployment according
 	// to the template exists. If none exists, it will create a new one.
-	// If the template changed, it will update the existing deployment
-	UpdateServiceMonitorDeployment(template monitoringv1.ServiceMonitor) error
+	// If the template changed, it will update the existing deployment. A deployment that doesn't match the
+	// recordedUID, or belongs to a deleted owner of the same name, is recreated. It returns the UID of the deployment
+	UpdateServiceMonitorDeployment(template monitoringv1.ServiceMonitor, recordedUID types.UID) (types.UID, error)
 
-	// TemplateAndUpdateServiceMonitorDeployment will generate a template and then
-	// call UpdateServiceMonitorDeployment to ensure its current state matches the template.
-	TemplateAndUpdateServiceMonitorDeployment(url, blackBoxExporterNamespace string, namespacedName types.NamespacedName, clusterID string, hcp bool, useInsecure bool, owner *metav1.OwnerReference) error
+	// TemplateAndUpdateServiceMonitorDeployment will generate a template probing the urls with the
+	// given blackbox exporter module, one endpoint per url, and then call UpdateServiceMonitorDeployment
+	// to ensure its current state matches the template. The metricLabels are added to every scraped series,
+	// followed by the relabelings. Scrapes exceeding the sampleLimit or targetLimit fail, zero is unlimited.
+	// The exporter probing the urls is the operator's one in its namespace, unless an external one is set.
+	TemplateAndUpdateServiceMonitorDeployment(urls []string, exporter servicemonitor.Exporter, namespacedName types.NamespacedName, clusterID string, hcp bool, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference, recordedUID types.UID) (types.UID, error)
 
-	// DeleteServiceMonitorDeployment deletes a ServiceMonitor refrenced by a namespaced name
-	DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName, hcp bool) error
+	// DeleteServiceMonitorDeployment deletes the ServiceMonitor, Probe or ScrapeConfig referenced by a namespaced name,
+	// whichever kind and monitoring API it was generated with
+	DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName) error
 
 	// HypershiftUpdateServiceMonitorDeployment is for HyperShift cluster to ensure that a ServiceMonitor deployment according
-	// to the template exists. If none exists, it will create a new one. If the template changed, it will update the existing deployment
-	HypershiftUpdateServiceMonitorDeployment(template rhobsv1.ServiceMonitor) error
+	// to the template exists. If none exists, it will create a new one. If the template changed, it will update the existing deployment.
+	// Stale deployments are recreated as in UpdateServiceMonitorDeployment. It returns the UID of the deployment
+	HypershiftUpdateServiceMonitorDeployment(template rhobsv1.ServiceMonitor, recordedUID types.UID) (types.UID, error)
 }
 
 type PrometheusRuleHandler interface {
@@ -89,4 +96,11 @@ type BlackBoxExporterHandler interface {
 	EnsureBlackBoxExporterResourcesAbsent() error
 	ShouldDeleteBlackBoxExporterResources() (blackboxexporter.ShouldDeleteBlackBoxExporter, error)
 	GetBlackBoxExporterNamespace() string
+	// GetBlackBoxExporterRollout describes the rollout of the exporter, empty once every pod runs the current version
+	GetBlackBoxExporterRollout() (string, error)
+	// ServesBlackBoxExporterModule reports whether the available pods of the exporter were rolled out with the module
+	ServesBlackBoxExporterModule(module string) (bool, error)
+	// DebugBlackBoxExporterProbe probes the url with the module and the exporter's debug output. It returns whether the
+	// probe succeeded and its log
+	DebugBlackBoxExporterProbe(url, module string) (bool, string, error)
 }
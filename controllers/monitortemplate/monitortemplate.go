@@ -0,0 +1,266 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitortemplate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TemplateLabel marks the ConfigMaps RouteMonitors are generated from
+	TemplateLabel = "monitoring.openshift.io/route-monitor-template"
+
+	// GeneratedByLabel is set on generated RouteMonitors to the name of the ConfigMap they were generated from
+	GeneratedByLabel = "monitoring.openshift.io/generated-by"
+
+	// ValuesKey is the key of the values within a template ConfigMap
+	ValuesKey = "values.yaml"
+)
+
+var logger logr.Logger = ctrl.Log.WithName("controllers").WithName("MonitorTemplate")
+
+// Values are the contents of a template ConfigMap
+type Values struct {
+	// Spec is shared by every generated RouteMonitor
+	Spec v1alpha1.RouteMonitorSpec `json:"spec"`
+	// Routes are the Routes a RouteMonitor is generated for. Fields left empty are taken from
+	// spec.route, the namespace defaults to the one of the ConfigMap
+	Routes []v1alpha1.RouteMonitorRouteSpec `json:"routes"`
+}
+
+// MonitorTemplateReconciler generates RouteMonitors from template ConfigMaps
+type MonitorTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// NewMonitorTemplateReconciler creates a MonitorTemplateReconciler
+func NewMonitorTemplateReconciler(mgr manager.Manager) *MonitorTemplateReconciler {
+	return &MonitorTemplateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+// +kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitors,verbs=get;list;watch;create;update;delete
+
+// Reconcile generates the RouteMonitors of a template ConfigMap and removes the ones it no longer lists.
+// Deleting the ConfigMap removes its RouteMonitors through their OwnerReferences
+func (r *MonitorTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logger.WithName("Reconcile").WithValues("name", req.Name, "namespace", req.Namespace)
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, req.NamespacedName, configMap)
+	if err != nil {
+		if kerr.IsNotFound(err) {
+			log.V(logging.DebugVerbosity).Info("ConfigMap not found, assumed deleted")
+			return utilreconcile.Stop()
+		}
+		return utilreconcile.RequeueWith(err)
+	}
+	if configMap.DeletionTimestamp != nil {
+		return utilreconcile.Stop()
+	}
+
+	values, err := ParseValues(configMap)
+	if err != nil {
+		// Retrying doesn't fix the values, the next change of the ConfigMap triggers a reconcile
+		log.Error(err, "skipped generating RouteMonitors: invalid values")
+		return utilreconcile.Stop()
+	}
+
+	err = r.deployRouteMonitors(ctx, log, configMap, buildRouteMonitors(configMap, values))
+	if err != nil {
+		log.Error(err, "failed to deploy generated RouteMonitors")
+		return utilreconcile.RequeueWith(err)
+	}
+	return utilreconcile.Stop()
+}
+
+// ParseValues reads the values of a template ConfigMap
+func ParseValues(configMap *corev1.ConfigMap) (Values, error) {
+	values := Values{}
+	data, ok := configMap.Data[ValuesKey]
+	if !ok {
+		return values, fmt.Errorf("the ConfigMap has no '%s' key", ValuesKey)
+	}
+	if err := yaml.UnmarshalStrict([]byte(data), &values); err != nil {
+		return values, err
+	}
+	for i, route := range values.Routes {
+		if route.Name == "" {
+			return values, fmt.Errorf("route %d has no name", i)
+		}
+	}
+	return values, nil
+}
+
+// buildRouteMonitors constructs the RouteMonitors a template ConfigMap expands into
+func buildRouteMonitors(configMap *corev1.ConfigMap, values Values) []v1alpha1.RouteMonitor {
+	routeMonitors := []v1alpha1.RouteMonitor{}
+	for _, route := range values.Routes {
+		spec := *values.Spec.DeepCopy()
+		spec.Route = mergeRoute(values.Spec.Route, route, configMap.Namespace)
+		routeMonitors = append(routeMonitors, v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            generatedName(configMap, spec.Route),
+				Namespace:       configMap.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(configMap, corev1.SchemeGroupVersion.WithKind("ConfigMap"))},
+				Labels: map[string]string{
+					GeneratedByLabel: configMap.Name,
+				},
+			},
+			Spec: spec,
+		})
+	}
+	return routeMonitors
+}
+
+// mergeRoute fills the fields a route leaves empty from the shared route
+func mergeRoute(shared, route v1alpha1.RouteMonitorRouteSpec, namespace string) v1alpha1.RouteMonitorRouteSpec {
+	if route.Namespace == "" {
+		route.Namespace = shared.Namespace
+	}
+	if route.Namespace == "" {
+		route.Namespace = namespace
+	}
+	if route.Port == 0 {
+		route.Port = shared.Port
+	}
+	if route.Suffix == "" {
+		route.Suffix = shared.Suffix
+	}
+	if route.RouterName == "" {
+		route.RouterName = shared.RouterName
+	}
+	if !route.ProbeAllIngresses {
+		route.ProbeAllIngresses = shared.ProbeAllIngresses
+	}
+	if route.Scheme == "" {
+		route.Scheme = shared.Scheme
+	}
+	return route
+}
+
+// generatedName names a generated RouteMonitor after its ConfigMap and Route, including the Route's
+// namespace if it differs from the ConfigMap's, so Routes of the same name don't collide
+func generatedName(configMap *corev1.ConfigMap, route v1alpha1.RouteMonitorRouteSpec) string {
+	if route.Namespace != configMap.Namespace {
+		return strings.Join([]string{configMap.Name, route.Namespace, route.Name}, "-")
+	}
+	return strings.Join([]string{configMap.Name, route.Name}, "-")
+}
+
+// deployRouteMonitors creates or updates the expected RouteMonitors and deletes the ones previously
+// generated from the ConfigMap that aren't expected anymore
+func (r *MonitorTemplateReconciler) deployRouteMonitors(ctx context.Context, log logr.Logger, configMap *corev1.ConfigMap, expected []v1alpha1.RouteMonitor) error {
+	expectedNames := map[string]bool{}
+	for i := range expected {
+		expectedRouteMonitor := expected[i]
+		expectedNames[expectedRouteMonitor.Name] = true
+		err := r.Client.Create(ctx, &expectedRouteMonitor)
+		if err == nil {
+			continue
+		}
+		if !kerr.IsAlreadyExists(err) {
+			return err
+		}
+		// Object already exists: update it
+		actualRouteMonitor := v1alpha1.RouteMonitor{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: expectedRouteMonitor.Name, Namespace: expectedRouteMonitor.Namespace}, &actualRouteMonitor)
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(&actualRouteMonitor, configMap) {
+			return fmt.Errorf("RouteMonitor %s/%s already exists and wasn't generated from the ConfigMap", actualRouteMonitor.Namespace, actualRouteMonitor.Name)
+		}
+		if reflect.DeepEqual(actualRouteMonitor.Spec, expectedRouteMonitor.Spec) &&
+			reflect.DeepEqual(actualRouteMonitor.Labels, expectedRouteMonitor.Labels) {
+			continue
+		}
+		actualRouteMonitor.Labels = expectedRouteMonitor.Labels
+		actualRouteMonitor.Spec = expectedRouteMonitor.Spec
+		err = r.Client.Update(ctx, &actualRouteMonitor)
+		if err != nil {
+			return err
+		}
+	}
+
+	generated := v1alpha1.RouteMonitorList{}
+	err := r.Client.List(ctx, &generated, client.InNamespace(configMap.Namespace), client.MatchingLabels{GeneratedByLabel: configMap.Name})
+	if err != nil {
+		return err
+	}
+	for i := range generated.Items {
+		routeMonitor := &generated.Items[i]
+		if expectedNames[routeMonitor.Name] || !metav1.IsControlledBy(routeMonitor, configMap) {
+			continue
+		}
+		err = r.Client.Delete(ctx, routeMonitor)
+		if err != nil && !kerr.IsNotFound(err) {
+			return err
+		}
+		log.Info(fmt.Sprintf("Deleted RouteMonitor %s/%s: no longer listed by the ConfigMap", routeMonitor.Namespace, routeMonitor.Name))
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MonitorTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	selector := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      TemplateLabel,
+				Operator: metav1.LabelSelectorOpExists,
+			},
+		},
+	}
+	selectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return fmt.Errorf("failed to build label selector predicate for configmaps: %w", err)
+	}
+
+	// Reconciles against the template ConfigMaps, and the RouteMonitors they control so changes
+	// to generated RouteMonitors are reverted
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("monitortemplate").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(selectorPredicate)).
+		Owns(&v1alpha1.RouteMonitor{}).
+		Complete(r)
+}
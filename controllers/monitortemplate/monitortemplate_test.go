@@ -0,0 +1,204 @@
+package monitortemplate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const testValues = `
+spec:
+  route:
+    suffix: /healthz
+  slo:
+    targetAvailabilityPercent: "99.5"
+routes:
+- name: frontend
+- name: backend
+  namespace: other
+  suffix: /livez
+`
+
+func newTestConfigMap(values string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shop",
+			Namespace: "test",
+			UID:       "shop-uid",
+			Labels:    map[string]string{TemplateLabel: "true"},
+		},
+		Data: map[string]string{ValuesKey: values},
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "values are parsed",
+			data: map[string]string{ValuesKey: testValues},
+		},
+		{
+			name:    "values key is missing",
+			data:    map[string]string{"other.yaml": testValues},
+			wantErr: true,
+		},
+		{
+			name:    "values contain an unknown field",
+			data:    map[string]string{ValuesKey: "routes:\n- name: frontend\n  host: example.com\n"},
+			wantErr: true,
+		},
+		{
+			name:    "a route has no name",
+			data:    map[string]string{ValuesKey: "routes:\n- namespace: test\n"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newTestConfigMap("")
+			configMap.Data = tt.data
+			_, err := ParseValues(configMap)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_buildRouteMonitors(t *testing.T) {
+	configMap := newTestConfigMap(testValues)
+	values, err := ParseValues(configMap)
+	if err != nil {
+		t.Fatalf("failed to parse test values: %v", err)
+	}
+
+	routeMonitors := buildRouteMonitors(configMap, values)
+	if len(routeMonitors) != 2 {
+		t.Fatalf("unexpected number of RouteMonitors: expected 2, got %d", len(routeMonitors))
+	}
+
+	want := []struct {
+		name  string
+		route v1alpha1.RouteMonitorRouteSpec
+	}{
+		{name: "shop-frontend", route: v1alpha1.RouteMonitorRouteSpec{Name: "frontend", Namespace: "test", Suffix: "/healthz"}},
+		{name: "shop-other-backend", route: v1alpha1.RouteMonitorRouteSpec{Name: "backend", Namespace: "other", Suffix: "/livez"}},
+	}
+	for i, w := range want {
+		routeMonitor := routeMonitors[i]
+		if routeMonitor.Name != w.name || routeMonitor.Namespace != "test" {
+			t.Errorf("RouteMonitor %d is named %s/%s, want test/%s", i, routeMonitor.Namespace, routeMonitor.Name, w.name)
+		}
+		if !reflect.DeepEqual(routeMonitor.Spec.Route, w.route) {
+			t.Errorf("RouteMonitor %d route = %#v, want %#v", i, routeMonitor.Spec.Route, w.route)
+		}
+		if routeMonitor.Spec.Slo.TargetAvailabilityPercent != "99.5" {
+			t.Errorf("RouteMonitor %d didn't inherit the shared slo: %#v", i, routeMonitor.Spec.Slo)
+		}
+		if !metav1.IsControlledBy(&routeMonitor, configMap) {
+			t.Errorf("RouteMonitor %d isn't controlled by the ConfigMap: %#v", i, routeMonitor.OwnerReferences)
+		}
+		if routeMonitor.Labels[GeneratedByLabel] != configMap.Name {
+			t.Errorf("RouteMonitor %d isn't labeled with its ConfigMap: %#v", i, routeMonitor.Labels)
+		}
+	}
+}
+
+func TestMonitorTemplateReconciler_deployRouteMonitors(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		log       = log.FromContext(ctx)
+		configMap = newTestConfigMap(testValues)
+		values, _ = ParseValues(configMap)
+		expected  = buildRouteMonitors(configMap, values)
+		owner     = []metav1.OwnerReference{*metav1.NewControllerRef(configMap, corev1.SchemeGroupVersion.WithKind("ConfigMap"))}
+	)
+
+	tests := []struct {
+		name      string
+		objs      []client.Object
+		wantErr   bool
+		wantNames []string
+	}{
+		{
+			name:      "RouteMonitors are created when none exist",
+			wantNames: []string{"shop-frontend", "shop-other-backend"},
+		},
+		{
+			name: "generated RouteMonitors are updated and pruned",
+			objs: []client.Object{
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop-frontend", Namespace: "test", OwnerReferences: owner, Labels: map[string]string{GeneratedByLabel: "shop"}},
+				},
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop-removed", Namespace: "test", OwnerReferences: owner, Labels: map[string]string{GeneratedByLabel: "shop"}},
+				},
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "test"},
+				},
+			},
+			wantNames: []string{"shop-frontend", "shop-other-backend", "unrelated"},
+		},
+		{
+			name: "RouteMonitors that weren't generated aren't taken over",
+			objs: []client.Object{
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop-frontend", Namespace: "test"},
+				},
+			},
+			wantErr:   true,
+			wantNames: []string{"shop-frontend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, tt.objs...)
+			err := r.deployRouteMonitors(ctx, log, configMap, expected)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deployRouteMonitors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			routeMonitors := v1alpha1.RouteMonitorList{}
+			if err := r.List(ctx, &routeMonitors); err != nil {
+				t.Fatalf("failed to retrieve routemonitors from test client: %v", err)
+			}
+			names := []string{}
+			for _, routeMonitor := range routeMonitors.Items {
+				names = append(names, routeMonitor.Name)
+				if routeMonitor.Name == "shop-frontend" && !tt.wantErr && routeMonitor.Spec.Route.Suffix != "/healthz" {
+					t.Errorf("RouteMonitor shop-frontend wasn't updated: %#v", routeMonitor.Spec)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("RouteMonitors = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *MonitorTemplateReconciler {
+	s := scheme.Scheme
+	if err := v1alpha1.AddToScheme(s); err != nil {
+		t.Errorf("failed to add v1alpha1 to scheme: %v", err)
+	}
+
+	client := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	return &MonitorTemplateReconciler{
+		Client: client,
+		Scheme: s,
+	}
+}
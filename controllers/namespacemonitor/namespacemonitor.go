@@ -0,0 +1,370 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacemonitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NamespaceMonitorReconciler reconciles a NamespaceMonitor object
+type NamespaceMonitorReconciler struct {
+	Client client.Client
+	Ctx    context.Context
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	BlackBoxExporter controllers.BlackBoxExporterHandler
+	ServiceMonitor   controllers.ServiceMonitorHandler
+	Prom             controllers.PrometheusRuleHandler
+	Common           controllers.MonitorResourceHandler
+
+	controllers.ReconcilerOptions
+
+	// Recorder emits Events about the monitors
+	Recorder record.EventRecorder
+
+	// DryRunClient, if set, dry-runs the writes of the generated resources and the NamespaceMonitors, except their status
+	DryRunClient *reconcileCommon.DryRunClient
+}
+
+// sloChangeAlertDuration returns how long the alert on a lowered availability target fires
+func (r *NamespaceMonitorReconciler) sloChangeAlertDuration() time.Duration {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloChangeAlertDuration
+	}
+	return r.SloChangeAlertDuration
+}
+
+// slo returns the SLO the monitor is held to, which is the default SLO if it doesn't set one
+func (r *NamespaceMonitorReconciler) slo(slo monitoringv1alpha1.SloSpec) monitoringv1alpha1.SloSpec {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloFor(slo)
+	}
+	return slo
+}
+
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *NamespaceMonitorReconciler {
+	log := ctrl.Log.WithName("controllers").WithName("NamespaceMonitor")
+	client := mgr.GetClient()
+	ctx := context.Background()
+	recorder := mgr.GetEventRecorderFor(config.OperatorName)
+	var dryRun *reconcileCommon.DryRunClient
+	if opts.DryRun {
+		dryRun = reconcileCommon.NewDryRunClient(client, recorder, log.WithName("DryRun"))
+		client = dryRun
+	}
+	common := reconcileCommon.NewMonitorResourceCommon(ctx, client)
+	common.DryRun = opts.DryRun
+	if opts.ClusterIdentity != nil {
+		common.ClusterIdentity = opts.ClusterIdentity
+	}
+	blackBoxExporter := blackboxexporter.New(client, log, ctx, opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	blackBoxExporter.FIPS = opts.FIPSMode
+	blackBoxExporter.Deployment = opts.BlackBoxExporterDeployment
+	blackBoxExporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	blackBoxExporter.RuntimeConfig = opts.RuntimeConfig
+	blackBoxExporter.FieldOwner = opts.FieldOwner
+	return &NamespaceMonitorReconciler{
+		Client:           client,
+		Ctx:              ctx,
+		Log:              log,
+		Scheme:           mgr.GetScheme(),
+		BlackBoxExporter: blackBoxExporter,
+		ServiceMonitor: &servicemonitor.ServiceMonitor{
+			Client:                client,
+			Ctx:                   ctx,
+			Comparer:              &reconcileCommon.ResourceComparer{},
+			LabelOwnedResources:   opts.LabelOwnedResources,
+			Scrubber:              opts.ScrubbedMetadata,
+			FieldOwner:            opts.FieldOwner,
+			ProbeResources:        opts.ProbeResources,
+			ScrapeConfigResources: opts.ScrapeConfigResources,
+			ZoneLabels:            opts.ZoneLabels,
+			NodeLabels:            opts.BlackBoxExporterDeployment.DaemonSet,
+			TLS:                   opts.BlackBoxExporterDeployment.ServingCert,
+			MeshTLS:               opts.BlackBoxExporterDeployment.MeshSidecar(),
+			Recorder:              recorder,
+		},
+		Prom: &alert.PrometheusRule{
+			Client:     client,
+			Ctx:        ctx,
+			Comparer:   &reconcileCommon.ResourceComparer{},
+			Scrubber:   opts.ScrubbedMetadata,
+			FieldOwner: opts.FieldOwner,
+		},
+		Common:            common,
+		ReconcilerOptions: opts,
+		Recorder:          recorder,
+		DryRunClient:      dryRun,
+	}
+}
+
+const (
+	FinalizerKey string = "namespacemonitor.routemonitoroperator.monitoring.openshift.io/finalizer"
+)
+
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=namespacemonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=namespacemonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
+
+func (r *NamespaceMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Ctx = ctx
+	reconcileLog := logging.ForReconcile(r.Log.WithName("Reconcile"), "NamespaceMonitor", req.NamespacedName)
+
+	log := logging.EnterStep(reconcileLog, "GetNamespaceMonitor")
+	namespaceMonitor, res, err := r.GetNamespaceMonitor(req)
+	if err != nil {
+		log.Error(err, "Failed to retrieve NamespaceMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		return res.ReturnWith(nil)
+	}
+	if !r.Scope.Matches(&namespaceMonitor) {
+		log.V(logging.DebugVerbosity).Info("Skipping the NamespaceMonitor: it's outside of the operator's scope")
+		return utilreconcile.Stop()
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureMonitorAndDependenciesAbsent")
+	res, err = r.EnsureMonitorAndDependenciesAbsent(namespaceMonitor)
+	if err != nil {
+		log.Error(err, "Failed to delete NamespaceMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully deleted NamespaceMonitor. Finished Reconcile")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureFinalizerSet")
+	res, err = r.EnsureFinalizerSet(namespaceMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set NamespaceMonitor's Finalizer. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully set NamespaceMonitor finalizers. Stopping...")
+		return res.ReturnWith(nil)
+	}
+
+	// The remaining steps record their outcome in the status, which is written once they're done
+	return controllers.ReconcileStatus(r.Common, r.DryRunClient, reconcileLog, &namespaceMonitor, &namespaceMonitor.Status.Conditions, func() (ctrl.Result, error) {
+		return r.ensureMonitorResources(reconcileLog, &namespaceMonitor)
+	})
+}
+
+// ensureMonitorResources deploys the blackbox exporter and the resources generated for the NamespaceMonitor
+func (r *NamespaceMonitorReconciler) ensureMonitorResources(reconcileLog logr.Logger, namespaceMonitor *monitoringv1alpha1.NamespaceMonitor) (ctrl.Result, error) {
+	log := logging.EnterStep(reconcileLog, "EnsureRouteURLsExist")
+	res, err := r.EnsureRouteURLsExist(namespaceMonitor)
+	if err != nil {
+		log.Error(err, "Failed to list the Routes of the namespace. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterResourcesExist")
+	err = r.BlackBoxExporter.EnsureBlackBoxExporterResourcesExist()
+	if err != nil {
+		log.Error(err, "Failed to create BlackBoxExporter. Requeueing...")
+		blackboxexporter.ReportInvalidConfig(r.Recorder, namespaceMonitor, &namespaceMonitor.Status.Conditions, err)
+		return utilreconcile.RequeueWith(err)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureServiceMonitorExists")
+	res, err = r.EnsureServiceMonitorExists(namespaceMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set ServiceMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsurePrometheusRuleExists")
+	res, err = r.EnsurePrometheusRuleExists(namespaceMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set PrometheusRule. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterRolledOut")
+	res, err = controllers.EnsureBlackBoxExporterRolledOut(r.BlackBoxExporter, &namespaceMonitor.Status.Conditions, namespaceMonitor.Generation)
+	if err != nil {
+		log.Error(err, "Failed to get the rollout of the BlackBoxExporter. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	reconcileLog.Info("All operations for NamespaceMonitor completed. Finished Reconcile.")
+	return utilreconcile.Stop()
+}
+
+func (r *NamespaceMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1alpha1.NamespaceMonitor{}, builder.WithPredicates(controllers.MonitorChanged, r.Scope.Predicate())).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter()}).
+		Watches(
+			&monitoringv1.ServiceMonitor{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.NamespaceMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		).
+		// Routes coming and going change the probed urls of the NamespaceMonitors of their namespace
+		Watches(&routev1.Route{}, handler.EnqueueRequestsFromMapFunc(r.namespaceMonitorsOf))
+	if r.ProbeResources {
+		bldr = bldr.Watches(
+			&monitoringv1.Probe{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.NamespaceMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.ScrapeConfigResources {
+		bldr = bldr.Watches(
+			&scrapeconfig.ScrapeConfig{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.NamespaceMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.VerifyInterval > 0 {
+		verifier := reconcileCommon.NewResourceVerifier(r.Client, r.Recorder, r.Log.WithName("Verify"), r.VerifyInterval, r.listNamespaceMonitors, r.generatedResources)
+		if err := mgr.Add(verifier); err != nil {
+			return err
+		}
+		bldr = bldr.WatchesRawSource(verifier.Source(), &handler.EnqueueRequestForObject{})
+	}
+	if r.RuntimeConfig != nil {
+		// Puts changed exporter settings and SLO defaults into effect without waiting for the next reconcile
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listNamespaceMonitors, r.Log))
+	}
+	if r.ProbeHealth != nil {
+		reporter := probehealth.NewReporter(r.Client, r.ProbeHealth, r.Log.WithName("ProbeHealth"), r.ProbeHealthInterval, r.listNamespaceMonitors, r.probeHealthTarget)
+		if err := mgr.Add(reporter); err != nil {
+			return err
+		}
+	}
+	return bldr.Complete(r)
+}
+
+// namespaceMonitorsOf requeues the NamespaceMonitors of the namespace of a Route
+func (r *NamespaceMonitorReconciler) namespaceMonitorsOf(ctx context.Context, o client.Object) []reconcile.Request {
+	list := &monitoringv1alpha1.NamespaceMonitorList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(o.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list NamespaceMonitors", "namespace", o.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, namespaceMonitor := range list.Items {
+		if !r.Scope.Matches(&namespaceMonitor) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: namespaceMonitor.Name, Namespace: namespaceMonitor.Namespace}})
+	}
+	return requests
+}
+
+// listNamespaceMonitors returns the NamespaceMonitors that aren't being deleted
+func (r *NamespaceMonitorReconciler) listNamespaceMonitors(ctx context.Context) ([]client.Object, error) {
+	list := &monitoringv1alpha1.NamespaceMonitorList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	monitors := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp == nil && r.Scope.Matches(&list.Items[i]) {
+			monitors = append(monitors, &list.Items[i])
+		}
+	}
+	return monitors, nil
+}
+
+// generatedResources returns the resources referenced by the status of a NamespaceMonitor
+func (r *NamespaceMonitorReconciler) generatedResources(o client.Object) []client.Object {
+	namespaceMonitor := o.(*monitoringv1alpha1.NamespaceMonitor)
+	resources := []client.Object{}
+	if ref := namespaceMonitor.Status.ServiceMonitorRef; ref.Name != "" {
+		meta := metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}
+		switch {
+		case r.ScrapeConfigResources:
+			resources = append(resources, &scrapeconfig.ScrapeConfig{ObjectMeta: meta})
+		case r.ProbeResources:
+			resources = append(resources, &monitoringv1.Probe{ObjectMeta: meta})
+		default:
+			resources = append(resources, &monitoringv1.ServiceMonitor{ObjectMeta: meta})
+		}
+	}
+	if ref := namespaceMonitor.Status.PrometheusRuleRef; ref.Name != "" {
+		resources = append(resources, &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return resources
+}
+
+// probeHealthTarget returns the probes of a NamespaceMonitor whose health is reported
+func (r *NamespaceMonitorReconciler) probeHealthTarget(o client.Object) (probehealth.Target, bool) {
+	namespaceMonitor := o.(*monitoringv1alpha1.NamespaceMonitor)
+	if len(namespaceMonitor.Status.RouteURLs) == 0 {
+		return probehealth.Target{}, false
+	}
+	return probehealth.Target{
+		URLs:   namespaceMonitor.Status.RouteURLs,
+		Slo:    r.slo(namespaceMonitor.Spec.Slo),
+		Health: &namespaceMonitor.Status.ProbeHealth,
+	}, true
+}
@@ -0,0 +1,13 @@
+package namespacemonitor_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestNamespacemonitor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Namespacemonitor Suite")
+}
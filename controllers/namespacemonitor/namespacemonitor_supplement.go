@@ -0,0 +1,277 @@
+package namespacemonitor
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	routev1 "github.com/openshift/api/route/v1"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Takes care that right PrometheusRules for the defined NamespaceMonitor are in place. The outcome is recorded
+// in the NamespaceMonitor's status, which is written by the caller
+func (s *NamespaceMonitorReconciler) EnsurePrometheusRuleExists(namespaceMonitor *v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	slo := s.slo(namespaceMonitor.Spec.Slo)
+
+	// Keep track of changes of the availability target before acting on them
+	alert.ObserveSloChange(s.Recorder, "NamespaceMonitor", namespaceMonitor, slo, &namespaceMonitor.Status.SloTarget, &namespaceMonitor.Status.LastSloChange)
+
+	// If .spec.skipPrometheusRule is true, or there is no Route to alert on, ensure that the PrometheusRule does NOT exist
+	if namespaceMonitor.Spec.SkipPrometheusRule || len(namespaceMonitor.Status.RouteURLs) == 0 {
+		if err := s.Prom.DeletePrometheusRuleDeployment(namespaceMonitor.Status.PrometheusRuleRef); err != nil {
+			return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+		}
+		_, _ = s.Common.SetResourceReference(&namespaceMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+		return utilreconcile.ContinueReconcile()
+	}
+
+	parsedSlo, err := s.Common.ParseMonitorSLOSpecs(namespaceMonitor.Status.RouteURLs[0], slo)
+	s.Common.SetErrorStatus(&namespaceMonitor.Status.ErrorStatus, err)
+	if parsedSlo == "" {
+		deleteErr := s.Prom.DeletePrometheusRuleDeployment(namespaceMonitor.Status.PrometheusRuleRef)
+		if deleteErr != nil {
+			return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionPrometheusRuleReady, deleteErr)
+		}
+		_, _ = s.Common.SetResourceReference(&namespaceMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		// An invalid SLO fails the PrometheusRule, without an SLO none is needed
+		if err != nil {
+			reconcileCommon.SetArtifactCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, namespaceMonitor.Generation, err)
+		} else {
+			meta.RemoveStatusCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+		}
+		return utilreconcile.StopReconcile()
+	}
+
+	namespacedName := types.NamespacedName{Namespace: namespaceMonitor.Namespace, Name: namespaceMonitor.Name}
+	ruleName := reconcileCommon.GeneratedResourceName(namespaceMonitor.Status.PrometheusRuleRef, "NamespaceMonitor", namespacedName)
+	template, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+		URL:                    "the Routes of namespace " + namespaceMonitor.Namespace,
+		URLs:                   namespaceMonitor.Status.RouteURLs,
+		Percent:                parsedSlo,
+		Slo:                    slo,
+		NamespacedName:         namespacedName,
+		Created:                namespaceMonitor.CreationTimestamp.Time,
+		MaintenanceWindows:     namespaceMonitor.Spec.MaintenanceWindows,
+		LastSloChange:          namespaceMonitor.Status.LastSloChange,
+		SloChangeAlertDuration: s.sloChangeAlertDuration(),
+		Owner:                  metav1.NewControllerRef(&namespaceMonitor.ObjectMeta, namespaceMonitor.GroupVersionKind()),
+	})
+	if err != nil {
+		return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+	if s.LabelOwnedResources {
+		template.Labels = consts.OwnerLabels("NamespaceMonitor", namespaceMonitor.Name)
+	}
+	// Alerts keep the name of the monitor, only the resource is named uniquely
+	template.Name = ruleName.Name
+	if err := s.Prom.UpdatePrometheusRuleDeployment(template); err != nil {
+		return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+
+	_, _ = s.Common.SetResourceReference(&namespaceMonitor.Status.PrometheusRuleRef, ruleName)
+	reconcileCommon.SetArtifactCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, namespaceMonitor.Generation, nil)
+	return utilreconcile.ContinueReconcile()
+}
+
+// Takes care that right ServiceMonitor for the defined NamespaceMonitor are in place. The outcome is recorded
+// in the NamespaceMonitor's status, which is written by the caller
+func (s *NamespaceMonitorReconciler) EnsureServiceMonitorExists(namespaceMonitor *v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	// Without a Route to probe, ensure that the ServiceMonitor does NOT exist
+	if len(namespaceMonitor.Status.RouteURLs) == 0 {
+		if err := s.ServiceMonitor.DeleteServiceMonitorDeployment(namespaceMonitor.Status.ServiceMonitorRef); err != nil {
+			return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+		}
+		_, _ = s.Common.SetResourceReference(&namespaceMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+		return utilreconcile.ContinueReconcile()
+	}
+
+	id, err := s.Common.GetOSDClusterID()
+	if err != nil {
+		return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	namespacedName := reconcileCommon.GeneratedResourceName(namespaceMonitor.Status.ServiceMonitorRef, "NamespaceMonitor", types.NamespacedName{Name: namespaceMonitor.Name, Namespace: namespaceMonitor.Namespace})
+	spec := namespaceMonitor.Spec
+	owner := metav1.NewControllerRef(&namespaceMonitor.ObjectMeta, namespaceMonitor.GroupVersionKind())
+	module, definition := blackboxexporter.ModuleFor(spec.InsecureSkipTLSVerify, spec.HTTPProbe)
+	if err := definition.ValidateRequired(s.FIPSMode); err != nil {
+		return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	res, err := controllers.EnsureBlackBoxExporterServesModule(s.BlackBoxExporter, s.DryRunClient, module, &namespaceMonitor.Status.Conditions, namespaceMonitor.Generation)
+	if err != nil || res.ShouldStop() {
+		return res, err
+	}
+	uid, err := s.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment(namespaceMonitor.Status.RouteURLs, servicemonitor.Exporter{Namespace: s.BlackBoxExporter.GetBlackBoxExporterNamespace()}, namespacedName, id, false, module, spec.MetricLabels, nil, 0, spec.TargetLimit, owner, namespaceMonitor.Status.ServiceMonitorRef.UID)
+	if err != nil {
+		return s.artifactFailed(namespaceMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	if _, err := s.Common.SetResourceReference(&namespaceMonitor.Status.ServiceMonitorRef, namespacedName); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	// Record the UID, so a ServiceMonitor recreated by someone else isn't adopted
+	if uid != "" {
+		namespaceMonitor.Status.ServiceMonitorRef.UID = uid
+	}
+	reconcileCommon.SetArtifactCondition(&namespaceMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady, namespaceMonitor.Generation, nil)
+	return utilreconcile.ContinueReconcile()
+}
+
+// EnsureRouteURLsExist verifies that .status.routeURLs holds the urls of the admitted Routes of the namespace
+func (s *NamespaceMonitorReconciler) EnsureRouteURLsExist(namespaceMonitor *v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	selector := labels.Everything()
+	if namespaceMonitor.Spec.RouteSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(namespaceMonitor.Spec.RouteSelector)
+		if err != nil {
+			// Retrying doesn't fix the selector, the next change of the NamespaceMonitor triggers a reconcile
+			s.Common.SetErrorStatus(&namespaceMonitor.Status.ErrorStatus, fmt.Errorf("invalid routeSelector: %w", err))
+			return utilreconcile.StopReconcile()
+		}
+	}
+	routes := routev1.RouteList{}
+	err := s.Client.List(s.Ctx, &routes, client.InNamespace(namespaceMonitor.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+
+	routeURLs := routeURLsFor(routes.Items, namespaceMonitor.Spec)
+	if slices.Equal(namespaceMonitor.Status.RouteURLs, routeURLs) {
+		return utilreconcile.ContinueReconcile()
+	}
+	s.Log.V(logging.TraceVerbosity).Info("Probed Routes changed", "namespace", namespaceMonitor.Namespace, "routes", len(routeURLs))
+	namespaceMonitor.Status.RouteURLs = routeURLs
+	return utilreconcile.ContinueReconcile()
+}
+
+// routeURLsFor returns the sorted urls probing the Routes, one per Route. Routes that weren't admitted by a
+// router yet have no host and are left out until they are
+func routeURLsFor(routes []routev1.Route, spec v1alpha1.NamespaceMonitorSpec) []string {
+	routeURLs := []string{}
+	for _, route := range routes {
+		if route.DeletionTimestamp != nil || len(route.Status.Ingress) == 0 || route.Status.Ingress[0].Host == "" {
+			continue
+		}
+		routeURLs = append(routeURLs, routeURLFor(route.Status.Ingress[0].Host, route, spec))
+	}
+	slices.Sort(routeURLs)
+	return slices.Compact(routeURLs)
+}
+
+// routeURLFor returns the url probing a host of the Route, with the scheme the NamespaceMonitor forces or https
+// if the Route has TLS configured
+func routeURLFor(host string, route routev1.Route, spec v1alpha1.NamespaceMonitorSpec) string {
+	routeURL := host + spec.Suffix
+	if spec.Scheme != "" {
+		return fmt.Sprintf("%s://%s", spec.Scheme, routeURL)
+	}
+	if route.Spec.TLS != nil {
+		routeURL = fmt.Sprintf("https://%s", routeURL)
+	}
+	return routeURL
+}
+
+// artifactFailed reports the failure of a generated resource in the NamespaceMonitor's status, so it's
+// visible which one needs attention. It requeues with the failure unless retrying can't fix it
+func (s *NamespaceMonitorReconciler) artifactFailed(namespaceMonitor *v1alpha1.NamespaceMonitor, conditionType string, err error) (utilreconcile.Result, error) {
+	reconcileCommon.SetArtifactCondition(&namespaceMonitor.Status.Conditions, conditionType, namespaceMonitor.Generation, err)
+	metrics.ReconcileErrors.WithLabelValues("NamespaceMonitor", string(customerrors.ClassOf(err))).Inc()
+	return utilreconcile.FailReconcileWith(err)
+}
+
+// Ensures that all dependencies related to a NamespaceMonitor are deleted
+func (s *NamespaceMonitorReconciler) EnsureMonitorAndDependenciesAbsent(namespaceMonitor v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	if namespaceMonitor.DeletionTimestamp == nil {
+		return utilreconcile.ContinueReconcile()
+	}
+
+	reason, err := reconcileCommon.CleanupSkipReason(s.Ctx, s.Client, &namespaceMonitor)
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if reason != "" {
+		reconcileCommon.Eventf(s.Recorder, &namespaceMonitor, corev1.EventTypeWarning, reconcileCommon.CleanupSkippedReason,
+			"Dropping the finalizer without deleting the generated resources: %s", reason)
+		return s.ensureFinalizerAbsent(namespaceMonitor)
+	}
+
+	// The resources controlled by the NamespaceMonitor are deleted with it by the garbage collector
+	resources := s.generatedResources(&namespaceMonitor)
+	if err := reconcileCommon.DeleteUncollected(s.Ctx, s.Client, &namespaceMonitor, resources...); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+
+	shouldDelete, err := s.BlackBoxExporter.ShouldDeleteBlackBoxExporterResources()
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if shouldDelete == blackboxexporterconsts.DeleteBlackBoxExporter {
+		err := s.BlackBoxExporter.EnsureBlackBoxExporterResourcesAbsent()
+		if err != nil {
+			return utilreconcile.RequeueReconcileWith(err)
+		}
+	}
+
+	return s.ensureFinalizerAbsent(namespaceMonitor)
+}
+
+// ensureFinalizerAbsent drops the finalizer of the NamespaceMonitor, so its deletion completes
+func (s *NamespaceMonitorReconciler) ensureFinalizerAbsent(namespaceMonitor v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	if s.Common.DeleteFinalizer(&namespaceMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&namespaceMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+func (s *NamespaceMonitorReconciler) EnsureFinalizerSet(namespaceMonitor v1alpha1.NamespaceMonitor) (utilreconcile.Result, error) {
+	if s.Common.SetFinalizer(&namespaceMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&namespaceMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+// GetNamespaceMonitor return the NamespaceMonitor that is tested
+func (s *NamespaceMonitorReconciler) GetNamespaceMonitor(req ctrl.Request) (v1alpha1.NamespaceMonitor, utilreconcile.Result, error) {
+	namespaceMonitor := v1alpha1.NamespaceMonitor{}
+	err := s.Client.Get(s.Ctx, req.NamespacedName, &namespaceMonitor)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			res, err := utilreconcile.RequeueReconcileWith(err)
+			return v1alpha1.NamespaceMonitor{}, res, err
+		}
+		s.Log.V(logging.DebugVerbosity).Info("Stopping the reconcile: the NamespaceMonitor wasn't found")
+
+		return v1alpha1.NamespaceMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	// if the resource is empty, we should terminate
+	if reflect.DeepEqual(namespaceMonitor, v1alpha1.NamespaceMonitor{}) {
+		return v1alpha1.NamespaceMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	return namespaceMonitor, utilreconcile.ContinueOperation(), nil
+}
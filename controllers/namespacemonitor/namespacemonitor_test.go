@@ -0,0 +1,221 @@
+package namespacemonitor_test
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers/namespacemonitor"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
+	controllermocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/controllers"
+)
+
+var _ = Describe("Namespacemonitor", func() {
+	var (
+		namespaceMonitor     v1alpha1.NamespaceMonitor
+		reconciler           namespacemonitor.NamespaceMonitorReconciler
+		mockClient           *clientmocks.MockClient
+		mockBlackBoxExporter *controllermocks.MockBlackBoxExporterHandler
+		mockCommon           *controllermocks.MockMonitorResourceHandler
+		mockPrometheusRule   *controllermocks.MockPrometheusRuleHandler
+		mockServiceMonitor   *controllermocks.MockServiceMonitorHandler
+
+		mockCtrl *gomock.Controller
+
+		res utilreconcile.Result
+		err error
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockBlackBoxExporter = controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+		mockServiceMonitor = controllermocks.NewMockServiceMonitorHandler(mockCtrl)
+		mockPrometheusRule = controllermocks.NewMockPrometheusRuleHandler(mockCtrl)
+		mockCommon = controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+		namespaceMonitor = v1alpha1.NamespaceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-namespacemonitor",
+				Namespace: "fake-namespace",
+			},
+			Spec: v1alpha1.NamespaceMonitorSpec{
+				Suffix: "/healthz",
+			},
+			Status: v1alpha1.NamespaceMonitorStatus{
+				RouteURLs: []string{"https://shop.apps.example.com/healthz", "https://web.apps.example.com/healthz"},
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+					{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		reconciler = namespacemonitor.NamespaceMonitorReconciler{
+			Log:              logr.Discard(),
+			Client:           mockClient,
+			Scheme:           constinit.Scheme,
+			BlackBoxExporter: mockBlackBoxExporter,
+			Common:           mockCommon,
+			ServiceMonitor:   mockServiceMonitor,
+			Prom:             mockPrometheusRule,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("EnsureRouteURLsExist", func() {
+		var routes routev1.RouteList
+		BeforeEach(func() {
+			routes = routev1.RouteList{Items: []routev1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "fake-namespace"},
+					Spec:       routev1.RouteSpec{TLS: &routev1.TLSConfig{}},
+					Status:     routev1.RouteStatus{Ingress: []routev1.RouteIngress{{Host: "web.apps.example.com"}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "fake-namespace"},
+					Spec:       routev1.RouteSpec{TLS: &routev1.TLSConfig{}},
+					Status:     routev1.RouteStatus{Ingress: []routev1.RouteIngress{{Host: "shop.apps.example.com"}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "fake-namespace"},
+				},
+			}}
+		})
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureRouteURLsExist(&namespaceMonitor)
+		})
+		When("the probed urls are up to date", func() {
+			BeforeEach(func() {
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, routes)
+			})
+			It("continues with the admitted Routes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+		When("a Route was added", func() {
+			BeforeEach(func() {
+				routes.Items = append(routes.Items, routev1.Route{
+					ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "fake-namespace"},
+					Status:     routev1.RouteStatus{Ingress: []routev1.RouteIngress{{Host: "api.apps.example.com"}}},
+				})
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, routes)
+			})
+			It("records its url", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(namespaceMonitor.Status.RouteURLs).To(Equal([]string{
+					"api.apps.example.com/healthz",
+					"https://shop.apps.example.com/healthz",
+					"https://web.apps.example.com/healthz",
+				}))
+			})
+		})
+	})
+
+	Describe("EnsureServiceMonitorExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureServiceMonitorExists(&namespaceMonitor)
+		})
+		When("the namespace has no Route to probe", func() {
+			BeforeEach(func() {
+				namespaceMonitor.Status.RouteURLs = nil
+				mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(namespaceMonitor.Status.ServiceMonitorRef).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&namespaceMonitor.Status.ServiceMonitorRef, types.NamespacedName{}).Times(1).Return(false, nil)
+			})
+			It("deletes the existing ServiceMonitor and removes its condition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.FindStatusCondition(namespaceMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)).To(BeNil())
+			})
+		})
+		When("the ServiceMonitor doesn't exist", func() {
+			BeforeEach(func() {
+				module, _ := blackboxexporter.ModuleFor(false, nil)
+				ns := reconcileCommon.GeneratedResourceName(namespaceMonitor.Status.ServiceMonitorRef, "NamespaceMonitor", types.NamespacedName{Name: namespaceMonitor.Name, Namespace: namespaceMonitor.Namespace})
+				mockBlackBoxExporter.EXPECT().GetBlackBoxExporterNamespace().Times(1).Return("")
+				mockBlackBoxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
+				mockCommon.EXPECT().GetOSDClusterID().Times(1).Return("fake-cluster-id", nil)
+				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(namespaceMonitor.Status.RouteURLs, servicemonitor.Exporter{}, ns, "fake-cluster-id", false, module, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&namespaceMonitor.Status.ServiceMonitorRef, ns).Times(1).Return(true, nil)
+			})
+			It("probes every Route and updates the ServiceRef", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+
+	Describe("EnsurePrometheusRuleExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsurePrometheusRuleExists(&namespaceMonitor)
+		})
+		When("the PrometheusRule doesn't exist", func() {
+			BeforeEach(func() {
+				namespaceMonitor.Spec.Slo = v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"}
+				namespaceMonitor.Status.SloTarget = "99.5"
+				mockCommon.EXPECT().ParseMonitorSLOSpecs(namespaceMonitor.Status.RouteURLs[0], namespaceMonitor.Spec.Slo).Times(1).Return("0.995", nil)
+				mockCommon.EXPECT().SetErrorStatus(&namespaceMonitor.Status.ErrorStatus, nil)
+				ns := reconcileCommon.GeneratedResourceName(namespaceMonitor.Status.PrometheusRuleRef, "NamespaceMonitor", types.NamespacedName{Name: namespaceMonitor.Name, Namespace: namespaceMonitor.Namespace})
+				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any()).Times(1).DoAndReturn(func(template interface{}) error {
+					Expect(template).To(HaveField("ObjectMeta.Name", ns.Name))
+					Expect(template).To(HaveField("Spec.Groups", HaveEach(HaveField("Rules", HaveEach(HaveField("Expr.StrVal", ContainSubstring(`probe_url=~"https://shop\\.apps\\.example\\.com/healthz|https://web\\.apps\\.example\\.com/healthz"`)))))))
+					return nil
+				})
+				mockCommon.EXPECT().SetResourceReference(&namespaceMonitor.Status.PrometheusRuleRef, ns).Times(1).Return(true, nil)
+			})
+			It("alerts on the Routes as one", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+
+	Describe("EnsureMonitorAndDependenciesAbsent", func() {
+		BeforeEach(func() {
+			// The namespace of the monitor isn't terminating, so its generated resources are cleaned up
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Namespace{})).AnyTimes()
+			namespaceMonitor.Finalizers = []string{namespacemonitor.FinalizerKey}
+		})
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureMonitorAndDependenciesAbsent(namespaceMonitor)
+		})
+		When("the NamespaceMonitor is being deleted", func() {
+			BeforeEach(func() {
+				namespaceMonitor.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
+				namespaceMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-servicemonitor", Namespace: "fake-namespace"}
+				namespaceMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "fake-namespace"}
+				// Neither is controlled by the NamespaceMonitor, e.g. because they were created before it controlled them
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(2)
+				mockBlackBoxExporter.EXPECT().ShouldDeleteBlackBoxExporterResources().Return(blackboxexporterconsts.KeepBlackBoxExporter, nil)
+				mockCommon.EXPECT().DeleteFinalizer(&namespaceMonitor, namespacemonitor.FinalizerKey).Times(1).Return(true)
+				mockCommon.EXPECT().UpdateMonitorResource(&namespaceMonitor).Return(utilreconcile.StopOperation(), nil)
+			})
+			It("removes the generated resources and the finalizer", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+			})
+		})
+	})
+})
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/clusteridentity"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcilerOptions holds the settings shared by the monitor reconcilers
+type ReconcilerOptions struct {
+	BlackBoxExporterImage     string
+	BlackBoxExporterNamespace string
+
+	// EnableHypershift treats the RouteMonitors in the namespace of a HostedControlPlane as monitoring it, unless they
+	// opt out with spec.hypershift. The RouteMonitors and ClusterUrlMonitors of a namespace are reconciled when its
+	// HostedControlPlane is created or deleted, so the ones of a deleted hosted cluster are orphaned rather than failing
+	EnableHypershift bool
+
+	// MonitoringAPIs, if set, picks the monitoring API the ServiceMonitors of RouteMonitors and ClusterUrlMonitors are
+	// generated with among the installed ones. Unset, the hosted control planes' use monitoring.rhobs and the others'
+	// monitoring.coreos.com
+	MonitoringAPIs *servicemonitor.MonitoringAPIs
+
+	// BlackBoxExporterDeployment sizes the blackbox exporter deployment
+	BlackBoxExporterDeployment blackboxexporter.DeploymentSettings
+
+	// BlackBoxExporterNetworkPolicy restricts the traffic of the blackbox exporter pods
+	BlackBoxExporterNetworkPolicy runtimeconfig.NetworkPolicy
+
+	// LabelOwnedResources enables labeling generated resources with their monitor
+	LabelOwnedResources bool
+
+	// ProbeResources generates Probes instead of ServiceMonitors for the monitors that aren't hosted by Hypershift
+	ProbeResources bool
+
+	// ScrapeConfigResources generates ScrapeConfigs instead of ServiceMonitors for the monitors that aren't hosted
+	// by Hypershift, for Prometheus agents that don't evaluate ServiceMonitors
+	ScrapeConfigResources bool
+
+	// ZoneLabels labels the series of the ServiceMonitors with the zone of the exporter pod that probed
+	ZoneLabels bool
+
+	// ScrubbedMetadata lists the labels and annotations removed from generated resources
+	ScrubbedMetadata reconcileCommon.MetadataScrubber
+
+	// FieldOwner, if set, applies changed generated resources server-side with it as the field manager
+	// instead of updating them, so fields set by other managers are kept
+	FieldOwner string
+
+	// SloChangeAlertDuration enables a temporary alert whenever an availability target is lowered
+	SloChangeAlertDuration time.Duration
+
+	// ClusterIdentity resolves the ID of a cluster that isn't hosted. Defaults to the ID
+	// stored in the cluster's ClusterVersion
+	ClusterIdentity clusteridentity.Provider
+
+	// FIPSMode rejects probes with TLS settings that aren't FIPS compliant and runs the exporter in FIPS mode
+	FIPSMode bool
+
+	// RuntimeConfig holds the settings that can be changed without restarting the operator. When set, it takes
+	// precedence over BlackBoxExporterImage, BlackBoxExporterDeployment, BlackBoxExporterNetworkPolicy,
+	// SloChangeAlertDuration and Backoff
+	RuntimeConfig *runtimeconfig.Config
+
+	// VerifyInterval is how often the resources referenced by the monitors' statuses are checked to exist,
+	// so they're regenerated after vanishing e.g. with their CRD. Zero disables the checks
+	VerifyInterval time.Duration
+
+	// ProbeHealth, if set, is queried every ProbeHealthInterval for the health of the probes of the monitors with an
+	// SLO, which is reported in their status
+	ProbeHealth         probehealth.Querier
+	ProbeHealthInterval time.Duration
+
+	// Backoff configures how the failed reconciles of a monitor are retried. The zero value keeps
+	// controller-runtime's default
+	Backoff Backoff
+
+	// Scope restricts the monitors that are reconciled, so several instances of the operator can run side
+	// by side in one cluster. The zero value reconciles all monitors
+	Scope Scope
+
+	// DryRun validates the writes of the generated resources and the monitors with the API server without
+	// persisting them. The monitors' status only lists the skipped writes in the DryRun condition then
+	DryRun bool
+}
+
+// Scope selects the monitors an instance of the operator reconciles: the ones in Namespaces, unless it's
+// empty, whose labels match Selector, unless it's nil
+type Scope struct {
+	Namespaces []string
+	Selector   labels.Selector
+}
+
+// Matches returns true if the monitor is in the scope
+func (s Scope) Matches(monitor client.Object) bool {
+	if len(s.Namespaces) > 0 && !slices.Contains(s.Namespaces, monitor.GetNamespace()) {
+		return false
+	}
+	return s.Selector == nil || s.Selector.Matches(labels.Set(monitor.GetLabels()))
+}
+
+// Predicate filters out the events of the monitors outside of the scope
+func (s Scope) Predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(s.Matches)
+}
+
+// NamespacePredicate filters out the events of the resources generated for monitors outside of the scope's
+// namespaces. The labels of their monitor aren't known, so the reconcile checks it against the Selector
+func (s Scope) NamespacePredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return len(s.Namespaces) == 0 || slices.Contains(s.Namespaces, o.GetNamespace())
+	})
+}
+
+// Backoff is the exponential backoff of a monitor's failed reconciles: the first retry waits BaseDelay, every
+// further failure doubles the delay up to MaxDelay. A successful reconcile resets it
+type Backoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// RateLimiter returns the rate limiter of the monitors' work queues, or nil for controller-runtime's default.
+// Its overall rate limit is kept, but its per-monitor backoff starts at BaseDelay and is capped at MaxDelay
+func (b Backoff) RateLimiter() ratelimiter.RateLimiter {
+	if b == (Backoff{}) {
+		return nil
+	}
+	return workqueue.NewWithMaxWaitRateLimiter(workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(b.BaseDelay, b.MaxDelay),
+		workqueue.DefaultControllerRateLimiter(),
+	), b.MaxDelay)
+}
+
+// RateLimiter returns the rate limiter of the monitors' work queues. While RuntimeConfig is set, its backoff
+// is used instead of Backoff, so changing it applies to the next failures
+func (o ReconcilerOptions) RateLimiter() ratelimiter.RateLimiter {
+	if o.RuntimeConfig == nil {
+		return o.Backoff.RateLimiter()
+	}
+	return &runtimeRateLimiter{config: o.RuntimeConfig}
+}
+
+// runtimeRateLimiter backs off with the delays of the runtime settings. Its limiter is replaced when they change,
+// which restarts the backoff of the monitors failing at that time
+type runtimeRateLimiter struct {
+	config *runtimeconfig.Config
+
+	mu      sync.Mutex
+	backoff Backoff
+	limiter ratelimiter.RateLimiter
+}
+
+// current returns the limiter of the current delays
+func (l *runtimeRateLimiter) current() ratelimiter.RateLimiter {
+	settings := l.config.Get()
+	backoff := Backoff{BaseDelay: settings.BackoffBaseDelay, MaxDelay: settings.BackoffMaxDelay}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limiter == nil || backoff != l.backoff {
+		l.backoff = backoff
+		l.limiter = backoff.RateLimiter()
+		if l.limiter == nil {
+			l.limiter = workqueue.DefaultControllerRateLimiter()
+		}
+	}
+	return l.limiter
+}
+
+func (l *runtimeRateLimiter) When(item interface{}) time.Duration {
+	return l.current().When(item)
+}
+
+func (l *runtimeRateLimiter) Forget(item interface{}) {
+	l.current().Forget(item)
+}
+
+func (l *runtimeRateLimiter) NumRequeues(item interface{}) int {
+	return l.current().NumRequeues(item)
+}
+
+// EnqueueAll returns a handler reconciling all the monitors returned by list on any event, e.g. of the Source of
+// the RuntimeConfig
+func EnqueueAll(list func(ctx context.Context) ([]client.Object, error), log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		monitors, err := list(ctx)
+		if err != nil {
+			log.Error(err, "Failed to list the monitors")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(monitors))
+		for _, monitor := range monitors {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(monitor)})
+		}
+		return requests
+	})
+}
@@ -0,0 +1,101 @@
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestBackoff_RateLimiter(t *testing.T) {
+	if limiter := (controllers.Backoff{}).RateLimiter(); limiter != nil {
+		t.Errorf("RateLimiter() = %v, want nil for controller-runtime's default", limiter)
+	}
+
+	limiter := controllers.Backoff{BaseDelay: time.Second, MaxDelay: 5 * time.Second}.RateLimiter()
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := limiter.When("fake-monitor"); got != want {
+			t.Errorf("When() of failure %d = %v, want %v", i+1, got, want)
+		}
+	}
+	if got := limiter.When("other-monitor"); got != time.Second {
+		t.Errorf("When() of another monitor = %v, want %v", got, time.Second)
+	}
+
+	limiter.Forget("fake-monitor")
+	if got := limiter.When("fake-monitor"); got != time.Second {
+		t.Errorf("When() after a successful reconcile = %v, want %v", got, time.Second)
+	}
+}
+
+func TestReconcilerOptions_RateLimiter(t *testing.T) {
+	config := runtimeconfig.New(runtimeconfig.Settings{BackoffBaseDelay: time.Second, BackoffMaxDelay: time.Minute}, zap.NewAtomicLevel())
+	limiter := controllers.ReconcilerOptions{
+		Backoff:       controllers.Backoff{BaseDelay: time.Hour, MaxDelay: time.Hour},
+		RuntimeConfig: config,
+	}.RateLimiter()
+	for i, want := range []time.Duration{time.Second, 2 * time.Second} {
+		if got := limiter.When("fake-monitor"); got != want {
+			t.Errorf("When() of failure %d = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if _, err := config.Apply(map[string]string{runtimeconfig.BackoffBaseDelayKey: "5s", runtimeconfig.BackoffMaxDelayKey: "8s"}); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []time.Duration{5 * time.Second, 8 * time.Second} {
+		if got := limiter.When("fake-monitor"); got != want {
+			t.Errorf("When() of failure %d with the new backoff = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestScope_Matches(t *testing.T) {
+	canary, err := labels.Parse("rmo-instance=canary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitor := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{
+		Name:      "fake-monitor",
+		Namespace: "fake-namespace",
+		Labels:    map[string]string{"rmo-instance": "canary"},
+	}}
+	for name, tc := range map[string]struct {
+		scope controllers.Scope
+		want  bool
+	}{
+		"zero value":          {controllers.Scope{}, true},
+		"watched namespace":   {controllers.Scope{Namespaces: []string{"other-namespace", "fake-namespace"}}, true},
+		"unwatched namespace": {controllers.Scope{Namespaces: []string{"other-namespace"}}, false},
+		"matching selector":   {controllers.Scope{Selector: canary}, true},
+		"other selector":      {controllers.Scope{Selector: labels.SelectorFromSet(labels.Set{"rmo-instance": "stable"})}, false},
+		"both":                {controllers.Scope{Namespaces: []string{"fake-namespace"}, Selector: canary}, true},
+	} {
+		if got := tc.scope.Matches(monitor); got != tc.want {
+			t.Errorf("%s: Matches() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestScope_NamespacePredicate(t *testing.T) {
+	object := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"}}
+	for name, tc := range map[string]struct {
+		scope controllers.Scope
+		want  bool
+	}{
+		"zero value":          {controllers.Scope{}, true},
+		"watched namespace":   {controllers.Scope{Namespaces: []string{"fake-namespace"}}, true},
+		"unwatched namespace": {controllers.Scope{Namespaces: []string{"other-namespace"}}, false},
+		"selector only":       {controllers.Scope{Selector: labels.SelectorFromSet(labels.Set{"rmo-instance": "stable"})}, true},
+	} {
+		if got := tc.scope.NamespacePredicate().Generic(event.GenericEvent{Object: object}); got != tc.want {
+			t.Errorf("%s: NamespacePredicate() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
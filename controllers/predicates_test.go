@@ -0,0 +1,32 @@
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestMonitorChanged(t *testing.T) {
+	old := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Generation: 1}}
+	now := metav1.Now()
+	for name, tc := range map[string]struct {
+		update func(m *v1alpha1.RouteMonitor)
+		want   bool
+	}{
+		"status":      {func(m *v1alpha1.RouteMonitor) { m.Status.RouteURL = "https://fake-route" }, false},
+		"spec":        {func(m *v1alpha1.RouteMonitor) { m.Generation = 2 }, true},
+		"labels":      {func(m *v1alpha1.RouteMonitor) { m.Labels = map[string]string{"fake": "label"} }, true},
+		"annotations": {func(m *v1alpha1.RouteMonitor) { m.Annotations = map[string]string{"fake": "annotation"} }, true},
+		"finalizers":  {func(m *v1alpha1.RouteMonitor) { m.Finalizers = []string{"fake-finalizer"} }, true},
+		"deletion":    {func(m *v1alpha1.RouteMonitor) { m.DeletionTimestamp = &now }, true},
+	} {
+		updated := old.DeepCopy()
+		tc.update(updated)
+		if got := controllers.MonitorChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated}); got != tc.want {
+			t.Errorf("Update() of the %s = %v, want %v", name, got, tc.want)
+		}
+	}
+}
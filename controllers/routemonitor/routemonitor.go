@@ -18,20 +18,32 @@ package routemonitor
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/config"
 	"github.com/openshift/route-monitor-operator/controllers"
 	"github.com/openshift/route-monitor-operator/pkg/alert"
 	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
 	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
 	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	"github.com/openshift/route-monitor-operator/pkg/util/finalizer"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -46,56 +58,154 @@ type RouteMonitorReconciler struct {
 	ServiceMonitor   controllers.ServiceMonitorHandler
 	Prom             controllers.PrometheusRuleHandler
 	Common           controllers.MonitorResourceHandler
+
+	controllers.ReconcilerOptions
+
+	// DedicatedBlackBoxExporter returns the exporter of the namespace probing its monitors asking for a dedicated
+	// exporter, which are probed by the shared BlackBoxExporter while it's nil
+	DedicatedBlackBoxExporter func(namespace string) controllers.BlackBoxExporterHandler
+
+	// Recorder emits Events about the monitors
+	Recorder record.EventRecorder
+
+	// DryRunClient, if set, dry-runs the writes of the generated resources and the RouteMonitors, except their status
+	DryRunClient *reconcileCommon.DryRunClient
+
+	// DebugProbes runs the debug probes of the RouteMonitors in debug mode once per DebugProbeInterval
+	DebugProbes controllers.DebugProbes
 }
 
-func NewReconciler(mgr manager.Manager, blackboxExporterImage, blackboxExporterNamespace string, enablehypershift bool) *RouteMonitorReconciler {
+// sloChangeAlertDuration returns how long the alert on a lowered availability target fires
+func (r *RouteMonitorReconciler) sloChangeAlertDuration() time.Duration {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloChangeAlertDuration
+	}
+	return r.SloChangeAlertDuration
+}
+
+// slo returns the SLO the monitor is held to, which is the default SLO if it doesn't set one
+func (r *RouteMonitorReconciler) slo(slo monitoringv1alpha1.SloSpec) monitoringv1alpha1.SloSpec {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloFor(slo)
+	}
+	return slo
+}
+
+// blackBoxExporterFor returns the exporter probing the RouteMonitor
+func (r *RouteMonitorReconciler) blackBoxExporterFor(routeMonitor *monitoringv1alpha1.RouteMonitor) controllers.BlackBoxExporterHandler {
+	if routeMonitor.Spec.ExternalExporter != nil {
+		return blackboxexporter.External{}
+	}
+	if routeMonitor.Spec.DedicatedExporter && r.DedicatedBlackBoxExporter != nil {
+		return r.DedicatedBlackBoxExporter(routeMonitor.Namespace)
+	}
+	return r.BlackBoxExporter
+}
+
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *RouteMonitorReconciler {
 	log := ctrl.Log.WithName("controllers").WithName("RouteMonitor")
 	client := mgr.GetClient()
 	ctx := context.Background()
+	recorder := mgr.GetEventRecorderFor(config.OperatorName)
+	var dryRun *reconcileCommon.DryRunClient
+	if opts.DryRun {
+		dryRun = reconcileCommon.NewDryRunClient(client, recorder, log.WithName("DryRun"))
+		client = dryRun
+	}
+	common := reconcileCommon.NewMonitorResourceCommon(ctx, client)
+	common.DryRun = opts.DryRun
+	if opts.ClusterIdentity != nil {
+		common.ClusterIdentity = opts.ClusterIdentity
+	}
+	blackBoxExporter := blackboxexporter.New(client, log, ctx, opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	blackBoxExporter.FIPS = opts.FIPSMode
+	blackBoxExporter.Deployment = opts.BlackBoxExporterDeployment
+	blackBoxExporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	blackBoxExporter.RuntimeConfig = opts.RuntimeConfig
+	blackBoxExporter.FieldOwner = opts.FieldOwner
 	return &RouteMonitorReconciler{
 		Client:           client,
 		Ctx:              ctx,
 		Log:              log,
 		Scheme:           mgr.GetScheme(),
-		BlackBoxExporter: blackboxexporter.New(client, log, ctx, blackboxExporterImage, blackboxExporterNamespace),
-		ServiceMonitor:   servicemonitor.NewServiceMonitor(ctx, client),
-		Prom:             alert.NewPrometheusRule(ctx, client),
-		Common:           reconcileCommon.NewMonitorResourceCommon(ctx, client),
+		BlackBoxExporter: blackBoxExporter,
+		DedicatedBlackBoxExporter: func(namespace string) controllers.BlackBoxExporterHandler {
+			return blackBoxExporter.DedicatedTo(namespace)
+		},
+		ServiceMonitor: &servicemonitor.ServiceMonitor{
+			Client:                client,
+			Ctx:                   ctx,
+			Comparer:              &reconcileCommon.ResourceComparer{},
+			LabelOwnedResources:   opts.LabelOwnedResources,
+			Scrubber:              opts.ScrubbedMetadata,
+			FieldOwner:            opts.FieldOwner,
+			ProbeResources:        opts.ProbeResources,
+			ScrapeConfigResources: opts.ScrapeConfigResources,
+			ZoneLabels:            opts.ZoneLabels,
+			NodeLabels:            opts.BlackBoxExporterDeployment.DaemonSet,
+			TLS:                   opts.BlackBoxExporterDeployment.ServingCert,
+			MeshTLS:               opts.BlackBoxExporterDeployment.MeshSidecar(),
+			Recorder:              recorder,
+		},
+		Prom: &alert.PrometheusRule{
+			Client:     client,
+			Ctx:        ctx,
+			Comparer:   &reconcileCommon.ResourceComparer{},
+			Scrubber:   opts.ScrubbedMetadata,
+			FieldOwner: opts.FieldOwner,
+		},
+		Common:            common,
+		ReconcilerOptions: opts,
+		Recorder:          recorder,
+		DryRunClient:      dryRun,
 	}
 }
 
-// +kubebuilder:rbac:groups=*,resources=services,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;delete;update
-// +kubebuilder:rbac:groups=monitoring.rhobs,resources=servicemonitors,verbs=get;list;watch;create;delete;update
+// +kubebuilder:rbac:groups=*,resources=services,verbs=get;list;watch;create;delete;patch
+// +kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.rhobs,resources=servicemonitors,verbs=get;list;watch;create;delete;update;patch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=blackboxexporterconfigs,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=blackboxexporterconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=probetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=ingresscontrollers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *RouteMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.Ctx = ctx
-	log := r.Log.WithName("Reconcile").WithValues("name", req.Name, "namespace", req.Namespace)
+	reconcileLog := logging.ForReconcile(r.Log.WithName("Reconcile"), "RouteMonitor", req.NamespacedName)
 
-	log.V(2).Info("Entering GetRouteMonitor")
+	log := logging.EnterStep(reconcileLog, "GetRouteMonitor")
 	routeMonitor, res, err := r.GetRouteMonitor(req)
 	if err != nil {
 		log.Error(err, "Failed to retreive RouteMonitor. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
+		return res.ReturnWith(nil)
+	}
+	if !r.Scope.Matches(&routeMonitor) {
+		log.V(logging.DebugVerbosity).Info("Skipping the RouteMonitor: it's outside of the operator's scope")
 		return utilreconcile.Stop()
 	}
 
 	// Handle deletion of RouteMonitor Resource
 	shouldDelete := finalizer.WasDeleteRequested(&routeMonitor)
-	log.V(2).Info("Response of WasDeleteRequested", "shouldDelete", shouldDelete)
+	log.V(logging.DebugVerbosity).Info("Response of WasDeleteRequested", "shouldDelete", shouldDelete)
 
 	if shouldDelete {
+		log = logging.EnterStep(reconcileLog, "EnsureMonitorAndDependenciesAbsent")
 		_, err := r.EnsureMonitorAndDependenciesAbsent(routeMonitor)
 		if err != nil {
 			log.Error(err, "Failed to delete RouteMonitor. Requeueing...")
@@ -105,7 +215,7 @@ func (r *RouteMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return utilreconcile.Stop()
 	}
 
-	log.V(2).Info("Entering EnsureFinalizerSet")
+	log = logging.EnterStep(reconcileLog, "EnsureFinalizerSet")
 	res, err = r.EnsureFinalizerSet(routeMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set RouteMonitor's finalizer. Requeueing...")
@@ -113,68 +223,230 @@ func (r *RouteMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	if res.ShouldStop() {
 		log.Info("Successfully set RouteMonitor finalizers. Stopping...")
-		return utilreconcile.Stop()
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsureBlackBoxExporterResourcesExist")
+	// The remaining steps record their outcome in the status, which is written once they're done
+	return controllers.ReconcileStatus(r.Common, r.DryRunClient, reconcileLog, &routeMonitor, &routeMonitor.Status.Conditions, func() (ctrl.Result, error) {
+		return r.ensureMonitorResources(reconcileLog, &routeMonitor)
+	})
+}
+
+// ensureMonitorResources deploys the blackbox exporter and the resources generated for the RouteMonitor
+func (r *RouteMonitorReconciler) ensureMonitorResources(reconcileLog logr.Logger, routeMonitor *monitoringv1alpha1.RouteMonitor) (ctrl.Result, error) {
+	log := logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterResourcesExist")
 	// Should happen once but cannot input in main.go
-	err = r.BlackBoxExporter.EnsureBlackBoxExporterResourcesExist()
+	err := r.blackBoxExporterFor(routeMonitor).EnsureBlackBoxExporterResourcesExist()
 	if err != nil {
 		log.Error(err, "Failed to create BlackBoxExporter. Requeueing...")
+		blackboxexporter.ReportInvalidConfig(r.Recorder, routeMonitor, &routeMonitor.Status.Conditions, err)
 		return utilreconcile.RequeueWith(err)
 	}
 
-	log.V(2).Info("Entering GetRoute")
-	route, err := r.GetRoute(routeMonitor)
+	var res utilreconcile.Result
+	if routeMonitor.Spec.RouteURLOverride != "" {
+		log = logging.EnterStep(reconcileLog, "EnsureRouteURLOverridden")
+		res, err = r.EnsureRouteURLOverridden(routeMonitor)
+	} else {
+		log = logging.EnterStep(reconcileLog, "GetRoute")
+		var route routev1.Route
+		route, err = r.GetRoute(*routeMonitor)
+		if err != nil {
+			log.Error(err, "Failed to get Route. Requeueing...")
+			return utilreconcile.RequeueWith(err)
+		}
+
+		log = logging.EnterStep(reconcileLog, "EnsureRouteURLExists")
+		res, err = r.EnsureRouteURLExists(route, routeMonitor)
+	}
 	if err != nil {
-		log.Error(err, "Failed to get Route. Requeueing...")
+		log.Error(err, "Failed to get RouteURL for RouteMonitor. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
 
-	log.V(2).Info("Entering EnsureRouteURLExists")
-	res, err = r.EnsureRouteURLExists(route, routeMonitor)
+	log = logging.EnterStep(reconcileLog, "EnsureTargetConflictsReported")
+	res, err = r.EnsureTargetConflictsReported(routeMonitor)
 	if err != nil {
-		log.Error(err, "Failed to get RouteURL for RouteMonitor. Requeueing...")
+		log.Error(err, "Failed to check RouteMonitors probing the same url. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
-		log.Info("Successfully patched RouteMonitor with RouteURL. Stopping...")
-		return utilreconcile.Stop()
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsureServiceMonitorExists")
+	log = logging.EnterStep(reconcileLog, "EnsureServiceMonitorExists")
 	res, err = r.EnsureServiceMonitorExists(routeMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set ServiceMonitor. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
-		log.Info("Successfully patched RouteMonitor with ServiceMonitorRef. Stopping...")
-		return utilreconcile.Stop()
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
 	}
 
-	log.V(2).Info("Entering EnsurePrometheusRuleResourceExists")
-	// result is silenced as it's the end of the function, if this moves add it back
+	log = logging.EnterStep(reconcileLog, "EnsurePrometheusRuleExists")
 	res, err = r.EnsurePrometheusRuleExists(routeMonitor)
 	if err != nil {
 		log.Error(err, "Failed to set PrometheusRule. Requeueing...")
 		return utilreconcile.RequeueWith(err)
 	}
 	if res.ShouldStop() {
-		log.Info("Successfully patched RouteMonitor with PrometheusRuleRef. Stopping...")
-		return utilreconcile.Stop()
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
 	}
 
-	log.Info("All operations for RouteMonitor completed. Finished Reconcile.")
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterRolledOut")
+	res, err = controllers.EnsureBlackBoxExporterRolledOut(r.blackBoxExporterFor(routeMonitor), &routeMonitor.Status.Conditions, routeMonitor.Generation)
+	if err != nil {
+		log.Error(err, "Failed to get the rollout of the BlackBoxExporter. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	reconcileLog.Info("All operations for RouteMonitor completed. Finished Reconcile.")
+	if controllers.Debugging(routeMonitor.Spec.DebugUntil, time.Now()) {
+		return utilreconcile.RequeueAfter(controllers.DebugProbeInterval).Because("the RouteMonitor is in debug mode").ReturnWith(nil)
+	}
 	return utilreconcile.Stop()
 }
 
 func (r *RouteMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringv1alpha1.RouteMonitor{}).
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1alpha1.RouteMonitor{}, builder.WithPredicates(controllers.MonitorChanged, r.Scope.Predicate())).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter()}).
 		Watches(
 			&monitoringv1.ServiceMonitor{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.RouteMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		).
+		// Reevaluates the conflicts of the RouteMonitors probing the same url as a changed monitor
+		Watches(
+			&monitoringv1alpha1.RouteMonitor{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsProbingSameURL),
+		).
+		Watches(
+			&monitoringv1alpha1.ClusterUrlMonitor{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsProbingSameURL),
+		).
+		Watches(
+			&monitoringv1alpha1.UrlMonitor{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsProbingSameURL),
 		).
-		Complete(r)
+		Watches(
+			&monitoringv1alpha1.ProbeTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsUsingProbeTemplate),
+		).
+		// Picks up changed hosts of the Routes without waiting for the resync
+		Watches(
+			&routev1.Route{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsOfRoute),
+		)
+	if r.ProbeResources {
+		bldr = bldr.Watches(
+			&monitoringv1.Probe{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.RouteMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.ScrapeConfigResources {
+		bldr = bldr.Watches(
+			&scrapeconfig.ScrapeConfig{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.RouteMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.EnableHypershift {
+		bldr = bldr.Watches(
+			&hypershiftv1beta1.HostedControlPlane{},
+			handler.EnqueueRequestsFromMapFunc(r.routeMonitorsOfNamespace),
+			builder.WithPredicates(controllers.CreatedOrDeleted),
+		)
+	}
+	if r.VerifyInterval > 0 {
+		verifier := reconcileCommon.NewResourceVerifier(r.Client, r.Recorder, r.Log.WithName("Verify"), r.VerifyInterval, r.listRouteMonitors, r.generatedResources)
+		if err := mgr.Add(verifier); err != nil {
+			return err
+		}
+		bldr = bldr.WatchesRawSource(verifier.Source(), &handler.EnqueueRequestForObject{})
+	}
+	if r.RuntimeConfig != nil {
+		// Puts changed exporter settings and SLO defaults into effect without waiting for the next reconcile
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listRouteMonitors, r.Log))
+	}
+	if r.ProbeHealth != nil {
+		reporter := probehealth.NewReporter(r.Client, r.ProbeHealth, r.Log.WithName("ProbeHealth"), r.ProbeHealthInterval, r.listRouteMonitors, r.probeHealthTarget)
+		if err := mgr.Add(reporter); err != nil {
+			return err
+		}
+	}
+	return bldr.Complete(r)
+}
+
+// listRouteMonitors returns the RouteMonitors that aren't being deleted
+func (r *RouteMonitorReconciler) listRouteMonitors(ctx context.Context) ([]client.Object, error) {
+	list := &monitoringv1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	monitors := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp == nil && r.Scope.Matches(&list.Items[i]) {
+			monitors = append(monitors, &list.Items[i])
+		}
+	}
+	return monitors, nil
+}
+
+// generatedResources returns the resources referenced by the status of a RouteMonitor
+func (r *RouteMonitorReconciler) generatedResources(o client.Object) []client.Object {
+	routeMonitor := o.(*monitoringv1alpha1.RouteMonitor)
+	resources := []client.Object{}
+	if ref := routeMonitor.Status.ServiceMonitorRef; ref.Name != "" {
+		meta := metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}
+		// The kind the ServiceMonitor was generated as is recorded. It's derived from the RouteMonitor for ones
+		// reconciled before, where failing to look up the HostedControlPlane it's only known to be RHOBS if asked for
+		if resource := servicemonitor.GeneratedResource(ref.Kind); resource != nil {
+			resource.SetName(ref.Name)
+			resource.SetNamespace(ref.Namespace)
+			resources = append(resources, resource)
+		} else if isHCP, _ := r.isHCP(routeMonitor); isHCP {
+			resources = append(resources, &rhobsv1.ServiceMonitor{ObjectMeta: meta})
+		} else if r.ScrapeConfigResources {
+			resources = append(resources, &scrapeconfig.ScrapeConfig{ObjectMeta: meta})
+		} else if r.ProbeResources {
+			resources = append(resources, &monitoringv1.Probe{ObjectMeta: meta})
+		} else {
+			resources = append(resources, &monitoringv1.ServiceMonitor{ObjectMeta: meta})
+		}
+	}
+	if ref := routeMonitor.Status.PrometheusRuleRef; ref.Name != "" {
+		resources = append(resources, &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return resources
+}
+
+// probeHealthTarget returns the probes of a RouteMonitor whose health is reported
+func (r *RouteMonitorReconciler) probeHealthTarget(o client.Object) (probehealth.Target, bool) {
+	routeMonitor := o.(*monitoringv1alpha1.RouteMonitor)
+	if routeMonitor.Status.RouteURL == "" {
+		return probehealth.Target{}, false
+	}
+	// The probes of HCP RouteMonitors are evaluated in the upstream RHOBS tenant
+	if isHCP, _ := r.isHCP(routeMonitor); isHCP {
+		return probehealth.Target{}, false
+	}
+	return probehealth.Target{
+		URLs:   []string{routeMonitor.Status.RouteURL},
+		Slo:    r.slo(routeMonitor.Spec.Slo),
+		Health: &routeMonitor.Status.ProbeHealth,
+	}, true
 }
@@ -5,144 +5,457 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
 
 	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
 	"github.com/openshift/route-monitor-operator/pkg/consts"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// Ensures that all PrometheusRules CR are created according to the RouteMonitor
-func (r *RouteMonitorReconciler) EnsurePrometheusRuleExists(routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
-	// If .spec.skipPrometheusRule is true, ensure that the PrometheusRule does NOT exist
-	if routeMonitor.Spec.SkipPrometheusRule {
+// Ensures that all PrometheusRules CR are created according to the RouteMonitor. The outcome is recorded in the
+// RouteMonitor's status, which is written by the caller
+func (r *RouteMonitorReconciler) EnsurePrometheusRuleExists(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	slo := r.slo(routeMonitor.Spec.Slo)
+
+	// Keep track of changes of the availability target before acting on them
+	alert.ObserveSloChange(r.Recorder, "RouteMonitor", routeMonitor, slo, &routeMonitor.Status.SloTarget, &routeMonitor.Status.LastSloChange)
+
+	// If .spec.skipPrometheusRule is true, or the RouteMonitor monitors a hosted control plane, ensure that the
+	// PrometheusRule does NOT exist
+	skip, err := r.skipsPrometheusRule(routeMonitor)
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+	if skip {
 		// Cleanup any existing PrometheusRules and update the status
 		if err := r.Prom.DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef); err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
-		}
-		updated, _ := r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
-		if updated {
-			return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
+			return r.artifactFailed(routeMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
 		}
+		_, _ = r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
 
 		return utilreconcile.ContinueReconcile()
 	}
 
-	parsedSlo, err := r.Common.ParseMonitorSLOSpecs(routeMonitor.Status.RouteURL, routeMonitor.Spec.Slo)
-	if r.Common.SetErrorStatus(&routeMonitor.Status.ErrorStatus, err) {
-		return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
+	parsedSlo, err := r.Common.ParseMonitorSLOSpecs(routeMonitor.Status.RouteURL, slo)
+	if r.Common.SetErrorStatus(&routeMonitor.Status.ErrorStatus, err) && errors.Is(err, customerrors.InvalidSLO) {
+		reconcileCommon.Eventf(r.Recorder, routeMonitor, corev1.EventTypeWarning, reconcileCommon.InvalidSloReason,
+			"No PrometheusRule is generated: the targetAvailabilityPercent '%s' or the latency objective is invalid", slo.TargetAvailabilityPercent)
 	}
 	if parsedSlo == "" {
 		// Delete existing PrometheusRules if required
-		err = r.Prom.DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef)
-		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+		deleteErr := r.Prom.DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef)
+		if deleteErr != nil {
+			return r.artifactFailed(routeMonitor, v1alpha1.ConditionPrometheusRuleReady, deleteErr)
 		}
-		updated, _ := r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
-		if updated {
-			return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
+		_, _ = r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		// An invalid SLO fails the PrometheusRule, without an SLO none is needed
+		if err != nil {
+			reconcileCommon.SetArtifactCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, routeMonitor.Generation, err)
+		} else {
+			meta.RemoveStatusCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
 		}
 		return utilreconcile.StopReconcile()
 	}
 
 	// Update PrometheusRule from templates
 	namespacedName := types.NamespacedName{Namespace: routeMonitor.Namespace, Name: routeMonitor.Name}
-	template := alert.TemplateForPrometheusRuleResource(routeMonitor.Status.RouteURL, parsedSlo, namespacedName)
+	ruleName := reconcileCommon.GeneratedResourceName(routeMonitor.Status.PrometheusRuleRef, "RouteMonitor", namespacedName)
+	template, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+		URL:                    routeMonitor.Status.RouteURL,
+		URLs:                   routeMonitor.Status.IngressURLs,
+		Percent:                parsedSlo,
+		Slo:                    slo,
+		NamespacedName:         namespacedName,
+		Created:                routeMonitor.CreationTimestamp.Time,
+		MaintenanceWindows:     routeMonitor.Spec.MaintenanceWindows,
+		LastSloChange:          routeMonitor.Status.LastSloChange,
+		SloChangeAlertDuration: r.sloChangeAlertDuration(),
+		Owner:                  metav1.NewControllerRef(&routeMonitor.ObjectMeta, routeMonitor.GroupVersionKind()),
+	})
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+	if r.LabelOwnedResources {
+		template.Labels = consts.OwnerLabels("RouteMonitor", routeMonitor.Name)
+	}
+	// Alerts keep the name of the monitor, only the resource is named uniquely
+	template.Name = ruleName.Name
 	err = r.Prom.UpdatePrometheusRuleDeployment(template)
 	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
 	}
 
 	// Update PrometheusRuleReference in RouteMonitor if necessary
-	updated, _ := r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, namespacedName)
-	if updated {
-		return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
-	}
+	_, _ = r.Common.SetResourceReference(&routeMonitor.Status.PrometheusRuleRef, ruleName)
+	reconcileCommon.SetArtifactCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, routeMonitor.Generation, nil)
 	return utilreconcile.ContinueReconcile()
 }
 
-// Ensures that a ServiceMonitor is created from the RouteMonitor CR
-func (r *RouteMonitorReconciler) EnsureServiceMonitorExists(routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+// Ensures that a ServiceMonitor is created from the RouteMonitor CR. The outcome is recorded in the RouteMonitor's
+// status, which is written by the caller
+func (r *RouteMonitorReconciler) EnsureServiceMonitorExists(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	hcp, err := r.isHCP(routeMonitor)
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	// If .spec.skipServiceMonitor is true, ensure that the ServiceMonitor does NOT exist
+	if routeMonitor.Spec.SkipServiceMonitor {
+		// Cleanup any existing ServiceMonitor and update the status
+		if err := r.ServiceMonitor.DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef); err != nil {
+			return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+		}
+		_, _ = r.Common.SetResourceReference(&routeMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+
+		return utilreconcile.ContinueReconcile()
+	}
+
 	// Was the RouteURL populated by a previous step?
 	if routeMonitor.Status.RouteURL == "" {
-		return utilreconcile.RequeueReconcileWith(customerrors.NoHost)
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, customerrors.NoHost)
 	}
 
 	var id string
-	var err error
-	useRHOBS := (routeMonitor.Spec.ServiceMonitorType == v1alpha1.ServiceMonitorTypeRHOBS)
-
-	if useRHOBS {
-		hcp, err := r.getHostedControlPlane(routeMonitor.Namespace)
+	if hcp {
+		id, err = r.Common.GetHypershiftClusterID(routeMonitor.Namespace)
+		if errors.Is(err, customerrors.NoHostedControlPlane) {
+			return r.hostedControlPlaneDeleted(routeMonitor)
+		}
 		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 		}
-		id = hcp.Spec.ClusterID
 	} else {
 		id, err = r.Common.GetOSDClusterID()
 		if err != nil {
-			return utilreconcile.RequeueReconcileWith(err)
+			return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 		}
 	}
 
+	// Hosted control planes are scraped through RHOBS and the others through the cluster's Prometheus, unless only the
+	// other API is installed
+	useRHOBS, err := r.MonitoringAPIs.ServiceMonitorAPI(hcp)
+	if errors.Is(err, customerrors.NoMonitoringAPI) {
+		return r.monitoringAPIUnavailable(routeMonitor)
+	}
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
 	// update ServiceMonitor if requiredctrl
-	namespacedName := types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace}
+	namespacedName := reconcileCommon.GeneratedResourceName(routeMonitor.Status.ServiceMonitorRef, "RouteMonitor", types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace})
 	owner := metav1.NewControllerRef(&routeMonitor.ObjectMeta, routeMonitor.GroupVersionKind())
-	if err := r.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment(routeMonitor.Status.RouteURL, r.BlackBoxExporter.GetBlackBoxExporterNamespace(), namespacedName, id, useRHOBS, routeMonitor.Spec.InsecureSkipTLSVerify, owner); err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+	insecureSkipTLSVerify, probe, err := blackboxexporter.ProbeSettingsOf(r.Ctx, r.Client, routeMonitor.Spec)
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	module, definition := blackboxexporter.ModuleFor(insecureSkipTLSVerify, probe)
+	if external := routeMonitor.Spec.ExternalExporter; external != nil {
+		// The configuration of an external exporter isn't managed, its module is named instead
+		module = external.Module
+	} else if err := definition.ValidateRequired(r.FIPSMode); err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	res, err := controllers.EnsureBlackBoxExporterServesModule(r.blackBoxExporterFor(routeMonitor), r.DryRunClient, module, &routeMonitor.Status.Conditions, routeMonitor.Generation)
+	if err != nil || res.ShouldStop() {
+		return res, err
+	}
+	urls := routeMonitor.Status.IngressURLs
+	if len(urls) == 0 {
+		urls = []string{routeMonitor.Status.RouteURL}
+	}
+	uid, err := r.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment(urls, servicemonitor.Exporter{Namespace: r.blackBoxExporterFor(routeMonitor).GetBlackBoxExporterNamespace(), External: routeMonitor.Spec.ExternalExporter}, namespacedName, id, useRHOBS, module, routeMonitor.Spec.MetricLabels, routeMonitor.Spec.MetricRelabelings, routeMonitor.Spec.SampleLimit, routeMonitor.Spec.TargetLimit, owner, routeMonitor.Status.ServiceMonitorRef.UID)
+	if err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
 	}
 	// update ServiceMonitorRef if required
-	updated, err := r.Common.SetResourceReference(&routeMonitor.Status.ServiceMonitorRef, namespacedName)
+	if _, err := r.Common.SetResourceReference(&routeMonitor.Status.ServiceMonitorRef, namespacedName); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	// Record the UID, so a ServiceMonitor recreated by someone else isn't adopted
+	if uid != "" {
+		routeMonitor.Status.ServiceMonitorRef.UID = uid
+	}
+	// Record the kind, which depends on the installed monitoring APIs, so it's the one verified and deleted
+	routeMonitor.Status.ServiceMonitorRef.Kind = servicemonitor.GeneratedKind(useRHOBS, r.ScrapeConfigResources, r.ProbeResources)
+	reconcileCommon.SetArtifactCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady, routeMonitor.Generation, nil)
+	r.DebugProbes.CaptureFailingProbe(r.blackBoxExporterFor(routeMonitor), r.DryRunClient, r.Recorder, routeMonitor, routeMonitor.Spec.DebugUntil, urls, module, &routeMonitor.Status.LastFailedProbe)
+	return utilreconcile.ContinueReconcile()
+}
+
+// EnsureTargetConflictsReported warns about other monitors probing the same url with a different
+// availability target, so the conflicting definitions can be consolidated. RouteMonitors are compared with
+// each other and with ClusterUrlMonitors and UrlMonitors. The conflicts are recorded in the RouteMonitor's
+// status, which is written by the caller
+func (r *RouteMonitorReconciler) EnsureTargetConflictsReported(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	others, err := r.probedURLs(r.Ctx)
 	if err != nil {
 		return utilreconcile.RequeueReconcileWith(err)
 	}
-	if updated {
-		return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
+	conflicts := conflictingMonitors(*routeMonitor, others, r.slo)
+	metrics.TargetConflicts.WithLabelValues("RouteMonitor", routeMonitor.Namespace, routeMonitor.Name).Set(float64(len(conflicts)))
+
+	if len(conflicts) == 0 {
+		meta.RemoveStatusCondition(&routeMonitor.Status.Conditions, v1alpha1.ConditionTargetConflict)
+	} else {
+		meta.SetStatusCondition(&routeMonitor.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionTargetConflict,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: routeMonitor.Generation,
+			Reason:             reconcileCommon.ReasonConflictingSLO,
+			Message:            fmt.Sprintf("%s is also probed with a different availability target by %s", routeMonitor.Status.RouteURL, strings.Join(conflicts, ", ")),
+		})
 	}
 	return utilreconcile.ContinueReconcile()
 }
 
+// probedURL is the url a monitor probes and the SLO it's held to
+type probedURL struct {
+	kind     string
+	monitor  types.NamespacedName
+	url      string
+	slo      v1alpha1.SloSpec
+	deleting bool
+}
+
+// name identifies the monitor in the conflicts: RouteMonitors by namespace/name, the others with their kind
+func (p probedURL) name() string {
+	if p.kind == "RouteMonitor" {
+		return p.monitor.String()
+	}
+	return p.kind + " " + p.monitor.String()
+}
+
+// probedURLs returns the urls probed by the RouteMonitors, ClusterUrlMonitors and UrlMonitors
+func (r *RouteMonitorReconciler) probedURLs(ctx context.Context) ([]probedURL, error) {
+	urls := []probedURL{}
+	routeMonitors := v1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, &routeMonitors); err != nil {
+		return nil, err
+	}
+	for _, m := range routeMonitors.Items {
+		urls = append(urls, probedURL{kind: "RouteMonitor", monitor: types.NamespacedName{Namespace: m.Namespace, Name: m.Name},
+			url: m.Status.RouteURL, slo: m.Spec.Slo, deleting: m.DeletionTimestamp != nil})
+	}
+	clusterUrlMonitors := v1alpha1.ClusterUrlMonitorList{}
+	if err := r.Client.List(ctx, &clusterUrlMonitors); err != nil {
+		return nil, err
+	}
+	for _, m := range clusterUrlMonitors.Items {
+		urls = append(urls, probedURL{kind: "ClusterUrlMonitor", monitor: types.NamespacedName{Namespace: m.Namespace, Name: m.Name},
+			url: m.Status.ClusterURL, slo: m.Spec.Slo, deleting: m.DeletionTimestamp != nil})
+	}
+	urlMonitors := v1alpha1.UrlMonitorList{}
+	if err := r.Client.List(ctx, &urlMonitors); err != nil {
+		return nil, err
+	}
+	for _, m := range urlMonitors.Items {
+		urls = append(urls, probedURL{kind: "UrlMonitor", monitor: types.NamespacedName{Namespace: m.Namespace, Name: m.Name},
+			url: m.Spec.URL, slo: m.Spec.Slo, deleting: m.DeletionTimestamp != nil})
+	}
+	return urls, nil
+}
+
+// conflictingMonitors returns the monitors that probe the url of the routeMonitor with a different availability
+// target. Monitors without a target don't alert, so they don't conflict. sloFor resolves the SLO a monitor is held to
+func conflictingMonitors(routeMonitor v1alpha1.RouteMonitor, others []probedURL, sloFor func(v1alpha1.SloSpec) v1alpha1.SloSpec) []string {
+	conflicts := []string{}
+	_, target := sloFor(routeMonitor.Spec.Slo).IsValid()
+	if routeMonitor.Status.RouteURL == "" || target == "" {
+		return conflicts
+	}
+	for _, other := range others {
+		if other.kind == "RouteMonitor" && other.monitor == (types.NamespacedName{Namespace: routeMonitor.Namespace, Name: routeMonitor.Name}) {
+			continue
+		}
+		if other.deleting || other.url != routeMonitor.Status.RouteURL {
+			continue
+		}
+		if _, otherTarget := sloFor(other.slo).IsValid(); otherTarget == "" || otherTarget == target {
+			continue
+		}
+		conflicts = append(conflicts, other.name())
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// routeMonitorsProbingSameURL maps a monitor to the RouteMonitors probing its url, so their conflicts are
+// reevaluated when it changes
+func (r *RouteMonitorReconciler) routeMonitorsProbingSameURL(ctx context.Context, o client.Object) []ctrl.Request {
+	var url string
+	_, isRouteMonitor := o.(*v1alpha1.RouteMonitor)
+	switch monitor := o.(type) {
+	case *v1alpha1.RouteMonitor:
+		url = monitor.Status.RouteURL
+	case *v1alpha1.ClusterUrlMonitor:
+		url = monitor.Status.ClusterURL
+	case *v1alpha1.UrlMonitor:
+		url = monitor.Spec.URL
+	}
+	if url == "" {
+		return nil
+	}
+	routeMonitors := v1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, &routeMonitors); err != nil {
+		r.Log.Error(err, "Failed to list RouteMonitors probing the same url")
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, other := range routeMonitors.Items {
+		if other.Status.RouteURL != url || (isRouteMonitor && other.Namespace == o.GetNamespace() && other.Name == o.GetName()) || !r.Scope.Matches(&other) {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: other.Name, Namespace: other.Namespace}})
+	}
+	return requests
+}
+
+// routeMonitorsUsingProbeTemplate maps a ProbeTemplate to the RouteMonitors referencing it, so they're probed
+// with its changed settings
+func (r *RouteMonitorReconciler) routeMonitorsUsingProbeTemplate(ctx context.Context, o client.Object) []ctrl.Request {
+	routeMonitors := v1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, &routeMonitors); err != nil {
+		r.Log.Error(err, "Failed to list RouteMonitors using the ProbeTemplate", "name", o.GetName())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, routeMonitor := range routeMonitors.Items {
+		if routeMonitor.Spec.ProbeTemplate != o.GetName() || !r.Scope.Matches(&routeMonitor) {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace}})
+	}
+	return requests
+}
+
+// routeMonitorsOfRoute maps a Route to the RouteMonitors reading their url from it, so a changed host is
+// probed right away
+func (r *RouteMonitorReconciler) routeMonitorsOfRoute(ctx context.Context, o client.Object) []ctrl.Request {
+	route := types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}
+	routeMonitors, err := reconcileCommon.RouteMonitorsOfRoute(ctx, r.Client, route)
+	if err != nil {
+		r.Log.Error(err, "Failed to list RouteMonitors of the Route", "route", route.String())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, routeMonitor := range routeMonitors {
+		if !r.Scope.Matches(&routeMonitor) {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace}})
+	}
+	return requests
+}
+
+// routeMonitorsOfNamespace returns the requests of the RouteMonitors in the namespace of a created or deleted
+// HostedControlPlane, so they are orphaned or resumed with it
+func (r *RouteMonitorReconciler) routeMonitorsOfNamespace(ctx context.Context, o client.Object) []ctrl.Request {
+	list := v1alpha1.RouteMonitorList{}
+	if err := r.Client.List(ctx, &list, client.InNamespace(o.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list the RouteMonitors of the namespace", "namespace", o.GetNamespace())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, routeMonitor := range list.Items {
+		if !r.Scope.Matches(&routeMonitor) {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace}})
+	}
+	return requests
+}
+
+// hostedControlPlaneDeleted orphans a RouteMonitor whose HostedControlPlane was deleted: its RHOBS ServiceMonitor is
+// deleted and it isn't requeued, until a HostedControlPlane created in its namespace triggers a reconcile
+func (r *RouteMonitorReconciler) hostedControlPlaneDeleted(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	if err := r.ServiceMonitor.DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef); err != nil {
+		return r.artifactFailed(routeMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	_, _ = r.Common.SetResourceReference(&routeMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+	reconcileCommon.SetHostedControlPlaneDeletedCondition(&routeMonitor.Status.Conditions, routeMonitor.Generation, routeMonitor.Namespace)
+	return utilreconcile.StopReconcile()
+}
+
+// monitoringAPIUnavailable reports that the RouteMonitor's ServiceMonitor can't be generated, as no monitoring API is
+// installed, and checks again once the discovered APIs expire
+func (r *RouteMonitorReconciler) monitoringAPIUnavailable(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	reconcileCommon.SetMonitoringAPIUnavailableCondition(&routeMonitor.Status.Conditions, routeMonitor.Generation)
+	return utilreconcile.RequeueAfter(servicemonitor.APIDiscoveryInterval).Because("no monitoring API is installed"), nil
+}
+
+// artifactFailed reports the failure of a generated resource in the RouteMonitor's status, so it's visible
+// which one needs attention. It requeues with the failure unless retrying can't fix it
+func (r *RouteMonitorReconciler) artifactFailed(routeMonitor *v1alpha1.RouteMonitor, conditionType string, err error) (utilreconcile.Result, error) {
+	reconcileCommon.SetArtifactCondition(&routeMonitor.Status.Conditions, conditionType, routeMonitor.Generation, err)
+	metrics.ReconcileErrors.WithLabelValues("RouteMonitor", string(customerrors.ClassOf(err))).Inc()
+	return utilreconcile.FailReconcileWith(err)
+}
+
 // Ensures that all dependencies related to a RouteMonitor are deleted
 func (r *RouteMonitorReconciler) EnsureMonitorAndDependenciesAbsent(routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
 	log := r.Log.WithName("Delete")
 
-	shouldDeleteBlackBoxResources, err := r.BlackBoxExporter.ShouldDeleteBlackBoxExporterResources()
+	metrics.TargetConflicts.DeleteLabelValues("RouteMonitor", routeMonitor.Namespace, routeMonitor.Name)
+
+	reason, err := reconcileCommon.CleanupSkipReason(r.Ctx, r.Client, &routeMonitor)
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if reason != "" {
+		reconcileCommon.Eventf(r.Recorder, &routeMonitor, corev1.EventTypeWarning, reconcileCommon.CleanupSkippedReason,
+			"Dropping the finalizer without deleting the generated resources: %s", reason)
+		return r.ensureFinalizerAbsent(routeMonitor)
+	}
+
+	shouldDeleteBlackBoxResources, err := r.blackBoxExporterFor(&routeMonitor).ShouldDeleteBlackBoxExporterResources()
 	if err != nil {
 		return utilreconcile.RequeueReconcileWith(err)
 	}
-	log.V(2).Info("Response of ShouldDeleteBlackBoxExporterResources", "shouldDeleteBlackBoxResources", shouldDeleteBlackBoxResources)
+	log.V(logging.DebugVerbosity).Info("Response of ShouldDeleteBlackBoxExporterResources", "shouldDeleteBlackBoxResources", shouldDeleteBlackBoxResources)
 
 	if shouldDeleteBlackBoxResources {
-		log.V(2).Info("Entering ensureBlackBoxExporterResourcesAbsent")
-		err := r.BlackBoxExporter.EnsureBlackBoxExporterResourcesAbsent()
+		log.V(logging.DebugVerbosity).Info("Entering ensureBlackBoxExporterResourcesAbsent")
+		err := r.blackBoxExporterFor(&routeMonitor).EnsureBlackBoxExporterResourcesAbsent()
 		if err != nil {
 			return utilreconcile.RequeueReconcileWith(err)
 		}
 	}
 
-	log.V(2).Info("Entering ensureServiceMonitorResourceAbsent")
-	isHCP := false
-	if err = r.ServiceMonitor.DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef, isHCP); err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
+	// The resources controlled by the RouteMonitor are deleted with it by the garbage collector
+	resources := r.generatedResources(&routeMonitor)
+	if err := reconcileCommon.DeleteUncollected(r.Ctx, r.Client, &routeMonitor, resources...); err != nil {
+		return r.deletionBlocked(routeMonitor, err)
 	}
 
-	log.V(2).Info("Entering ensurePrometheusRuleResourceAbsent")
-	err = r.Prom.DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef)
-	if err != nil {
-		return utilreconcile.RequeueReconcileWith(err)
-	}
+	log.V(logging.DebugVerbosity).Info("Entering ensureFinalizerAbsent")
+	return r.ensureFinalizerAbsent(routeMonitor)
+}
 
-	log.V(2).Info("Entering ensureFinalizerAbsent")
+// ensureFinalizerAbsent drops the finalizer of the RouteMonitor, so its deletion completes
+func (r *RouteMonitorReconciler) ensureFinalizerAbsent(routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
 	if r.Common.DeleteFinalizer(&routeMonitor, consts.FinalizerKey) {
 		// ignore the output as we want to remove the PrevFinalizerKey anyways
 		r.Common.DeleteFinalizer(&routeMonitor, consts.PrevFinalizerKey)
@@ -151,6 +464,14 @@ func (r *RouteMonitorReconciler) EnsureMonitorAndDependenciesAbsent(routeMonitor
 	return utilreconcile.StopReconcile()
 }
 
+// deletionBlocked warns that the RouteMonitor keeps its finalizer because its generated resources couldn't be
+// deleted, and requeues with the failure
+func (r *RouteMonitorReconciler) deletionBlocked(routeMonitor v1alpha1.RouteMonitor, err error) (utilreconcile.Result, error) {
+	reconcileCommon.Eventf(r.Recorder, &routeMonitor, corev1.EventTypeWarning, reconcileCommon.DeletionBlockedReason,
+		"Keeping the finalizer until the generated resources are deleted: %v", err)
+	return utilreconcile.RequeueReconcileWith(err)
+}
+
 func (s *RouteMonitorReconciler) EnsureFinalizerSet(routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
 	if s.Common.SetFinalizer(&routeMonitor, consts.FinalizerKey) {
 		// ignore the output as we want to remove the PrevFinalizerKey anyways
@@ -170,7 +491,7 @@ func (r *RouteMonitorReconciler) GetRouteMonitor(req ctrl.Request) (v1alpha1.Rou
 			res, err := utilreconcile.RequeueReconcileWith(err)
 			return v1alpha1.RouteMonitor{}, res, err
 		}
-		r.Log.V(2).Info("StopRequeue", "As RouteMonitor is 'NotFound', stopping requeue", nil)
+		r.Log.V(logging.DebugVerbosity).Info("Stopping the reconcile: the RouteMonitor wasn't found")
 		return v1alpha1.RouteMonitor{}, utilreconcile.StopOperation(), nil
 	}
 
@@ -199,58 +520,152 @@ func (r *RouteMonitorReconciler) GetRoute(routeMonitor v1alpha1.RouteMonitor) (r
 	return res, err
 }
 
-// EnsureRouteURLExists verifies that the .spec.RouteURL has the Route URL inside
-func (r *RouteMonitorReconciler) EnsureRouteURLExists(route routev1.Route, routeMonitor v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+// EnsureRouteURLExists verifies that the .spec.RouteURL has the Route URL inside. The url is recorded in the
+// RouteMonitor's status, which is written by the caller
+func (r *RouteMonitorReconciler) EnsureRouteURLExists(route routev1.Route, routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
 	amountOfIngress := len(route.Status.Ingress)
 	if amountOfIngress == 0 {
 		err := errors.New("No Ingress: cannot extract route url from the Route resource")
 		return utilreconcile.RequeueReconcileWith(err)
 	}
-	extractedRouteURL := route.Status.Ingress[0].Host
-	if amountOfIngress > 1 {
-		r.Log.V(1).Info(fmt.Sprintf("Too many Ingress: assuming first ingress is the correct, chosen ingress '%s'", extractedRouteURL))
+	var extractedHost string
+	var ingressURLs []string
+	switch routerName := routeMonitor.Spec.Route.RouterName; {
+	case routeMonitor.Spec.Route.ProbeAllIngresses:
+		extractedHost = route.Status.Ingress[0].Host
+		for _, ingress := range route.Status.Ingress {
+			if ingress.Host == "" {
+				return utilreconcile.RequeueReconcileWith(customerrors.NoHost)
+			}
+			ingressURLs = append(ingressURLs, routeURLFor(ingress.Host, route, *routeMonitor))
+		}
+	case routerName != "":
+		ingress, found := ingressOfRouter(route, routerName)
+		if !found {
+			err := fmt.Errorf("No Ingress: the Route wasn't admitted by router '%s'", routerName)
+			return utilreconcile.RequeueReconcileWith(err)
+		}
+		extractedHost = ingress.Host
+	default:
+		extractedHost = route.Status.Ingress[0].Host
+		if amountOfIngress > 1 {
+			r.Log.Info(fmt.Sprintf("Too many Ingress: assuming first ingress is the correct, chosen ingress '%s'", extractedHost))
+		}
 	}
 
-	if extractedRouteURL == "" {
+	if extractedHost == "" {
 		return utilreconcile.RequeueReconcileWith(customerrors.NoHost)
 	}
+	extractedRouteURL := routeURLFor(extractedHost, route, *routeMonitor)
+
+	currentRouteURL := routeMonitor.Status.RouteURL
+	if currentRouteURL == extractedRouteURL && slices.Equal(routeMonitor.Status.IngressURLs, ingressURLs) {
+		r.Log.V(logging.TraceVerbosity).Info("Same RouteURL: currentRouteURL and extractedRouteURL are equal, update not required")
+		return utilreconcile.ContinueReconcile()
+	}
 
+	if currentRouteURL != "" && extractedRouteURL != currentRouteURL {
+		r.Log.V(logging.TraceVerbosity).Info("RouteURL mismatch: currentRouteURL and extractedRouteURL are not equal, taking extractedRouteURL as source of truth")
+	}
+
+	routeMonitor.Status.IngressURLs = ingressURLs
+	routeMonitor.Status.RouteURL = extractedRouteURL
+	if extractedRouteURL != currentRouteURL {
+		reconcileCommon.Eventf(r.Recorder, routeMonitor, corev1.EventTypeNormal, reconcileCommon.RouteURLResolvedReason,
+			"Probing %s, the url of Route %s/%s", extractedRouteURL, route.Namespace, route.Name)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+// routeURLFor returns the url probing a host of the Route, with the scheme the RouteMonitor forces or https if the
+// Route has TLS configured
+func routeURLFor(host string, route routev1.Route, routeMonitor v1alpha1.RouteMonitor) string {
+	routeURL := host
 	if routeMonitor.Spec.Route.Port != 0 {
-		extractedRouteURL = fmt.Sprintf("%s:%d", extractedRouteURL, routeMonitor.Spec.Route.Port)
+		routeURL = fmt.Sprintf("%s:%d", routeURL, routeMonitor.Spec.Route.Port)
 	}
 	if routeMonitor.Spec.Route.Suffix != "" {
-		extractedRouteURL = fmt.Sprintf("%s%s", extractedRouteURL, routeMonitor.Spec.Route.Suffix)
+		routeURL = fmt.Sprintf("%s%s", routeURL, routeMonitor.Spec.Route.Suffix)
+	}
+	if routeMonitor.Spec.Route.Scheme != "" {
+		return fmt.Sprintf("%s://%s", routeMonitor.Spec.Route.Scheme, routeURL)
 	}
-
-	currentRouteURL := routeMonitor.Status.RouteURL
 	if route.Spec.TLS != nil {
-		r.Log.V(3).Info("TLS detected: adding https to extractedRouteURL as the url ")
-		extractedRouteURL = fmt.Sprintf("https://%s", extractedRouteURL)
+		routeURL = fmt.Sprintf("https://%s", routeURL)
 	}
+	return routeURL
+}
 
-	if currentRouteURL == extractedRouteURL {
-		r.Log.V(3).Info("Same RouteURL: currentRouteURL and extractedRouteURL are equal, update not required")
+// ingressOfRouter returns the ingress of the Route admitted by the given router
+func ingressOfRouter(route routev1.Route, routerName string) (routev1.RouteIngress, bool) {
+	for _, ingress := range route.Status.Ingress {
+		if ingress.RouterName == routerName {
+			return ingress, true
+		}
+	}
+	return routev1.RouteIngress{}, false
+}
+
+// EnsureRouteURLOverridden verifies that the .status.RouteURL is the url the RouteMonitor overrides the Route's with.
+// The url is recorded in the RouteMonitor's status, which is written by the caller
+func (r *RouteMonitorReconciler) EnsureRouteURLOverridden(routeMonitor *v1alpha1.RouteMonitor) (utilreconcile.Result, error) {
+	if routeMonitor.Status.RouteURL == routeMonitor.Spec.RouteURLOverride && len(routeMonitor.Status.IngressURLs) == 0 {
+		r.Log.V(logging.TraceVerbosity).Info("Same RouteURL: currentRouteURL and routeURLOverride are equal, update not required")
 		return utilreconcile.ContinueReconcile()
 	}
+	routeMonitor.Status.RouteURL = routeMonitor.Spec.RouteURLOverride
+	routeMonitor.Status.IngressURLs = nil
+	return utilreconcile.ContinueReconcile()
+}
 
-	if currentRouteURL != "" && extractedRouteURL != currentRouteURL {
-		r.Log.V(3).Info("RouteURL mismatch: currentRouteURL and extractedRouteURL are not equal, taking extractedRouteURL as source of truth")
+// skipsPrometheusRule reports whether the RouteMonitor has no PrometheusRule: spec.skipPrometheusRule skips it, and
+// as for ClusterUrlMonitors the alerting of hosted control planes is implemented in the upstream RHOBS tenant, which
+// holds their probe series. Only the RouteMonitors enabling spec.hypershift, or in the namespace of a
+// HostedControlPlane, count as such here: the ones merely asking for a RHOBS ServiceMonitor keep their rule
+func (r *RouteMonitorReconciler) skipsPrometheusRule(routeMonitor *v1alpha1.RouteMonitor) (bool, error) {
+	if routeMonitor.Spec.SkipPrometheusRule {
+		return true, nil
+	}
+	if routeMonitor.Spec.Hypershift != nil {
+		return routeMonitor.Spec.Hypershift.Enabled, nil
+	}
+	if !r.EnableHypershift {
+		return false, nil
 	}
+	return r.inHostedControlPlaneNamespace(routeMonitor)
+}
 
-	routeMonitor.Status.RouteURL = extractedRouteURL
-	return r.Common.UpdateMonitorResourceStatus(&routeMonitor)
+// isHCP reports whether the RouteMonitor monitors a hosted control plane, so it's scraped through a RHOBS ServiceMonitor
+// labeled with the hosted cluster's ID. Unless spec.hypershift overrides it, RouteMonitors asking for a RHOBS
+// ServiceMonitor do, and with Hypershift enabled so do the ones in the namespace of a HostedControlPlane. Once that
+// HostedControlPlane is deleted, a RouteMonitor still referencing its RHOBS ServiceMonitor, or already orphaned, stays
+// one, so it's orphaned instead of being scraped as the management cluster's
+func (r *RouteMonitorReconciler) isHCP(routeMonitor *v1alpha1.RouteMonitor) (bool, error) {
+	if routeMonitor.Spec.Hypershift != nil {
+		return routeMonitor.Spec.Hypershift.Enabled, nil
+	}
+	if routeMonitor.Spec.ServiceMonitorType == v1alpha1.ServiceMonitorTypeRHOBS {
+		return true, nil
+	}
+	if !r.EnableHypershift {
+		return false, nil
+	}
+	inNamespace, err := r.inHostedControlPlaneNamespace(routeMonitor)
+	if err != nil || inNamespace {
+		return inNamespace, err
+	}
+	if routeMonitor.Status.ServiceMonitorRef.Kind == servicemonitor.RHOBSServiceMonitorKind {
+		return true, nil
+	}
+	condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+	return condition != nil && condition.Reason == reconcileCommon.ReasonHostedControlPlaneDeleted, nil
 }
 
-// getHostedControlPlane retrieves the HostedControlPlane object from the provided namespace. It's expected that only a single HCP object is present in the namespace,
-// if multiple are found, an error is returned instead.
-func (r *RouteMonitorReconciler) getHostedControlPlane(namespace string) (hypershiftv1beta1.HostedControlPlane, error) {
+// inHostedControlPlaneNamespace reports whether the namespace of the RouteMonitor holds a HostedControlPlane
+func (r *RouteMonitorReconciler) inHostedControlPlaneNamespace(routeMonitor *v1alpha1.RouteMonitor) (bool, error) {
 	hcpList := hypershiftv1beta1.HostedControlPlaneList{}
-	err := r.Client.List(context.TODO(), &hcpList, client.InNamespace(namespace))
-	if err != nil {
-		return hypershiftv1beta1.HostedControlPlane{}, fmt.Errorf("failed to retrieve HostedControlPlanes in namespace '%s': %w", namespace, err)
-	}
-	if len(hcpList.Items) != 1 {
-		return hypershiftv1beta1.HostedControlPlane{}, fmt.Errorf("unexpected number of HostedControlPlane objects in namespace '%s': expected 1, got %d", namespace, len(hcpList.Items))
+	if err := r.Client.List(r.Ctx, &hcpList, client.InNamespace(routeMonitor.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to retrieve HostedControlPlanes in namespace '%s': %w", routeMonitor.Namespace, err)
 	}
-	return hcpList.Items[0], nil
+	return len(hcpList.Items) > 0, nil
 }
@@ -1,6 +1,10 @@
 package routemonitor_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/go-logr/logr"
 	fuzz "github.com/google/gofuzz"
 	. "github.com/onsi/ginkgo"
@@ -13,19 +17,29 @@ import (
 	"github.com/openshift/route-monitor-operator/controllers/routemonitor"
 
 	routev1 "github.com/openshift/api/route/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	blackboxexporterpkg "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
 	routemonitorconst "github.com/openshift/route-monitor-operator/pkg/consts"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
 	consterror "github.com/openshift/route-monitor-operator/pkg/consts/test/error"
 	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
@@ -87,7 +101,8 @@ var _ = Describe("Routemonitor", func() {
 				Finalizers:        routeMonitorFinalizers,
 			},
 			Status: v1alpha1.RouteMonitorStatus{
-				RouteURL: "fake-route-url",
+				RouteURL:  "fake-route-url",
+				SloTarget: "99.5",
 			},
 			Spec: v1alpha1.RouteMonitorSpec{
 				Slo: v1alpha1.SloSpec{
@@ -127,8 +142,6 @@ var _ = Describe("Routemonitor", func() {
 			shouldDeleteBlackBoxExporterResources         helper.MockHelper
 			ensureBlackBoxExporterResourcesAbsent         helper.MockHelper
 			ensureBlackBoxExporterResourcesExist          helper.MockHelper
-			deleteServiceMonitorDeployment                helper.MockHelper
-			deletePrometheusRuleDeployment                helper.MockHelper
 			deleteFinalizer                               helper.MockHelper
 			shouldDeleteBlackBoxExporterResourcesResponse blackboxexporter.ShouldDeleteBlackBoxExporter
 
@@ -136,13 +149,16 @@ var _ = Describe("Routemonitor", func() {
 			err error
 		)
 		BeforeEach(func() {
+			// The namespace of the monitor isn't terminating, so its generated resources are cleaned up
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Namespace{})).AnyTimes()
 			shouldDeleteBlackBoxExporterResources = helper.MockHelper{}
 			ensureBlackBoxExporterResourcesAbsent = helper.MockHelper{}
 			ensureBlackBoxExporterResourcesExist = helper.MockHelper{}
-			deleteServiceMonitorDeployment = helper.MockHelper{}
-			deletePrometheusRuleDeployment = helper.MockHelper{}
 			deleteFinalizer = helper.MockHelper{}
 			shouldDeleteBlackBoxExporterResourcesResponse = blackboxexporter.KeepBlackBoxExporter
+			// The generated resources are read from the client and deleted unless the RouteMonitor controls them
+			routeMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-servicemonitor", Namespace: "the-world"}
+			routeMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "the-world"}
 		})
 		JustBeforeEach(func() {
 
@@ -160,14 +176,6 @@ var _ = Describe("Routemonitor", func() {
 				Times(ensureBlackBoxExporterResourcesExist.CalledTimes).
 				Return(ensureBlackBoxExporterResourcesExist.ErrorResponse)
 
-			mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(gomock.Any(), gomock.Any()).
-				Times(deleteServiceMonitorDeployment.CalledTimes).
-				Return(deleteServiceMonitorDeployment.ErrorResponse)
-
-			mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(gomock.Any()).
-				Times(deletePrometheusRuleDeployment.CalledTimes).
-				Return(deletePrometheusRuleDeployment.ErrorResponse)
-
 			// act
 			res, err = routeMonitorReconciler.EnsureMonitorAndDependenciesAbsent(routeMonitor)
 		})
@@ -203,21 +211,21 @@ var _ = Describe("Routemonitor", func() {
 					Expect(err).To(MatchError(consterror.CustomError))
 				})
 			})
-			When("func deleteServiceMonitorDeployment fails unexpectedly", func() {
+			When("reading the generated resources fails unexpectedly", func() {
 				BeforeEach(func() {
 					ensureBlackBoxExporterResourcesAbsent.CalledTimes = 1
-					deleteServiceMonitorDeployment = helper.CustomErrorHappensOnce()
+					get = helper.CustomErrorHappensOnce()
 				})
 				It("should bubble up the error", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(MatchError(consterror.CustomError))
 				})
 			})
-			When("func DeletePrometheusRuleDeployment fails unexpectedly", func() {
+			When("deleting the generated resources fails unexpectedly", func() {
 				BeforeEach(func() {
 					ensureBlackBoxExporterResourcesAbsent.CalledTimes = 1
-					deleteServiceMonitorDeployment.CalledTimes = 1
-					deletePrometheusRuleDeployment = helper.CustomErrorHappensOnce()
+					get.CalledTimes = 1
+					delete = helper.CustomErrorHappensOnce()
 				})
 				It("should bubble up the error", func() {
 					Expect(err).To(HaveOccurred())
@@ -227,8 +235,8 @@ var _ = Describe("Routemonitor", func() {
 			When("func EnsureFinalizerAbsent fails unexpectedly", func() {
 				BeforeEach(func() {
 					ensureBlackBoxExporterResourcesAbsent.CalledTimes = 1
-					deleteServiceMonitorDeployment.CalledTimes = 1
-					deletePrometheusRuleDeployment.CalledTimes = 1
+					get.CalledTimes = 2
+					delete.CalledTimes = 2
 					mockUtils.EXPECT().DeleteFinalizer(gomock.Any(), gomock.Any()).Return(true).Times(2)
 					mockUtils.EXPECT().UpdateMonitorResource(gomock.Any()).Return(utilreconcile.RequeueOperation(), consterror.CustomError)
 				})
@@ -240,8 +248,8 @@ var _ = Describe("Routemonitor", func() {
 			When("all deletions happened successfully", func() {
 				BeforeEach(func() {
 					ensureBlackBoxExporterResourcesAbsent.CalledTimes = 1
-					deleteServiceMonitorDeployment.CalledTimes = 1
-					deletePrometheusRuleDeployment.CalledTimes = 1
+					get.CalledTimes = 2
+					delete.CalledTimes = 2
 					deleteFinalizer.CalledTimes = 1
 					mockUtils.EXPECT().DeleteFinalizer(gomock.Any(), gomock.Any()).Return(true).Times(2)
 					mockUtils.EXPECT().UpdateMonitorResource(gomock.Any())
@@ -256,22 +264,23 @@ var _ = Describe("Routemonitor", func() {
 			BeforeEach(func() {
 				shouldDeleteBlackBoxExporterResources.CalledTimes = 1
 				shouldDeleteBlackBoxExporterResourcesResponse = blackboxexporter.KeepBlackBoxExporter
-				deleteServiceMonitorDeployment.CalledTimes = 1
-				deletePrometheusRuleDeployment.CalledTimes = 1
+				get.CalledTimes = 2
+				delete.CalledTimes = 2
 			})
-			When("func EnsureServiceMonitorResourceAbsent fails unexpectedly", func() {
+			When("reading the generated resources fails unexpectedly", func() {
 				BeforeEach(func() {
-					deleteServiceMonitorDeployment.ErrorResponse = consterror.CustomError
-					deletePrometheusRuleDeployment.CalledTimes = 0
+					get = helper.CustomErrorHappensOnce()
+					delete.CalledTimes = 0
 				})
 				It("should bubble up the error", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(MatchError(consterror.CustomError))
 				})
 			})
-			When("func EnsurePrometheusRuleResourceAbsent fails unexpectedly", func() {
+			When("deleting the generated resources fails unexpectedly", func() {
 				BeforeEach(func() {
-					deletePrometheusRuleDeployment.ErrorResponse = consterror.CustomError
+					get.CalledTimes = 1
+					delete = helper.CustomErrorHappensOnce()
 				})
 				It("should bubble up the error", func() {
 					Expect(err).To(HaveOccurred())
@@ -288,6 +297,19 @@ var _ = Describe("Routemonitor", func() {
 					Expect(err).To(MatchError(consterror.CustomError))
 				})
 			})
+			When("the kind of the ServiceMonitor is recorded", func() {
+				BeforeEach(func() {
+					routeMonitor.Status.ServiceMonitorRef.Kind = servicemonitor.ProbeKind
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.Probe{})).Times(1)
+					get.CalledTimes = 1
+					mockUtils.EXPECT().DeleteFinalizer(gomock.Any(), gomock.Any()).Return(true).Times(2)
+					mockUtils.EXPECT().UpdateMonitorResource(gomock.Any()).Return(utilreconcile.StopOperation(), nil)
+				})
+				It("deletes the resource of that kind", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(res).To(Equal(utilreconcile.StopOperation()))
+				})
+			})
 			When("the resource has a finalizer but 'Update' succeeds", func() {
 				BeforeEach(func() {
 					mockUtils.EXPECT().DeleteFinalizer(gomock.Any(), gomock.Any()).Return(true).Times(2)
@@ -303,13 +325,11 @@ var _ = Describe("Routemonitor", func() {
 				BeforeEach(func() {
 					routeMonitorFinalizers = []string{}
 					routeMonitorDeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
-					delete.CalledTimes = 1
 					mockUtils.EXPECT().DeleteFinalizer(gomock.Any(), gomock.Any()).Return(false)
 				})
 				When("no deletion was requested", func() {
 					BeforeEach(func() {
 						routeMonitorDeletionTimestamp = nil
-						delete.CalledTimes = 0
 						deleteFinalizer.CalledTimes = 1
 					})
 					It("should skip next steps and stop processing", func() {
@@ -490,6 +510,132 @@ var _ = Describe("Routemonitor", func() {
 		})
 	})
 	//--------------------------------------------------------------------------------------
+	// 		EnsureRouteURLOverridden
+	//--------------------------------------------------------------------------------------
+	Describe("EnsureRouteURLOverridden", func() {
+		var (
+			res utilreconcile.Result
+			err error
+		)
+		BeforeEach(func() {
+			routeMonitor.Spec.RouteURLOverride = "https://www.example.com/healthz"
+		})
+		JustBeforeEach(func() {
+			res, err = routeMonitorReconciler.EnsureRouteURLOverridden(&routeMonitor)
+		})
+		When("the RouteURL differs from the override", func() {
+			It("should update the RouteURL with the override", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(routeMonitor.Status.RouteURL).To(Equal("https://www.example.com/healthz"))
+			})
+		})
+		When("the RouteURL is the override", func() {
+			BeforeEach(func() {
+				routeMonitor.Status.RouteURL = routeMonitor.Spec.RouteURLOverride
+			})
+			It("should skip this operation", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+	//--------------------------------------------------------------------------------------
+	// 		EnsureTargetConflictsReported
+	//--------------------------------------------------------------------------------------
+	Describe("EnsureTargetConflictsReported", func() {
+		var (
+			res                utilreconcile.Result
+			err                error
+			others             []v1alpha1.RouteMonitor
+			clusterUrlMonitors []v1alpha1.ClusterUrlMonitor
+			urlMonitors        []v1alpha1.UrlMonitor
+		)
+		otherMonitor := func(namespace, target string) v1alpha1.RouteMonitor {
+			return v1alpha1.RouteMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "ramona-flowers", Namespace: namespace},
+				Spec:       v1alpha1.RouteMonitorSpec{Slo: v1alpha1.SloSpec{TargetAvailabilityPercent: target}},
+				Status:     v1alpha1.RouteMonitorStatus{RouteURL: "fake-route-url"},
+			}
+		}
+		BeforeEach(func() {
+			routeMonitor.DeletionTimestamp = nil
+			others = nil
+			clusterUrlMonitors = nil
+			urlMonitors = nil
+		})
+		JustBeforeEach(func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				switch list := list.(type) {
+				case *v1alpha1.RouteMonitorList:
+					list.Items = append([]v1alpha1.RouteMonitor{routeMonitor}, others...)
+				case *v1alpha1.ClusterUrlMonitorList:
+					list.Items = clusterUrlMonitors
+				case *v1alpha1.UrlMonitorList:
+					list.Items = urlMonitors
+				}
+				return nil
+			}).Times(3)
+			res, err = routeMonitorReconciler.EnsureTargetConflictsReported(&routeMonitor)
+		})
+		When("another monitor probes the url with a different target", func() {
+			BeforeEach(func() {
+				others = []v1alpha1.RouteMonitor{otherMonitor("knives-chau", "99.9"), otherMonitor("envy-adams", "99.5")}
+			})
+			It("should report the conflicting monitor", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionTargetConflict)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Reason).To(Equal(reconcileCommon.ReasonConflictingSLO))
+				Expect(condition.Message).To(ContainSubstring("knives-chau/ramona-flowers"))
+				Expect(condition.Message).NotTo(ContainSubstring("envy-adams"))
+			})
+		})
+		When("a ClusterUrlMonitor and a UrlMonitor probe the url with a different target", func() {
+			BeforeEach(func() {
+				clusterUrlMonitors = []v1alpha1.ClusterUrlMonitor{{
+					ObjectMeta: metav1.ObjectMeta{Name: "gideon-graves", Namespace: "chaos-theatre"},
+					Spec:       v1alpha1.ClusterUrlMonitorSpec{Slo: v1alpha1.SloSpec{TargetAvailabilityPercent: "99.9"}},
+					Status:     v1alpha1.ClusterUrlMonitorStatus{ClusterURL: "fake-route-url"},
+				}}
+				urlMonitors = []v1alpha1.UrlMonitor{{
+					ObjectMeta: metav1.ObjectMeta{Name: "todd-ingram", Namespace: "vegan-academy"},
+					Spec:       v1alpha1.UrlMonitorSpec{URL: "fake-route-url", Slo: v1alpha1.SloSpec{TargetAvailabilityPercent: "99.0"}},
+				}}
+			})
+			It("should report them with their kind", func() {
+				Expect(err).NotTo(HaveOccurred())
+				condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionTargetConflict)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Message).To(ContainSubstring("ClusterUrlMonitor chaos-theatre/gideon-graves"))
+				Expect(condition.Message).To(ContainSubstring("UrlMonitor vegan-academy/todd-ingram"))
+			})
+		})
+		When("other monitors probe the url with the same target", func() {
+			BeforeEach(func() {
+				others = []v1alpha1.RouteMonitor{otherMonitor("envy-adams", "99.5")}
+			})
+			It("should skip this operation", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+		When("a previously reported conflict is resolved", func() {
+			BeforeEach(func() {
+				routeMonitor.Status.Conditions = []metav1.Condition{{
+					Type:   v1alpha1.ConditionTargetConflict,
+					Status: metav1.ConditionTrue,
+					Reason: reconcileCommon.ReasonConflictingSLO,
+				}}
+			})
+			It("should remove the condition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionTargetConflict)).To(BeNil())
+			})
+		})
+	})
+	//--------------------------------------------------------------------------------------
 	// 		EnsureRouteURLExists
 	//--------------------------------------------------------------------------------------
 	Describe("EnsureRouteURLExists", func() {
@@ -502,9 +648,11 @@ var _ = Describe("Routemonitor", func() {
 			routeMonitorName      string
 			routeMonitorNamespace string
 
-			res       utilreconcile.Result
-			err       error
-			ingresses []string
+			res         utilreconcile.Result
+			err         error
+			ingresses   []string
+			routerNames []string
+			routeTLS    *routev1.TLSConfig
 		)
 
 		// Start Fuzz testing for values
@@ -517,17 +665,23 @@ var _ = Describe("Routemonitor", func() {
 				Namespace: routeMonitorNamespace,
 			}
 			expectedRouteMonitor = routeMonitor
+			routerNames = nil
+			routeTLS = nil
 		})
 
 		JustBeforeEach(func() {
 			route = routev1.Route{
+				Spec: routev1.RouteSpec{TLS: routeTLS},
 				Status: routev1.RouteStatus{
 					Ingress: ConvertToIngressHosts(ingresses),
 				},
 			}
+			for i, routerName := range routerNames {
+				route.Status.Ingress[i].RouterName = routerName
+			}
 
 			// act
-			res, err = routeMonitorReconciler.EnsureRouteURLExists(route, routeMonitor)
+			res, err = routeMonitorReconciler.EnsureRouteURLExists(route, &routeMonitor)
 		})
 
 		When("the Route has no Ingresses", func() {
@@ -549,26 +703,6 @@ var _ = Describe("Routemonitor", func() {
 			})
 		})
 
-		When("func Update fails unexpectedly", func() {
-			var (
-				firstRouteURL = "freddy"
-			)
-			BeforeEach(func() {
-				ingresses = []string{
-					firstRouteURL,
-					"eddie",
-				}
-				mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Times(1).Return(utilreconcile.RequeueOperation(), consterror.CustomError)
-				routeMonitorReconciler.Client = mockClient
-			})
-			JustBeforeEach(func() {
-				expectedRouteMonitor.Status.RouteURL = firstRouteURL
-			})
-			It("should bubble up the error", func() {
-				Expect(err).To(HaveOccurred())
-				Expect(err).To(MatchError(consterror.CustomError))
-			})
-		})
 		When("the Route has too many Ingress", func() {
 			var (
 				firstRouteURL = "freddy"
@@ -578,7 +712,6 @@ var _ = Describe("Routemonitor", func() {
 					firstRouteURL,
 					"eddie",
 				}
-				mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Times(1).Return(utilreconcile.StopOperation(), nil)
 			})
 			JustBeforeEach(func() {
 				expectedRouteMonitor.Status.RouteURL = firstRouteURL
@@ -586,7 +719,8 @@ var _ = Describe("Routemonitor", func() {
 			It("should update the first ingress", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res).NotTo(BeNil())
-				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(routeMonitor.Status.RouteURL).To(Equal(firstRouteURL))
 			})
 		})
 		When("the RouteURL is not like the extracted Route", func() {
@@ -597,8 +731,6 @@ var _ = Describe("Routemonitor", func() {
 				ingresses = []string{
 					firstRouteURL,
 				}
-
-				mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Times(1).Return(utilreconcile.StopOperation(), nil)
 			})
 			JustBeforeEach(func() {
 				routeMonitor.Status.RouteURL = firstRouteURL + "but-different"
@@ -608,10 +740,89 @@ var _ = Describe("Routemonitor", func() {
 			It("should update with the Route information", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res).NotTo(BeNil())
-				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
 			})
 		})
 
+		When("the RouteMonitor probes all ingresses", func() {
+			BeforeEach(func() {
+				ingresses = []string{"default-host", "sharded-host"}
+				routeMonitor.Spec.Route.ProbeAllIngresses = true
+				routeMonitor.Spec.Route.RouterName = "sharded"
+			})
+			It("should record the url of every ingress and keep the first as the RouteURL", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routeMonitor.Status.RouteURL).To(Equal("default-host"))
+				Expect(routeMonitor.Status.IngressURLs).To(Equal([]string{"default-host", "sharded-host"}))
+			})
+		})
+
+		Describe("the RouteMonitor selects the ingress of a router", func() {
+			BeforeEach(func() {
+				ingresses = []string{"default-host", "sharded-host"}
+				routerNames = []string{"default", "sharded"}
+			})
+			When("the Route was admitted by the router", func() {
+				BeforeEach(func() {
+					routeMonitor.Spec.Route.RouterName = "sharded"
+				})
+				It("should probe the host of the router's ingress", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(routeMonitor.Status.RouteURL).To(Equal("sharded-host"))
+				})
+			})
+			When("the Route wasn't admitted by the router", func() {
+				BeforeEach(func() {
+					routeMonitor.Spec.Route.RouterName = "unknown"
+				})
+				It("should return No Ingress error", func() {
+					Expect(res).To(Equal(utilreconcile.RequeueOperation()))
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(HavePrefix("No Ingress:"))
+				})
+			})
+		})
+
+		When("the RouteMonitor defines a port and a suffix", func() {
+			BeforeEach(func() {
+				ingresses = []string{"fake-route-url"}
+				routeMonitor.Spec.Route.Port = 8443
+				routeMonitor.Spec.Route.Suffix = "/healthz"
+			})
+			It("should probe the path on the route host", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routeMonitor.Status.RouteURL).To(Equal("fake-route-url:8443/healthz"))
+			})
+		})
+
+		Describe("the scheme of the probed url", func() {
+			BeforeEach(func() {
+				ingresses = []string{"fake-route-url"}
+			})
+			for _, tc := range []struct {
+				name     string
+				tls      *routev1.TLSConfig
+				scheme   v1alpha1.RouteScheme
+				expected string
+			}{
+				{"a Route with TLS", &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}, "", "https://fake-route-url"},
+				{"a Route with TLS forced to http", &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}, v1alpha1.RouteSchemeHTTP, "http://fake-route-url"},
+				{"a Route without TLS forced to https", nil, v1alpha1.RouteSchemeHTTPS, "https://fake-route-url"},
+			} {
+				tc := tc
+				When("probing "+tc.name, func() {
+					BeforeEach(func() {
+						routeTLS = tc.tls
+						routeMonitor.Spec.Route.Scheme = tc.scheme
+					})
+					It("should probe "+tc.expected, func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(routeMonitor.Status.RouteURL).To(Equal(tc.expected))
+					})
+				})
+			}
+		})
+
 		When("the Route has the same RouteURL as the extracted one", func() {
 			BeforeEach(func() {
 				ingresses = []string{
@@ -641,74 +852,82 @@ var _ = Describe("Routemonitor", func() {
 			err  error
 		)
 		JustBeforeEach(func() {
-			resp, err = routeMonitorReconciler.EnsurePrometheusRuleExists(routeMonitor)
+			resp, err = routeMonitorReconciler.EnsurePrometheusRuleExists(&routeMonitor)
+		})
+		Describe("The availability target changed", func() {
+			BeforeEach(func() {
+				routeMonitor.Status.SloTarget = "99.9"
+				mockUtils.EXPECT().ParseMonitorSLOSpecs(routeMonitor.Status.RouteURL, routeMonitor.Spec.Slo).Return("99.5", nil).Times(1)
+				mockUtils.EXPECT().SetErrorStatus(gomock.Any(), nil).Return(false)
+				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any())
+				mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(false, nil)
+			})
+			It("records the change in the status and continues reconciling", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(routeMonitor.Status.SloTarget).To(Equal("99.5"))
+				Expect(routeMonitor.Status.LastSloChange).NotTo(BeNil())
+				Expect(routeMonitor.Status.LastSloChange.From).To(Equal("99.9"))
+				Expect(routeMonitor.Status.LastSloChange.To).To(Equal("99.5"))
+			})
+		})
+		Describe("The RouteMonitor monitors a hosted control plane", func() {
+			BeforeEach(func() {
+				routeMonitorReconciler.EnableHypershift = true
+				routeMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "test", Namespace: "the-world"}
+				meta.SetStatusCondition(&routeMonitor.Status.Conditions, metav1.Condition{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled})
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("the-world")).DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					list.(*hypershiftv1beta1.HostedControlPlaneList).Items = []hypershiftv1beta1.HostedControlPlane{{ObjectMeta: metav1.ObjectMeta{Name: "hcp", Namespace: "the-world"}}}
+					return nil
+				})
+				mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef).Times(1)
+				mockUtils.EXPECT().SetResourceReference(gomock.Any(), types.NamespacedName{}).Return(true, nil)
+			})
+			It("deletes the PrometheusRule of the management cluster, as alerting is implemented in RHOBS", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)).To(BeNil())
+			})
+		})
+		Describe("The RouteMonitor only asks for a RHOBS ServiceMonitor", func() {
+			BeforeEach(func() {
+				routeMonitor.Spec.ServiceMonitorType = v1alpha1.ServiceMonitorTypeRHOBS
+				mockUtils.EXPECT().ParseMonitorSLOSpecs(routeMonitor.Status.RouteURL, routeMonitor.Spec.Slo).Return("99.5", nil).Times(1)
+				mockUtils.EXPECT().SetErrorStatus(gomock.Any(), nil).Return(false)
+				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any())
+				mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
+			})
+			It("keeps its PrometheusRule", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.IsStatusConditionTrue(routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)).To(BeTrue())
+			})
 		})
 		Describe("The RouteMonitor settings are INVALID", func() {
 			BeforeEach(func() {
+				routeMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "test", Namespace: "test2"}
 				mockUtils.EXPECT().ParseMonitorSLOSpecs(routeMonitor.Status.RouteURL, routeMonitor.Spec.Slo).Return("", customerrors.NoHost).Times(1)
+				mockUtils.EXPECT().SetErrorStatus(gomock.Any(), customerrors.NoHost).Return(true)
 			})
-			Describe("It sets the Error state in the RouteMonitor the first time", func() {
+			When("the PrometheusRule deletion fails", func() {
 				BeforeEach(func() {
-					mockUtils.EXPECT().SetErrorStatus(gomock.Any(), customerrors.NoHost).Return(true)
-				})
-				When("updating the RouteMonitor with the new error State works", func() {
-					BeforeEach(func() {
-						mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.StopOperation(), nil)
-					})
-					It("stops reconcileing", func() {
-						Expect(err).NotTo(HaveOccurred())
-						Expect(resp).To(Equal(utilreconcile.StopOperation()))
-					})
+					mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef).Times(1).Return(consterror.CustomError)
 				})
-				When("updating the RouteMonitor new error State failes", func() {
-					BeforeEach(func() {
-						mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.RequeueOperation(), consterror.CustomError)
-					})
-					It("requeues with the particular error", func() {
-						Expect(err).To(Equal(consterror.CustomError))
-						Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
-					})
+				It("should reconcile with the particular error", func() {
+					Expect(err).To(Equal(consterror.CustomError))
+					Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
+					expectArtifactFailure(routeMonitor, v1alpha1.ConditionPrometheusRuleReady)
 				})
 			})
-
-			Describe("It deletes existing PrometheusRules", func() {
+			When("the PrometheusRule deletion was successful", func() {
 				BeforeEach(func() {
-					routeMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "test", Namespace: "test2"}
-					mockUtils.EXPECT().SetErrorStatus(gomock.Any(), customerrors.NoHost).Return(false)
-				})
-				When("the PrometheusRule deletion fails", func() {
-					BeforeEach(func() {
-						mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef).Times(1).Return(consterror.CustomError)
-					})
-					It("should reconcile with the particular error", func() {
-						Expect(err).To(Equal(consterror.CustomError))
-						Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
-					})
+					mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef).Times(1)
+					mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
 				})
-				When("the PrometheusRule deletion was successful", func() {
-					BeforeEach(func() {
-						mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(routeMonitor.Status.PrometheusRuleRef).Times(1)
-					})
-					When("updating PrometheusRuleRef in the RouteMonitor fails", func() {
-						BeforeEach(func() {
-							mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
-							mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.RequeueOperation(), consterror.CustomError)
-						})
-						It("should reconcile with the particular error", func() {
-							Expect(err).To(Equal(consterror.CustomError))
-							Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
-						})
-					})
-					When("updating PrometheusRuleRef in the RouteMonitor was successful", func() {
-						BeforeEach(func() {
-							mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
-							mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.StopOperation(), nil)
-						})
-						It("stops reconciling", func() {
-							Expect(err).NotTo(HaveOccurred())
-							Expect(resp).To(Equal(utilreconcile.StopOperation()))
-						})
-					})
+				It("reports the error in the status and stops reconciling", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.StopOperation()))
+					expectArtifactFailure(routeMonitor, v1alpha1.ConditionPrometheusRuleReady)
 				})
 			})
 		})
@@ -724,34 +943,35 @@ var _ = Describe("Routemonitor", func() {
 				It("requeues with the error", func() {
 					Expect(err).To(Equal(consterror.CustomError))
 					Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
+					expectArtifactFailure(routeMonitor, v1alpha1.ConditionPrometheusRuleReady)
 				})
 			})
 			When("the update of the PrometheusRule succeded", func() {
 				BeforeEach(func() {
 					mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any())
+					mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
 				})
-				When("a new PrometheusRule was created", func() {
-					BeforeEach(func() {
-						mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
-					})
-					When("the ServiceMonitor is updated successfully", func() {
-						BeforeEach(func() {
-							mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.StopOperation(), nil)
-						})
-						It("stops reconciling", func() {
-							Expect(err).NotTo(HaveOccurred())
-							Expect(resp).To(Equal(utilreconcile.StopOperation()))
-						})
-					})
-					When("updating the reference fails", func() {
-						BeforeEach(func() {
-							mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.RequeueOperation(), consterror.CustomError)
-						})
-						It("requeus with error", func() {
-							Expect(err).To(Equal(consterror.CustomError))
-							Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
-						})
+				It("reports it in the status and continues reconciling", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+					Expect(meta.IsStatusConditionTrue(routeMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)).To(BeTrue())
+				})
+			})
+			When("every ingress of the Route is probed", func() {
+				var rule monitoringv1.PrometheusRule
+				BeforeEach(func() {
+					routeMonitor.Status.IngressURLs = []string{"https://default-host/healthz", "https://sharded-host/healthz"}
+					mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any()).DoAndReturn(func(template monitoringv1.PrometheusRule) error {
+						rule = template
+						return nil
 					})
+					mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
+				})
+				It("alerts on the probes of all of them", func() {
+					Expect(err).NotTo(HaveOccurred())
+					expr := rule.Spec.Groups[0].Rules[0].Expr.String()
+					Expect(expr).To(ContainSubstring("default-host"))
+					Expect(expr).To(ContainSubstring("sharded-host"))
 				})
 			})
 		})
@@ -765,7 +985,19 @@ var _ = Describe("Routemonitor", func() {
 			err  error
 		)
 		JustBeforeEach(func() {
-			resp, err = routeMonitorReconciler.EnsureServiceMonitorExists(routeMonitor)
+			resp, err = routeMonitorReconciler.EnsureServiceMonitorExists(&routeMonitor)
+		})
+		When("the ServiceMonitor is skipped", func() {
+			BeforeEach(func() {
+				routeMonitor.Spec.SkipServiceMonitor = true
+				routeMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "scott-pilgrim", Namespace: "the-world"}
+				mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef).Times(1)
+				mockUtils.EXPECT().SetResourceReference(gomock.Any(), types.NamespacedName{}).Return(true, nil)
+			})
+			It("deletes the existing ServiceMonitor and clears the reference", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+			})
 		})
 		When("The RouteUrl is not set", func() {
 			BeforeEach(func() {
@@ -774,24 +1006,40 @@ var _ = Describe("Routemonitor", func() {
 			It("will requeue with the NoHost error ", func() {
 				Expect(err).To(Equal(customerrors.NoHost))
 				Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
+				expectArtifactFailure(routeMonitor, v1alpha1.ConditionServiceMonitorReady)
+			})
+		})
+		When("the exporter couldn't load the module of the RouteMonitor", func() {
+			BeforeEach(func() {
+				routeMonitor.Spec.HTTPProbe = &v1alpha1.HTTPProbeSpec{ProxyURL: "://proxy.example.com"}
+				mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
+			})
+			It("reports the invalid module on the RouteMonitor", func() {
+				var invalidConfig *blackboxexporterpkg.InvalidConfigError
+				Expect(errors.As(err, &invalidConfig)).To(BeTrue())
+				Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
+				expectArtifactFailure(routeMonitor, v1alpha1.ConditionServiceMonitorReady)
 			})
 		})
 		Describe("It updates the ServiceMonitor targeting the blackbox Exporter Namespace", func() {
 			When("the update of the ServiceMonitor fails", func() {
 				BeforeEach(func() {
-					mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(consterror.CustomError)
+					mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(types.UID(""), consterror.CustomError)
 					mockBlackboxExporter.EXPECT().GetBlackBoxExporterNamespace().Return("bla")
+					mockBlackboxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
 					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
 				})
 				It("will requeue with the error", func() {
 					Expect(err).To(Equal(consterror.CustomError))
 					Expect(resp).To(Equal(utilreconcile.RequeueOperation()))
+					expectArtifactFailure(routeMonitor, v1alpha1.ConditionServiceMonitorReady)
 				})
 			})
 			When("the update of the ServiceMonitor is successfull", func() {
 				BeforeEach(func() {
-					mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+					mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 					mockBlackboxExporter.EXPECT().GetBlackBoxExporterNamespace().Return("bla")
+					mockBlackboxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
 					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
 				})
 				When("the update of the ServiceMonitorRef fails", func() {
@@ -807,18 +1055,149 @@ var _ = Describe("Routemonitor", func() {
 					BeforeEach(func() {
 						mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
 					})
-					When("it updates the RouteMonitor", func() {
-						BeforeEach(func() {
-							mockUtils.EXPECT().UpdateMonitorResourceStatus(gomock.Any()).Return(utilreconcile.StopOperation(), nil)
-						})
-						It("stops reconciiling", func() {
-							Expect(err).NotTo(HaveOccurred())
-							Expect(resp).To(Equal(utilreconcile.StopOperation()))
-						})
+					It("reports it in the status and continues reconciling", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+						Expect(meta.IsStatusConditionTrue(routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)).To(BeTrue())
 					})
 				})
 			})
 		})
+		Describe("It generates a RHOBS ServiceMonitor for a hosted control plane", func() {
+			expectServiceMonitor := func(hcp bool, clusterID string) {
+				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment(gomock.Any(), gomock.Any(), gomock.Any(), clusterID, hcp, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+				mockBlackboxExporter.EXPECT().GetBlackBoxExporterNamespace().Return("bla")
+				mockBlackboxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
+				mockUtils.EXPECT().SetResourceReference(gomock.Any(), gomock.Any()).Return(true, nil)
+			}
+			When("the RouteMonitor asks for a RHOBS ServiceMonitor", func() {
+				BeforeEach(func() {
+					routeMonitor.Spec.ServiceMonitorType = v1alpha1.ServiceMonitorTypeRHOBS
+					mockUtils.EXPECT().GetHypershiftClusterID("the-world").Return("hosted-cluster-id", nil)
+					expectServiceMonitor(true, "hosted-cluster-id")
+				})
+				It("labels it with the hosted cluster's ID", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				})
+			})
+			When("Hypershift is enabled and the RouteMonitor is in the namespace of a HostedControlPlane", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.EnableHypershift = true
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("the-world")).DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+						list.(*hypershiftv1beta1.HostedControlPlaneList).Items = []hypershiftv1beta1.HostedControlPlane{{ObjectMeta: metav1.ObjectMeta{Name: "hcp", Namespace: "the-world"}}}
+						return nil
+					})
+					mockUtils.EXPECT().GetHypershiftClusterID("the-world").Return("hosted-cluster-id", nil)
+					expectServiceMonitor(true, "hosted-cluster-id")
+				})
+				It("labels it with the hosted cluster's ID and records its kind", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+					Expect(routeMonitor.Status.ServiceMonitorRef.Kind).To(Equal(servicemonitor.RHOBSServiceMonitorKind))
+				})
+			})
+			When("Hypershift is enabled but the namespace has no HostedControlPlane", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.EnableHypershift = true
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("the-world")).Return(nil)
+					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
+					expectServiceMonitor(false, "test-cluster-id")
+				})
+				It("generates the ServiceMonitor of the cluster", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+					Expect(routeMonitor.Status.ServiceMonitorRef.Kind).To(Equal(servicemonitor.ServiceMonitorKind))
+				})
+			})
+			When("the RouteMonitor opts out of Hypershift", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.EnableHypershift = true
+					routeMonitor.Spec.ServiceMonitorType = v1alpha1.ServiceMonitorTypeRHOBS
+					routeMonitor.Spec.Hypershift = &v1alpha1.HypershiftSpec{Enabled: false}
+					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
+					expectServiceMonitor(false, "test-cluster-id")
+				})
+				It("generates the ServiceMonitor of the cluster without looking up a HostedControlPlane", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				})
+			})
+			When("the HostedControlPlane of the RouteMonitor was deleted", func() {
+				BeforeEach(func() {
+					routeMonitor.Spec.ServiceMonitorType = v1alpha1.ServiceMonitorTypeRHOBS
+					routeMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-name", Namespace: "the-world"}
+					mockUtils.EXPECT().GetHypershiftClusterID("the-world").Return("", fmt.Errorf("failed to retrieve the HostedControlPlane: %w", customerrors.NoHostedControlPlane))
+					mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef).Times(1).Return(nil)
+					mockUtils.EXPECT().SetResourceReference(gomock.Any(), types.NamespacedName{}).Return(true, nil)
+				})
+				It("deletes the RHOBS ServiceMonitor and orphans the RouteMonitor without requeueing", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.StopOperation()))
+					condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+					Expect(condition).NotTo(BeNil())
+					Expect(condition.Reason).To(Equal(reconcileCommon.ReasonHostedControlPlaneDeleted))
+				})
+			})
+			When("the HostedControlPlane of the namespace of the RouteMonitor was deleted", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.EnableHypershift = true
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("the-world")).Return(nil)
+					mockUtils.EXPECT().GetHypershiftClusterID("the-world").Return("", fmt.Errorf("failed to retrieve the HostedControlPlane: %w", customerrors.NoHostedControlPlane))
+					mockUtils.EXPECT().SetResourceReference(gomock.Any(), types.NamespacedName{}).Return(true, nil)
+				})
+				When("it still references its RHOBS ServiceMonitor", func() {
+					BeforeEach(func() {
+						routeMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-name", Namespace: "the-world", Kind: servicemonitor.RHOBSServiceMonitorKind}
+						mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(routeMonitor.Status.ServiceMonitorRef).Times(1).Return(nil)
+					})
+					It("deletes the RHOBS ServiceMonitor and orphans the RouteMonitor instead of monitoring the management cluster", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resp).To(Equal(utilreconcile.StopOperation()))
+						condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+						Expect(condition).NotTo(BeNil())
+						Expect(condition.Reason).To(Equal(reconcileCommon.ReasonHostedControlPlaneDeleted))
+					})
+				})
+				When("it's already orphaned", func() {
+					BeforeEach(func() {
+						reconcileCommon.SetHostedControlPlaneDeletedCondition(&routeMonitor.Status.Conditions, routeMonitor.Generation, "the-world")
+						mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(v1alpha1.NamespacedName{}).Times(1).Return(nil)
+					})
+					It("keeps it orphaned instead of monitoring the management cluster", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resp).To(Equal(utilreconcile.StopOperation()))
+					})
+				})
+			})
+			When("no monitoring API serving ServiceMonitors is installed", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.MonitoringAPIs = servicemonitor.NewMonitoringAPIs(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}})
+					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
+				})
+				It("reports it in the status and checks again later without failing", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.RequeueAfter(servicemonitor.APIDiscoveryInterval).Because("no monitoring API is installed")))
+					condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+					Expect(condition).NotTo(BeNil())
+					Expect(condition.Reason).To(Equal(reconcileCommon.ReasonMonitoringAPIUnavailable))
+				})
+			})
+			When("only the monitoring.rhobs API is installed", func() {
+				BeforeEach(func() {
+					routeMonitorReconciler.MonitoringAPIs = servicemonitor.NewMonitoringAPIs(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+						GroupVersion: "monitoring.rhobs/v1",
+						APIResources: []metav1.APIResource{{Name: "servicemonitors"}},
+					}}}})
+					mockUtils.EXPECT().GetOSDClusterID().Return("test-cluster-id", nil)
+					expectServiceMonitor(true, "test-cluster-id")
+				})
+				It("generates a RHOBS ServiceMonitor labeled with the cluster's ID", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp).To(Equal(utilreconcile.ContinueOperation()))
+				})
+			})
+		})
 	})
 })
 
@@ -835,3 +1214,10 @@ func ConvertToIngressHosts(in []string) []routev1.RouteIngress {
 	}
 	return res
 }
+
+// expectArtifactFailure expects the failure of a generated resource to be reported in the status
+func expectArtifactFailure(routeMonitor v1alpha1.RouteMonitor, conditionType string) {
+	condition := meta.FindStatusCondition(routeMonitor.Status.Conditions, conditionType)
+	Expect(condition).NotTo(BeNil())
+	Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+}
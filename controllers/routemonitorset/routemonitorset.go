@@ -0,0 +1,266 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routemonitorset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// GeneratedBySetLabel is set on generated RouteMonitors to the name of the RouteMonitorSet they were generated from
+	GeneratedBySetLabel = "monitoring.openshift.io/generated-by-set"
+)
+
+var logger logr.Logger = ctrl.Log.WithName("controllers").WithName("RouteMonitorSet")
+
+// RouteMonitorSetReconciler generates RouteMonitors for the Routes selected by RouteMonitorSets
+type RouteMonitorSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// NewRouteMonitorSetReconciler creates a RouteMonitorSetReconciler
+func NewRouteMonitorSetReconciler(mgr manager.Manager) *RouteMonitorSetReconciler {
+	return &RouteMonitorSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitorsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitorsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitorsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=routemonitors,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile generates a RouteMonitor for every Route selected by a RouteMonitorSet and removes the ones whose
+// Route isn't selected anymore. Deleting the RouteMonitorSet removes its RouteMonitors through their OwnerReferences
+func (r *RouteMonitorSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logger.WithName("Reconcile").WithValues("name", req.Name)
+
+	set := &v1alpha1.RouteMonitorSet{}
+	err := r.Client.Get(ctx, req.NamespacedName, set)
+	if err != nil {
+		if kerr.IsNotFound(err) {
+			log.V(logging.DebugVerbosity).Info("RouteMonitorSet not found, assumed deleted")
+			return utilreconcile.Stop()
+		}
+		return utilreconcile.RequeueWith(err)
+	}
+	if set.DeletionTimestamp != nil {
+		return utilreconcile.Stop()
+	}
+
+	routes, err := r.selectedRoutes(ctx, set)
+	if err != nil {
+		if _, ok := err.(invalidSelectorError); ok {
+			// Retrying doesn't fix the selectors, the next change of the RouteMonitorSet triggers a reconcile
+			log.Error(err, "skipped generating RouteMonitors: invalid selector")
+			return utilreconcile.RequeueWith(r.updateStatus(ctx, set, set.Status.RouteMonitors, err))
+		}
+		return utilreconcile.RequeueWith(err)
+	}
+
+	expected := buildRouteMonitors(set, routes)
+	err = r.deployRouteMonitors(ctx, log, set, expected)
+	if err != nil {
+		log.Error(err, "failed to deploy generated RouteMonitors")
+		if updateErr := r.updateStatus(ctx, set, set.Status.RouteMonitors, err); updateErr != nil {
+			log.Error(updateErr, "failed to report the failure in the status")
+		}
+		return utilreconcile.RequeueWith(err)
+	}
+	return utilreconcile.RequeueWith(r.updateStatus(ctx, set, len(expected), nil))
+}
+
+// invalidSelectorError is returned for selectors of a RouteMonitorSet that can't be parsed
+type invalidSelectorError struct {
+	error
+}
+
+// selectedRoutes returns the Routes matching the route selector, in the namespaces matching the namespace selector
+func (r *RouteMonitorSetReconciler) selectedRoutes(ctx context.Context, set *v1alpha1.RouteMonitorSet) ([]routev1.Route, error) {
+	routeSelector, err := metav1.LabelSelectorAsSelector(&set.Spec.RouteSelector)
+	if err != nil {
+		return nil, invalidSelectorError{fmt.Errorf("invalid routeSelector: %w", err)}
+	}
+	var namespaces map[string]bool
+	if set.Spec.NamespaceSelector != nil {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(set.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, invalidSelectorError{fmt.Errorf("invalid namespaceSelector: %w", err)}
+		}
+		namespaceList := corev1.NamespaceList{}
+		if err := r.Client.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, err
+		}
+		namespaces = map[string]bool{}
+		for _, namespace := range namespaceList.Items {
+			namespaces[namespace.Name] = true
+		}
+	}
+
+	routeList := routev1.RouteList{}
+	if err := r.Client.List(ctx, &routeList, client.MatchingLabelsSelector{Selector: routeSelector}); err != nil {
+		return nil, err
+	}
+	routes := []routev1.Route{}
+	for _, route := range routeList.Items {
+		if route.DeletionTimestamp != nil || (namespaces != nil && !namespaces[route.Namespace]) {
+			continue
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// buildRouteMonitors constructs the RouteMonitors of the Routes selected by a RouteMonitorSet
+func buildRouteMonitors(set *v1alpha1.RouteMonitorSet, routes []routev1.Route) []v1alpha1.RouteMonitor {
+	routeMonitors := []v1alpha1.RouteMonitor{}
+	for _, route := range routes {
+		spec := *set.Spec.Template.DeepCopy()
+		spec.Route.Name = route.Name
+		spec.Route.Namespace = route.Namespace
+		routeMonitors = append(routeMonitors, v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            strings.Join([]string{set.Name, route.Name}, "-"),
+				Namespace:       route.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(set, v1alpha1.GroupVersion.WithKind("RouteMonitorSet"))},
+				Labels: map[string]string{
+					GeneratedBySetLabel: set.Name,
+				},
+			},
+			Spec: spec,
+		})
+	}
+	return routeMonitors
+}
+
+// deployRouteMonitors creates or updates the expected RouteMonitors and deletes the ones previously
+// generated from the RouteMonitorSet that aren't expected anymore
+func (r *RouteMonitorSetReconciler) deployRouteMonitors(ctx context.Context, log logr.Logger, set *v1alpha1.RouteMonitorSet, expected []v1alpha1.RouteMonitor) error {
+	expectedNames := map[types.NamespacedName]bool{}
+	for i := range expected {
+		expectedRouteMonitor := expected[i]
+		expectedNames[types.NamespacedName{Name: expectedRouteMonitor.Name, Namespace: expectedRouteMonitor.Namespace}] = true
+		err := r.Client.Create(ctx, &expectedRouteMonitor)
+		if err == nil {
+			continue
+		}
+		if !kerr.IsAlreadyExists(err) {
+			return err
+		}
+		// Object already exists: update it
+		actualRouteMonitor := v1alpha1.RouteMonitor{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: expectedRouteMonitor.Name, Namespace: expectedRouteMonitor.Namespace}, &actualRouteMonitor)
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(&actualRouteMonitor, set) {
+			return fmt.Errorf("RouteMonitor %s/%s already exists and wasn't generated from the RouteMonitorSet", actualRouteMonitor.Namespace, actualRouteMonitor.Name)
+		}
+		if reflect.DeepEqual(actualRouteMonitor.Spec, expectedRouteMonitor.Spec) &&
+			reflect.DeepEqual(actualRouteMonitor.Labels, expectedRouteMonitor.Labels) {
+			continue
+		}
+		actualRouteMonitor.Labels = expectedRouteMonitor.Labels
+		actualRouteMonitor.Spec = expectedRouteMonitor.Spec
+		err = r.Client.Update(ctx, &actualRouteMonitor)
+		if err != nil {
+			return err
+		}
+	}
+
+	generated := v1alpha1.RouteMonitorList{}
+	err := r.Client.List(ctx, &generated, client.MatchingLabels{GeneratedBySetLabel: set.Name})
+	if err != nil {
+		return err
+	}
+	for i := range generated.Items {
+		routeMonitor := &generated.Items[i]
+		if expectedNames[types.NamespacedName{Name: routeMonitor.Name, Namespace: routeMonitor.Namespace}] || !metav1.IsControlledBy(routeMonitor, set) {
+			continue
+		}
+		err = r.Client.Delete(ctx, routeMonitor)
+		if err != nil && !kerr.IsNotFound(err) {
+			return err
+		}
+		log.Info(fmt.Sprintf("Deleted RouteMonitor %s/%s: its Route is no longer selected", routeMonitor.Namespace, routeMonitor.Name))
+	}
+	return nil
+}
+
+// updateStatus records the number of generated RouteMonitors and the error of the last reconcile, if they changed
+func (r *RouteMonitorSetReconciler) updateStatus(ctx context.Context, set *v1alpha1.RouteMonitorSet, routeMonitors int, reconcileErr error) error {
+	status := v1alpha1.RouteMonitorSetStatus{RouteMonitors: routeMonitors}
+	if reconcileErr != nil {
+		status.ErrorStatus = reconcileErr.Error()
+	}
+	if set.Status == status {
+		return nil
+	}
+	set.Status = status
+	return r.Client.Status().Update(ctx, set)
+}
+
+// enqueueAllSets requeues every RouteMonitorSet, as any of them may select a changed Route or Namespace
+func (r *RouteMonitorSetReconciler) enqueueAllSets(ctx context.Context, _ client.Object) []reconcile.Request {
+	sets := v1alpha1.RouteMonitorSetList{}
+	if err := r.Client.List(ctx, &sets); err != nil {
+		logger.Error(err, "failed to list RouteMonitorSets")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(sets.Items))
+	for _, set := range sets.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: set.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RouteMonitorSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Reconciles against the RouteMonitorSets, the RouteMonitors they control so changes to generated
+	// RouteMonitors are reverted, and the Routes and Namespaces whose labels decide what is selected
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.RouteMonitorSet{}).
+		Owns(&v1alpha1.RouteMonitor{}).
+		Watches(&routev1.Route{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllSets)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllSets)).
+		Complete(r)
+}
@@ -0,0 +1,193 @@
+package routemonitorset
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestSet() *v1alpha1.RouteMonitorSet {
+	return &v1alpha1.RouteMonitorSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "shop",
+			UID:  "shop-uid",
+		},
+		Spec: v1alpha1.RouteMonitorSetSpec{
+			RouteSelector: metav1.LabelSelector{MatchLabels: map[string]string{"monitored": "true"}},
+			Template: v1alpha1.RouteMonitorSpec{
+				Route: v1alpha1.RouteMonitorRouteSpec{Name: "ignored", Suffix: "/healthz"},
+				Slo:   v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"},
+			},
+		},
+	}
+}
+
+func newTestRoute(namespace, name string, labels map[string]string) *routev1.Route {
+	return &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func Test_buildRouteMonitors(t *testing.T) {
+	set := newTestSet()
+	routes := []routev1.Route{
+		*newTestRoute("frontend", "web", nil),
+		*newTestRoute("backend", "api", nil),
+	}
+
+	routeMonitors := buildRouteMonitors(set, routes)
+	if len(routeMonitors) != 2 {
+		t.Fatalf("unexpected number of RouteMonitors: expected 2, got %d", len(routeMonitors))
+	}
+
+	want := []struct {
+		namespace string
+		name      string
+		route     v1alpha1.RouteMonitorRouteSpec
+	}{
+		{namespace: "frontend", name: "shop-web", route: v1alpha1.RouteMonitorRouteSpec{Name: "web", Namespace: "frontend", Suffix: "/healthz"}},
+		{namespace: "backend", name: "shop-api", route: v1alpha1.RouteMonitorRouteSpec{Name: "api", Namespace: "backend", Suffix: "/healthz"}},
+	}
+	for i, w := range want {
+		routeMonitor := routeMonitors[i]
+		if routeMonitor.Name != w.name || routeMonitor.Namespace != w.namespace {
+			t.Errorf("RouteMonitor %d is named %s/%s, want %s/%s", i, routeMonitor.Namespace, routeMonitor.Name, w.namespace, w.name)
+		}
+		if !reflect.DeepEqual(routeMonitor.Spec.Route, w.route) {
+			t.Errorf("RouteMonitor %d route = %#v, want %#v", i, routeMonitor.Spec.Route, w.route)
+		}
+		if routeMonitor.Spec.Slo.TargetAvailabilityPercent != "99.5" {
+			t.Errorf("RouteMonitor %d didn't inherit the template's slo: %#v", i, routeMonitor.Spec.Slo)
+		}
+		if !metav1.IsControlledBy(&routeMonitor, set) {
+			t.Errorf("RouteMonitor %d isn't controlled by the RouteMonitorSet: %#v", i, routeMonitor.OwnerReferences)
+		}
+		if routeMonitor.Labels[GeneratedBySetLabel] != set.Name {
+			t.Errorf("RouteMonitor %d isn't labeled with its RouteMonitorSet: %#v", i, routeMonitor.Labels)
+		}
+	}
+	if set.Spec.Template.Route.Name != "ignored" {
+		t.Errorf("the template of the RouteMonitorSet was modified: %#v", set.Spec.Template.Route)
+	}
+}
+
+func TestRouteMonitorSetReconciler_Reconcile(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		monitored = map[string]string{"monitored": "true"}
+		owner     = []metav1.OwnerReference{*metav1.NewControllerRef(newTestSet(), v1alpha1.GroupVersion.WithKind("RouteMonitorSet"))}
+		generated = map[string]string{GeneratedBySetLabel: "shop"}
+	)
+
+	tests := []struct {
+		name              string
+		namespaceSelector *metav1.LabelSelector
+		objs              []client.Object
+		wantErr           bool
+		wantNames         []string
+		wantStatus        v1alpha1.RouteMonitorSetStatus
+	}{
+		{
+			name: "RouteMonitors are created for the selected Routes",
+			objs: []client.Object{
+				newTestRoute("frontend", "web", monitored),
+				newTestRoute("backend", "api", monitored),
+				newTestRoute("backend", "internal", nil),
+			},
+			wantNames:  []string{"backend/shop-api", "frontend/shop-web"},
+			wantStatus: v1alpha1.RouteMonitorSetStatus{RouteMonitors: 2},
+		},
+		{
+			name:              "Routes outside the selected namespaces are ignored",
+			namespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			objs: []client.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Labels: map[string]string{"team": "web"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "backend"}},
+				newTestRoute("frontend", "web", monitored),
+				newTestRoute("backend", "api", monitored),
+			},
+			wantNames:  []string{"frontend/shop-web"},
+			wantStatus: v1alpha1.RouteMonitorSetStatus{RouteMonitors: 1},
+		},
+		{
+			name: "RouteMonitors of Routes no longer selected are pruned",
+			objs: []client.Object{
+				newTestRoute("frontend", "web", monitored),
+				newTestRoute("backend", "api", nil),
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop-api", Namespace: "backend", OwnerReferences: owner, Labels: generated},
+				},
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "backend"},
+				},
+			},
+			wantNames:  []string{"backend/unrelated", "frontend/shop-web"},
+			wantStatus: v1alpha1.RouteMonitorSetStatus{RouteMonitors: 1},
+		},
+		{
+			name: "RouteMonitors that weren't generated aren't taken over",
+			objs: []client.Object{
+				newTestRoute("frontend", "web", monitored),
+				&v1alpha1.RouteMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "shop-web", Namespace: "frontend"},
+				},
+			},
+			wantErr:   true,
+			wantNames: []string{"frontend/shop-web"},
+			wantStatus: v1alpha1.RouteMonitorSetStatus{
+				ErrorStatus: "RouteMonitor frontend/shop-web already exists and wasn't generated from the RouteMonitorSet",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := newTestSet()
+			set.Spec.NamespaceSelector = tt.namespaceSelector
+			r := newTestReconciler(append(tt.objs, set)...)
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: set.Name}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			routeMonitors := v1alpha1.RouteMonitorList{}
+			if err := r.List(ctx, &routeMonitors); err != nil {
+				t.Fatalf("failed to retrieve routemonitors from test client: %v", err)
+			}
+			names := []string{}
+			for _, routeMonitor := range routeMonitors.Items {
+				names = append(names, routeMonitor.Namespace+"/"+routeMonitor.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("RouteMonitors = %v, want %v", names, tt.wantNames)
+			}
+
+			if err := r.Get(ctx, types.NamespacedName{Name: set.Name}, set); err != nil {
+				t.Fatalf("failed to retrieve the RouteMonitorSet from test client: %v", err)
+			}
+			if set.Status != tt.wantStatus {
+				t.Errorf("status = %#v, want %#v", set.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func newTestReconciler(objs ...client.Object) *RouteMonitorSetReconciler {
+	client := fake.NewClientBuilder().
+		WithScheme(constinit.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.RouteMonitorSet{}).
+		Build()
+	return &RouteMonitorSetReconciler{
+		Client: client,
+		Scheme: constinit.Scheme,
+	}
+}
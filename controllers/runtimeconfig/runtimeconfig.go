@@ -0,0 +1,106 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimeconfig
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// InvalidConfigReason is the reason of the Event emitted for a ConfigMap holding invalid settings
+const InvalidConfigReason = "InvalidConfig"
+
+var logger logr.Logger = ctrl.Log.WithName("controllers").WithName("RuntimeConfig")
+
+// RuntimeConfigReconciler puts the settings of the operator's ConfigMap into effect whenever it changes
+type RuntimeConfigReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// ConfigMap is the ConfigMap holding the settings
+	ConfigMap types.NamespacedName
+	// Config receives the settings
+	Config *runtimeconfig.Config
+}
+
+// NewRuntimeConfigReconciler creates a RuntimeConfigReconciler reading the named ConfigMap in the operator's namespace
+func NewRuntimeConfigReconciler(mgr manager.Manager, name string, c *runtimeconfig.Config) *RuntimeConfigReconciler {
+	return &RuntimeConfigReconciler{
+		Client:    mgr.GetClient(),
+		Recorder:  mgr.GetEventRecorderFor(config.OperatorName),
+		ConfigMap: types.NamespacedName{Name: name, Namespace: config.OperatorNamespace},
+		Config:    c,
+	}
+}
+
+// +kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch
+
+// Reconcile applies the settings of the ConfigMap, or restores the defaults if it's gone
+func (r *RuntimeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logger.WithName("Reconcile").WithValues("name", req.Name, "namespace", req.Namespace)
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, req.NamespacedName, configMap)
+	if err != nil && !kerr.IsNotFound(err) {
+		return utilreconcile.RequeueWith(err)
+	}
+	var data map[string]string
+	if err == nil && configMap.DeletionTimestamp == nil {
+		data = configMap.Data
+	}
+
+	settings, err := r.Config.Apply(data)
+	if err != nil {
+		// Retrying doesn't fix the settings, the next change of the ConfigMap triggers a reconcile
+		log.Error(err, "kept the current settings: invalid settings")
+		r.Recorder.Eventf(configMap, corev1.EventTypeWarning, InvalidConfigReason, "Kept the current settings: %v", err)
+		return utilreconcile.Stop()
+	}
+	log.Info("Applied settings", "logLevel", settings.LogLevel.String(), "blackboxImage", settings.BlackBoxExporterImage,
+		"blackboxReplicas", settings.BlackBoxExporterReplicas, "blackboxResources", settings.BlackBoxExporterResources.String(),
+		"blackboxNetworkPolicy", settings.BlackBoxExporterNetworkPolicy.Enabled,
+		"sloChangeAlertDuration", settings.SloChangeAlertDuration.String(),
+		"defaultSloTarget", settings.DefaultSlo.TargetAvailabilityPercent,
+		"backoffBaseDelay", settings.BackoffBaseDelay.String(), "backoffMaxDelay", settings.BackoffMaxDelay.String())
+	return utilreconcile.Stop()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RuntimeConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isConfigMap := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == r.ConfigMap.Name && o.GetNamespace() == r.ConfigMap.Namespace
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("runtimeconfig").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isConfigMap)).
+		Complete(r)
+}
@@ -0,0 +1,77 @@
+package runtimeconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+)
+
+func TestRuntimeConfigReconciler_Reconcile(t *testing.T) {
+	name := types.NamespacedName{Name: runtimeconfig.DefaultConfigMapName, Namespace: "test"}
+	defaults := runtimeconfig.Settings{BlackBoxExporterImage: "fake-image", SloChangeAlertDuration: time.Hour}
+	tests := []struct {
+		name       string
+		data       map[string]string
+		wantImage  string
+		wantEvents int
+	}{
+		{
+			name:      "the ConfigMap doesn't exist",
+			wantImage: "fake-image",
+		},
+		{
+			name:      "the ConfigMap overrides the image",
+			data:      map[string]string{runtimeconfig.BlackBoxExporterImageKey: "other-image"},
+			wantImage: "other-image",
+		},
+		{
+			name:       "the ConfigMap is invalid",
+			data:       map[string]string{runtimeconfig.SloChangeAlertDurationKey: "soon", runtimeconfig.BlackBoxExporterImageKey: "other-image"},
+			wantImage:  "fake-image",
+			wantEvents: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{}
+			if tt.data != nil {
+				objs = append(objs, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+					Data:       tt.data,
+				})
+			}
+			recorder := record.NewFakeRecorder(1)
+			r := &RuntimeConfigReconciler{
+				Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+				Recorder:  recorder,
+				ConfigMap: name,
+				Config:    runtimeconfig.New(defaults, zap.NewAtomicLevelAt(zapcore.InfoLevel)),
+			}
+
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: name})
+			if err != nil || result.Requeue || result.RequeueAfter != 0 {
+				t.Fatalf("Reconcile() = %v, %v, want no requeue", result, err)
+			}
+			if got := r.Config.Get().BlackBoxExporterImage; got != tt.wantImage {
+				t.Errorf("BlackBoxExporterImage = %s, want %s", got, tt.wantImage)
+			}
+			if len(recorder.Events) != tt.wantEvents {
+				t.Errorf("got %d events, want %d", len(recorder.Events), tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileStatus runs the steps of a reconcile, which record their outcome in the status of the monitor
+// instead of writing it, and writes the status once they're done if they changed it. A failed write requeues
+// the monitor, unless one of the steps failed already.
+// In dry-run mode, if dryRun is set, the status the steps computed is dropped, as it refers to resources that
+// weren't written. Only the DryRun condition in conditions, the ones of the monitor, lists what they'd have written
+func ReconcileStatus(common MonitorResourceHandler, dryRun *reconcileCommon.DryRunClient, log logr.Logger, monitor client.Object,
+	conditions *[]metav1.Condition, steps func() (ctrl.Result, error)) (ctrl.Result, error) {
+	original := monitor.DeepCopyObject()
+	if dryRun != nil {
+		// The writes before the steps, e.g. of the finalizer, aren't listed
+		dryRun.Actions()
+	}
+	result, err := steps()
+	if dryRun == nil {
+		setReadyCondition(conditions, monitor.GetGeneration(), err)
+	} else {
+		actions := dryRun.Actions()
+		reflect.ValueOf(monitor).Elem().Set(reflect.ValueOf(original.DeepCopyObject()).Elem())
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionDryRun,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: monitor.GetGeneration(),
+			Reason:             reconcileCommon.DryRunReason,
+			Message:            reconcileCommon.DryRunMessage(actions),
+		})
+	}
+	if reflect.DeepEqual(original, monitor) {
+		return result, err
+	}
+	if _, updateErr := common.UpdateMonitorResourceStatus(monitor); updateErr != nil {
+		log.Error(updateErr, "Failed to update the status. Requeueing...")
+		if err == nil {
+			return utilreconcile.RequeueWith(updateErr)
+		}
+	}
+	return result, err
+}
+
+// EnsureBlackBoxExporterRolledOut reports in the BlackBoxExporterReady condition whether the exporter runs its current
+// configuration, e.g. one with a module the monitor just added, and requeues the monitor until it does. The requeues
+// back off like failed reconciles, as the exporter's deployment doesn't trigger reconciles of the monitors and a
+// stalled rollout, e.g. of an image that can't be pulled, may never complete
+func EnsureBlackBoxExporterRolledOut(exporter BlackBoxExporterHandler, conditions *[]metav1.Condition, generation int64) (utilreconcile.Result, error) {
+	rollout, err := exporter.GetBlackBoxExporterRollout()
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionBlackBoxExporterReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reconcileCommon.ReasonReconciled,
+	}
+	if rollout != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reconcileCommon.ReasonRollingOut
+		condition.Message = rollout
+	}
+	meta.SetStatusCondition(conditions, condition)
+	if rollout != "" {
+		return utilreconcile.RequeueOperation().Because("the blackbox exporter is rolling out"), nil
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+// EnsureBlackBoxExporterServesModule holds back the ServiceMonitor of a monitor until the exporter was rolled out with
+// the module it probes with, e.g. one added along with an upgrade of the exporter: the probes of a module the available
+// pods don't know fail. The wait is reported in the BlackBoxExporterReady condition, and its requeues back off like
+// those of EnsureBlackBoxExporterRolledOut. In dry-run mode, the exporter isn't rolled out, so the ServiceMonitor
+// doesn't wait
+func EnsureBlackBoxExporterServesModule(exporter BlackBoxExporterHandler, dryRun *reconcileCommon.DryRunClient, module string, conditions *[]metav1.Condition, generation int64) (utilreconcile.Result, error) {
+	if dryRun != nil {
+		return utilreconcile.ContinueReconcile()
+	}
+	served, err := exporter.ServesBlackBoxExporterModule(module)
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if served {
+		return utilreconcile.ContinueReconcile()
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionBlackBoxExporterReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             reconcileCommon.ReasonRollingOut,
+		Message:            fmt.Sprintf("Waiting for the blackbox exporter to be rolled out with the module %s", module),
+	})
+	return utilreconcile.RequeueOperation().Because("the blackbox exporter is rolling out the module"), nil
+}
+
+// setReadyCondition sums up the conditions of the generated resources in the Ready condition, err being the failure
+// of the reconcile, if any
+func setReadyCondition(conditions *[]metav1.Condition, generation int64, err error) {
+	ready := metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reconcileCommon.ReasonReconciled,
+	}
+	serviceMonitor := meta.FindStatusCondition(*conditions, v1alpha1.ConditionServiceMonitorReady)
+	prometheusRule := meta.FindStatusCondition(*conditions, v1alpha1.ConditionPrometheusRuleReady)
+	switch {
+	case err != nil:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = reconcileCommon.ReasonReconcileFailed
+		ready.Message = err.Error()
+	case serviceMonitor != nil && (serviceMonitor.Reason == reconcileCommon.ReasonHostedControlPlaneDeleted ||
+		serviceMonitor.Reason == reconcileCommon.ReasonMonitoringAPIUnavailable):
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = serviceMonitor.Reason
+		ready.Message = serviceMonitor.Message
+	case serviceMonitor != nil && serviceMonitor.Status == metav1.ConditionFalse:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = reconcileCommon.ReasonReconcileFailed
+		ready.Message = serviceMonitor.Type + ": " + serviceMonitor.Message
+	case prometheusRule != nil && prometheusRule.Status == metav1.ConditionFalse:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = reconcileCommon.ReasonReconcileFailed
+		ready.Message = prometheusRule.Type + ": " + prometheusRule.Message
+	case serviceMonitor == nil:
+		ready.Status = metav1.ConditionUnknown
+		ready.Reason = reconcileCommon.ReasonPending
+		ready.Message = "The ServiceMonitor hasn't been generated yet"
+	}
+	meta.SetStatusCondition(conditions, ready)
+}
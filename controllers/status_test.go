@@ -0,0 +1,254 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	controllermocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/controllers"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileStatus(t *testing.T) {
+	stepErr := errors.New("step failed")
+	updateErr := errors.New("update failed")
+	for name, tc := range map[string]struct {
+		changeStatus bool
+		stepErr      error
+		updateErr    error
+		wantUpdates  int
+		wantErr      error
+	}{
+		"unchanged status":              {wantUpdates: 0},
+		"failed step, unchanged status": {stepErr: stepErr, wantUpdates: 0, wantErr: stepErr},
+		"changed status":                {changeStatus: true, wantUpdates: 1},
+		"failed step, changed status":   {changeStatus: true, stepErr: stepErr, wantUpdates: 1, wantErr: stepErr},
+		"failed write":                  {changeStatus: true, updateErr: updateErr, wantUpdates: 1, wantErr: updateErr},
+		"failed step and write":         {changeStatus: true, stepErr: stepErr, updateErr: updateErr, wantUpdates: 1, wantErr: stepErr},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			common := controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+			// The monitor was reconciled before, so its Ready condition is only changed by the steps failing
+			monitor := &v1alpha1.RouteMonitor{Status: v1alpha1.RouteMonitorStatus{Conditions: []metav1.Condition{
+				{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+				readyCondition(tc.stepErr),
+			}}}
+			common.EXPECT().UpdateMonitorResourceStatus(monitor).Times(tc.wantUpdates).Return(utilreconcile.ContinueOperation(), tc.updateErr)
+
+			steps := 0
+			_, err := controllers.ReconcileStatus(common, nil, logr.Discard(), monitor, &monitor.Status.Conditions, func() (ctrl.Result, error) {
+				steps++
+				if tc.changeStatus {
+					monitor.Status.RouteURL = "https://fake-route"
+				}
+				return ctrl.Result{}, tc.stepErr
+			})
+			if steps != 1 {
+				t.Errorf("steps ran %d times, want once", steps)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("ReconcileStatus() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// readyCondition returns the Ready condition of a monitor whose ServiceMonitor is ready, after a reconcile failing with err
+func readyCondition(err error) metav1.Condition {
+	if err != nil {
+		return metav1.Condition{Type: v1alpha1.ConditionReady, Status: metav1.ConditionFalse, Reason: reconcileCommon.ReasonReconcileFailed, Message: err.Error()}
+	}
+	return metav1.Condition{Type: v1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled}
+}
+
+func TestReconcileStatus_Ready(t *testing.T) {
+	for name, tc := range map[string]struct {
+		conditions []metav1.Condition
+		stepErr    error
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantMsg    string
+	}{
+		"no ServiceMonitor yet": {
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: reconcileCommon.ReasonPending,
+			wantMsg:    "The ServiceMonitor hasn't been generated yet",
+		},
+		"ServiceMonitor ready": {
+			conditions: []metav1.Condition{{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue}},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reconcileCommon.ReasonReconciled,
+		},
+		"ServiceMonitor failed": {
+			conditions: []metav1.Condition{{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionFalse, Message: "forbidden"}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reconcileCommon.ReasonReconcileFailed,
+			wantMsg:    "ServiceMonitorReady: forbidden",
+		},
+		"HostedControlPlane deleted": {
+			conditions: []metav1.Condition{{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionFalse, Reason: reconcileCommon.ReasonHostedControlPlaneDeleted, Message: "orphaned"}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reconcileCommon.ReasonHostedControlPlaneDeleted,
+			wantMsg:    "orphaned",
+		},
+		"PrometheusRule failed": {
+			conditions: []metav1.Condition{
+				{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue},
+				{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionFalse, Message: "invalid rule"},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reconcileCommon.ReasonReconcileFailed,
+			wantMsg:    "PrometheusRuleReady: invalid rule",
+		},
+		"failed step": {
+			conditions: []metav1.Condition{{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue}},
+			stepErr:    errors.New("step failed"),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reconcileCommon.ReasonReconcileFailed,
+			wantMsg:    "step failed",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			common := controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+			monitor := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+			common.EXPECT().UpdateMonitorResourceStatus(monitor).Times(1).Return(utilreconcile.ContinueOperation(), nil)
+
+			_, _ = controllers.ReconcileStatus(common, nil, logr.Discard(), monitor, &monitor.Status.Conditions, func() (ctrl.Result, error) {
+				monitor.Status.Conditions = tc.conditions
+				return ctrl.Result{}, tc.stepErr
+			})
+			condition := meta.FindStatusCondition(monitor.Status.Conditions, v1alpha1.ConditionReady)
+			if condition == nil {
+				t.Fatalf("no Ready condition in %+v", monitor.Status.Conditions)
+			}
+			if condition.Status != tc.wantStatus || condition.Reason != tc.wantReason || condition.Message != tc.wantMsg || condition.ObservedGeneration != 3 {
+				t.Errorf("Ready condition = %+v, want status %s, reason %s, message %q", condition, tc.wantStatus, tc.wantReason, tc.wantMsg)
+			}
+		})
+	}
+}
+
+func TestReconcileStatus_DryRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	common := controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+	monitor := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"}}
+	common.EXPECT().UpdateMonitorResourceStatus(monitor).Times(1).Return(utilreconcile.ContinueOperation(), nil)
+	fakeClient := fake.NewClientBuilder().WithScheme(constinit.Scheme).Build()
+	dryRun := reconcileCommon.NewDryRunClient(fakeClient, nil, logr.Discard())
+
+	_, err := controllers.ReconcileStatus(common, dryRun, logr.Discard(), monitor, &monitor.Status.Conditions, func() (ctrl.Result, error) {
+		serviceMonitor := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"}}
+		monitor.Status.ServiceMonitorRef.Name = serviceMonitor.Name
+		return ctrl.Result{}, dryRun.Create(context.Background(), serviceMonitor)
+	})
+	if err != nil {
+		t.Fatalf("ReconcileStatus() error = %v", err)
+	}
+	if monitor.Status.ServiceMonitorRef.Name != "" {
+		t.Errorf("the status the steps computed was kept: %+v", monitor.Status)
+	}
+	condition := meta.FindStatusCondition(monitor.Status.Conditions, v1alpha1.ConditionDryRun)
+	if condition == nil || condition.Message != "Dry run: would create ServiceMonitor fake-namespace/fake-monitor" {
+		t.Errorf("DryRun condition = %+v", condition)
+	}
+	err = fakeClient.Get(context.Background(), client.ObjectKey{Name: "fake-monitor", Namespace: "fake-namespace"}, &monitoringv1.ServiceMonitor{})
+	if err == nil {
+		t.Errorf("the dry-run create was persisted")
+	}
+}
+
+func TestEnsureBlackBoxExporterRolledOut(t *testing.T) {
+	getErr := errors.New("get failed")
+	for name, tc := range map[string]struct {
+		rollout     string
+		getErr      error
+		wantStatus  metav1.ConditionStatus
+		wantRequeue bool
+	}{
+		"rolled out":  {wantStatus: metav1.ConditionTrue},
+		"rolling out": {rollout: "1 of 2 blackbox exporter pods are up to date", wantStatus: metav1.ConditionFalse, wantRequeue: true},
+		"failed get":  {getErr: getErr},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			exporter := controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+			exporter.EXPECT().GetBlackBoxExporterRollout().Times(1).Return(tc.rollout, tc.getErr)
+			conditions := []metav1.Condition{}
+
+			res, err := controllers.EnsureBlackBoxExporterRolledOut(exporter, &conditions, 2)
+			if !errors.Is(err, tc.getErr) {
+				t.Fatalf("err = %v, want %v", err, tc.getErr)
+			}
+			if tc.getErr != nil {
+				if len(conditions) != 0 {
+					t.Errorf("conditions = %+v, want none", conditions)
+				}
+				return
+			}
+			if requeue := res.Requeue; requeue != tc.wantRequeue || res.ShouldStop() != tc.wantRequeue {
+				t.Errorf("result = %+v, want requeue %t", res, tc.wantRequeue)
+			}
+			condition := meta.FindStatusCondition(conditions, v1alpha1.ConditionBlackBoxExporterReady)
+			if condition == nil || condition.Status != tc.wantStatus || condition.Message != tc.rollout || condition.ObservedGeneration != 2 {
+				t.Errorf("BlackBoxExporterReady condition = %+v, want status %s and message %q", condition, tc.wantStatus, tc.rollout)
+			}
+		})
+	}
+}
+
+func TestEnsureBlackBoxExporterServesModule(t *testing.T) {
+	getErr := errors.New("get failed")
+	for name, tc := range map[string]struct {
+		served   bool
+		getErr   error
+		dryRun   bool
+		wantWait bool
+	}{
+		"module rolled out":           {served: true},
+		"module not rolled out yet":   {wantWait: true},
+		"failed get":                  {getErr: getErr},
+		"dry run doesn't roll it out": {dryRun: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			exporter := controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+			if !tc.dryRun {
+				exporter.EXPECT().ServesBlackBoxExporterModule("http_2xx").Times(1).Return(tc.served, tc.getErr)
+			}
+			var dryRun *reconcileCommon.DryRunClient
+			if tc.dryRun {
+				dryRun = reconcileCommon.NewDryRunClient(fake.NewClientBuilder().WithScheme(constinit.Scheme).Build(), nil, logr.Discard())
+			}
+			conditions := []metav1.Condition{}
+
+			res, err := controllers.EnsureBlackBoxExporterServesModule(exporter, dryRun, "http_2xx", &conditions, 2)
+			if !errors.Is(err, tc.getErr) {
+				t.Fatalf("err = %v, want %v", err, tc.getErr)
+			}
+			if tc.getErr != nil {
+				return
+			}
+			if wait := res.Requeue; wait != tc.wantWait || res.ShouldStop() != tc.wantWait {
+				t.Errorf("result = %+v, want to wait %t", res, tc.wantWait)
+			}
+			condition := meta.FindStatusCondition(conditions, v1alpha1.ConditionBlackBoxExporterReady)
+			if tc.wantWait != (condition != nil && condition.Status == metav1.ConditionFalse && condition.ObservedGeneration == 2) {
+				t.Errorf("BlackBoxExporterReady condition = %+v, want to wait %t", condition, tc.wantWait)
+			}
+		})
+	}
+}
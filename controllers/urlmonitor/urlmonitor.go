@@ -0,0 +1,357 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package urlmonitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// UrlMonitorReconciler reconciles a UrlMonitor object
+type UrlMonitorReconciler struct {
+	Client client.Client
+	Ctx    context.Context
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	BlackBoxExporter controllers.BlackBoxExporterHandler
+	ServiceMonitor   controllers.ServiceMonitorHandler
+	Prom             controllers.PrometheusRuleHandler
+	Common           controllers.MonitorResourceHandler
+
+	controllers.ReconcilerOptions
+
+	// DedicatedBlackBoxExporter returns the exporter of the namespace probing its monitors asking for a dedicated
+	// exporter, which are probed by the shared BlackBoxExporter while it's nil
+	DedicatedBlackBoxExporter func(namespace string) controllers.BlackBoxExporterHandler
+
+	// Recorder emits Events about the monitors
+	Recorder record.EventRecorder
+
+	// DryRunClient, if set, dry-runs the writes of the generated resources and the UrlMonitors, except their status
+	DryRunClient *reconcileCommon.DryRunClient
+
+	// DebugProbes runs the debug probes of the UrlMonitors in debug mode once per DebugProbeInterval
+	DebugProbes controllers.DebugProbes
+}
+
+// sloChangeAlertDuration returns how long the alert on a lowered availability target fires
+func (r *UrlMonitorReconciler) sloChangeAlertDuration() time.Duration {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloChangeAlertDuration
+	}
+	return r.SloChangeAlertDuration
+}
+
+// slo returns the SLO the monitor is held to, which is the default SLO if it doesn't set one
+func (r *UrlMonitorReconciler) slo(slo monitoringv1alpha1.SloSpec) monitoringv1alpha1.SloSpec {
+	if r.RuntimeConfig != nil {
+		return r.RuntimeConfig.Get().SloFor(slo)
+	}
+	return slo
+}
+
+// blackBoxExporterFor returns the exporter probing the UrlMonitor
+func (r *UrlMonitorReconciler) blackBoxExporterFor(urlMonitor *monitoringv1alpha1.UrlMonitor) controllers.BlackBoxExporterHandler {
+	if urlMonitor.Spec.ExternalExporter != nil {
+		return blackboxexporter.External{}
+	}
+	if urlMonitor.Spec.DedicatedExporter && r.DedicatedBlackBoxExporter != nil {
+		return r.DedicatedBlackBoxExporter(urlMonitor.Namespace)
+	}
+	return r.BlackBoxExporter
+}
+
+func NewReconciler(mgr manager.Manager, opts controllers.ReconcilerOptions) *UrlMonitorReconciler {
+	log := ctrl.Log.WithName("controllers").WithName("UrlMonitor")
+	client := mgr.GetClient()
+	ctx := context.Background()
+	recorder := mgr.GetEventRecorderFor(config.OperatorName)
+	var dryRun *reconcileCommon.DryRunClient
+	if opts.DryRun {
+		dryRun = reconcileCommon.NewDryRunClient(client, recorder, log.WithName("DryRun"))
+		client = dryRun
+	}
+	common := reconcileCommon.NewMonitorResourceCommon(ctx, client)
+	common.DryRun = opts.DryRun
+	if opts.ClusterIdentity != nil {
+		common.ClusterIdentity = opts.ClusterIdentity
+	}
+	blackBoxExporter := blackboxexporter.New(client, log, ctx, opts.BlackBoxExporterImage, opts.BlackBoxExporterNamespace)
+	blackBoxExporter.FIPS = opts.FIPSMode
+	blackBoxExporter.Deployment = opts.BlackBoxExporterDeployment
+	blackBoxExporter.NetworkPolicy = opts.BlackBoxExporterNetworkPolicy
+	blackBoxExporter.RuntimeConfig = opts.RuntimeConfig
+	blackBoxExporter.FieldOwner = opts.FieldOwner
+	return &UrlMonitorReconciler{
+		Client:           client,
+		Ctx:              ctx,
+		Log:              log,
+		Scheme:           mgr.GetScheme(),
+		BlackBoxExporter: blackBoxExporter,
+		DedicatedBlackBoxExporter: func(namespace string) controllers.BlackBoxExporterHandler {
+			return blackBoxExporter.DedicatedTo(namespace)
+		},
+		ServiceMonitor: &servicemonitor.ServiceMonitor{
+			Client:                client,
+			Ctx:                   ctx,
+			Comparer:              &reconcileCommon.ResourceComparer{},
+			LabelOwnedResources:   opts.LabelOwnedResources,
+			Scrubber:              opts.ScrubbedMetadata,
+			FieldOwner:            opts.FieldOwner,
+			ProbeResources:        opts.ProbeResources,
+			ScrapeConfigResources: opts.ScrapeConfigResources,
+			ZoneLabels:            opts.ZoneLabels,
+			NodeLabels:            opts.BlackBoxExporterDeployment.DaemonSet,
+			TLS:                   opts.BlackBoxExporterDeployment.ServingCert,
+			MeshTLS:               opts.BlackBoxExporterDeployment.MeshSidecar(),
+			Recorder:              recorder,
+		},
+		Prom: &alert.PrometheusRule{
+			Client:     client,
+			Ctx:        ctx,
+			Comparer:   &reconcileCommon.ResourceComparer{},
+			Scrubber:   opts.ScrubbedMetadata,
+			FieldOwner: opts.FieldOwner,
+		},
+		Common:            common,
+		ReconcilerOptions: opts,
+		Recorder:          recorder,
+		DryRunClient:      dryRun,
+	}
+}
+
+const (
+	FinalizerKey string = "urlmonitor.routemonitoroperator.monitoring.openshift.io/finalizer"
+)
+
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=urlmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.openshift.io,resources=urlmonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;delete;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
+
+func (r *UrlMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Ctx = ctx
+	reconcileLog := logging.ForReconcile(r.Log.WithName("Reconcile"), "UrlMonitor", req.NamespacedName)
+
+	log := logging.EnterStep(reconcileLog, "GetUrlMonitor")
+	urlMonitor, res, err := r.GetUrlMonitor(req)
+	if err != nil {
+		log.Error(err, "Failed to retrieve UrlMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		return res.ReturnWith(nil)
+	}
+	if !r.Scope.Matches(&urlMonitor) {
+		log.V(logging.DebugVerbosity).Info("Skipping the UrlMonitor: it's outside of the operator's scope")
+		return utilreconcile.Stop()
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureMonitorAndDependenciesAbsent")
+	res, err = r.EnsureMonitorAndDependenciesAbsent(urlMonitor)
+	if err != nil {
+		log.Error(err, "Failed to delete UrlMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully deleted UrlMonitor. Finished Reconcile")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureFinalizerSet")
+	res, err = r.EnsureFinalizerSet(urlMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set UrlMonitor's Finalizer. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Successfully set UrlMonitor finalizers. Stopping...")
+		return res.ReturnWith(nil)
+	}
+
+	// The remaining steps record their outcome in the status, which is written once they're done
+	return controllers.ReconcileStatus(r.Common, r.DryRunClient, reconcileLog, &urlMonitor, &urlMonitor.Status.Conditions, func() (ctrl.Result, error) {
+		return r.ensureMonitorResources(reconcileLog, &urlMonitor)
+	})
+}
+
+// ensureMonitorResources deploys the blackbox exporter and the resources generated for the UrlMonitor
+func (r *UrlMonitorReconciler) ensureMonitorResources(reconcileLog logr.Logger, urlMonitor *monitoringv1alpha1.UrlMonitor) (ctrl.Result, error) {
+	log := logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterResourcesExist")
+	err := r.blackBoxExporterFor(urlMonitor).EnsureBlackBoxExporterResourcesExist()
+	if err != nil {
+		log.Error(err, "Failed to create BlackBoxExporter. Requeueing...")
+		blackboxexporter.ReportInvalidConfig(r.Recorder, urlMonitor, &urlMonitor.Status.Conditions, err)
+		return utilreconcile.RequeueWith(err)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureServiceMonitorExists")
+	res, err := r.EnsureServiceMonitorExists(urlMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set ServiceMonitor. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsurePrometheusRuleExists")
+	res, err = r.EnsurePrometheusRuleExists(urlMonitor)
+	if err != nil {
+		log.Error(err, "Failed to set PrometheusRule. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	log = logging.EnterStep(reconcileLog, "EnsureBlackBoxExporterRolledOut")
+	res, err = controllers.EnsureBlackBoxExporterRolledOut(r.blackBoxExporterFor(urlMonitor), &urlMonitor.Status.Conditions, urlMonitor.Generation)
+	if err != nil {
+		log.Error(err, "Failed to get the rollout of the BlackBoxExporter. Requeueing...")
+		return utilreconcile.RequeueWith(err)
+	}
+	if res.ShouldStop() {
+		log.Info("Stopping after the step")
+		return res.ReturnWith(nil)
+	}
+
+	reconcileLog.Info("All operations for UrlMonitor completed. Finished Reconcile.")
+	if controllers.Debugging(urlMonitor.Spec.DebugUntil, time.Now()) {
+		return utilreconcile.RequeueAfter(controllers.DebugProbeInterval).Because("the UrlMonitor is in debug mode").ReturnWith(nil)
+	}
+	return utilreconcile.Stop()
+}
+
+func (r *UrlMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1alpha1.UrlMonitor{}, builder.WithPredicates(controllers.MonitorChanged, r.Scope.Predicate())).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter()}).
+		Watches(
+			&monitoringv1.ServiceMonitor{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.UrlMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	if r.ProbeResources {
+		bldr = bldr.Watches(
+			&monitoringv1.Probe{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.UrlMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.ScrapeConfigResources {
+		bldr = bldr.Watches(
+			&scrapeconfig.ScrapeConfig{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &monitoringv1alpha1.UrlMonitor{}, handler.OnlyControllerOwner()),
+			builder.WithPredicates(r.Scope.NamespacePredicate()),
+		)
+	}
+	if r.VerifyInterval > 0 {
+		verifier := reconcileCommon.NewResourceVerifier(r.Client, r.Recorder, r.Log.WithName("Verify"), r.VerifyInterval, r.listUrlMonitors, r.generatedResources)
+		if err := mgr.Add(verifier); err != nil {
+			return err
+		}
+		bldr = bldr.WatchesRawSource(verifier.Source(), &handler.EnqueueRequestForObject{})
+	}
+	if r.RuntimeConfig != nil {
+		// Puts changed exporter settings and SLO defaults into effect without waiting for the next reconcile
+		bldr = bldr.WatchesRawSource(r.RuntimeConfig.Source(), controllers.EnqueueAll(r.listUrlMonitors, r.Log))
+	}
+	if r.ProbeHealth != nil {
+		reporter := probehealth.NewReporter(r.Client, r.ProbeHealth, r.Log.WithName("ProbeHealth"), r.ProbeHealthInterval, r.listUrlMonitors, r.probeHealthTarget)
+		if err := mgr.Add(reporter); err != nil {
+			return err
+		}
+	}
+	return bldr.Complete(r)
+}
+
+// listUrlMonitors returns the UrlMonitors that aren't being deleted
+func (r *UrlMonitorReconciler) listUrlMonitors(ctx context.Context) ([]client.Object, error) {
+	list := &monitoringv1alpha1.UrlMonitorList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	monitors := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp == nil && r.Scope.Matches(&list.Items[i]) {
+			monitors = append(monitors, &list.Items[i])
+		}
+	}
+	return monitors, nil
+}
+
+// generatedResources returns the resources referenced by the status of a UrlMonitor
+func (r *UrlMonitorReconciler) generatedResources(o client.Object) []client.Object {
+	urlMonitor := o.(*monitoringv1alpha1.UrlMonitor)
+	resources := []client.Object{}
+	if ref := urlMonitor.Status.ServiceMonitorRef; ref.Name != "" {
+		meta := metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}
+		switch {
+		case r.ScrapeConfigResources:
+			resources = append(resources, &scrapeconfig.ScrapeConfig{ObjectMeta: meta})
+		case r.ProbeResources:
+			resources = append(resources, &monitoringv1.Probe{ObjectMeta: meta})
+		default:
+			resources = append(resources, &monitoringv1.ServiceMonitor{ObjectMeta: meta})
+		}
+	}
+	if ref := urlMonitor.Status.PrometheusRuleRef; ref.Name != "" {
+		resources = append(resources, &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return resources
+}
+
+// probeHealthTarget returns the probes of a UrlMonitor whose health is reported
+func (r *UrlMonitorReconciler) probeHealthTarget(o client.Object) (probehealth.Target, bool) {
+	urlMonitor := o.(*monitoringv1alpha1.UrlMonitor)
+	return probehealth.Target{
+		URLs:   []string{urlMonitor.Spec.URL},
+		Slo:    r.slo(urlMonitor.Spec.Slo),
+		Health: &urlMonitor.Status.ProbeHealth,
+	}, true
+}
@@ -0,0 +1,13 @@
+package urlmonitor_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestUrlmonitor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Urlmonitor Suite")
+}
@@ -0,0 +1,220 @@
+package urlmonitor
+
+import (
+	"reflect"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Takes care that right PrometheusRules for the defined UrlMonitor are in place. The outcome is recorded
+// in the UrlMonitor's status, which is written by the caller
+func (s *UrlMonitorReconciler) EnsurePrometheusRuleExists(urlMonitor *v1alpha1.UrlMonitor) (utilreconcile.Result, error) {
+	slo := s.slo(urlMonitor.Spec.Slo)
+
+	// Keep track of changes of the availability target before acting on them
+	alert.ObserveSloChange(s.Recorder, "UrlMonitor", urlMonitor, slo, &urlMonitor.Status.SloTarget, &urlMonitor.Status.LastSloChange)
+
+	// If .spec.skipPrometheusRule is true, ensure that the PrometheusRule does NOT exist
+	if urlMonitor.Spec.SkipPrometheusRule {
+		if err := s.Prom.DeletePrometheusRuleDeployment(urlMonitor.Status.PrometheusRuleRef); err != nil {
+			return s.artifactFailed(urlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+		}
+		_, _ = s.Common.SetResourceReference(&urlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+		return utilreconcile.ContinueReconcile()
+	}
+
+	parsedSlo, err := s.Common.ParseMonitorSLOSpecs(urlMonitor.Spec.URL, slo)
+	s.Common.SetErrorStatus(&urlMonitor.Status.ErrorStatus, err)
+	if parsedSlo == "" {
+		deleteErr := s.Prom.DeletePrometheusRuleDeployment(urlMonitor.Status.PrometheusRuleRef)
+		if deleteErr != nil {
+			return s.artifactFailed(urlMonitor, v1alpha1.ConditionPrometheusRuleReady, deleteErr)
+		}
+		_, _ = s.Common.SetResourceReference(&urlMonitor.Status.PrometheusRuleRef, types.NamespacedName{})
+		// An invalid SLO fails the PrometheusRule, without an SLO none is needed
+		if err != nil {
+			reconcileCommon.SetArtifactCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, urlMonitor.Generation, err)
+		} else {
+			meta.RemoveStatusCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+		}
+		return utilreconcile.StopReconcile()
+	}
+
+	namespacedName := types.NamespacedName{Namespace: urlMonitor.Namespace, Name: urlMonitor.Name}
+	ruleName := reconcileCommon.GeneratedResourceName(urlMonitor.Status.PrometheusRuleRef, "UrlMonitor", namespacedName)
+	template, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+		URL:                    urlMonitor.Spec.URL,
+		Percent:                parsedSlo,
+		Slo:                    slo,
+		NamespacedName:         namespacedName,
+		Created:                urlMonitor.CreationTimestamp.Time,
+		MaintenanceWindows:     urlMonitor.Spec.MaintenanceWindows,
+		LastSloChange:          urlMonitor.Status.LastSloChange,
+		SloChangeAlertDuration: s.sloChangeAlertDuration(),
+		Owner:                  metav1.NewControllerRef(&urlMonitor.ObjectMeta, urlMonitor.GroupVersionKind()),
+	})
+	if err != nil {
+		return s.artifactFailed(urlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+	if s.LabelOwnedResources {
+		template.Labels = consts.OwnerLabels("UrlMonitor", urlMonitor.Name)
+	}
+	// Alerts keep the name of the monitor, only the resource is named uniquely
+	template.Name = ruleName.Name
+	if err := s.Prom.UpdatePrometheusRuleDeployment(template); err != nil {
+		return s.artifactFailed(urlMonitor, v1alpha1.ConditionPrometheusRuleReady, err)
+	}
+
+	_, _ = s.Common.SetResourceReference(&urlMonitor.Status.PrometheusRuleRef, ruleName)
+	reconcileCommon.SetArtifactCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady, urlMonitor.Generation, nil)
+	return utilreconcile.ContinueReconcile()
+}
+
+// Takes care that right ServiceMonitor for the defined UrlMonitor are in place. The outcome is recorded
+// in the UrlMonitor's status, which is written by the caller
+func (s *UrlMonitorReconciler) EnsureServiceMonitorExists(urlMonitor *v1alpha1.UrlMonitor) (utilreconcile.Result, error) {
+	// If .spec.skipServiceMonitor is true, ensure that the ServiceMonitor does NOT exist
+	if urlMonitor.Spec.SkipServiceMonitor {
+		if err := s.ServiceMonitor.DeleteServiceMonitorDeployment(urlMonitor.Status.ServiceMonitorRef); err != nil {
+			return s.artifactFailed(urlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+		}
+		_, _ = s.Common.SetResourceReference(&urlMonitor.Status.ServiceMonitorRef, types.NamespacedName{})
+		meta.RemoveStatusCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)
+		return utilreconcile.ContinueReconcile()
+	}
+
+	id, err := s.Common.GetOSDClusterID()
+	if err != nil {
+		return s.artifactFailed(urlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	namespacedName := reconcileCommon.GeneratedResourceName(urlMonitor.Status.ServiceMonitorRef, "UrlMonitor", types.NamespacedName{Name: urlMonitor.Name, Namespace: urlMonitor.Namespace})
+	spec := urlMonitor.Spec
+	owner := metav1.NewControllerRef(&urlMonitor.ObjectMeta, urlMonitor.GroupVersionKind())
+	module, definition := blackboxexporter.ModuleFor(spec.InsecureSkipTLSVerify, spec.HTTPProbe)
+	if spec.ExternalExporter != nil {
+		// The configuration of an external exporter isn't managed, its module is named instead
+		module = spec.ExternalExporter.Module
+	} else if err := definition.ValidateRequired(s.FIPSMode); err != nil {
+		return s.artifactFailed(urlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+	res, err := controllers.EnsureBlackBoxExporterServesModule(s.blackBoxExporterFor(urlMonitor), s.DryRunClient, module, &urlMonitor.Status.Conditions, urlMonitor.Generation)
+	if err != nil || res.ShouldStop() {
+		return res, err
+	}
+	uid, err := s.ServiceMonitor.TemplateAndUpdateServiceMonitorDeployment([]string{spec.URL}, servicemonitor.Exporter{Namespace: s.blackBoxExporterFor(urlMonitor).GetBlackBoxExporterNamespace(), External: spec.ExternalExporter}, namespacedName, id, false, module, spec.MetricLabels, spec.MetricRelabelings, spec.SampleLimit, spec.TargetLimit, owner, urlMonitor.Status.ServiceMonitorRef.UID)
+	if err != nil {
+		return s.artifactFailed(urlMonitor, v1alpha1.ConditionServiceMonitorReady, err)
+	}
+
+	if _, err := s.Common.SetResourceReference(&urlMonitor.Status.ServiceMonitorRef, namespacedName); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	// Record the UID, so a ServiceMonitor recreated by someone else isn't adopted
+	if uid != "" {
+		urlMonitor.Status.ServiceMonitorRef.UID = uid
+	}
+	reconcileCommon.SetArtifactCondition(&urlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady, urlMonitor.Generation, nil)
+	s.DebugProbes.CaptureFailingProbe(s.blackBoxExporterFor(urlMonitor), s.DryRunClient, s.Recorder, urlMonitor, spec.DebugUntil, []string{spec.URL}, module, &urlMonitor.Status.LastFailedProbe)
+	return utilreconcile.ContinueReconcile()
+}
+
+// artifactFailed reports the failure of a generated resource in the UrlMonitor's status, so it's
+// visible which one needs attention. It requeues with the failure unless retrying can't fix it
+func (s *UrlMonitorReconciler) artifactFailed(urlMonitor *v1alpha1.UrlMonitor, conditionType string, err error) (utilreconcile.Result, error) {
+	reconcileCommon.SetArtifactCondition(&urlMonitor.Status.Conditions, conditionType, urlMonitor.Generation, err)
+	metrics.ReconcileErrors.WithLabelValues("UrlMonitor", string(customerrors.ClassOf(err))).Inc()
+	return utilreconcile.FailReconcileWith(err)
+}
+
+// Ensures that all dependencies related to a UrlMonitor are deleted
+func (s *UrlMonitorReconciler) EnsureMonitorAndDependenciesAbsent(urlMonitor v1alpha1.UrlMonitor) (utilreconcile.Result, error) {
+	if urlMonitor.DeletionTimestamp == nil {
+		return utilreconcile.ContinueReconcile()
+	}
+
+	reason, err := reconcileCommon.CleanupSkipReason(s.Ctx, s.Client, &urlMonitor)
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if reason != "" {
+		reconcileCommon.Eventf(s.Recorder, &urlMonitor, corev1.EventTypeWarning, reconcileCommon.CleanupSkippedReason,
+			"Dropping the finalizer without deleting the generated resources: %s", reason)
+		return s.ensureFinalizerAbsent(urlMonitor)
+	}
+
+	// The resources controlled by the UrlMonitor are deleted with it by the garbage collector
+	resources := s.generatedResources(&urlMonitor)
+	if err := reconcileCommon.DeleteUncollected(s.Ctx, s.Client, &urlMonitor, resources...); err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+
+	shouldDelete, err := s.blackBoxExporterFor(&urlMonitor).ShouldDeleteBlackBoxExporterResources()
+	if err != nil {
+		return utilreconcile.RequeueReconcileWith(err)
+	}
+	if shouldDelete == blackboxexporterconsts.DeleteBlackBoxExporter {
+		err := s.blackBoxExporterFor(&urlMonitor).EnsureBlackBoxExporterResourcesAbsent()
+		if err != nil {
+			return utilreconcile.RequeueReconcileWith(err)
+		}
+	}
+
+	return s.ensureFinalizerAbsent(urlMonitor)
+}
+
+// ensureFinalizerAbsent drops the finalizer of the UrlMonitor, so its deletion completes
+func (s *UrlMonitorReconciler) ensureFinalizerAbsent(urlMonitor v1alpha1.UrlMonitor) (utilreconcile.Result, error) {
+	if s.Common.DeleteFinalizer(&urlMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&urlMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+func (s *UrlMonitorReconciler) EnsureFinalizerSet(urlMonitor v1alpha1.UrlMonitor) (utilreconcile.Result, error) {
+	if s.Common.SetFinalizer(&urlMonitor, FinalizerKey) {
+		return s.Common.UpdateMonitorResource(&urlMonitor)
+	}
+	return utilreconcile.ContinueReconcile()
+}
+
+// GetUrlMonitor return the UrlMonitor that is tested
+func (s *UrlMonitorReconciler) GetUrlMonitor(req ctrl.Request) (v1alpha1.UrlMonitor, utilreconcile.Result, error) {
+	urlMonitor := v1alpha1.UrlMonitor{}
+	err := s.Client.Get(s.Ctx, req.NamespacedName, &urlMonitor)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			res, err := utilreconcile.RequeueReconcileWith(err)
+			return v1alpha1.UrlMonitor{}, res, err
+		}
+		s.Log.V(logging.DebugVerbosity).Info("Stopping the reconcile: the UrlMonitor wasn't found")
+
+		return v1alpha1.UrlMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	// if the resource is empty, we should terminate
+	if reflect.DeepEqual(urlMonitor, v1alpha1.UrlMonitor{}) {
+		return v1alpha1.UrlMonitor{}, utilreconcile.StopOperation(), nil
+	}
+
+	return urlMonitor, utilreconcile.ContinueOperation(), nil
+}
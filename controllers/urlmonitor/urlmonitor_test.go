@@ -0,0 +1,204 @@
+package urlmonitor_test
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers/urlmonitor"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
+	controllermocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/controllers"
+)
+
+var _ = Describe("Urlmonitor", func() {
+	var (
+		urlMonitor           v1alpha1.UrlMonitor
+		reconciler           urlmonitor.UrlMonitorReconciler
+		mockClient           *clientmocks.MockClient
+		mockBlackBoxExporter *controllermocks.MockBlackBoxExporterHandler
+		mockCommon           *controllermocks.MockMonitorResourceHandler
+		mockPrometheusRule   *controllermocks.MockPrometheusRuleHandler
+		mockServiceMonitor   *controllermocks.MockServiceMonitorHandler
+
+		mockCtrl *gomock.Controller
+
+		res utilreconcile.Result
+		err error
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockBlackBoxExporter = controllermocks.NewMockBlackBoxExporterHandler(mockCtrl)
+		mockServiceMonitor = controllermocks.NewMockServiceMonitorHandler(mockCtrl)
+		mockPrometheusRule = controllermocks.NewMockPrometheusRuleHandler(mockCtrl)
+		mockCommon = controllermocks.NewMockMonitorResourceHandler(mockCtrl)
+		urlMonitor = v1alpha1.UrlMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-urlmonitor",
+				Namespace: "fake-namespace",
+			},
+			Spec: v1alpha1.UrlMonitorSpec{
+				URL: "http://fake-service.fake-namespace.svc:8080/healthz",
+			},
+			Status: v1alpha1.UrlMonitorStatus{
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionServiceMonitorReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+					{Type: v1alpha1.ConditionPrometheusRuleReady, Status: metav1.ConditionTrue, Reason: reconcileCommon.ReasonReconciled},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		reconciler = urlmonitor.UrlMonitorReconciler{
+			Log:              logr.Discard(),
+			Client:           mockClient,
+			Scheme:           constinit.Scheme,
+			BlackBoxExporter: mockBlackBoxExporter,
+			Common:           mockCommon,
+			ServiceMonitor:   mockServiceMonitor,
+			Prom:             mockPrometheusRule,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("EnsureServiceMonitorExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureServiceMonitorExists(&urlMonitor)
+		})
+		When("the ServiceMonitor is skipped", func() {
+			BeforeEach(func() {
+				urlMonitor.Spec.SkipServiceMonitor = true
+				mockServiceMonitor.EXPECT().DeleteServiceMonitorDeployment(urlMonitor.Status.ServiceMonitorRef).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&urlMonitor.Status.ServiceMonitorRef, types.NamespacedName{}).Times(1).Return(false, nil)
+			})
+			It("deletes the existing ServiceMonitor and removes its condition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.FindStatusCondition(urlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)).To(BeNil())
+			})
+		})
+		When("the ServiceMonitor doesn't exist", func() {
+			BeforeEach(func() {
+				urlMonitor.Spec.InsecureSkipTLSVerify = true
+				module, _ := blackboxexporter.ModuleFor(true, nil)
+				ns := reconcileCommon.GeneratedResourceName(urlMonitor.Status.ServiceMonitorRef, "UrlMonitor", types.NamespacedName{Name: urlMonitor.Name, Namespace: urlMonitor.Namespace})
+				mockBlackBoxExporter.EXPECT().GetBlackBoxExporterNamespace().Times(1).Return("")
+				mockBlackBoxExporter.EXPECT().ServesBlackBoxExporterModule(gomock.Any()).Return(true, nil)
+				mockCommon.EXPECT().GetOSDClusterID().Times(1).Return("fake-cluster-id", nil)
+				mockServiceMonitor.EXPECT().TemplateAndUpdateServiceMonitorDeployment([]string{urlMonitor.Spec.URL}, servicemonitor.Exporter{}, ns, "fake-cluster-id", false, module, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&urlMonitor.Status.ServiceMonitorRef, ns).Times(1).Return(true, nil)
+			})
+			It("probes the url and updates the ServiceRef", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+				Expect(meta.IsStatusConditionTrue(urlMonitor.Status.Conditions, v1alpha1.ConditionServiceMonitorReady)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("EnsurePrometheusRuleExists", func() {
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsurePrometheusRuleExists(&urlMonitor)
+		})
+		When("the UrlMonitor has an invalid slo value", func() {
+			BeforeEach(func() {
+				err := customerrors.InvalidSLO
+				mockCommon.EXPECT().ParseMonitorSLOSpecs(urlMonitor.Spec.URL, urlMonitor.Spec.Slo).Times(1).Return("", err)
+				mockCommon.EXPECT().SetErrorStatus(&urlMonitor.Status.ErrorStatus, err)
+				mockPrometheusRule.EXPECT().DeletePrometheusRuleDeployment(gomock.Any()).Times(1)
+				mockCommon.EXPECT().SetResourceReference(&urlMonitor.Status.PrometheusRuleRef, types.NamespacedName{}).Times(1)
+			})
+			It("sets the error in the UrlMonitor and stops processing", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+				condition := meta.FindStatusCondition(urlMonitor.Status.Conditions, v1alpha1.ConditionPrometheusRuleReady)
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Message).To(Equal(customerrors.InvalidSLO.Error()))
+			})
+		})
+		When("the PrometheusRule doesn't exist", func() {
+			BeforeEach(func() {
+				mockCommon.EXPECT().ParseMonitorSLOSpecs(urlMonitor.Spec.URL, urlMonitor.Spec.Slo).Times(1).Return("99.5", nil)
+				mockCommon.EXPECT().SetErrorStatus(&urlMonitor.Status.ErrorStatus, nil)
+				ns := reconcileCommon.GeneratedResourceName(urlMonitor.Status.PrometheusRuleRef, "UrlMonitor", types.NamespacedName{Name: urlMonitor.Name, Namespace: urlMonitor.Namespace})
+				mockPrometheusRule.EXPECT().UpdatePrometheusRuleDeployment(gomock.Any()).Times(1).DoAndReturn(func(template interface{}) error {
+					Expect(template).To(HaveField("ObjectMeta.Name", ns.Name))
+					return nil
+				})
+				mockCommon.EXPECT().SetResourceReference(&urlMonitor.Status.PrometheusRuleRef, ns).Times(1).Return(true, nil)
+			})
+			It("creates one and updates the UrlMonitor", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+	})
+
+	Describe("EnsureMonitorAndDependenciesAbsent", func() {
+		BeforeEach(func() {
+			// The namespace of the monitor isn't terminating, so its generated resources are cleaned up
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Namespace{})).AnyTimes()
+			urlMonitor.Finalizers = []string{urlmonitor.FinalizerKey}
+		})
+		JustBeforeEach(func() {
+			res, err = reconciler.EnsureMonitorAndDependenciesAbsent(urlMonitor)
+		})
+		When("the UrlMonitor is not being deleted", func() {
+			It("does nothing", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.ContinueOperation()))
+			})
+		})
+		When("the UrlMonitor is being deleted", func() {
+			BeforeEach(func() {
+				urlMonitor.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
+				urlMonitor.Status.ServiceMonitorRef = v1alpha1.NamespacedName{Name: "fake-servicemonitor", Namespace: "fake-namespace"}
+				urlMonitor.Status.PrometheusRuleRef = v1alpha1.NamespacedName{Name: "fake-prometheusrule", Namespace: "fake-namespace"}
+				// Neither is controlled by the UrlMonitor, e.g. because they were created before it controlled them
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(2)
+				mockBlackBoxExporter.EXPECT().ShouldDeleteBlackBoxExporterResources().Return(blackboxexporterconsts.DeleteBlackBoxExporter, nil)
+				mockBlackBoxExporter.EXPECT().EnsureBlackBoxExporterResourcesAbsent().Times(1)
+				mockCommon.EXPECT().DeleteFinalizer(&urlMonitor, urlmonitor.FinalizerKey).Times(1).Return(true)
+				mockCommon.EXPECT().UpdateMonitorResource(&urlMonitor).Return(utilreconcile.StopOperation(), nil)
+			})
+			It("removes the generated resources, the blackbox exporter and the finalizer", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+			})
+		})
+		When("the deletion of the UrlMonitor is forced", func() {
+			BeforeEach(func() {
+				urlMonitor.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
+				urlMonitor.Annotations = map[string]string{consts.ForceDeleteAnnotation: "true"}
+				mockCommon.EXPECT().DeleteFinalizer(&urlMonitor, urlmonitor.FinalizerKey).Times(1).Return(true)
+				mockCommon.EXPECT().UpdateMonitorResource(&urlMonitor).Return(utilreconcile.StopOperation(), nil)
+			})
+			It("only removes the finalizer", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+			})
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/importer"
+)
+
+// runImport implements the 'import' subcommand, which converts the targets of an existing blackbox
+// exporter setup into monitors. The monitors are written to stdout, skipped targets to stderr
+func runImport(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	namespace := flags.String("namespace", config.OperatorNamespace, "The namespace the monitors are created in, RouteMonitors use the namespace of their Route if it's empty")
+	clusterDomain := flags.String("cluster-domain", "", "The domain of the cluster, targets below it become ClusterUrlMonitors")
+	routesFile := flags.String("routes", "", "A file holding the Routes of the cluster, as written by 'oc get routes -A -o yaml'. Targets served by one of them become RouteMonitors")
+	modulesFile := flags.String("blackbox-config", "", "The configuration file of the existing blackbox exporter, used to translate the modules of the targets")
+	sloTarget := flags.String("slo", "99.5", "The availability target, in percent, set on every monitor")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s import [flags] <prometheus config or target file>...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	opts := importer.Options{
+		Namespace:     *namespace,
+		ClusterDomain: *clusterDomain,
+		SloTarget:     *sloTarget,
+	}
+	if *routesFile != "" {
+		routes := routev1.RouteList{}
+		if err := readYAML(*routesFile, &routes); err != nil {
+			fmt.Fprintf(stderr, "unable to read the Routes: %v\n", err)
+			return 1
+		}
+		opts.Routes = routes.Items
+	}
+	if *modulesFile != "" {
+		blackboxConfig := blackboxexporter.Config{}
+		if err := readYAML(*modulesFile, &blackboxConfig); err != nil {
+			fmt.Fprintf(stderr, "unable to read the blackbox exporter configuration: %v\n", err)
+			return 1
+		}
+		opts.Modules = blackboxConfig.Modules
+	}
+
+	targets := []importer.Target{}
+	for _, file := range flags.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "unable to read the targets: %v\n", err)
+			return 1
+		}
+		fileTargets, err := importer.ParseTargets(file, data)
+		if err != nil {
+			fmt.Fprintf(stderr, "unable to parse the targets: %v\n", err)
+			return 1
+		}
+		targets = append(targets, fileTargets...)
+	}
+
+	objects, skipped := importer.Convert(targets, opts)
+	for _, s := range skipped {
+		fmt.Fprintf(stderr, "skipped target '%s' of '%s': %s\n", s.Target.URL, s.Target.Job, s.Reason)
+	}
+	out, err := importer.Render(objects)
+	if err != nil {
+		fmt.Fprintf(stderr, "unable to render the monitors: %v\n", err)
+		return 1
+	}
+	if _, err := stdout.Write(out); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func readYAML(file string, into interface{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, into)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	integration "github.com/openshift/route-monitor-operator/int"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
 	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 )
 
@@ -46,7 +47,7 @@ var _ = Describe("Integrationtests", func() {
 
 			err := i.RemoveClusterUrlMonitor(clusterUrlMonitorNamespace, clusterUrlMonitorName)
 			Expect(err).NotTo(HaveOccurred())
-			expectedServiceMonitorName = types.NamespacedName{Name: clusterUrlMonitorName, Namespace: clusterUrlMonitorNamespace}
+			expectedServiceMonitorName = reconcileCommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "ClusterUrlMonitor", types.NamespacedName{Name: clusterUrlMonitorName, Namespace: clusterUrlMonitorNamespace})
 			clusterUrlMonitor = v1alpha1.ClusterUrlMonitor{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: clusterUrlMonitorNamespace,
@@ -139,7 +140,7 @@ var _ = Describe("Integrationtests", func() {
 			})
 
 			It("removes the corresponding PrometheusRule", func() {
-				latestClusterUrlMonitor, err := i.ClusterUrlMonitorWaitForPrometheusRuleRef(expectedServiceMonitorName, 20)
+				latestClusterUrlMonitor, err := i.ClusterUrlMonitorWaitForPrometheusRuleRef(types.NamespacedName{Namespace: clusterUrlMonitorNamespace, Name: clusterUrlMonitorName}, 20)
 				Expect(err).NotTo(HaveOccurred())
 				latestClusterUrlMonitor.Spec.Slo.TargetAvailabilityPercent = ""
 				err = i.Client.Update(context.TODO(), &latestClusterUrlMonitor)
@@ -169,7 +170,7 @@ var _ = Describe("Integrationtests", func() {
 			})
 
 			It("changes the corresponding PrometheusRule", func() {
-				latestClusterUrlMonitor, err := i.ClusterUrlMonitorWaitForPrometheusRuleRef(expectedServiceMonitorName, 20)
+				latestClusterUrlMonitor, err := i.ClusterUrlMonitorWaitForPrometheusRuleRef(types.NamespacedName{Namespace: clusterUrlMonitorNamespace, Name: clusterUrlMonitorName}, 20)
 				Expect(err).NotTo(HaveOccurred())
 				latestClusterUrlMonitor.Spec.Slo.TargetAvailabilityPercent = "99.5"
 				err = i.Client.Update(context.TODO(), &latestClusterUrlMonitor)
@@ -181,7 +182,7 @@ var _ = Describe("Integrationtests", func() {
 				Expect(err).NotTo(HaveOccurred())
 				spec := clusterUrlMonitor.Spec
 				expectedUrl := spec.Prefix + clusterConfig.Spec.BaseDomain + ":" + spec.Port + spec.Suffix
-				err = i.ClusterUrlMonitorWaitForPrometheusRuleCorrectSLO(expectedServiceMonitorName, parsedSlo, 20, expectedUrl)
+				err = i.ClusterUrlMonitorWaitForPrometheusRuleCorrectSLO(types.NamespacedName{Namespace: clusterUrlMonitorNamespace, Name: clusterUrlMonitorName}, parsedSlo, 20, expectedUrl)
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
@@ -199,7 +200,7 @@ var _ = Describe("Integrationtests", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				By("adding a SLO")
-				err = i.Client.Get(context.TODO(), expectedServiceMonitorName, &clusterUrlMonitor)
+				err = i.Client.Get(context.TODO(), types.NamespacedName{Namespace: clusterUrlMonitorNamespace, Name: clusterUrlMonitorName}, &clusterUrlMonitor)
 				Expect(err).NotTo(HaveOccurred())
 				clusterUrlMonitor.Spec.Slo.TargetAvailabilityPercent = "99.5"
 				err = i.Client.Update(context.TODO(), &clusterUrlMonitor)
@@ -209,7 +210,7 @@ var _ = Describe("Integrationtests", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				By("removing the SLO again")
-				err = i.Client.Get(context.TODO(), expectedServiceMonitorName, &clusterUrlMonitor)
+				err = i.Client.Get(context.TODO(), types.NamespacedName{Namespace: clusterUrlMonitorNamespace, Name: clusterUrlMonitorName}, &clusterUrlMonitor)
 				Expect(err).NotTo(HaveOccurred())
 				clusterUrlMonitor.Spec.Slo = v1alpha1.SloSpec{}
 				err = i.Client.Update(context.TODO(), &clusterUrlMonitor)
@@ -263,7 +264,7 @@ var _ = Describe("Integrationtests", func() {
 			routeMonitorName = "fake-route-monitor"
 			routeMonitorNamespace = "default"
 			err := i.RemoveRouteMonitor(routeMonitorNamespace, routeMonitorName)
-			expectedDependentResource = types.NamespacedName{Name: routeMonitorName, Namespace: routeMonitorNamespace}
+			expectedDependentResource = reconcileCommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "RouteMonitor", types.NamespacedName{Name: routeMonitorName, Namespace: routeMonitorNamespace})
 			Expect(err).NotTo(HaveOccurred())
 			routeMonitor = v1alpha1.RouteMonitor{
 				ObjectMeta: metav1.ObjectMeta{
@@ -375,7 +376,7 @@ var _ = Describe("Integrationtests", func() {
 			})
 
 			It("removes the corresponding PrometheusRule", func() {
-				latestRouteMonitor, err := i.RouteMonitorWaitForPrometheusRuleRef(expectedDependentResource, 20)
+				latestRouteMonitor, err := i.RouteMonitorWaitForPrometheusRuleRef(types.NamespacedName{Namespace: routeMonitorNamespace, Name: routeMonitorName}, 20)
 				Expect(err).NotTo(HaveOccurred())
 				latestRouteMonitor.Spec.Slo.TargetAvailabilityPercent = ""
 				err = i.Client.Update(context.TODO(), &latestRouteMonitor)
@@ -405,13 +406,13 @@ var _ = Describe("Integrationtests", func() {
 			})
 
 			It("changes the corresponding PrometheusRule", func() {
-				latestRouteMonitor, err := i.RouteMonitorWaitForPrometheusRuleRef(expectedDependentResource, 20)
+				latestRouteMonitor, err := i.RouteMonitorWaitForPrometheusRuleRef(types.NamespacedName{Namespace: routeMonitorNamespace, Name: routeMonitorName}, 20)
 				Expect(err).NotTo(HaveOccurred())
 				latestRouteMonitor.Spec.Slo.TargetAvailabilityPercent = "99.5"
 				err = i.Client.Update(context.TODO(), &latestRouteMonitor)
 				Expect(err).NotTo(HaveOccurred())
 				_, parsedSlo := latestRouteMonitor.Spec.Slo.IsValid()
-				err = i.RouteMonitorWaitForPrometheusRuleCorrectSLO(expectedDependentResource, parsedSlo, 20)
+				err = i.RouteMonitorWaitForPrometheusRuleCorrectSLO(types.NamespacedName{Namespace: routeMonitorNamespace, Name: routeMonitorName}, parsedSlo, 20)
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
@@ -429,7 +430,7 @@ var _ = Describe("Integrationtests", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				By("adding a SLO")
-				err = i.Client.Get(context.TODO(), expectedDependentResource, &routeMonitor)
+				err = i.Client.Get(context.TODO(), types.NamespacedName{Namespace: routeMonitorNamespace, Name: routeMonitorName}, &routeMonitor)
 				Expect(err).NotTo(HaveOccurred())
 				routeMonitor.Spec.Slo.TargetAvailabilityPercent = "99.5"
 				err = i.Client.Update(context.TODO(), &routeMonitor)
@@ -439,7 +440,7 @@ var _ = Describe("Integrationtests", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				By("removing the SLO again")
-				err = i.Client.Get(context.TODO(), expectedDependentResource, &routeMonitor)
+				err = i.Client.Get(context.TODO(), types.NamespacedName{Namespace: routeMonitorNamespace, Name: routeMonitorName}, &routeMonitor)
 				Expect(err).NotTo(HaveOccurred())
 				routeMonitor.Spec.Slo = v1alpha1.SloSpec{}
 				err = i.Client.Update(context.TODO(), &routeMonitor)
@@ -12,6 +12,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/route-monitor-operator/pkg/alert"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -217,28 +218,29 @@ func (i *Integration) WaitForPrometheusRuleToClear(name types.NamespacedName, se
 }
 
 func (i *Integration) RouteMonitorWaitForPrometheusRuleCorrectSLO(name types.NamespacedName, targetSlo string, seconds int) error {
-	prometheusRule := monitoringv1.PrometheusRule{}
-	err := i.Client.Get(context.TODO(), name, &prometheusRule)
+	routeMonitor := v1alpha1.RouteMonitor{}
+	err := i.Client.Get(context.TODO(), name, &routeMonitor)
 	if errors.IsNotFound(err) {
-		return fmt.Errorf("PrometheusRule wasn't found")
+		return fmt.Errorf("RouteMonitor wasn't found")
 	}
 	if err != nil {
 		return err
 	}
 
-	routeMonitor := v1alpha1.RouteMonitor{}
-	err = i.Client.Get(context.TODO(), name, &routeMonitor)
+	ruleName := reconcileCommon.GeneratedResourceName(routeMonitor.Status.PrometheusRuleRef, "RouteMonitor", name)
+	prometheusRule := monitoringv1.PrometheusRule{}
+	err = i.Client.Get(context.TODO(), ruleName, &prometheusRule)
 	if errors.IsNotFound(err) {
-		return fmt.Errorf("RouteMonitor wasn't found")
+		return fmt.Errorf("PrometheusRule wasn't found")
 	}
 	if err != nil {
 		return err
 	}
 
-	template := alert.TemplateForPrometheusRuleResource(routeMonitor.Status.RouteURL, targetSlo, name)
+	template := alert.TemplateForPrometheusRuleResource(routeMonitor.Status.RouteURL, targetSlo, routeMonitor.Spec.Slo.ClientErrorsFail(), name)
 	t := 0
 	for ; t < seconds; t++ {
-		err := i.Client.Get(context.TODO(), name, &prometheusRule)
+		err := i.Client.Get(context.TODO(), ruleName, &prometheusRule)
 		if errors.IsNotFound(err) {
 			return fmt.Errorf("PrometheusRule wasn't found")
 		}
@@ -254,28 +256,29 @@ func (i *Integration) RouteMonitorWaitForPrometheusRuleCorrectSLO(name types.Nam
 }
 
 func (i *Integration) ClusterUrlMonitorWaitForPrometheusRuleCorrectSLO(name types.NamespacedName, targetSlo string, seconds int, expectedUrl string) error {
-	prometheusRule := monitoringv1.PrometheusRule{}
-	err := i.Client.Get(context.TODO(), name, &prometheusRule)
+	clusterUrlMonitor := v1alpha1.ClusterUrlMonitor{}
+	err := i.Client.Get(context.TODO(), name, &clusterUrlMonitor)
 	if errors.IsNotFound(err) {
-		return fmt.Errorf("PrometheusRule wasn't found")
+		return fmt.Errorf("ClusterUrlMonitor wasn't found")
 	}
 	if err != nil {
 		return err
 	}
 
-	clusterUrlMonitor := v1alpha1.ClusterUrlMonitor{}
-	err = i.Client.Get(context.TODO(), name, &clusterUrlMonitor)
+	ruleName := reconcileCommon.GeneratedResourceName(clusterUrlMonitor.Status.PrometheusRuleRef, "ClusterUrlMonitor", name)
+	prometheusRule := monitoringv1.PrometheusRule{}
+	err = i.Client.Get(context.TODO(), ruleName, &prometheusRule)
 	if errors.IsNotFound(err) {
-		return fmt.Errorf("ClusterUrlMonitor wasn't found")
+		return fmt.Errorf("PrometheusRule wasn't found")
 	}
 	if err != nil {
 		return err
 	}
 
-	template := alert.TemplateForPrometheusRuleResource(expectedUrl, targetSlo, name)
+	template := alert.TemplateForPrometheusRuleResource(expectedUrl, targetSlo, clusterUrlMonitor.Spec.Slo.ClientErrorsFail(), name)
 	t := 0
 	for ; t < seconds; t++ {
-		err := i.Client.Get(context.TODO(), name, &prometheusRule)
+		err := i.Client.Get(context.TODO(), ruleName, &prometheusRule)
 		if errors.IsNotFound(err) {
 			return fmt.Errorf("PrometheusRule wasn't found")
 		}
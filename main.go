@@ -19,18 +19,27 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -43,9 +52,28 @@ import (
 	monitoringopenshiftiov1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
 	monitoringv1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/config"
+	"github.com/openshift/route-monitor-operator/controllers"
+	blackboxexportercontroller "github.com/openshift/route-monitor-operator/controllers/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/controllers/certificatemonitor"
 	"github.com/openshift/route-monitor-operator/controllers/clusterurlmonitor"
+	"github.com/openshift/route-monitor-operator/controllers/consoledashboard"
 	"github.com/openshift/route-monitor-operator/controllers/hostedcontrolplane"
+	"github.com/openshift/route-monitor-operator/controllers/monitortemplate"
+	"github.com/openshift/route-monitor-operator/controllers/namespacemonitor"
 	"github.com/openshift/route-monitor-operator/controllers/routemonitor"
+	"github.com/openshift/route-monitor-operator/controllers/routemonitorset"
+	runtimeconfigcontroller "github.com/openshift/route-monitor-operator/controllers/runtimeconfig"
+	"github.com/openshift/route-monitor-operator/controllers/urlmonitor"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/clusteridentity"
+	blackboxconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/health"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	// +kubebuilder:scaffold:imports
 )
@@ -66,13 +94,36 @@ func init() {
 	utilruntime.Must(hypershiftv1beta1.AddToScheme(scheme))
 	utilruntime.Must(rhobsv1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(scrapeconfig.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var enablehypershift bool
+	var hcpDedicatedExporters bool
+	var hcpEndpointMonitors bool
+	var labelOwnedResources bool
+	var probeResources bool
+	var scrapeConfigResources bool
+	var zoneLabels bool
+	var fipsMode bool
+	var consoleDashboard bool
+	var enableWebhooks bool
+	var serverSideApply bool
+	var dryRun bool
+	var sloChangeAlertDuration time.Duration
+	var verifyInterval time.Duration
+	var sweepInterval time.Duration
+	var maxReconcileDuration time.Duration
+	var probeHealthInterval time.Duration
+	var backoffBaseDelay, backoffMaxDelay time.Duration
+	var scrubLabels, scrubAnnotations string
 	var probeAddr string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -80,18 +131,196 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&enablehypershift, "enable-hypershift", false,
-		"Enabling this for HyperShift")
+		"Deprecated: HyperShift is enabled when the HostedControlPlane API is installed, and the monitoring API of the ServiceMonitors is discovered")
+	flag.BoolVar(&hcpDedicatedExporters, "hcp-dedicated-exporters", false,
+		"Probe the kube-apiserver of each HostedControlPlane from a blackbox-exporter deployed into its namespace, scraped by its RHOBS ServiceMonitor, "+
+			"instead of the shared blackbox-exporter of the management cluster")
+	flag.BoolVar(&hcpEndpointMonitors, "hcp-endpoint-monitors", false,
+		"Monitor the kube-apiserver, oauth and console endpoints of each hosted cluster with a ClusterUrlMonitor created in the namespace of its HostedControlPlane")
+	flag.BoolVar(&labelOwnedResources, "label-owned-resources", false,
+		"Label the generated ServiceMonitors and PrometheusRules with the monitor they belong to")
+	flag.BoolVar(&probeResources, "probe-resources", false,
+		"Generate Probes addressing the blackbox-exporter directly instead of ServiceMonitors selecting its Service, except for Hypershift monitors")
+	flag.BoolVar(&scrapeConfigResources, "scrape-configs", false,
+		"Generate ScrapeConfigs instead of ServiceMonitors, for Prometheus agents that don't evaluate ServiceMonitors, except for Hypershift monitors")
+	flag.BoolVar(&zoneLabels, "zone-labels", false,
+		"Label the probes of the ServiceMonitors with the zone of the blackbox-exporter pod, requires Prometheus to be allowed to get Nodes")
+	flag.BoolVar(&fipsMode, "fips-mode", false,
+		"Reject probes allowing TLS versions below TLS12 and run the blackbox-exporter in FIPS mode, which requires a FIPS capable image")
+	flag.BoolVar(&consoleDashboard, "console-dashboard", false,
+		"Maintain a dashboard of the RouteMonitors and ClusterUrlMonitors in the Observe section of the OpenShift console")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the webhooks rejecting invalid monitors at admission, which requires a serving certificate in /tmp/k8s-webhook-server/serving-certs")
+	flag.BoolVar(&serverSideApply, "server-side-apply", true,
+		"Apply the generated ServiceMonitors and PrometheusRules and the blackbox exporter's resources server-side instead of updating them, keeping fields set by other managers")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Only validate the writes of the generated resources with the API server instead of persisting them, and report them as Events, "+
+			"metrics and the DryRun condition of the monitors. The RouteMonitorSet, MonitorTemplate, ConsoleDashboard and HostedControlPlane controllers don't run then")
+	flag.StringVar(&scrubLabels, "scrub-labels", "",
+		"Comma separated keys of labels that are removed from the generated resources, a key ending in '*' matches every key with that prefix")
+	flag.StringVar(&scrubAnnotations, "scrub-annotations", "",
+		"Comma separated keys of annotations that are removed from the generated resources, a key ending in '*' matches every key with that prefix")
+	flag.DurationVar(&sloChangeAlertDuration, "slo-change-alert-duration", 0,
+		"How long an informational alert fires after the availability target of a monitor has been lowered, 0 disables it")
+	flag.DurationVar(&verifyInterval, "verify-interval", 10*time.Minute,
+		"How often the resources referenced by the monitors are checked to exist, so they're regenerated after vanishing e.g. with their CRD, 0 disables it")
+	flag.DurationVar(&sweepInterval, "sweep-interval", time.Hour,
+		"How often the generated resources labelled with a monitor that no longer exists are deleted, starting at startup, 0 disables it")
+	flag.DurationVar(&maxReconcileDuration, "max-reconcile-duration", 10*time.Minute,
+		"How long a reconcile may run before the liveness probe considers its controller stuck, 0 disables the check")
+	flag.DurationVar(&probeHealthInterval, "probe-health-interval", 5*time.Minute,
+		"How often the health of the probes is queried from --prometheus-url and reported in the status of the monitors")
+	flag.DurationVar(&backoffBaseDelay, "backoff-base-delay", time.Second,
+		"How long the first retry of a monitor's failed reconcile waits, every further failure doubles the delay")
+	flag.DurationVar(&backoffMaxDelay, "backoff-max-delay", 10*time.Minute,
+		"The longest delay between the retries of a monitor's failed reconciles")
 
 	var blackboxExporterImage string
 	var blackboxExporterNamespace string
+	var clusterIDSource string
+	var clusterIDMetadataURL string
+	var runtimeConfigMap string
+	var leaderElectionID string
+	var watchNamespaces string
+	var monitorSelector string
+	var prometheusURL string
+	var prometheusTokenFile string
+	var prometheusCAFile string
 
-	flag.StringVar(&blackboxExporterImage, "blackbox-image", "quay.io/prometheus/blackbox-exporter:master", "The image that will be used for the blackbox-exporter deployment")
+	flag.StringVar(&blackboxExporterImage, "blackbox-image", defaultBlackboxExporterImage,
+		"The image that will be used for the blackbox-exporter deployment, by tag or pinned to a digest, e.g. of a mirror registry")
+	var blackboxImagePullSecrets string
+	flag.StringVar(&blackboxImagePullSecrets, "blackbox-image-pull-secrets", "",
+		"Comma separated names of Secrets in the blackbox-exporter's namespace its image is pulled with")
 	flag.StringVar(&blackboxExporterNamespace, "blackbox-namespace", config.OperatorNamespace, "Blackbox-exporter deployment will reside on this Namespace")
+	var blackboxExporterDeployment blackboxexporter.DeploymentSettings
+	flag.Func("blackbox-replicas", "The number of blackbox-exporter pods (default 1)", func(value string) error {
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || replicas < 1 {
+			return fmt.Errorf("invalid number of replicas %q: it must be positive", value)
+		}
+		blackboxExporterDeployment.Replicas = int32(replicas)
+		return nil
+	})
+	flag.Func("blackbox-resources", `The requests and limits of the blackbox-exporter container, e.g. '{"requests": {"cpu": "100m", "memory": "64Mi"}}'`,
+		func(value string) error {
+			resources, err := runtimeconfig.ParseResources(value)
+			blackboxExporterDeployment.Resources = resources
+			return err
+		})
+	flag.StringVar(&blackboxExporterDeployment.PriorityClassName, "blackbox-priority-class", "",
+		"The PriorityClass of the blackbox-exporter pods, e.g. system-cluster-critical on managed clusters so they aren't evicted before the workloads they monitor")
+	var blackboxExporterNetworkPolicy runtimeconfig.NetworkPolicy
+	flag.Func("blackbox-network-policy", `The NetworkPolicy of the blackbox-exporter, e.g. '{"enabled": true, "prometheusNamespaces": ["openshift-monitoring"]}'`,
+		func(value string) error {
+			policy, err := runtimeconfig.ParseNetworkPolicy(value)
+			blackboxExporterNetworkPolicy = policy
+			return err
+		})
+	flag.BoolVar(&blackboxExporterDeployment.RelaxedSecurity, "blackbox-relaxed-security", false,
+		"Run the blackbox-exporter without the security context of the restricted Pod Security Standard, e.g. for icmp probes needing NET_RAW")
+	flag.BoolVar(&blackboxExporterDeployment.DaemonSet, "blackbox-daemonset", false,
+		"Run the blackbox-exporter as a DaemonSet and label the probes of the ServiceMonitors with the node they ran from, instead of --blackbox-replicas pods")
+	flag.BoolVar(&blackboxExporterDeployment.ServingCert, "blackbox-serving-cert", false,
+		"Serve the probes of the blackbox-exporter over TLS with a certificate of the service CA, and scrape the ServiceMonitors over https")
+	flag.Func("blackbox-service-mesh", "Run the blackbox-exporter in an Istio service mesh: 'inject' its sidecar and scrape the ServiceMonitors with the mesh's certificates mounted into Prometheus, "+
+		"or 'exclude' the pods from the mesh (default the namespace's injection policy)",
+		func(mode string) error {
+			if mode != blackboxconsts.ServiceMeshInject && mode != blackboxconsts.ServiceMeshExclude {
+				return fmt.Errorf("unknown service mesh mode %q, expected %q or %q", mode, blackboxconsts.ServiceMeshInject, blackboxconsts.ServiceMeshExclude)
+			}
+			blackboxExporterDeployment.ServiceMesh = mode
+			return nil
+		})
+	flag.BoolVar(&blackboxExporterDeployment.ZoneSpread, "blackbox-zone-spread", false,
+		"Spread the blackbox-exporter pods over the zones and nodes of the cluster")
+	flag.Func("blackbox-pod-labels", `Labels added to the blackbox-exporter pods, e.g. '{"cost-center": "monitoring"}'`,
+		func(value string) error {
+			labels, err := runtimeconfig.ParsePodLabels(value)
+			blackboxExporterDeployment.PodLabels = labels
+			return err
+		})
+	flag.Func("blackbox-pod-annotations", `Annotations added to the blackbox-exporter pods, e.g. '{"sidecar.istio.io/inject": "false"}'`,
+		func(value string) error {
+			annotations, err := runtimeconfig.ParsePodAnnotations(value)
+			blackboxExporterDeployment.PodAnnotations = annotations
+			return err
+		})
+	flag.Func("blackbox-log-level", "The level of the blackbox-exporter's logs, 'debug', 'info', 'warn' or 'error' (default the exporter's own)",
+		func(level string) error {
+			blackboxExporterDeployment.LogLevel = level
+			return runtimeconfig.ValidateExporterLogLevel(level)
+		})
+	flag.StringVar(&clusterIDSource, "cluster-id-source", clusteridentity.SourceClusterVersion,
+		"Where the ID of a cluster that isn't hosted is read from: "+
+			"'clusterversion', 'env' (the "+clusteridentity.EnvVariable+" environment variable) or 'metadata'")
+	flag.StringVar(&clusterIDMetadataURL, "cluster-id-metadata-url", "", "The URL of the metadata service serving the cluster ID, used by the 'metadata' source")
+	flag.StringVar(&runtimeConfigMap, "runtime-config-map", runtimeconfig.DefaultConfigMapName,
+		"The ConfigMap in the operator's namespace whose settings override the log level, --blackbox-image, --blackbox-replicas, --blackbox-resources, --blackbox-network-policy, --blackbox-log-level, --blackbox-pod-labels, --blackbox-pod-annotations and --slo-change-alert-duration "+
+			"without restarting the operator, empty disables it")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "2793210b.openshift.io",
+		"The name of the lease of the leader election, instances of the operator running side by side need different ones")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"The comma separated namespaces whose monitors are reconciled, empty reconciles the monitors of all namespaces")
+	flag.StringVar(&monitorSelector, "monitor-selector", "",
+		"The label selector of the monitors that are reconciled, e.g. 'rmo-instance=canary', empty reconciles all monitors")
+
+	var logFormat string
+	var logLevelOverride *zapcore.Level
+	var loggerLevels map[string]zapcore.Level
+	flag.Func("log-format", "The format of the logs, 'json' or 'console', overriding --zap-encoder", func(format string) error {
+		if format != "json" && format != "console" {
+			return fmt.Errorf("unknown log format %q", format)
+		}
+		logFormat = format
+		return nil
+	})
+	flag.Func("log-level", "The level of the logs, e.g. 'info', 'debug' or a verbosity like '3' for the traces of the reconciles, overriding --zap-log-level",
+		func(level string) error {
+			parsed, err := logging.ParseLevel(level)
+			logLevelOverride = &parsed
+			return err
+		})
+	flag.Func("controller-log-levels", "The comma separated levels of single controllers overriding --log-level, e.g. 'RouteMonitor=3,UrlMonitor=info'",
+		func(levels string) (err error) {
+			loggerLevels, err = logging.ParseLoggerLevels(levels)
+			return err
+		})
+
+	flag.StringVar(&prometheusURL, "prometheus-url", "",
+		"The url of the Prometheus or Thanos querier API the health of the probes is queried from, "+
+			"e.g. https://thanos-querier.openshift-monitoring.svc:9091, empty disables reporting it")
+	flag.StringVar(&prometheusTokenFile, "prometheus-token-file", "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		"The file holding the bearer token sent to --prometheus-url, empty sends none")
+	flag.StringVar(&prometheusCAFile, "prometheus-ca-file", "",
+		"The file holding the CAs trusted for --prometheus-url on top of the system ones, e.g. the service CA")
 
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	// The level is shared with the runtime config, so it can be changed while running
+	logLevel := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	if opts.Level != nil {
+		logLevel.SetLevel(zapcore.LevelOf(opts.Level))
+	} else if opts.Development {
+		logLevel.SetLevel(zapcore.DebugLevel)
+	}
+	if logLevelOverride != nil {
+		logLevel.SetLevel(*logLevelOverride)
+	}
+	opts.Level = logLevel
+	if len(loggerLevels) > 0 {
+		// The core logs every level, the level of the entry's logger decides whether it's written
+		opts.Level = uberzap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+		opts.ZapOpts = append(opts.ZapOpts, logging.WithLoggerLevels(loggerLevels, logLevel))
+	}
+	switch logFormat {
+	case "json":
+		zap.JSONEncoder()(&opts)
+	case "console":
+		zap.ConsoleEncoder()(&opts)
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	options := ctrl.Options{
@@ -101,7 +330,58 @@ func main() {
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "2793210b.openshift.io",
+		LeaderElectionID:       leaderElectionID,
+	}
+
+	if probeResources && scrapeConfigResources {
+		setupLog.Error(fmt.Errorf("--probe-resources and --scrape-configs are mutually exclusive"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if zoneLabels && (probeResources || scrapeConfigResources) {
+		setupLog.Error(fmt.Errorf("--zone-labels only applies to ServiceMonitors, not to --probe-resources or --scrape-configs"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if blackboxExporterDeployment.DaemonSet && (probeResources || scrapeConfigResources) {
+		setupLog.Error(fmt.Errorf("--blackbox-daemonset only applies to ServiceMonitors, not to --probe-resources or --scrape-configs"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if blackboxExporterDeployment.ServingCert && (probeResources || scrapeConfigResources) {
+		setupLog.Error(fmt.Errorf("--blackbox-serving-cert only applies to ServiceMonitors, not to --probe-resources or --scrape-configs"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if blackboxExporterDeployment.MeshSidecar() && blackboxExporterDeployment.ServingCert {
+		setupLog.Error(fmt.Errorf("--blackbox-service-mesh=inject secures the scrapes with the mesh's mTLS, not with --blackbox-serving-cert"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if blackboxExporterDeployment.MeshSidecar() && (probeResources || scrapeConfigResources) {
+		setupLog.Error(fmt.Errorf("--blackbox-service-mesh=inject only applies to ServiceMonitors, not to --probe-resources or --scrape-configs"), "invalid flags")
+		os.Exit(1)
+	}
+
+	if backoffBaseDelay <= 0 || backoffMaxDelay < backoffBaseDelay {
+		setupLog.Error(fmt.Errorf("--backoff-base-delay must be positive and at most --backoff-max-delay"), "invalid flags")
+		os.Exit(1)
+	}
+
+	selector, err := labels.Parse(monitorSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --monitor-selector")
+		os.Exit(1)
+	}
+
+	if err := runtimeconfig.ValidateImage(blackboxExporterImage); err != nil {
+		setupLog.Error(err, "invalid --blackbox-image")
+		os.Exit(1)
+	}
+	blackboxExporterDeployment.ImagePullSecrets = splitKeys(blackboxImagePullSecrets)
+
+	if fipsMode && blackboxExporterImage == defaultBlackboxExporterImage {
+		setupLog.Info("FIPS mode is enabled, but the upstream blackbox-exporter image isn't built with a FIPS capable toolchain, set --blackbox-image")
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
@@ -110,37 +390,227 @@ func main() {
 		os.Exit(1)
 	}
 
-	routeMonitorReconciler := routemonitor.NewReconciler(mgr, blackboxExporterImage, blackboxExporterNamespace, enablehypershift)
+	clusterIdentity, err := clusteridentity.ForSource(mgr.GetClient(), clusterIDSource, clusterIDMetadataURL)
+	if err != nil {
+		setupLog.Error(err, "unable to determine the cluster ID source")
+		os.Exit(1)
+	}
+	var runtimeConfig *runtimeconfig.Config
+	if runtimeConfigMap != "" {
+		runtimeConfig = runtimeconfig.New(runtimeconfig.Settings{
+			BlackBoxExporterImage:          blackboxExporterImage,
+			BlackBoxExporterReplicas:       blackboxExporterDeployment.Replicas,
+			BlackBoxExporterResources:      blackboxExporterDeployment.Resources,
+			BlackBoxExporterNetworkPolicy:  blackboxExporterNetworkPolicy,
+			BlackBoxExporterLogLevel:       blackboxExporterDeployment.LogLevel,
+			BlackBoxExporterPodLabels:      blackboxExporterDeployment.PodLabels,
+			BlackBoxExporterPodAnnotations: blackboxExporterDeployment.PodAnnotations,
+			SloChangeAlertDuration:         sloChangeAlertDuration,
+			BackoffBaseDelay:               backoffBaseDelay,
+			BackoffMaxDelay:                backoffMaxDelay,
+		}, logLevel)
+		runtimeConfigReconciler := runtimeconfigcontroller.NewRuntimeConfigReconciler(mgr, runtimeConfigMap, runtimeConfig)
+		if err := runtimeConfigReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RuntimeConfig")
+			os.Exit(1)
+		}
+	}
+	enableHCP, err := shouldEnableHCP(mgr)
+	if err != nil {
+		setupLog.Error(err, "failed to determine whether HCP controller should be enabled", "controller", "HostedControlPlane")
+	}
+	if enablehypershift && !enableHCP {
+		setupLog.Info("Ignoring the deprecated --enable-hypershift: the HostedControlPlane API isn't installed")
+	}
+	if enableHCP && blackboxExporterDeployment.ServingCert {
+		// The RHOBS Prometheus scraping the monitors of the hosted control planes doesn't mount the service CA bundle
+		setupLog.Error(fmt.Errorf("--blackbox-serving-cert can't be used on a management cluster, the hosted control planes' ServiceMonitors are scraped over http"), "invalid flags")
+		os.Exit(1)
+	}
+	if enableHCP && blackboxExporterDeployment.MeshSidecar() {
+		// Nor does it mount the mesh's certificates
+		setupLog.Error(fmt.Errorf("--blackbox-service-mesh=inject can't be used on a management cluster, the hosted control planes' ServiceMonitors are scraped over http"), "invalid flags")
+		os.Exit(1)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create the discovery client")
+		os.Exit(1)
+	}
+	onOpenShift, err := isOpenShift(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to determine whether the cluster is OpenShift")
+		os.Exit(1)
+	}
+	if !onOpenShift {
+		// OpenShift assigns the pods a user of their namespace's range, other platforms run the image's user
+		uid := blackboxconsts.NobodyUID
+		blackboxExporterDeployment.RunAsUser = &uid
+	}
+	reconcilerOptions := controllers.ReconcilerOptions{
+		BlackBoxExporterImage:         blackboxExporterImage,
+		BlackBoxExporterNamespace:     blackboxExporterNamespace,
+		BlackBoxExporterDeployment:    blackboxExporterDeployment,
+		BlackBoxExporterNetworkPolicy: blackboxExporterNetworkPolicy,
+		EnableHypershift:              enableHCP,
+		MonitoringAPIs:                servicemonitor.NewMonitoringAPIs(discoveryClient),
+		LabelOwnedResources:           labelOwnedResources,
+		ProbeResources:                probeResources,
+		ScrapeConfigResources:         scrapeConfigResources,
+		ZoneLabels:                    zoneLabels,
+		SloChangeAlertDuration:        sloChangeAlertDuration,
+		ClusterIdentity:               clusterIdentity,
+		FIPSMode:                      fipsMode,
+		RuntimeConfig:                 runtimeConfig,
+		VerifyInterval:                verifyInterval,
+		Backoff:                       controllers.Backoff{BaseDelay: backoffBaseDelay, MaxDelay: backoffMaxDelay},
+		Scope:                         controllers.Scope{Namespaces: splitKeys(watchNamespaces), Selector: selector},
+		DryRun:                        dryRun,
+		ScrubbedMetadata: reconcileCommon.MetadataScrubber{
+			Labels:      splitKeys(scrubLabels),
+			Annotations: splitKeys(scrubAnnotations),
+		},
+	}
+	if serverSideApply {
+		reconcilerOptions.FieldOwner = config.OperatorName
+	}
+	// Dry runs don't report the probe health, as it's written to the status of the monitors
+	if prometheusURL != "" && !dryRun {
+		if probeHealthInterval <= 0 {
+			setupLog.Error(fmt.Errorf("--probe-health-interval must be positive"), "invalid flags")
+			os.Exit(1)
+		}
+		querier, err := probehealth.NewPrometheusClient(prometheusURL, prometheusTokenFile, prometheusCAFile)
+		if err != nil {
+			setupLog.Error(err, "invalid --prometheus-url or --prometheus-ca-file")
+			os.Exit(1)
+		}
+		reconcilerOptions.ProbeHealth = querier
+		reconcilerOptions.ProbeHealthInterval = probeHealthInterval
+	}
+
+	if err := reconcileCommon.IndexRouteMonitors(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to index RouteMonitors by Route")
+		os.Exit(1)
+	}
+
+	routeMonitorReconciler := routemonitor.NewReconciler(mgr, reconcilerOptions)
 	if err := routeMonitorReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RouteMonitor")
 		os.Exit(1)
 	}
 
-	clusterUrlMonitorReconciler := clusterurlmonitor.NewReconciler(mgr, blackboxExporterImage, blackboxExporterNamespace, enablehypershift)
+	clusterUrlMonitorReconciler := clusterurlmonitor.NewReconciler(mgr, reconcilerOptions)
 	if err := clusterUrlMonitorReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "clusterUrlMonitorReconciler")
 		os.Exit(1)
 	}
 
-	enableHCP, err := shouldEnableHCP(mgr)
-	if err != nil {
-		setupLog.Error(err, "failed to determine whether HCP controller should be enabled", "controller", "HostedControlPlane")
+	urlMonitorReconciler := urlmonitor.NewReconciler(mgr, reconcilerOptions)
+	if err := urlMonitorReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "UrlMonitor")
+		os.Exit(1)
+	}
+
+	namespaceMonitorReconciler := namespacemonitor.NewReconciler(mgr, reconcilerOptions)
+	if err := namespaceMonitorReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceMonitor")
+		os.Exit(1)
+	}
+
+	certificateMonitorReconciler := certificatemonitor.NewReconciler(mgr, reconcilerOptions)
+	if err := certificateMonitorReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CertificateMonitor")
+		os.Exit(1)
 	}
-	if enableHCP {
-		hostedControlPlaneReconciler := hostedcontrolplane.NewHostedControlPlaneReconciler(mgr)
+
+	// These controllers create monitors and resources of their own, which can't be dry-run
+	if !dryRun {
+		blackBoxExporterReconciler := blackboxexportercontroller.NewReconciler(mgr, reconcilerOptions)
+		if err := blackBoxExporterReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BlackBoxExporter")
+			os.Exit(1)
+		}
+
+		monitorTemplateReconciler := monitortemplate.NewMonitorTemplateReconciler(mgr)
+		if err := monitorTemplateReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MonitorTemplate")
+			os.Exit(1)
+		}
+
+		routeMonitorSetReconciler := routemonitorset.NewRouteMonitorSetReconciler(mgr)
+		if err := routeMonitorSetReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RouteMonitorSet")
+			os.Exit(1)
+		}
+
+		if consoleDashboard {
+			consoleDashboardReconciler := consoledashboard.NewConsoleDashboardReconciler(mgr)
+			if err := consoleDashboardReconciler.SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "ConsoleDashboard")
+				os.Exit(1)
+			}
+		}
+	}
+
+	if enableWebhooks {
+		if err := (&monitoringv1alpha1.RouteMonitor{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RouteMonitor")
+			os.Exit(1)
+		}
+		if err := (&monitoringv1alpha1.ClusterUrlMonitor{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterUrlMonitor")
+			os.Exit(1)
+		}
+		if err := (&monitoringv1alpha1.UrlMonitor{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "UrlMonitor")
+			os.Exit(1)
+		}
+		if err := (&monitoringv1alpha1.NamespaceMonitor{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespaceMonitor")
+			os.Exit(1)
+		}
+	}
+
+	if enableHCP && !dryRun {
+		hostedControlPlaneReconciler := hostedcontrolplane.NewHostedControlPlaneReconciler(mgr, hcpDedicatedExporters, hcpEndpointMonitors)
 		if err = hostedControlPlaneReconciler.SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "HostedControlPlane")
 			os.Exit(1)
 		}
 	}
 
+	if sweepInterval > 0 {
+		var sweepClient client.Client = mgr.GetClient()
+		if dryRun {
+			sweepClient = reconcileCommon.NewDryRunClient(sweepClient, nil, ctrl.Log.WithName("Sweep").WithName("DryRun"))
+		}
+		sweeper := reconcileCommon.NewOrphanSweeper(sweepClient, mgr.GetAPIReader(), ctrl.Log.WithName("Sweep"), sweepInterval,
+			&monitoringv1.ServiceMonitorList{}, &monitoringv1.ProbeList{}, &monitoringv1.PrometheusRuleList{},
+			&rhobsv1.ServiceMonitorList{}, &scrapeconfig.ScrapeConfigList{})
+		if err := mgr.Add(sweeper); err != nil {
+			setupLog.Error(err, "unable to sweep the orphaned resources")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if maxReconcileDuration > 0 {
+		if err := mgr.AddHealthzCheck("workqueues", health.WorkqueueProgress(ctrlmetrics.Registry, maxReconcileDuration)); err != nil {
+			setupLog.Error(err, "unable to set up health check")
+			os.Exit(1)
+		}
+	}
+	if err := mgr.AddReadyzCheck("informers", health.CacheSynced(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("blackbox-namespace", health.NamespaceReachable(mgr.GetAPIReader(), blackboxExporterNamespace)); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
@@ -155,6 +625,9 @@ func main() {
 		"so this message (or anything that resides after it) won't execute until teardown", nil)
 }
 
+// defaultBlackboxExporterImage is the upstream image of the blackbox-exporter
+const defaultBlackboxExporterImage = "quay.io/prometheus/blackbox-exporter:master"
+
 // shouldEnableHCP checks for the existence of the 'hostedcontrolplane' CRD to determine whether this controller should be enabled or not:
 //   - if it exists, enable the HCP controller
 //   - if we get an error unrelated to it's existence (ie - kubeapiserver is down) return the error
@@ -174,3 +647,23 @@ func shouldEnableHCP(mgr ctrl.Manager) (bool, error) {
 	}
 	return true, nil
 }
+
+// isOpenShift returns whether the cluster is OpenShift, which serves the SecurityContextConstraints API
+func isOpenShift(d discovery.DiscoveryInterface) (bool, error) {
+	_, err := d.ServerResourcesForGroupVersion("security.openshift.io/v1")
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// splitKeys splits a comma separated list of label or annotation keys, or of names
+func splitKeys(list string) []string {
+	keys := []string{}
+	for _, key := range strings.Split(list, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,75 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ProbeScrapeFailureRatio is the ratio of the scrapes of the probes which have to fail, rather than the probes
+// themselves, before it's alerted on
+const ProbeScrapeFailureRatio = "0.1"
+
+// TemplateForBlackBoxExporterPrometheusRule returns the PrometheusRule alerting on the health of the exporter of the
+// namespace: on the exporter being down, on its configuration failing to load and on the scrapes of its probes
+// failing. A dead exporter doesn't probe anymore, which silences the alerts of every monitor, so it pages
+func TemplateForBlackBoxExporterPrometheusRule(namespace string) monitoringv1.PrometheusRule {
+	self := fmt.Sprintf(`job=%q,namespace=%q`, blackboxexporter.BlackBoxExporterHealthName, namespace)
+	// The scrapes of the probes are labeled with the job of the exporter's Service
+	probes := fmt.Sprintf(`job=%q,namespace=%q`, blackboxexporter.BlackBoxExporterName, namespace)
+	return monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxexporter.BlackBoxExporterHealthName,
+			Namespace: namespace,
+			Labels:    blackboxexporter.GenerateBlackBoxExporterLables(),
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: "blackbox-exporter-health",
+					Rules: []monitoringv1.Rule{
+						{
+							Alert:  "BlackBoxExporterDown",
+							Expr:   intstr.FromString("up{" + self + "} == 0 or absent(up{" + self + "})"),
+							Labels: exporterLabels(severities[pageResponse], namespace),
+							Annotations: map[string]string{
+								"message": fmt.Sprintf("The blackbox exporter in %s is down, the urls it probes aren't alerted on", namespace),
+							},
+							For: monitoringv1.Duration("5m"),
+						},
+						{
+							Alert:  "BlackBoxExporterConfigReloadFailed",
+							Expr:   intstr.FromString("blackbox_exporter_config_last_reload_successful{" + self + "} == 0"),
+							Labels: exporterLabels(severities[ticketResponse], namespace),
+							Annotations: map[string]string{
+								"message": fmt.Sprintf("The blackbox exporter in %s failed to load its configuration, the modules of new or changed monitors are missing", namespace),
+							},
+							For: monitoringv1.Duration("10m"),
+						},
+						{
+							Alert: "BlackBoxExporterProbeScrapesFailing",
+							Expr: intstr.FromString("sum(up{" + probes + "} == bool 0) / count(up{" + probes + "}) > " +
+								ProbeScrapeFailureRatio),
+							Labels: exporterLabels(severities[ticketResponse], namespace),
+							Annotations: map[string]string{
+								"message": fmt.Sprintf("{{ $value | humanizePercentage }} of the probes of the blackbox exporter in %s can't be scraped, their urls aren't alerted on", namespace),
+							},
+							For: monitoringv1.Duration("10m"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func exporterLabels(severity, namespace string) map[string]string {
+	return map[string]string{
+		"namespace": namespace,
+		"severity":  severity,
+		"response":  string(responseFor(severity)),
+	}
+}
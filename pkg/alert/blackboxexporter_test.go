@@ -0,0 +1,28 @@
+package alert_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+)
+
+var _ = Describe("TemplateForBlackBoxExporterPrometheusRule", func() {
+	It("pages on a dead exporter and tickets on its configuration and scrapes", func() {
+		template := alert.TemplateForBlackBoxExporterPrometheusRule("fake-namespace")
+		Expect(template.Name).To(Equal("blackbox-exporter-health"))
+		Expect(template.Namespace).To(Equal("fake-namespace"))
+
+		rules := template.Spec.Groups[0].Rules
+		Expect(rules).To(HaveLen(3))
+		Expect(rules[0].Alert).To(Equal("BlackBoxExporterDown"))
+		Expect(rules[0].Expr.String()).To(Equal(`up{job="blackbox-exporter-health",namespace="fake-namespace"} == 0 or absent(up{job="blackbox-exporter-health",namespace="fake-namespace"})`))
+		Expect(rules[0].Labels).To(HaveKeyWithValue("severity", "critical"))
+		Expect(rules[0].Labels).To(HaveKeyWithValue("response", "page"))
+		Expect(rules[1].Alert).To(Equal("BlackBoxExporterConfigReloadFailed"))
+		Expect(rules[1].Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(rules[2].Alert).To(Equal("BlackBoxExporterProbeScrapesFailing"))
+		Expect(rules[2].Expr.String()).To(Equal(`sum(up{job="blackbox-exporter",namespace="fake-namespace"} == bool 0) / count(up{job="blackbox-exporter",namespace="fake-namespace"}) > 0.1`))
+		Expect(rules[2].Labels).To(HaveKeyWithValue("namespace", "fake-namespace"))
+	})
+})
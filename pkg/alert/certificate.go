@@ -0,0 +1,104 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// CertificateRuleOptions describe the certificate a PrometheusRule alerts on
+type CertificateRuleOptions struct {
+	// Target is the probed host:port
+	Target string
+	// WarningDays and CriticalDays are how many days before the expiry the alerts of the severities fire
+	WarningDays  int
+	CriticalDays int
+	// Issuer, if set, is a regular expression the issuer of the certificate has to match
+	Issuer string
+
+	NamespacedName types.NamespacedName
+	AlertLabels    map[string]string
+	// Owner, if set, controls the PrometheusRule, so it's garbage collected with the monitor
+	Owner *metav1.OwnerReference
+}
+
+// certificateProbeFailedDuration is how long the handshakes with a target have to fail before it's alerted on,
+// so a single restart of the target doesn't
+const certificateProbeFailedDuration = "10m"
+
+// TemplateForCertificatePrometheusRule returns the PrometheusRule alerting on the certificate of a target: on its
+// expiry in two tiers, on failed handshakes, which also cover broken chains and server names missing from the
+// SANs, and on unexpected issuers
+func TemplateForCertificatePrometheusRule(opts CertificateRuleOptions) monitoringv1.PrometheusRule {
+	target := urlTarget(opts.Target)
+	rules := []monitoringv1.Rule{
+		expiryRule(target, opts.WarningDays, severities[ticketResponse], opts.NamespacedName),
+		expiryRule(target, opts.CriticalDays, severities[pageResponse], opts.NamespacedName),
+		{
+			Alert:  opts.NamespacedName.Name + "-CertificateProbeFailed",
+			Expr:   intstr.FromString("probe_success{" + target.selector + "} == 0"),
+			Labels: certificateLabels(target, severities[ticketResponse], opts.NamespacedName.Namespace),
+			Annotations: map[string]string{
+				"message": fmt.Sprintf("TLS handshakes with %s fail: its certificate chain is invalid, doesn't cover the server name or it's unreachable", target.name),
+			},
+			For: monitoringv1.Duration(certificateProbeFailedDuration),
+		},
+	}
+	if opts.Issuer != "" {
+		rules = append(rules, monitoringv1.Rule{
+			Alert: opts.NamespacedName.Name + "-CertificateIssuerMismatch",
+			// PromQL strings are escaped like Go's, so the backslashes of the pattern are doubled
+			Expr:   intstr.FromString("probe_ssl_last_chain_info{" + target.selector + ",issuer!~" + strconv.Quote(opts.Issuer) + "}"),
+			Labels: certificateLabels(target, severities[ticketResponse], opts.NamespacedName.Namespace),
+			Annotations: map[string]string{
+				"message": fmt.Sprintf("The certificate of %s is issued by {{ $labels.issuer }}, expected an issuer matching %s", target.name, opts.Issuer),
+			},
+		})
+	}
+
+	template := monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamespacedName.Name,
+			Namespace: opts.NamespacedName.Namespace,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:  "certificate-probe",
+					Rules: rules,
+				},
+			},
+		},
+	}
+	if opts.Owner != nil {
+		template.OwnerReferences = []metav1.OwnerReference{*opts.Owner}
+	}
+	AddAlertLabels(&template, opts.AlertLabels)
+	return template
+}
+
+// expiryRule alerts once the first certificate of the chain expires within the given days
+func expiryRule(target probeTarget, days int, severity string, namespacedName types.NamespacedName) monitoringv1.Rule {
+	return monitoringv1.Rule{
+		Alert:  namespacedName.Name + "-CertificateExpiring",
+		Expr:   intstr.FromString("(probe_ssl_earliest_cert_expiry{" + target.selector + "} - time()) / 86400 < " + strconv.Itoa(days)),
+		Labels: certificateLabels(target, severity, namespacedName.Namespace),
+		Annotations: map[string]string{
+			"message": fmt.Sprintf("The certificate of %s expires in {{ $value | humanize }} days", target.name),
+		},
+		For: monitoringv1.Duration("15m"),
+	}
+}
+
+func certificateLabels(target probeTarget, severity, namespace string) map[string]string {
+	return labels.Merge(target.labels, map[string]string{
+		"namespace": namespace,
+		"severity":  severity,
+		"response":  string(responseFor(severity)),
+	})
+}
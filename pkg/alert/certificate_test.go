@@ -0,0 +1,62 @@
+package alert_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+)
+
+var _ = Describe("TemplateForCertificatePrometheusRule", func() {
+	var (
+		opts  alert.CertificateRuleOptions
+		rules []monitoringv1.Rule
+	)
+	BeforeEach(func() {
+		opts = alert.CertificateRuleOptions{
+			Target:         "db.example.com:5432",
+			WarningDays:    30,
+			CriticalDays:   7,
+			NamespacedName: types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"},
+			AlertLabels:    map[string]string{"team": "db", "severity": "none"},
+		}
+	})
+	JustBeforeEach(func() {
+		template := alert.TemplateForCertificatePrometheusRule(opts)
+		Expect(template.Name).To(Equal("fake-name"))
+		Expect(template.Namespace).To(Equal("fake-namespace"))
+		rules = template.Spec.Groups[0].Rules
+	})
+	It("alerts on the expiry in two tiers and on failed handshakes", func() {
+		Expect(rules).To(HaveLen(3))
+		Expect(rules[0].Alert).To(Equal("fake-name-CertificateExpiring"))
+		Expect(rules[0].Expr.String()).To(Equal(`(probe_ssl_earliest_cert_expiry{probe_url="db.example.com:5432"} - time()) / 86400 < 30`))
+		Expect(rules[0].Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(rules[0].Labels).To(HaveKeyWithValue("response", "ticket"))
+		Expect(rules[1].Alert).To(Equal("fake-name-CertificateExpiring"))
+		Expect(rules[1].Expr.String()).To(HaveSuffix("< 7"))
+		Expect(rules[1].Labels).To(HaveKeyWithValue("severity", "critical"))
+		Expect(rules[1].Labels).To(HaveKeyWithValue("response", "page"))
+		Expect(rules[2].Alert).To(Equal("fake-name-CertificateProbeFailed"))
+		Expect(rules[2].Expr.String()).To(Equal(`probe_success{probe_url="db.example.com:5432"} == 0`))
+		for _, rule := range rules {
+			Expect(rule.Labels).To(HaveKeyWithValue("probe_url", "db.example.com:5432"))
+			Expect(rule.Labels).To(HaveKeyWithValue("namespace", "fake-namespace"))
+			Expect(rule.Labels).To(HaveKeyWithValue("team", "db"))
+			Expect(rule.Labels["severity"]).NotTo(Equal("none"))
+		}
+	})
+	When("an issuer is expected", func() {
+		BeforeEach(func() {
+			opts.Issuer = `CN=R\d+,O=Let's Encrypt`
+		})
+		It("alerts on certificates of other issuers", func() {
+			Expect(rules).To(HaveLen(4))
+			Expect(rules[3].Alert).To(Equal("fake-name-CertificateIssuerMismatch"))
+			Expect(rules[3].Expr.String()).To(Equal(`probe_ssl_last_chain_info{probe_url="db.example.com:5432",issuer!~"CN=R\\d+,O=Let's Encrypt"}`))
+		})
+	})
+})
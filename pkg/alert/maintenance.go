@@ -0,0 +1,229 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	prometheus "github.com/prometheus/common/model"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// cronField is a field of a cron expression, evaluated with the PromQL function returning the
+// corresponding part of the evaluation time
+type cronField struct {
+	name     string
+	function string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", "minute()", 0, 59},
+	{"hour", "hour()", 0, 23},
+	{"day of month", "day_of_month()", 1, 31},
+	{"month", "month()", 1, 12},
+	// 7 is accepted for Sunday, as by most cron implementations
+	{"day of week", "day_of_week()", 0, 7},
+}
+
+// ApplyMaintenanceWindows keeps every alert of the PrometheusRule from firing during the maintenance
+// windows. The guard is part of the expressions, so it holds without the operator reconciling on time
+func ApplyMaintenanceWindows(template *monitoringv1.PrometheusRule, windows []v1alpha1.MaintenanceWindow) error {
+	if len(windows) == 0 {
+		return nil
+	}
+	guard, err := maintenanceGuard(windows)
+	if err != nil {
+		return customerrors.Validation("maintenanceWindows", err)
+	}
+	suppressAlerts(template, guard)
+	return nil
+}
+
+// ApplyGracePeriod keeps every alert of the PrometheusRule from firing for the grace period after the monitor
+// was created, while its target warms up and the probes build a baseline
+func ApplyGracePeriod(template *monitoringv1.PrometheusRule, created time.Time, gracePeriod string) error {
+	if gracePeriod == "" {
+		return nil
+	}
+	duration, err := prometheus.ParseDuration(gracePeriod)
+	if err != nil {
+		return customerrors.Validation("slo.gracePeriod", fmt.Errorf("invalid grace period '%s': %w", gracePeriod, err))
+	}
+	suppressAlerts(template, fmt.Sprintf("vector(time()) < %d", created.Add(time.Duration(duration)).Unix()))
+	return nil
+}
+
+// suppressAlerts keeps the alerts of the PrometheusRule from firing while the guard has a result
+func suppressAlerts(template *monitoringv1.PrometheusRule, guard string) {
+	for i := range template.Spec.Groups {
+		rules := template.Spec.Groups[i].Rules
+		for j := range rules {
+			if rules[j].Alert == "" {
+				continue
+			}
+			rules[j].Expr = intstr.FromString(fmt.Sprintf("(%s)\nunless on() (%s)", rules[j].Expr.String(), guard))
+		}
+	}
+}
+
+// maintenanceGuard returns an expression that has a result while any of the windows is open
+func maintenanceGuard(windows []v1alpha1.MaintenanceWindow) (string, error) {
+	guards := []string{}
+	for i, window := range windows {
+		guard, err := windowGuard(window)
+		if err != nil {
+			return "", fmt.Errorf("invalid maintenance window %d: %w", i, err)
+		}
+		guards = append(guards, guard)
+	}
+	return strings.Join(guards, " or "), nil
+}
+
+// windowGuard returns an expression that has a result while the window is open
+func windowGuard(window v1alpha1.MaintenanceWindow) (string, error) {
+	recurring := window.Schedule != "" || window.Duration != ""
+	oneOff := window.Start != nil || window.End != nil
+	switch {
+	case recurring && oneOff:
+		return "", fmt.Errorf("either a schedule and duration or a start and end can be set")
+	case oneOff:
+		if window.Start == nil || window.End == nil || !window.End.After(window.Start.Time) {
+			return "", fmt.Errorf("a one-off window requires a start before its end")
+		}
+		return fmt.Sprintf("vector(time()) >= %d and vector(time()) < %d", window.Start.Unix(), window.End.Unix()), nil
+	case recurring:
+		duration, err := prometheus.ParseDuration(window.Duration)
+		if err != nil || duration <= 0 {
+			return "", fmt.Errorf("a recurring window requires a duration")
+		}
+		schedule, err := cronSchedule(window.Schedule)
+		if err != nil {
+			return "", err
+		}
+		// A window is open while its schedule matched at any minute since the duration
+		return fmt.Sprintf("max_over_time((%s)[%s:1m])", schedule, duration), nil
+	default:
+		return "", fmt.Errorf("either a schedule and duration or a start and end are required")
+	}
+}
+
+// cronSchedule returns an expression that has a result at the minutes matching the cron expression
+func cronSchedule(schedule string) (string, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return "", fmt.Errorf("schedule '%s' must consist of %d fields", schedule, len(cronFields))
+	}
+	conditions := make([]string, len(fields))
+	for i, field := range fields {
+		condition, err := cronFields[i].condition(field)
+		if err != nil {
+			return "", fmt.Errorf("schedule '%s': %w", schedule, err)
+		}
+		conditions[i] = condition
+	}
+	dayOfMonth, dayOfWeek := conditions[2], conditions[4]
+	// As in cron, a day matches either restriction if both the day of month and of week are restricted
+	days := dayOfMonth + dayOfWeek
+	if dayOfMonth != "" && dayOfWeek != "" {
+		days = fmt.Sprintf("(%s or %s)", dayOfMonth, dayOfWeek)
+	}
+
+	matches := []string{"vector(1)"}
+	for _, condition := range []string{conditions[0], conditions[1], days, conditions[3]} {
+		if condition != "" {
+			matches = append(matches, condition)
+		}
+	}
+	return strings.Join(matches, " and on() "), nil
+}
+
+// condition returns the expression matching the values of the field, or nothing if it matches all of them
+func (f cronField) condition(field string) (string, error) {
+	matched := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		if err := f.match(part, matched); err != nil {
+			return "", err
+		}
+	}
+	last := f.max
+	if f.function == "day_of_week()" {
+		// Sunday is 0 to PromQL
+		matched[0] = matched[0] || matched[7]
+		last = 6
+	}
+
+	// Consecutive values are matched as a range
+	alternatives := []string{}
+	all := true
+	for from := f.min; from <= last; from++ {
+		if !matched[from] {
+			all = false
+			continue
+		}
+		to := from
+		for to < last && matched[to+1] {
+			to++
+		}
+		if from == to {
+			alternatives = append(alternatives, fmt.Sprintf("%s == %d", f.function, from))
+		} else {
+			alternatives = append(alternatives, fmt.Sprintf("%s >= %d <= %d", f.function, from, to))
+		}
+		from = to
+	}
+	if all {
+		return "", nil
+	}
+	return "(" + strings.Join(alternatives, " or ") + ")", nil
+}
+
+// match marks the values matched by a single value, range or step of the field
+func (f cronField) match(part string, matched map[int]bool) error {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+			return fmt.Errorf("invalid step '%s' of the %s", stepPart, f.name)
+		}
+	}
+
+	from, to := f.min, f.max
+	if rangePart != "*" {
+		lower, upper, isRange := strings.Cut(rangePart, "-")
+		var err error
+		if from, err = f.value(lower); err != nil {
+			return err
+		}
+		to = from
+		if isRange {
+			if to, err = f.value(upper); err != nil {
+				return err
+			}
+		} else if hasStep {
+			to = f.max
+		}
+		if to < from {
+			return fmt.Errorf("invalid range '%s' of the %s", rangePart, f.name)
+		}
+	}
+	for value := from; value <= to; value += step {
+		matched[value] = true
+	}
+	return nil
+}
+
+// value parses a single value of the field
+func (f cronField) value(raw string) (int, error) {
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s '%s', it must be between %d and %d", f.name, raw, f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package alert_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+)
+
+var _ = Describe("ApplyMaintenanceWindows", func() {
+	var (
+		template monitoringv1.PrometheusRule
+		original monitoringv1.PrometheusRule
+	)
+	BeforeEach(func() {
+		template = alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		original = *template.DeepCopy()
+	})
+
+	It("keeps the rules without maintenance windows", func() {
+		Expect(alert.ApplyMaintenanceWindows(&template, nil)).To(Succeed())
+		Expect(template).To(Equal(original))
+	})
+
+	It("suppresses every alert during a one-off window", func() {
+		start := metav1.NewTime(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC))
+		end := metav1.NewTime(time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC))
+		Expect(alert.ApplyMaintenanceWindows(&template, []v1alpha1.MaintenanceWindow{{Start: &start, End: &end}})).To(Succeed())
+		for i, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Expr.String()).To(HavePrefix("(" + original.Spec.Groups[0].Rules[i].Expr.String() + ")\nunless on() "))
+			Expect(rule.Expr.String()).To(HaveSuffix("(vector(time()) >= 1709330400 and vector(time()) < 1709344800)"))
+		}
+	})
+
+	It("suppresses every alert for the duration of a recurring window", func() {
+		Expect(alert.ApplyMaintenanceWindows(&template, []v1alpha1.MaintenanceWindow{{Schedule: "0,30 2 * * 7", Duration: "2h"}})).To(Succeed())
+		Expect(template.Spec.Groups[0].Rules[0].Expr.String()).To(HaveSuffix(
+			"unless on() (max_over_time((vector(1) and on() (minute() == 0 or minute() == 30) and on() (hour() == 2) and on() (day_of_week() == 0))[2h:1m]))"))
+	})
+
+	It("matches either day restriction as cron does", func() {
+		Expect(alert.ApplyMaintenanceWindows(&template, []v1alpha1.MaintenanceWindow{{Schedule: "*/20 * 1-7 * 1-5", Duration: "1h"}})).To(Succeed())
+		Expect(template.Spec.Groups[0].Rules[0].Expr.String()).To(HaveSuffix(
+			"unless on() (max_over_time((vector(1) and on() (minute() == 0 or minute() == 20 or minute() == 40) and on() ((day_of_month() >= 1 <= 7) or (day_of_week() >= 1 <= 5)))[1h:1m]))"))
+	})
+
+	It("rejects invalid windows", func() {
+		start := metav1.NewTime(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC))
+		for _, window := range []v1alpha1.MaintenanceWindow{
+			{},
+			{Schedule: "0 2 * * *"},
+			{Schedule: "0 2 * *", Duration: "1h"},
+			{Schedule: "0 24 * * *", Duration: "1h"},
+			{Schedule: "5-1 2 * * *", Duration: "1h"},
+			{Schedule: "0 2 * * *", Duration: "1h", Start: &start},
+			{Start: &start, End: &start},
+		} {
+			Expect(alert.ApplyMaintenanceWindows(&template, []v1alpha1.MaintenanceWindow{window})).NotTo(Succeed())
+		}
+		Expect(template).To(Equal(original))
+	})
+})
+
+var _ = Describe("ApplyGracePeriod", func() {
+	var (
+		template monitoringv1.PrometheusRule
+		original monitoringv1.PrometheusRule
+		created  = time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	)
+	BeforeEach(func() {
+		template = alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		original = *template.DeepCopy()
+	})
+
+	It("keeps the rules without a grace period", func() {
+		Expect(alert.ApplyGracePeriod(&template, created, "")).To(Succeed())
+		Expect(template).To(Equal(original))
+	})
+
+	It("suppresses every alert until the grace period after the creation passed", func() {
+		Expect(alert.ApplyGracePeriod(&template, created, "30m")).To(Succeed())
+		for _, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Expr.String()).To(HaveSuffix("\nunless on() (vector(time()) < 1709332200)"))
+		}
+	})
+
+	It("rejects an invalid grace period", func() {
+		Expect(alert.ApplyGracePeriod(&template, created, "soon")).NotTo(Succeed())
+		Expect(template).To(Equal(original))
+	})
+})
@@ -3,7 +3,10 @@ package alert
 import (
 	"context"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	prometheus "github.com/prometheus/common/model"
@@ -14,6 +17,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -24,6 +28,10 @@ type PrometheusRule struct {
 	Client   client.Client
 	Ctx      context.Context
 	Comparer util.ResourceComparerInterface
+	// Scrubber removes labels and annotations the PrometheusRules mustn't carry
+	Scrubber util.MetadataScrubber
+	// FieldOwner, if set, applies the PrometheusRules server-side with it as the field manager
+	FieldOwner string
 }
 
 func NewPrometheusRule(ctx context.Context, c client.Client) *PrometheusRule {
@@ -36,22 +44,16 @@ func NewPrometheusRule(ctx context.Context, c client.Client) *PrometheusRule {
 
 // Creates or Updates PrometheusRule Deployment according to the template
 func (u *PrometheusRule) UpdatePrometheusRuleDeployment(template monitoringv1.PrometheusRule) error {
-	namespacedName := types.NamespacedName{Name: template.Name, Namespace: template.Namespace}
-	deployedPrometheusRule := &monitoringv1.PrometheusRule{}
-	err := u.Client.Get(u.Ctx, namespacedName, deployedPrometheusRule)
-	if err != nil {
-		// No similar Prometheus Rule exists
-		if !k8serrors.IsNotFound(err) {
-			return err
-		}
-		return u.Client.Create(u.Ctx, &template)
-	}
-	if !u.Comparer.DeepEqual(template.Spec, deployedPrometheusRule.Spec) {
-		// Update existing PrometheuesRule for the case that the template changed
-		deployedPrometheusRule.Spec = template.Spec
-		return u.Client.Update(u.Ctx, deployedPrometheusRule)
-	}
-	return nil
+	_, err := util.EnsureResource(u.Ctx, util.EnsureOptions[*monitoringv1.PrometheusRule]{
+		Client:   u.Client,
+		Scrubber: u.Scrubber,
+		SpecEqual: func(deployed, template *monitoringv1.PrometheusRule) bool {
+			return u.Comparer.DeepEqual(template.Spec, deployed.Spec)
+		},
+		CopySpec:   func(deployed, template *monitoringv1.PrometheusRule) { deployed.Spec = template.Spec },
+		FieldOwner: u.FieldOwner,
+	}, &template)
+	return err
 }
 
 func (u *PrometheusRule) DeletePrometheusRuleDeployment(prometheusRuleRef v1alpha1.NamespacedName) error {
@@ -75,17 +77,117 @@ func (u *PrometheusRule) DeletePrometheusRuleDeployment(prometheusRuleRef v1alph
 }
 
 type multiWindowMultiBurnAlertRule struct {
+	tier        burnTier
 	duration    string
 	severity    string
+	response    alertResponse
 	longWindow  string
 	shortWindow string
 	burnRate    string
 }
 
-func alertThreshold(windowSize, percent, label, burnRate string) string {
+// SloPeriod is the period error budgets are spent over
+const SloPeriod = 30 * 24 * time.Hour
+
+// alertResponse is how a budget burn has to be responded to
+type alertResponse string
+
+const (
+	pageResponse   alertResponse = "page"
+	ticketResponse alertResponse = "ticket"
+)
+
+// severities maps responses to the severities alerts are routed by
+var severities = map[alertResponse]string{
+	pageResponse:   "critical",
+	ticketResponse: "warning",
+}
+
+// responseFor returns how alerts of the severity have to be responded to
+func responseFor(severity string) alertResponse {
+	if severity == severities[pageResponse] {
+		return pageResponse
+	}
+	return ticketResponse
+}
+
+// burnTier tells apart the burn rate alerts, so their severities can be overridden
+type burnTier string
+
+const (
+	fastBurn   burnTier = "fast"
+	mediumBurn burnTier = "medium"
+	slowBurn   burnTier = "slow"
+)
+
+// severityIn returns the severity the tier is overridden with, empty if it isn't
+func (t burnTier) severityIn(overrides v1alpha1.AlertSeveritySpec) string {
+	switch t {
+	case fastBurn:
+		return overrides.FastBurn
+	case mediumBurn:
+		return overrides.MediumBurn
+	case slowBurn:
+		return overrides.SlowBurn
+	}
+	return ""
+}
+
+// budgetBurn alerts when budgetConsumed of the error budget is spent within longWindow
+type budgetBurn struct {
+	tier           burnTier
+	budgetConsumed float64
+	longWindow     time.Duration
+	shortWindow    time.Duration
+	duration       string
+	response       alertResponse
+}
+
+// alertRule derives the burn rate alerted on from the share of the budget consumed within the long window
+func (b budgetBurn) alertRule() multiWindowMultiBurnAlertRule {
+	burnRate := b.budgetConsumed * SloPeriod.Hours() / b.longWindow.Hours()
+	return multiWindowMultiBurnAlertRule{
+		tier:        b.tier,
+		duration:    b.duration,
+		severity:    severities[b.response],
+		response:    b.response,
+		longWindow:  prometheus.Duration(b.longWindow).String(),
+		shortWindow: prometheus.Duration(b.shortWindow).String(),
+		burnRate:    strconv.FormatFloat(math.Round(burnRate*100)/100, 'f', -1, 64),
+	}
+}
+
+// QueryAnnotation holds the query of the error ratio an alert is based on, ready to be run in the console
+const QueryAnnotation = "query"
+
+// errorRatio is the share of failed probes within the window. If client errors don't fail, probes answered
+// with a 4xx status code are counted as successful, which requires evaluating probes one by one
+func errorRatio(windowSize, label string, clientErrorsFail bool) string {
+	if !clientErrorsFail {
+		success := "clamp_max(probe_success{" + label + "} + " +
+			"(probe_http_status_code{" + label + "} >= bool 400) * (probe_http_status_code{" + label + "} < bool 500), 1)"
+		return "1-(sum(sum_over_time((" + success + ")[" + windowSize + ":" + servicemonitor.ServiceMonitorPeriod + "]))" +
+			"/ sum(count_over_time(probe_success{" + label + "}[" + windowSize + "])))"
+	}
+	return "1-(sum(sum_over_time(probe_success{" + label + "}[" + windowSize + "]))" +
+		"/ sum(count_over_time(probe_success{" + label + "}[" + windowSize + "])))"
+}
+
+// AvailabilityQuery returns the query of the share of successful probes of the urls over the SLO period, the
+// complement of the error ratio the alerts are based on
+func AvailabilityQuery(urls []string, clientErrorsFail bool) string {
+	return "1-(" + errorRatio(prometheus.Duration(SloPeriod).String(), ProbeSelector(urls), clientErrorsFail) + ")"
+}
+
+// LastSuccessQuery returns the query of the unix time of the last successful probe of the urls within the SLO period
+func LastSuccessQuery(urls []string) string {
+	return "max(max_over_time(timestamp(probe_success{" + ProbeSelector(urls) + "} == 1)" +
+		"[" + prometheus.Duration(SloPeriod).String() + ":" + servicemonitor.ServiceMonitorPeriod + "]))"
+}
+
+func alertThreshold(windowSize, percent, label, burnRate string, clientErrorsFail bool) string {
 
-	rule := "1-(sum(sum_over_time(probe_success{" + label + "}[" + windowSize + "]))" +
-		"/ sum(count_over_time(probe_success{" + label + "}[" + windowSize + "])))" +
+	rule := errorRatio(windowSize, label, clientErrorsFail) +
 		"> (" + burnRate + "*(1-" + percent + "))"
 
 	return rule
@@ -104,77 +206,163 @@ func sufficientProbes(windowSize, label string) string {
 	return rule
 }
 
+// probeTarget selects the probes an alert is evaluated on
+type probeTarget struct {
+	// selector matches the series of the probes
+	selector string
+	// name names the target in the alert messages
+	name string
+	// labels identify the target on the alerts
+	labels map[string]string
+}
+
+// urlTarget alerts on the probes of a single url
+func urlTarget(url string) probeTarget {
+	return probeTarget{
+		selector: fmt.Sprintf(`%s="%s"`, servicemonitor.UrlLabelName, url),
+		name:     url,
+		labels:   map[string]string{servicemonitor.UrlLabelName: url},
+	}
+}
+
+// aggregateTarget alerts on the probes of all urls as one: their failed probes share the error budget
+func aggregateTarget(name string, urls []string) probeTarget {
+	patterns := make([]string, 0, len(urls))
+	for _, url := range urls {
+		patterns = append(patterns, regexp.QuoteMeta(url))
+	}
+	return probeTarget{
+		// PromQL strings are escaped like Go's, so the backslashes of the pattern are doubled
+		selector: servicemonitor.UrlLabelName + "=~" + strconv.Quote(strings.Join(patterns, "|")),
+		name:     name,
+	}
+}
+
+// targetFor selects the probes of the urls, aggregated if there are several
+func targetFor(name string, urls []string) probeTarget {
+	if len(urls) == 1 {
+		return urlTarget(urls[0])
+	}
+	return aggregateTarget(name, urls)
+}
+
+// ProbeSelector returns the label selector matching the probes of the urls
+func ProbeSelector(urls []string) string {
+	return targetFor("", urls).selector
+}
+
+// ErrorBudgetRemaining returns the query of the share of the error budget of the urls left within the window,
+// where percent is the availability target as a ratio. Failed probes are counted like by the burn rate alerts
+func ErrorBudgetRemaining(urls []string, percent string, clientErrorsFail bool, windowSize string) string {
+	return "1-((" + errorRatio(windowSize, ProbeSelector(urls), clientErrorsFail) + ")/(1-" + percent + "))"
+}
+
 // render creates a monitoring rule for the defined multiwindow multi-burn rate alert
-func (r *multiWindowMultiBurnAlertRule) render(url string, percent string, namespacedName types.NamespacedName) monitoringv1.Rule {
-	labelSelector := fmt.Sprintf(`%s="%s"`, servicemonitor.UrlLabelName, url)
+func (r *multiWindowMultiBurnAlertRule) render(target probeTarget, percent string, clientErrorsFail bool, namespacedName types.NamespacedName) monitoringv1.Rule {
+	labelSelector := target.selector
 
 	alertString := "" +
-		alertThreshold(r.shortWindow, percent, labelSelector, r.burnRate) +
+		alertThreshold(r.shortWindow, percent, labelSelector, r.burnRate, clientErrorsFail) +
 		" and " +
 		sufficientProbes(r.shortWindow, labelSelector) +
 		"\nand\n" +
-		alertThreshold(r.longWindow, percent, labelSelector, r.burnRate) +
+		alertThreshold(r.longWindow, percent, labelSelector, r.burnRate, clientErrorsFail) +
 		" and " +
 		sufficientProbes(r.longWindow, labelSelector)
 
 	return monitoringv1.Rule{
 		Alert:  namespacedName.Name + "-ErrorBudgetBurn",
 		Expr:   intstr.FromString(alertString),
-		Labels: r.renderLabels(url, namespacedName.Namespace),
+		Labels: r.renderLabels(target, namespacedName.Namespace),
 		Annotations: map[string]string{
-			"message": fmt.Sprintf("High error budget burn for %s (current value: {{ $value }})", url),
+			"message":       fmt.Sprintf("High error budget burn for %s (current value: {{ $value }})", target.name),
+			QueryAnnotation: errorRatio(r.longWindow, labelSelector, clientErrorsFail),
 		},
 		For: monitoringv1.Duration(r.duration),
 	}
 }
 
-func (r *multiWindowMultiBurnAlertRule) renderLabels(url, namespace string) map[string]string {
-	return map[string]string{
-		servicemonitor.UrlLabelName: url,
-		"namespace":                 namespace,
-		"severity":                  r.severity,
-		"long_window":               r.longWindow,
-		"short_window":              r.shortWindow,
-	}
+// latencyThreshold is the burn rate condition of a latency objective: the share of probes taking longer
+// than threshold seconds. Probes are evaluated one by one, so the subquery steps at the probe period
+func latencyThreshold(windowSize, threshold, percent, label, burnRate string) string {
+
+	rule := latencyRatio(windowSize, threshold, label) +
+		"> (" + burnRate + "*(1-" + percent + "))"
+
+	return rule
 }
 
-// TemplateForPrometheusRuleResource returns a PrometheusRule
-func TemplateForPrometheusRuleResource(url, percent string, namespacedName types.NamespacedName) monitoringv1.PrometheusRule {
+// latencyRatio is the share of probes within the window taking longer than threshold seconds
+func latencyRatio(windowSize, threshold, label string) string {
+	return "1-(sum(sum_over_time((probe_duration_seconds{" + label + "} <= bool " + threshold + ")[" + windowSize + ":" + servicemonitor.ServiceMonitorPeriod + "]))" +
+		"/ sum(count_over_time(probe_duration_seconds{" + label + "}[" + windowSize + "])))"
+}
 
-	rules := []monitoringv1.Rule{}
-	alertRules := []multiWindowMultiBurnAlertRule{
-		{
-			duration:    "2m",
-			severity:    "critical",
-			longWindow:  "1h",
-			shortWindow: "5m",
-			burnRate:    "14.40",
-		},
-		{
-			duration:    "15m",
-			severity:    "critical",
-			longWindow:  "6h",
-			shortWindow: "30m",
-			burnRate:    "6",
-		},
-		{
-			duration:    "1h",
-			severity:    "warning",
-			longWindow:  "1d",
-			shortWindow: "2h",
-			burnRate:    "3",
-		},
-		{
-			duration:    "3h",
-			severity:    "warning",
-			longWindow:  "3d",
-			shortWindow: "6h",
-			burnRate:    "1",
+// renderLatency creates a monitoring rule for the defined multiwindow multi-burn rate alert on a latency objective
+func (r *multiWindowMultiBurnAlertRule) renderLatency(target probeTarget, threshold, percent string, namespacedName types.NamespacedName) monitoringv1.Rule {
+	labelSelector := target.selector
+
+	alertString := "" +
+		latencyThreshold(r.shortWindow, threshold, percent, labelSelector, r.burnRate) +
+		" and " +
+		sufficientProbes(r.shortWindow, labelSelector) +
+		"\nand\n" +
+		latencyThreshold(r.longWindow, threshold, percent, labelSelector, r.burnRate) +
+		" and " +
+		sufficientProbes(r.longWindow, labelSelector)
+
+	return monitoringv1.Rule{
+		Alert:  namespacedName.Name + "-LatencyBudgetBurn",
+		Expr:   intstr.FromString(alertString),
+		Labels: r.renderLabels(target, namespacedName.Namespace),
+		Annotations: map[string]string{
+			"message":       fmt.Sprintf("High latency budget burn for %s, probes slower than %ss (current value: {{ $value }})", target.name, threshold),
+			QueryAnnotation: latencyRatio(r.longWindow, threshold, labelSelector),
 		},
+		For: monitoringv1.Duration(r.duration),
+	}
+}
+
+func (r *multiWindowMultiBurnAlertRule) renderLabels(target probeTarget, namespace string) map[string]string {
+	return labels.Merge(target.labels, map[string]string{
+		"namespace":    namespace,
+		"severity":     r.severity,
+		"response":     string(r.response),
+		"long_window":  r.longWindow,
+		"short_window": r.shortWindow,
+	})
+}
+
+// burnRateAlertRules returns the windows and burn rates error budgets are alerted on, following
+// https://sre.google/workbook/alerting-on-slos/#6-multiwindow-multi-burn-rate-alerts
+func burnRateAlertRules() []multiWindowMultiBurnAlertRule {
+	budgetBurns := []budgetBurn{
+		{tier: fastBurn, budgetConsumed: 0.02, longWindow: time.Hour, shortWindow: 5 * time.Minute, duration: "2m", response: pageResponse},
+		{tier: mediumBurn, budgetConsumed: 0.05, longWindow: 6 * time.Hour, shortWindow: 30 * time.Minute, duration: "15m", response: pageResponse},
+		// Kept besides the recommended set, so the alerts existing monitors are routed on don't change
+		{tier: slowBurn, budgetConsumed: 0.10, longWindow: 24 * time.Hour, shortWindow: 2 * time.Hour, duration: "1h", response: ticketResponse},
+		{tier: slowBurn, budgetConsumed: 0.10, longWindow: 3 * 24 * time.Hour, shortWindow: 6 * time.Hour, duration: "3h", response: ticketResponse},
 	}
+	rules := []multiWindowMultiBurnAlertRule{}
+	for _, budgetBurn := range budgetBurns {
+		rules = append(rules, budgetBurn.alertRule())
+	}
+	return rules
+}
+
+// TemplateForPrometheusRuleResource returns a PrometheusRule. If clientErrorsFail is false, probes answered
+// with a 4xx status code don't burn the error budget
+func TemplateForPrometheusRuleResource(url, percent string, clientErrorsFail bool, namespacedName types.NamespacedName) monitoringv1.PrometheusRule {
+	return templateForPrometheusRuleResource(urlTarget(url), percent, clientErrorsFail, namespacedName)
+}
+
+func templateForPrometheusRuleResource(target probeTarget, percent string, clientErrorsFail bool, namespacedName types.NamespacedName) monitoringv1.PrometheusRule {
+
+	rules := []monitoringv1.Rule{}
+	alertRules := burnRateAlertRules()
 
 	for _, alertrule := range alertRules { // Create all the alerts
-		rules = append(rules, alertrule.render(url, percent, namespacedName))
+		rules = append(rules, alertrule.render(target, percent, clientErrorsFail, namespacedName))
 	}
 
 	resource := monitoringv1.PrometheusRule{
@@ -193,3 +381,140 @@ func TemplateForPrometheusRuleResource(url, percent string, namespacedName types
 	}
 	return resource
 }
+
+// AddAlertLabels adds the labels to every alert of the PrometheusRule, without overriding the labels
+// the alerts are already labeled with
+func AddAlertLabels(template *monitoringv1.PrometheusRule, alertLabels map[string]string) {
+	if len(alertLabels) == 0 {
+		return
+	}
+	for i := range template.Spec.Groups {
+		rules := template.Spec.Groups[i].Rules
+		for j := range rules {
+			rules[j].Labels = labels.Merge(alertLabels, rules[j].Labels)
+		}
+	}
+}
+
+// AddAlertAnnotations adds the annotations to every alert of the PrometheusRule, without overriding the
+// annotations the alerts are already annotated with
+func AddAlertAnnotations(template *monitoringv1.PrometheusRule, alertAnnotations map[string]string) {
+	if len(alertAnnotations) == 0 {
+		return
+	}
+	for i := range template.Spec.Groups {
+		rules := template.Spec.Groups[i].Rules
+		for j := range rules {
+			rules[j].Annotations = labels.Merge(alertAnnotations, rules[j].Annotations)
+		}
+	}
+}
+
+// ApplySeverities overrides the severities of the burn rate alerts of the PrometheusRule per tier.
+// Critical alerts page, all others open a ticket
+func ApplySeverities(template *monitoringv1.PrometheusRule, overrides *v1alpha1.AlertSeveritySpec) {
+	if overrides == nil {
+		return
+	}
+	// The tiers are told apart by their long window
+	severityByWindow := map[string]string{}
+	for _, alertRule := range burnRateAlertRules() {
+		if severity := alertRule.tier.severityIn(*overrides); severity != "" {
+			severityByWindow[alertRule.longWindow] = severity
+		}
+	}
+	for i := range template.Spec.Groups {
+		rules := template.Spec.Groups[i].Rules
+		for j := range rules {
+			severity, ok := severityByWindow[rules[j].Labels["long_window"]]
+			if !ok {
+				continue
+			}
+			rules[j].Labels["severity"] = severity
+			rules[j].Labels["response"] = string(responseFor(severity))
+		}
+	}
+}
+
+// LatencyRules returns the burn rate alerts of a latency objective, false is returned if the monitor
+// has none
+func LatencyRules(latency *v1alpha1.LatencySloSpec, url string, namespacedName types.NamespacedName) ([]monitoringv1.Rule, bool) {
+	return latencyRules(latency, urlTarget(url), namespacedName)
+}
+
+func latencyRules(latency *v1alpha1.LatencySloSpec, target probeTarget, namespacedName types.NamespacedName) ([]monitoringv1.Rule, bool) {
+	if latency == nil {
+		return nil, false
+	}
+	isValid, percent := latency.IsValid()
+	if !isValid {
+		return nil, false
+	}
+	threshold := strconv.FormatFloat(float64(latency.ThresholdMilliseconds)/1000, 'f', -1, 64)
+
+	rules := []monitoringv1.Rule{}
+	for _, alertrule := range burnRateAlertRules() {
+		rules = append(rules, alertrule.renderLatency(target, threshold, percent, namespacedName))
+	}
+	return rules, true
+}
+
+// MonitorRuleOptions describe the monitor a PrometheusRule alerts on
+type MonitorRuleOptions struct {
+	// URL is the probed url, Percent the availability target parsed from the Slo
+	URL     string
+	Percent string
+	Slo     v1alpha1.SloSpec
+
+	// URLs, if set, are alerted on as one instead of the URL: the failed probes of all of them burn the
+	// same error budget. URL then only names them in the alert messages and isn't set as a label
+	URLs []string
+
+	NamespacedName     types.NamespacedName
+	Created            time.Time
+	MaintenanceWindows []v1alpha1.MaintenanceWindow
+
+	// LastSloChange is announced by an informational alert for the SloChangeAlertDuration if it lowered the target
+	LastSloChange          *v1alpha1.SloChange
+	SloChangeAlertDuration time.Duration
+
+	// RuleLabels are set on every rule, the alert labels of the Slo don't override them
+	RuleLabels map[string]string
+
+	// Owner, if set, controls the PrometheusRule, so it's garbage collected with the monitor
+	Owner *metav1.OwnerReference
+}
+
+// TemplateForMonitorPrometheusRule returns the PrometheusRule alerting on the SLO of a monitor, with its latency
+// objective, severities, alert labels and annotations, maintenance windows and grace period applied
+func TemplateForMonitorPrometheusRule(opts MonitorRuleOptions) (monitoringv1.PrometheusRule, error) {
+	target := urlTarget(opts.URL)
+	if len(opts.URLs) > 0 {
+		target = aggregateTarget(opts.URL, opts.URLs)
+	}
+	template := templateForPrometheusRuleResource(target, opts.Percent, opts.Slo.ClientErrorsFail(), opts.NamespacedName)
+	if rules, ok := latencyRules(opts.Slo.Latency, target, opts.NamespacedName); ok {
+		template.Spec.Groups[0].Rules = append(template.Spec.Groups[0].Rules, rules...)
+	}
+	if rule, ok := SloChangeRule(opts.LastSloChange, opts.SloChangeAlertDuration, opts.NamespacedName); ok {
+		template.Spec.Groups[0].Rules = append(template.Spec.Groups[0].Rules, rule)
+	}
+	for i := range template.Spec.Groups[0].Rules {
+		for key, value := range opts.RuleLabels {
+			template.Spec.Groups[0].Rules[i].Labels[key] = value
+		}
+	}
+	if opts.Owner != nil {
+		template.OwnerReferences = []metav1.OwnerReference{*opts.Owner}
+	}
+	ApplySeverities(&template, opts.Slo.Severities)
+	AddAlertLabels(&template, opts.Slo.AlertLabels)
+	AddAlertAnnotations(&template, opts.Slo.AlertAnnotations)
+	if err := ApplyMaintenanceWindows(&template, opts.MaintenanceWindows); err != nil {
+		return monitoringv1.PrometheusRule{}, err
+	}
+	if err := ApplyGracePeriod(&template, opts.Created, opts.Slo.GracePeriod); err != nil {
+		return monitoringv1.PrometheusRule{}, err
+	}
+	return template, nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	// tested package
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/pkg/alert"
@@ -200,3 +202,174 @@ var _ = Describe("CR Deployment Handling", func() {
 		})
 	})
 })
+
+var _ = Describe("TemplateForPrometheusRuleResource", func() {
+	It("pages on fast and ticketizes slow error budget burns", func() {
+		template := alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		expected := []struct {
+			longWindow, shortWindow, severity, response, burnRate string
+		}{
+			{"1h", "5m", "critical", "page", "14.4"},
+			{"6h", "30m", "critical", "page", "6"},
+			{"1d", "2h", "warning", "ticket", "3"},
+			{"3d", "6h", "warning", "ticket", "1"},
+		}
+		rules := template.Spec.Groups[0].Rules
+		Expect(rules).To(HaveLen(len(expected)))
+		for i, rule := range rules {
+			Expect(rule.Labels["long_window"]).To(Equal(expected[i].longWindow))
+			Expect(rule.Labels["short_window"]).To(Equal(expected[i].shortWindow))
+			Expect(rule.Labels["severity"]).To(Equal(expected[i].severity))
+			Expect(rule.Labels["response"]).To(Equal(expected[i].response))
+			Expect(rule.Expr.String()).To(ContainSubstring("> (" + expected[i].burnRate + "*(1-0.995))"))
+		}
+	})
+	It("annotates every alert with the query of its long window error ratio", func() {
+		template := alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		for _, rule := range template.Spec.Groups[0].Rules {
+			query := rule.Annotations[alert.QueryAnnotation]
+			Expect(query).To(HavePrefix(`1-(sum(sum_over_time(probe_success{probe_url="https://fake-url"}[` + rule.Labels["long_window"] + "]))"))
+			Expect(rule.Expr.String()).To(ContainSubstring(query))
+		}
+	})
+})
+
+var _ = Describe("TemplateForPrometheusRuleResource client errors", func() {
+	It("counts probes answered with a 4xx status code as failures by default", func() {
+		template := alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		for _, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Expr.String()).NotTo(ContainSubstring("probe_http_status_code"))
+		}
+	})
+	It("counts probes answered with a 4xx status code as successful if client errors don't fail", func() {
+		template := alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", false, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+		for _, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Expr.String()).To(ContainSubstring(`(probe_http_status_code{probe_url="https://fake-url"} >= bool 400) * (probe_http_status_code{probe_url="https://fake-url"} < bool 500)`))
+			Expect(rule.Annotations[alert.QueryAnnotation]).To(ContainSubstring("[" + rule.Labels["long_window"] + ":30s]"))
+		}
+	})
+})
+
+var _ = Describe("AddAlertLabels", func() {
+	var template monitoringv1.PrometheusRule
+	BeforeEach(func() {
+		template = alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+	})
+	It("labels every alert without overriding the operator's labels", func() {
+		alert.AddAlertLabels(&template, map[string]string{"team": "payments", "severity": "info"})
+		for _, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(rule.Labels["severity"]).NotTo(Equal("info"))
+		}
+	})
+})
+
+var _ = Describe("AddAlertAnnotations", func() {
+	var template monitoringv1.PrometheusRule
+	BeforeEach(func() {
+		template = alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+	})
+	It("annotates every alert without overriding the operator's annotations", func() {
+		alert.AddAlertAnnotations(&template, map[string]string{"runbook_url": "https://runbooks/fake", "message": "overridden"})
+		for _, rule := range template.Spec.Groups[0].Rules {
+			Expect(rule.Annotations).To(HaveKeyWithValue("runbook_url", "https://runbooks/fake"))
+			Expect(rule.Annotations["message"]).NotTo(Equal("overridden"))
+		}
+	})
+})
+
+var _ = Describe("ApplySeverities", func() {
+	var template monitoringv1.PrometheusRule
+	BeforeEach(func() {
+		template = alert.TemplateForPrometheusRuleResource("https://fake-url", "0.995", true, types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+	})
+	It("keeps the default severities without overrides", func() {
+		alert.ApplySeverities(&template, nil)
+		Expect(template.Spec.Groups[0].Rules[0].Labels["severity"]).To(Equal("critical"))
+	})
+	It("overrides the severities of the configured tiers", func() {
+		alert.ApplySeverities(&template, &v1alpha1.AlertSeveritySpec{MediumBurn: "warning", SlowBurn: "info"})
+		rules := template.Spec.Groups[0].Rules
+		Expect(rules[0].Labels).To(HaveKeyWithValue("severity", "critical"))
+		Expect(rules[0].Labels).To(HaveKeyWithValue("response", "page"))
+		Expect(rules[1].Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(rules[1].Labels).To(HaveKeyWithValue("response", "ticket"))
+		Expect(rules[2].Labels).To(HaveKeyWithValue("severity", "info"))
+		Expect(rules[2].Labels).To(HaveKeyWithValue("response", "ticket"))
+	})
+})
+
+var _ = Describe("LatencyRules", func() {
+	var (
+		latency *v1alpha1.LatencySloSpec
+		rules   []monitoringv1.Rule
+		ok      bool
+	)
+	JustBeforeEach(func() {
+		rules, ok = alert.LatencyRules(latency, "https://fake-url", types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"})
+	})
+	When("the monitor has no latency objective", func() {
+		BeforeEach(func() {
+			latency = nil
+		})
+		It("returns no rules", func() {
+			Expect(ok).To(BeFalse())
+			Expect(rules).To(BeEmpty())
+		})
+	})
+	When("the latency objective is invalid", func() {
+		BeforeEach(func() {
+			latency = &v1alpha1.LatencySloSpec{ThresholdMilliseconds: 500, TargetPercent: "0"}
+		})
+		It("returns no rules", func() {
+			Expect(ok).To(BeFalse())
+			Expect(rules).To(BeEmpty())
+		})
+	})
+	When("the latency objective is valid", func() {
+		BeforeEach(func() {
+			latency = &v1alpha1.LatencySloSpec{ThresholdMilliseconds: 500, TargetPercent: "95"}
+		})
+		It("alerts on probes slower than the threshold", func() {
+			Expect(ok).To(BeTrue())
+			Expect(rules).To(HaveLen(4))
+			for _, rule := range rules {
+				Expect(rule.Alert).To(Equal("fake-name-LatencyBudgetBurn"))
+				Expect(rule.Expr.String()).To(ContainSubstring(`probe_duration_seconds{probe_url="https://fake-url"} <= bool 0.5`))
+				Expect(rule.Expr.String()).To(ContainSubstring("(1-0.95)"))
+				Expect(rule.Annotations[alert.QueryAnnotation]).To(ContainSubstring("[" + rule.Labels["long_window"] + ":30s]"))
+			}
+		})
+	})
+})
+
+var _ = Describe("TemplateForMonitorPrometheusRule aggregating urls", func() {
+	It("alerts on the probes of all urls as one", func() {
+		template, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+			URL:            "the Routes of namespace fake-namespace",
+			URLs:           []string{"https://a.example.com/healthz", "b.example.com"},
+			Percent:        "0.995",
+			Slo:            v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"},
+			NamespacedName: types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		rules := template.Spec.Groups[0].Rules
+		Expect(rules).To(HaveLen(4))
+		for _, rule := range rules {
+			Expect(rule.Expr.String()).To(ContainSubstring(`probe_success{probe_url=~"https://a\\.example\\.com/healthz|b\\.example\\.com"}`))
+			Expect(rule.Labels).NotTo(HaveKey("probe_url"))
+			Expect(rule.Labels).To(HaveKeyWithValue("namespace", "fake-namespace"))
+			Expect(rule.Annotations["message"]).To(ContainSubstring("for the Routes of namespace fake-namespace"))
+		}
+	})
+})
+
+var _ = Describe("Probe health queries", func() {
+	It("query the availability and the last success over the SLO period", func() {
+		Expect(alert.AvailabilityQuery([]string{"https://fake-url"}, true)).To(Equal(
+			`1-(1-(sum(sum_over_time(probe_success{probe_url="https://fake-url"}[30d]))` +
+				`/ sum(count_over_time(probe_success{probe_url="https://fake-url"}[30d]))))`))
+		Expect(alert.LastSuccessQuery([]string{"https://fake-url"})).To(Equal(
+			`max(max_over_time(timestamp(probe_success{probe_url="https://fake-url"} == 1)[30d:30s]))`))
+	})
+})
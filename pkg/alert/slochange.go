@@ -0,0 +1,135 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"gopkg.in/inf.v0"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+)
+
+const (
+	SloTargetChangedReason = "SloTargetChanged"
+
+	SloChangeRaised  = "raised"
+	SloChangeLowered = "lowered"
+	SloChangeRemoved = "removed"
+	SloChangeAdded   = "added"
+)
+
+// ObserveSloChange compares the availability target of a monitor with the target observed last.
+// If it changed, the change is recorded as an Event and in the operator's metrics, and stored in
+// lastChange. It returns whether observed or lastChange were updated
+func ObserveSloChange(recorder record.EventRecorder, kind string, monitor metav1.Object, slo v1alpha1.SloSpec, observed *string, lastChange **v1alpha1.SloChange) bool {
+	target := slo.TargetAvailabilityPercent
+	if isValid, _ := slo.IsValid(); !isValid && !reflect.DeepEqual(slo, v1alpha1.SloSpec{}) {
+		// Invalid targets are reported through the error status
+		return false
+	}
+	if *observed == target {
+		return false
+	}
+
+	changedBy, changedAt := sloAuthor(monitor)
+	change := &v1alpha1.SloChange{
+		From:      *observed,
+		To:        target,
+		ChangedBy: changedBy,
+		ChangedAt: changedAt,
+	}
+	direction := sloChangeDirection(change)
+	*observed = target
+	*lastChange = change
+
+	metrics.SloTargetChanges.WithLabelValues(kind, monitor.GetNamespace(), monitor.GetName(), direction).Inc()
+	if recorder != nil {
+		if obj, ok := monitor.(runtime.Object); ok {
+			eventType := corev1.EventTypeNormal
+			if direction == SloChangeLowered || direction == SloChangeRemoved {
+				eventType = corev1.EventTypeWarning
+			}
+			recorder.Eventf(obj, eventType, SloTargetChangedReason, "Availability target %s from '%s' to '%s' by %s at %s",
+				direction, change.From, change.To, change.ChangedBy, change.ChangedAt.UTC().Format(time.RFC3339))
+		}
+	}
+	return true
+}
+
+// sloChangeDirection classifies a change of the availability target
+func sloChangeDirection(change *v1alpha1.SloChange) string {
+	switch {
+	case change.From == "":
+		return SloChangeAdded
+	case change.To == "":
+		return SloChangeRemoved
+	}
+	from, _ := new(inf.Dec).SetString(change.From)
+	to, _ := new(inf.Dec).SetString(change.To)
+	if from == nil || to == nil || to.Cmp(from) >= 0 {
+		return SloChangeRaised
+	}
+	return SloChangeLowered
+}
+
+// sloAuthor returns the field manager that last wrote the availability target of a monitor, and
+// when it did so. It falls back to the current time if the managed fields don't tell
+func sloAuthor(monitor metav1.Object) (string, metav1.Time) {
+	author, at := "unknown", metav1.Now()
+	var latest *metav1.Time
+	for _, entry := range monitor.GetManagedFields() {
+		if entry.FieldsV1 == nil || entry.Time == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		spec, _ := fields["f:spec"].(map[string]interface{})
+		if _, ok := spec["f:slo"]; !ok {
+			continue
+		}
+		if latest == nil || entry.Time.After(latest.Time) {
+			latest = entry.Time
+			author = entry.Manager
+		}
+	}
+	if latest != nil {
+		at = *latest
+	}
+	return author, at
+}
+
+// SloChangeRule returns an informational alert announcing that the availability target of a monitor
+// has been lowered. It fires for the given duration after the change, false is returned once it's over
+func SloChangeRule(change *v1alpha1.SloChange, duration time.Duration, namespacedName types.NamespacedName) (monitoringv1.Rule, bool) {
+	if duration <= 0 || change == nil || sloChangeDirection(change) != SloChangeLowered {
+		return monitoringv1.Rule{}, false
+	}
+	until := change.ChangedAt.Add(duration)
+	if !time.Now().Before(until) {
+		return monitoringv1.Rule{}, false
+	}
+	return monitoringv1.Rule{
+		Alert: namespacedName.Name + "-SloTargetLowered",
+		Expr:  intstr.FromString("vector(1) and on() time() < " + strconv.FormatInt(until.Unix(), 10)),
+		Labels: map[string]string{
+			"namespace": namespacedName.Namespace,
+			"severity":  "info",
+		},
+		Annotations: map[string]string{
+			"message": fmt.Sprintf("The availability target of %s was lowered from %s%% to %s%% by %s", namespacedName.Name, change.From, change.To, change.ChangedBy),
+		},
+	}, true
+}
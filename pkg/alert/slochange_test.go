@@ -0,0 +1,141 @@
+package alert_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+)
+
+var _ = Describe("SLO target changes", func() {
+	var (
+		recorder *record.FakeRecorder
+		monitor  v1alpha1.RouteMonitor
+		slo      v1alpha1.SloSpec
+		changed  bool
+	)
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(1)
+		monitor = v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-name",
+				Namespace: "fake-namespace",
+			},
+		}
+	})
+
+	Describe("ObserveSloChange", func() {
+		JustBeforeEach(func() {
+			changed = alert.ObserveSloChange(recorder, "RouteMonitor", &monitor, slo, &monitor.Status.SloTarget, &monitor.Status.LastSloChange)
+		})
+		When("the target didn't change", func() {
+			BeforeEach(func() {
+				slo = v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"}
+				monitor.Status.SloTarget = "99.5"
+			})
+			It("doesn't record anything", func() {
+				Expect(changed).To(BeFalse())
+				Expect(monitor.Status.LastSloChange).To(BeNil())
+				Expect(recorder.Events).To(BeEmpty())
+			})
+		})
+		When("the target is invalid", func() {
+			BeforeEach(func() {
+				slo = v1alpha1.SloSpec{TargetAvailabilityPercent: "120"}
+				monitor.Status.SloTarget = "99.5"
+			})
+			It("leaves it to the error status", func() {
+				Expect(changed).To(BeFalse())
+				Expect(monitor.Status.SloTarget).To(Equal("99.5"))
+			})
+		})
+		When("the target was lowered", func() {
+			BeforeEach(func() {
+				slo = v1alpha1.SloSpec{TargetAvailabilityPercent: "99"}
+				monitor.Status.SloTarget = "99.5"
+				monitor.ManagedFields = []metav1.ManagedFieldsEntry{
+					{
+						Manager:  "kubectl-edit",
+						Time:     &metav1.Time{Time: time.Unix(1000, 0)},
+						FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:slo":{}}}`)},
+					},
+					{
+						Manager:  "route-monitor-operator",
+						Time:     &metav1.Time{Time: time.Unix(2000, 0)},
+						FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)},
+					},
+				}
+			})
+			It("records who changed it and emits a warning", func() {
+				Expect(changed).To(BeTrue())
+				Expect(monitor.Status.SloTarget).To(Equal("99"))
+				Expect(monitor.Status.LastSloChange).To(Equal(&v1alpha1.SloChange{
+					From:      "99.5",
+					To:        "99",
+					ChangedBy: "kubectl-edit",
+					ChangedAt: metav1.Time{Time: time.Unix(1000, 0)},
+				}))
+				Expect(<-recorder.Events).To(HavePrefix("Warning SloTargetChanged Availability target lowered from '99.5' to '99' by kubectl-edit"))
+			})
+		})
+		When("the target was raised", func() {
+			BeforeEach(func() {
+				slo = v1alpha1.SloSpec{TargetAvailabilityPercent: "99.95"}
+				monitor.Status.SloTarget = "99.5"
+			})
+			It("emits a normal event", func() {
+				Expect(changed).To(BeTrue())
+				Expect(monitor.Status.LastSloChange.ChangedBy).To(Equal("unknown"))
+				Expect(<-recorder.Events).To(HavePrefix("Normal SloTargetChanged Availability target raised"))
+			})
+		})
+		When("the target was removed", func() {
+			BeforeEach(func() {
+				slo = v1alpha1.SloSpec{}
+				monitor.Status.SloTarget = "99.5"
+			})
+			It("emits a warning", func() {
+				Expect(changed).To(BeTrue())
+				Expect(monitor.Status.SloTarget).To(BeEmpty())
+				Expect(<-recorder.Events).To(HavePrefix("Warning SloTargetChanged Availability target removed"))
+			})
+		})
+	})
+
+	Describe("SloChangeRule", func() {
+		var (
+			change         *v1alpha1.SloChange
+			namespacedName = types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"}
+		)
+		BeforeEach(func() {
+			change = &v1alpha1.SloChange{From: "99.5", To: "99", ChangedBy: "kubectl-edit", ChangedAt: metav1.Now()}
+		})
+		It("announces a recently lowered target", func() {
+			rule, ok := alert.SloChangeRule(change, time.Hour, namespacedName)
+			Expect(ok).To(BeTrue())
+			Expect(rule.Alert).To(Equal("fake-name-SloTargetLowered"))
+			Expect(rule.Labels).To(HaveKeyWithValue("severity", "info"))
+		})
+		It("doesn't announce raised targets", func() {
+			change.To = "99.9"
+			_, ok := alert.SloChangeRule(change, time.Hour, namespacedName)
+			Expect(ok).To(BeFalse())
+		})
+		It("stops announcing once the duration passed", func() {
+			change.ChangedAt = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			_, ok := alert.SloChangeRule(change, time.Hour, namespacedName)
+			Expect(ok).To(BeFalse())
+		})
+		It("is disabled without a duration", func() {
+			_, ok := alert.SloChangeRule(change, 0, namespacedName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
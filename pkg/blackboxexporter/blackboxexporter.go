@@ -1,22 +1,33 @@
 package blackboxexporter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
 	"reflect"
+	"strconv"
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
 	"github.com/openshift/route-monitor-operator/pkg/util"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	"github.com/openshift/route-monitor-operator/pkg/util/finalizer"
 
 	"context"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,56 +39,258 @@ type BlackBoxExporter struct {
 	Ctx            context.Context
 	Image          string
 	NamespacedName types.NamespacedName
+	// FIPS restricts the probes to FIPS approved TLS settings and runs the exporter in FIPS mode
+	FIPS bool
+	// Deployment sizes the exporter deployment
+	Deployment DeploymentSettings
+	// NetworkPolicy restricts the traffic of the exporter pods
+	NetworkPolicy runtimeconfig.NetworkPolicy
+	// RuntimeConfig overrides the Image, the Deployment and the NetworkPolicy while set, so the exporter can be updated without
+	// restarting the operator
+	RuntimeConfig *runtimeconfig.Config
+	// FieldOwner, if set, applies the exporter's resources server-side with it as the field manager instead of
+	// creating and updating them, so labels, annotations and other fields set by other managers are kept
+	FieldOwner string
+	// Dedicated restricts the exporter to the RouteMonitors and UrlMonitors of its namespace asking for a dedicated
+	// exporter, which the shared exporter leaves out
+	Dedicated bool
+	// HTTPClient sends the debug probes to the exporter. If it's nil, a client trusting the service CA of the operator's
+	// pod is used
+	HTTPClient *http.Client
 }
 
 func New(client client.Client, log logr.Logger, ctx context.Context, blackBoxImage string, blackBoxExporterNamespace string) *BlackBoxExporter {
 	blackboxNamespacedName := types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: blackBoxExporterNamespace}
-	return &BlackBoxExporter{client, log, ctx, blackBoxImage, blackboxNamespacedName}
+	return &BlackBoxExporter{Client: client, Log: log, Ctx: ctx, Image: blackBoxImage, NamespacedName: blackboxNamespacedName}
+}
+
+// DedicatedTo returns the dedicated exporter of the namespace, configured like the shared one
+func (b *BlackBoxExporter) DedicatedTo(namespace string) *BlackBoxExporter {
+	dedicated := *b
+	dedicated.NamespacedName.Namespace = namespace
+	dedicated.Dedicated = true
+	return &dedicated
+}
+
+// serves reports whether the exporter probes for a monitor of the namespace which asks, or not, for a dedicated exporter.
+// Monitors probed by an external exporter aren't served by any
+func (b *BlackBoxExporter) serves(namespace string, dedicatedExporter bool, external *v1alpha1.ExternalExporterSpec) bool {
+	if external != nil {
+		return false
+	}
+	if b.Dedicated {
+		return dedicatedExporter && namespace == b.NamespacedName.Namespace
+	}
+	return !dedicatedExporter
+}
+
+// image returns the image of the exporter deployment
+func (b *BlackBoxExporter) image() string {
+	if b.RuntimeConfig != nil {
+		return b.RuntimeConfig.Get().BlackBoxExporterImage
+	}
+	return b.Image
+}
+
+// deploymentSettings returns the size of the exporter deployment
+func (b *BlackBoxExporter) deploymentSettings() DeploymentSettings {
+	if b.RuntimeConfig != nil {
+		settings := b.RuntimeConfig.Get()
+		return DeploymentSettings{
+			Replicas:          settings.BlackBoxExporterReplicas,
+			Resources:         settings.BlackBoxExporterResources,
+			ZoneSpread:        b.Deployment.ZoneSpread,
+			PriorityClassName: b.Deployment.PriorityClassName,
+			RelaxedSecurity:   b.Deployment.RelaxedSecurity,
+			RunAsUser:         b.Deployment.RunAsUser,
+			ImagePullSecrets:  b.Deployment.ImagePullSecrets,
+			DaemonSet:         b.Deployment.DaemonSet,
+			ServingCert:       b.Deployment.ServingCert,
+			ServiceMesh:       b.Deployment.ServiceMesh,
+			LogLevel:          settings.BlackBoxExporterLogLevel,
+			PodLabels:         settings.BlackBoxExporterPodLabels,
+			PodAnnotations:    settings.BlackBoxExporterPodAnnotations,
+		}
+	}
+	return b.Deployment
+}
+
+// networkPolicy returns the NetworkPolicy settings of the exporter
+func (b *BlackBoxExporter) networkPolicy() runtimeconfig.NetworkPolicy {
+	if b.RuntimeConfig != nil {
+		return b.RuntimeConfig.Get().BlackBoxExporterNetworkPolicy
+	}
+	return b.NetworkPolicy
 }
 
 func (b *BlackBoxExporter) GetBlackBoxExporterNamespace() string {
 	return b.NamespacedName.Namespace
 }
 
+// GetBlackBoxExporterRollout describes the rollout of the exporter deployment, e.g. of a changed configuration. It's
+// empty once every pod runs the current version of the deployment and is available
+func (b *BlackBoxExporter) GetBlackBoxExporterRollout() (string, error) {
+	if b.deploymentSettings().DaemonSet {
+		return b.daemonSetRollout()
+	}
+	deployment := appsv1.Deployment{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &deployment); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "The blackbox exporter deployment doesn't exist yet", nil
+		}
+		return "", err
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	switch {
+	case status.ObservedGeneration < deployment.Generation:
+		return "Waiting for the rollout of the blackbox exporter to start", nil
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d blackbox exporter pods are up to date", status.UpdatedReplicas, replicas), nil
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("%d outdated blackbox exporter pods are terminating", status.Replicas-status.UpdatedReplicas), nil
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return fmt.Sprintf("%d of %d up to date blackbox exporter pods are available", status.AvailableReplicas, status.UpdatedReplicas), nil
+	}
+	return "", nil
+}
+
+// daemonSetRollout describes the rollout of the exporter daemonset, empty once every node runs an available pod of
+// its current version
+func (b *BlackBoxExporter) daemonSetRollout() (string, error) {
+	daemonSet := appsv1.DaemonSet{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &daemonSet); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "The blackbox exporter daemonset doesn't exist yet", nil
+		}
+		return "", err
+	}
+	status := daemonSet.Status
+	switch {
+	case status.ObservedGeneration < daemonSet.Generation:
+		return "Waiting for the rollout of the blackbox exporter to start", nil
+	case status.UpdatedNumberScheduled < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d blackbox exporter pods are up to date", status.UpdatedNumberScheduled, status.DesiredNumberScheduled), nil
+	case status.NumberAvailable < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d up to date blackbox exporter pods are available", status.NumberAvailable, status.DesiredNumberScheduled), nil
+	}
+	return "", nil
+}
+
 func (b *BlackBoxExporter) ShouldDeleteBlackBoxExporterResources() (blackboxexporter.ShouldDeleteBlackBoxExporter, error) {
+	objectsDependingOnExporter, err := b.dependents()
+	if err != nil {
+		return blackboxexporter.KeepBlackBoxExporter, err
+	}
+	b.Log.V(logging.TraceVerbosity).Info("Number of objects depending on BlackBoxExporter:", "amountOfObjects", len(objectsDependingOnExporter))
+
+	if len(objectsDependingOnExporter) == 1 && finalizer.WasDeleteRequested(objectsDependingOnExporter[0]) {
+		b.Log.V(logging.TraceVerbosity).Info("Deleting BlackBoxResources: decided to clean BlackBoxExporter resources")
+		return blackboxexporter.DeleteBlackBoxExporter, nil
+	}
+	return blackboxexporter.KeepBlackBoxExporter, nil
+}
+
+// InUse reports whether a monitor which isn't being deleted is probed by the exporter, so its resources are kept
+func (b *BlackBoxExporter) InUse() (bool, error) {
+	objectsDependingOnExporter, err := b.dependents()
+	if err != nil {
+		return false, err
+	}
+	for _, object := range objectsDependingOnExporter {
+		if !finalizer.WasDeleteRequested(object) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// dependents returns the monitors served by the exporter
+func (b *BlackBoxExporter) dependents() ([]v1.Object, error) {
 	objectsDependingOnExporter := []v1.Object{}
 
 	routeMonitors := &v1alpha1.RouteMonitorList{}
 	if err := b.Client.List(b.Ctx, routeMonitors); err != nil {
-		return blackboxexporter.KeepBlackBoxExporter, err
+		return nil, err
 	}
 	for i := range routeMonitors.Items {
+		if !b.serves(routeMonitors.Items[i].Namespace, routeMonitors.Items[i].Spec.DedicatedExporter, routeMonitors.Items[i].Spec.ExternalExporter) {
+			continue
+		}
 		objectsDependingOnExporter = append(objectsDependingOnExporter, &routeMonitors.Items[i])
 	}
 
 	clusterUrlMonitors := &v1alpha1.ClusterUrlMonitorList{}
 	if err := b.Client.List(b.Ctx, clusterUrlMonitors); err != nil {
-		return blackboxexporter.KeepBlackBoxExporter, err
+		return nil, err
 	}
 	for i := range clusterUrlMonitors.Items {
+		if !b.serves("", false, nil) {
+			continue
+		}
 		objectsDependingOnExporter = append(objectsDependingOnExporter, &clusterUrlMonitors.Items[i])
 	}
-	b.Log.V(4).Info("Number of objects depending on BlackBoxExporter:", "amountOfObjects", len(objectsDependingOnExporter))
 
-	if len(objectsDependingOnExporter) == 1 && finalizer.WasDeleteRequested(objectsDependingOnExporter[0]) {
-		b.Log.V(3).Info("Deleting BlackBoxResources: decided to clean BlackBoxExporter resources")
-		return blackboxexporter.DeleteBlackBoxExporter, nil
+	urlMonitors := &v1alpha1.UrlMonitorList{}
+	if err := b.Client.List(b.Ctx, urlMonitors); err != nil {
+		return nil, err
 	}
-	return blackboxexporter.KeepBlackBoxExporter, nil
+	for i := range urlMonitors.Items {
+		if !b.serves(urlMonitors.Items[i].Namespace, urlMonitors.Items[i].Spec.DedicatedExporter, urlMonitors.Items[i].Spec.ExternalExporter) {
+			continue
+		}
+		objectsDependingOnExporter = append(objectsDependingOnExporter, &urlMonitors.Items[i])
+	}
+
+	namespaceMonitors := &v1alpha1.NamespaceMonitorList{}
+	if err := b.Client.List(b.Ctx, namespaceMonitors); err != nil {
+		return nil, err
+	}
+	for i := range namespaceMonitors.Items {
+		if !b.serves("", false, nil) {
+			continue
+		}
+		objectsDependingOnExporter = append(objectsDependingOnExporter, &namespaceMonitors.Items[i])
+	}
+
+	certificateMonitors := &v1alpha1.CertificateMonitorList{}
+	if err := b.Client.List(b.Ctx, certificateMonitors); err != nil {
+		return nil, err
+	}
+	for i := range certificateMonitors.Items {
+		if !b.serves("", false, nil) {
+			continue
+		}
+		objectsDependingOnExporter = append(objectsDependingOnExporter, &certificateMonitors.Items[i])
+	}
+	return objectsDependingOnExporter, nil
 }
 
-func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentExists() error {
+// EnsureBlackBoxExporterDeploymentExists creates or updates the exporter deployment. The configHash
+// is stamped onto the pod template, so the exporter is rolled out whenever its configuration changes
+func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentExists(configHash string) error {
 	resource := appsv1.Deployment{}
-	template := b.templateForBlackBoxExporterDeployment(b.Image, b.NamespacedName)
+	settings, err := b.podSettings()
+	if err != nil {
+		return err
+	}
+	template := TemplateForBlackBoxExporterDeployment(b.image(), b.NamespacedName, configHash, b.schedulingNodeLabel(), b.FIPS, settings)
+	hash := stampSpecHash(&template)
 
 	// Does the resource already exist?
-	err := b.Client.Get(b.Ctx, b.NamespacedName, &resource)
+	err = b.Client.Get(b.Ctx, b.NamespacedName, &resource)
 	if err != nil {
 		// If this is an unknown error
 		if !k8serrors.IsNotFound(err) {
 			// return unexpectedly
 			return err
 		}
+		if b.FieldOwner != "" {
+			return b.apply(&template, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		}
 		// and create it
 		err = b.Client.Create(b.Ctx, &template)
 		if err != nil {
@@ -87,9 +300,13 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentExists() error {
 		return nil
 	}
 
-	// Update the deployment if it's different than the template
-	if !reflect.DeepEqual(resource.Spec, template.Spec) {
+	// Update the deployment if it drifted from the template
+	if drifted(&resource, resource.Spec, template.Spec, hash) {
+		if b.FieldOwner != "" {
+			return b.apply(&template, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		}
 		resource.ObjectMeta.ResourceVersion = ""
+		metav1.SetMetaDataAnnotation(&resource.ObjectMeta, blackboxexporter.BlackBoxExporterSpecHashAnnotation, hash)
 		resource.Spec = template.Spec
 		err = b.Client.Update(b.Ctx, &resource)
 		if err != nil {
@@ -100,9 +317,45 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentExists() error {
 	return nil
 }
 
+// EnsureBlackBoxExporterDaemonSetExists creates or updates the exporter daemonset, which runs the pods of the
+// deployment on every node. The configHash rolls it out like the deployment
+func (b *BlackBoxExporter) EnsureBlackBoxExporterDaemonSetExists(configHash string) error {
+	settings, err := b.podSettings()
+	if err != nil {
+		return err
+	}
+	template := TemplateForBlackBoxExporterDaemonSet(b.image(), b.NamespacedName, configHash, b.schedulingNodeLabel(), b.FIPS, settings)
+	hash := stampSpecHash(&template)
+
+	resource := appsv1.DaemonSet{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &resource); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if b.FieldOwner != "" {
+			return b.apply(&template, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+		}
+		return b.Client.Create(b.Ctx, &template)
+	}
+
+	if drifted(&resource, resource.Spec, template.Spec, hash) {
+		if b.FieldOwner != "" {
+			return b.apply(&template, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+		}
+		metav1.SetMetaDataAnnotation(&resource.ObjectMeta, blackboxexporter.BlackBoxExporterSpecHashAnnotation, hash)
+		resource.Spec = template.Spec
+		return b.Client.Update(b.Ctx, &resource)
+	}
+	return nil
+}
+
+// EnsureBlackBoxExporterServiceExists creates or updates the exporter Service. The cluster IP the API server assigned
+// is kept
 func (b *BlackBoxExporter) EnsureBlackBoxExporterServiceExists() error {
 	resource := corev1.Service{}
-	populationFunc := func() corev1.Service { return templateForBlackBoxExporterService(b.NamespacedName) }
+	servingCert := b.deploymentSettings().ServingCert
+	template := TemplateForBlackBoxExporterService(b.NamespacedName, servingCert)
+	hash := stampSpecHash(&template)
 
 	// Does the resource already exist?
 	if err := b.Client.Get(b.Ctx, b.NamespacedName, &resource); err != nil {
@@ -111,48 +364,376 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterServiceExists() error {
 			// return unexpectedly
 			return err
 		}
-		// populate the resource with the template
-		resource := populationFunc()
+		if b.FieldOwner != "" {
+			return b.apply(&template, corev1.SchemeGroupVersion.WithKind("Service"))
+		}
 		// and create it
-		if err = b.Client.Create(b.Ctx, &resource); err != nil {
+		return b.Client.Create(b.Ctx, &template)
+	}
+
+	if !drifted(&resource, resource.Spec, template.Spec, hash) {
+		return nil
+	}
+	if b.FieldOwner != "" {
+		return b.apply(&template, corev1.SchemeGroupVersion.WithKind("Service"))
+	}
+	resource.Spec.Ports = template.Spec.Ports
+	resource.Spec.Selector = template.Spec.Selector
+	metav1.SetMetaDataAnnotation(&resource.ObjectMeta, blackboxexporter.BlackBoxExporterSpecHashAnnotation, hash)
+	if servingCert {
+		metav1.SetMetaDataAnnotation(&resource.ObjectMeta, blackboxexporter.ServingCertSecretAnnotation, blackboxexporter.BlackBoxExporterServingCertSecretName)
+	} else {
+		delete(resource.Annotations, blackboxexporter.ServingCertSecretAnnotation)
+	}
+	return b.Client.Update(b.Ctx, &resource)
+}
+
+// EnsureBlackBoxExporterNetworkPolicy creates or updates the exporter NetworkPolicy while it's enabled, and deletes
+// it otherwise
+func (b *BlackBoxExporter) EnsureBlackBoxExporterNetworkPolicy() error {
+	settings := b.networkPolicy()
+	if !settings.Enabled {
+		return b.EnsureBlackBoxExporterNetworkPolicyAbsent()
+	}
+	template := TemplateForBlackBoxExporterNetworkPolicy(b.NamespacedName, settings)
+
+	resource := networkingv1.NetworkPolicy{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &resource); err != nil {
+		if !k8serrors.IsNotFound(err) {
 			return err
 		}
+		if b.FieldOwner != "" {
+			return b.apply(&template, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+		}
+		return b.Client.Create(b.Ctx, &template)
+	}
+
+	if !reflect.DeepEqual(resource.Spec, template.Spec) {
+		if b.FieldOwner != "" {
+			return b.apply(&template, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+		}
+		resource.Spec = template.Spec
+		return b.Client.Update(b.Ctx, &resource)
 	}
 	return nil
 }
 
-func (b *BlackBoxExporter) EnsureBlackBoxExporterConfigMapExists() error {
-	resource := corev1.ConfigMap{}
-	populationFunc := func() corev1.ConfigMap { return templateForBlackBoxExporterConfigMap(b.NamespacedName) }
+// EnsureBlackBoxExporterConfigMapExists creates or updates the exporter configuration, so it contains
+// the modules required by every monitor. It returns a hash of the configuration
+func (b *BlackBoxExporter) EnsureBlackBoxExporterConfigMapExists() (string, error) {
+	modules, err := b.desiredModules()
+	if err != nil {
+		return "", err
+	}
+	return b.ensureConfigMap(modules)
+}
 
+// ensureConfigMap creates or updates the exporter configuration holding the modules
+func (b *BlackBoxExporter) ensureConfigMap(modules map[string]Module) (string, error) {
+	template, err := TemplateForBlackBoxExporterConfigMap(b.NamespacedName, modules, b.deploymentSettings().ServingCert)
+	if err != nil {
+		return "", err
+	}
+	// A configuration the exporter can't load would crash it once rolled out, the running
+	// configuration is kept instead
+	if err := ValidateConfig(template.Data[blackboxexporter.BlackBoxExporterConfigFile]); err != nil {
+		return "", err
+	}
+	configHash := HashConfigMap(template)
+
+	resource := corev1.ConfigMap{}
 	// Does the resource already exist?
 	if err := b.Client.Get(b.Ctx, b.NamespacedName, &resource); err != nil {
 		// If this is an unknown error
 		if !k8serrors.IsNotFound(err) {
 			// return unexpectedly
-			return err
+			return "", err
+		}
+		if b.FieldOwner != "" {
+			return configHash, b.apply(&template, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
 		}
-		// populate the resource with the template
-		resource := populationFunc()
 		// and create it
-		return b.Client.Create(b.Ctx, &resource)
+		return configHash, b.Client.Create(b.Ctx, &template)
 	}
-	return nil
+
+	// Update the configuration if the required modules changed
+	if !reflect.DeepEqual(resource.Data, template.Data) {
+		if b.FieldOwner != "" {
+			return configHash, b.apply(&template, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		}
+		resource.Data = template.Data
+		if err := b.Client.Update(b.Ctx, &resource); err != nil {
+			return "", err
+		}
+	}
+	return configHash, nil
 }
 
-// deploymentForBlackBoxExporter returns a blackbox deployment
-func (b *BlackBoxExporter) templateForBlackBoxExporterDeployment(blackBoxImage string, blackBoxNamespacedName types.NamespacedName) appsv1.Deployment {
-	nodeLabel := "node-role.kubernetes.io/infra"
+// apply creates or patches the resource server-side with the FieldOwner as the field manager. Conflicting fields
+// are taken over, as the operator is the authority on the exporter's resources
+func (b *BlackBoxExporter) apply(obj client.Object, gvk schema.GroupVersionKind) error {
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	return b.Client.Patch(b.Ctx, obj, client.Apply, client.FieldOwner(b.FieldOwner), client.ForceOwnership)
+}
+
+// desiredModules collects the modules required by all monitors served by the exporter which aren't being deleted
+func (b *BlackBoxExporter) desiredModules() (map[string]Module, error) {
+	modules := map[string]Module{}
+
+	routeMonitors := &v1alpha1.RouteMonitorList{}
+	if err := b.Client.List(b.Ctx, routeMonitors); err != nil {
+		return nil, err
+	}
+	for i := range routeMonitors.Items {
+		routeMonitor := &routeMonitors.Items[i]
+		if finalizer.WasDeleteRequested(routeMonitor) || !b.serves(routeMonitor.Namespace, routeMonitor.Spec.DedicatedExporter, routeMonitor.Spec.ExternalExporter) {
+			continue
+		}
+		insecureSkipTLSVerify, probe, err := ProbeSettingsOf(b.Ctx, b.Client, routeMonitor.Spec)
+		if err != nil {
+			// The RouteMonitor reports its missing ProbeTemplate, it isn't probed until it exists
+			var missing *customerrors.DependencyMissingError
+			if errors.As(err, &missing) {
+				continue
+			}
+			return nil, err
+		}
+		name, module := ModuleFor(insecureSkipTLSVerify, probe)
+		modules[name] = module
+	}
+
+	clusterUrlMonitors := &v1alpha1.ClusterUrlMonitorList{}
+	if err := b.Client.List(b.Ctx, clusterUrlMonitors); err != nil {
+		return nil, err
+	}
+	for i := range clusterUrlMonitors.Items {
+		clusterUrlMonitor := &clusterUrlMonitors.Items[i]
+		if finalizer.WasDeleteRequested(clusterUrlMonitor) || !b.serves("", false, nil) {
+			continue
+		}
+		name, module := ModuleFor(clusterUrlMonitor.Spec.ProbedTarget() == v1alpha1.ClusterUrlTargetAPIInternal, clusterUrlMonitor.Spec.HTTPProbe)
+		modules[name] = module
+	}
+
+	urlMonitors := &v1alpha1.UrlMonitorList{}
+	if err := b.Client.List(b.Ctx, urlMonitors); err != nil {
+		return nil, err
+	}
+	for i := range urlMonitors.Items {
+		urlMonitor := &urlMonitors.Items[i]
+		if finalizer.WasDeleteRequested(urlMonitor) || !b.serves(urlMonitor.Namespace, urlMonitor.Spec.DedicatedExporter, urlMonitor.Spec.ExternalExporter) {
+			continue
+		}
+		name, module := ModuleFor(urlMonitor.Spec.InsecureSkipTLSVerify, urlMonitor.Spec.HTTPProbe)
+		modules[name] = module
+	}
+
+	namespaceMonitors := &v1alpha1.NamespaceMonitorList{}
+	if err := b.Client.List(b.Ctx, namespaceMonitors); err != nil {
+		return nil, err
+	}
+	for i := range namespaceMonitors.Items {
+		namespaceMonitor := &namespaceMonitors.Items[i]
+		if finalizer.WasDeleteRequested(namespaceMonitor) || !b.serves("", false, nil) {
+			continue
+		}
+		name, module := ModuleFor(namespaceMonitor.Spec.InsecureSkipTLSVerify, namespaceMonitor.Spec.HTTPProbe)
+		modules[name] = module
+	}
+
+	certificateMonitors := &v1alpha1.CertificateMonitorList{}
+	if err := b.Client.List(b.Ctx, certificateMonitors); err != nil {
+		return nil, err
+	}
+	for i := range certificateMonitors.Items {
+		certificateMonitor := &certificateMonitors.Items[i]
+		if finalizer.WasDeleteRequested(certificateMonitor) || !b.serves("", false, nil) {
+			continue
+		}
+		name, module := CertificateModuleFor(certificateMonitor.Spec.ServerName)
+		modules[name] = module
+	}
+	proxy, err := ClusterProxy(b.Ctx, b.Client)
+	if err != nil {
+		return nil, err
+	}
+	return ExporterModules(modules, b.FIPS, proxy), nil
+}
+
+// ExporterModules returns the modules of the exporter configuration: the default ones and the ones required by
+// monitors. In FIPS mode, every module is restricted to FIPS approved settings, and on clusters with a proxy the
+// http modules probe through it. Required modules failing ValidateRequired are left out, so they don't block the
+// configuration of the other monitors; their monitors report the error
+func ExporterModules(required map[string]Module, fips bool, proxy Proxy) map[string]Module {
+	modules := DefaultModules()
+	for name, module := range required {
+		if module.ValidateRequired(fips) != nil {
+			continue
+		}
+		modules[name] = module
+	}
+	if fips {
+		for name, module := range modules {
+			modules[name] = module.WithFIPS()
+		}
+	}
+	if proxy.Enabled() {
+		for name, module := range modules {
+			modules[name] = module.WithProxyFromEnvironment()
+		}
+	}
+	return modules
+}
+
+// HashConfigMap returns a digest of the data held by the ConfigMap
+func HashConfigMap(cm corev1.ConfigMap) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+const (
+	// InfraNodeLabel labels the nodes the exporter prefers to run on
+	InfraNodeLabel = "node-role.kubernetes.io/infra"
+	// MasterNodeLabel labels the nodes the exporter prefers to run on in clusters with a private NLB
+	MasterNodeLabel = "node-role.kubernetes.io/master"
+)
+
+// schedulingNodeLabel returns the label of the nodes the exporter prefers to run on
+func (b *BlackBoxExporter) schedulingNodeLabel() string {
 	if util.IsClusterVersionHigherOrEqualThan(b.Client, "4.13") && util.ClusterHasPrivateNLB(b.Client) {
-		nodeLabel = "node-role.kubernetes.io/master"
+		return MasterNodeLabel
 	}
+	return InfraNodeLabel
+}
+
+// DeploymentSettings size the exporter deployment, e.g. to spread the probes of a large cluster over several pods
+type DeploymentSettings struct {
+	// Replicas is the number of exporter pods, one if it's zero
+	Replicas int32
+	// Resources are the requests and limits of the exporter container, none by default
+	Resources corev1.ResourceRequirements
+	// ZoneSpread spreads the exporter pods over the zones and nodes of the cluster, so each zone probes the urls
+	ZoneSpread bool
+	// PriorityClassName keeps the exporter pods from being the first ones evicted under node pressure, none by default
+	PriorityClassName string
+	// RelaxedSecurity runs the exporter without the restricted security context, e.g. for icmp probes needing NET_RAW
+	RelaxedSecurity bool
+	// RunAsUser is the numeric user of the restricted exporter pods. The kubelet can't verify that the image's
+	// non-numeric user isn't root, so it has to be set unless the platform assigns one, as OpenShift does
+	RunAsUser *int64
+	// ImagePullSecrets name the Secrets of the exporter's namespace the image is pulled with, e.g. from a mirror registry
+	ImagePullSecrets []string
+	// DaemonSet runs an exporter pod on every node instead of the Replicas, so the probes of a ServiceMonitor are run
+	// from every node
+	DaemonSet bool
+	// ServingCert serves the probes over TLS with a certificate issued by the service CA, so they aren't scraped in
+	// plaintext
+	ServingCert bool
+	// Proxy routes the probes through the cluster-wide proxy, trusting its CA bundle. It's read from the cluster rather
+	// than configured
+	Proxy Proxy
+	// LogLevel is the level of the exporter's logs, e.g. 'debug' to log every step of the probes. The exporter's default
+	// if it's empty
+	LogLevel string
+	// PodLabels are added to the labels of the exporter pods, e.g. for cost attribution. The labels set by the operator,
+	// which select the pods, take precedence
+	PodLabels map[string]string
+	// PodAnnotations are added to the annotations of the exporter pods, e.g. 'sidecar.istio.io/inject: "false"' to keep
+	// them out of a service mesh. The annotations set by the operator take precedence
+	PodAnnotations map[string]string
+	// ServiceMesh is 'inject' to run the exporter pods with the sidecar of an Istio service mesh, or 'exclude' to keep
+	// them out of it. The namespace's injection policy applies if it's empty
+	ServiceMesh string
+}
+
+// MeshSidecar reports whether the exporter pods run with the mesh's sidecar, so they are scraped with its mTLS
+// certificates
+func (s DeploymentSettings) MeshSidecar() bool {
+	return s.ServiceMesh == blackboxexporter.ServiceMeshInject
+}
 
+// servingCertDir is where the serving certificate of the exporter is mounted
+const servingCertDir = "/etc/tls/private"
+
+// webConfig serves the probes of the exporter over TLS with its serving certificate. The certificate is read for every
+// connection, so it's rotated by the service CA without restarting the exporter
+const webConfig = `tls_server_config:
+  cert_file: ` + servingCertDir + `/tls.crt
+  key_file: ` + servingCertDir + `/tls.key
+`
+
+// TemplateForBlackBoxExporterDeployment returns a blackbox deployment preferring the nodes with the nodeLabel, sized by
+// the settings. In FIPS mode the exporter is forced into FIPS mode
+func TemplateForBlackBoxExporterDeployment(blackBoxImage string, blackBoxNamespacedName types.NamespacedName, configHash string, nodeLabel string, fips bool, settings DeploymentSettings) appsv1.Deployment {
 	labels := blackboxexporter.GenerateBlackBoxExporterLables()
 	labelSelectors := metav1.LabelSelector{
 		MatchLabels: labels}
-	// hardcode the replicasize for no
-	// replicas := m.Spec.Size
 	var replicas int32 = 1
+	if settings.Replicas > 0 {
+		replicas = settings.Replicas
+	}
+	maxUnavailable, maxSurge := intstr.FromInt32(0), intstr.FromInt32(1)
+	var pullSecrets []corev1.LocalObjectReference
+	for _, secret := range settings.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	args := []string{"--config.file=/config/" + blackboxexporter.BlackBoxExporterConfigFile}
+	mounts := []corev1.VolumeMount{{Name: "blackbox-config", ReadOnly: true, MountPath: "/config"}}
+	volumes := []corev1.Volume{{
+		Name: "blackbox-config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: blackBoxNamespacedName.Name},
+			},
+		},
+	}}
+	if settings.LogLevel != "" {
+		args = append(args, "--log.level="+settings.LogLevel)
+	}
+	if settings.ServingCert {
+		args = append(args, "--web.config.file=/config/"+blackboxexporter.BlackBoxExporterWebConfigFile)
+		mounts = append(mounts, corev1.VolumeMount{Name: "blackbox-tls", ReadOnly: true, MountPath: servingCertDir})
+		volumes = append(volumes, corev1.Volume{
+			Name: "blackbox-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: blackboxexporter.BlackBoxExporterServingCertSecretName},
+			},
+		})
+	}
+	if settings.Proxy.Enabled() {
+		optional := true
+		mounts = append(mounts, corev1.VolumeMount{Name: "trusted-ca-bundle", ReadOnly: true, MountPath: trustedCABundleDir})
+		volumes = append(volumes, corev1.Volume{
+			Name: "trusted-ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: blackboxexporter.BlackBoxExporterTrustedCABundleName},
+					Items:                []corev1.KeyToPath{{Key: blackboxexporter.TrustedCABundleFile, Path: trustedCABundleFile}},
+					// The bundle is injected after the ConfigMap is created, the pods start with the system CAs meanwhile
+					Optional: &optional,
+				},
+			},
+		})
+	}
+	annotations := map[string]string{blackboxexporter.BlackBoxExporterConfigHashAnnotation: configHash}
+	if settings.ServiceMesh != "" {
+		annotations[blackboxexporter.SidecarInjectAnnotation] = strconv.FormatBool(settings.MeshSidecar())
+	}
+	probeScheme := corev1.URISchemeHTTP
+	if settings.ServingCert {
+		probeScheme = corev1.URISchemeHTTPS
+	}
+	var spread []corev1.TopologySpreadConstraint
+	if settings.ZoneSpread {
+		// Preferred only, so the exporter is still scheduled while a zone is down
+		spread = []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: &labelSelectors},
+			{MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: &labelSelectors},
+		}
+	}
 
 	dep := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -163,9 +744,18 @@ func (b *BlackBoxExporter) templateForBlackBoxExporterDeployment(blackBoxImage s
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
 			Selector: &labelSelectors,
+			// The outdated pods keep probing until their replacements are ready, e.g. when the image is upgraded
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: &maxUnavailable,
+					MaxSurge:       &maxSurge,
+				},
+			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      mergeMetadata(settings.PodLabels, labels),
+					Annotations: mergeMetadata(settings.PodAnnotations, annotations),
 				},
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
@@ -181,41 +771,38 @@ func (b *BlackBoxExporter) templateForBlackBoxExporterDeployment(blackBoxImage s
 							}},
 						},
 					},
+					TopologySpreadConstraints: spread,
+					PriorityClassName:         settings.PriorityClassName,
+					SecurityContext:           podSecurityContext(settings.RelaxedSecurity, settings.RunAsUser),
+					ImagePullSecrets:          pullSecrets,
 					Tolerations: []corev1.Toleration{{
 						Operator: corev1.TolerationOpExists,
 						Effect:   corev1.TaintEffectNoSchedule,
 						Key:      nodeLabel,
 					}},
 					Containers: []corev1.Container{{
-						Image: blackBoxImage,
-						Name:  "blackbox-exporter",
-						Args: []string{
-							"--config.file=/config/blackbox.yaml",
-						},
+						Image:           blackBoxImage,
+						Name:            "blackbox-exporter",
+						Args:            args,
+						Env:             append(fipsEnv(fips), settings.Proxy.env()...),
+						Resources:       settings.Resources,
+						SecurityContext: containerSecurityContext(settings.RelaxedSecurity),
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: blackboxexporter.BlackBoxExporterPortNumber,
 							Name:          blackboxexporter.BlackBoxExporterPortName,
 						}},
-						VolumeMounts: []corev1.VolumeMount{
-							{
-								Name:      "blackbox-config",
-								ReadOnly:  true,
-								MountPath: "/config",
-							},
-						},
-					}},
-					Volumes: []corev1.Volume{
-						{
-							Name: "blackbox-config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: blackBoxNamespacedName.Name,
-									},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{
+									Path:   "/-/healthy",
+									Port:   intstr.FromString(blackboxexporter.BlackBoxExporterPortName),
+									Scheme: probeScheme,
 								},
 							},
 						},
-					},
+						VolumeMounts: mounts,
+					}},
+					Volumes: volumes,
 				},
 			},
 		},
@@ -223,8 +810,79 @@ func (b *BlackBoxExporter) templateForBlackBoxExporterDeployment(blackBoxImage s
 	return dep
 }
 
-// templateForBlackBoxExporterService returns a blackbox service
-func templateForBlackBoxExporterService(blackboxNamespacedName types.NamespacedName) corev1.Service {
+// mergeMetadata returns the labels or annotations of the user merged with the ones of the operator, which take
+// precedence
+func mergeMetadata(user, operator map[string]string) map[string]string {
+	merged := make(map[string]string, len(user)+len(operator))
+	for key, value := range user {
+		merged[key] = value
+	}
+	for key, value := range operator {
+		merged[key] = value
+	}
+	return merged
+}
+
+// TemplateForBlackBoxExporterDaemonSet returns a blackbox daemonset running the pods of the deployment on every node
+// tolerating the nodeLabel, so each node probes the urls
+func TemplateForBlackBoxExporterDaemonSet(blackBoxImage string, blackBoxNamespacedName types.NamespacedName, configHash string, nodeLabel string, fips bool, settings DeploymentSettings) appsv1.DaemonSet {
+	deployment := TemplateForBlackBoxExporterDeployment(blackBoxImage, blackBoxNamespacedName, configHash, nodeLabel, fips, settings)
+	template := deployment.Spec.Template
+	// There's one pod per node already
+	template.Spec.TopologySpreadConstraints = nil
+	return appsv1.DaemonSet{
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: appsv1.DaemonSetSpec{
+			Selector: deployment.Spec.Selector,
+			Template: template,
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: deployment.Spec.Strategy.RollingUpdate.MaxUnavailable,
+					MaxSurge:       deployment.Spec.Strategy.RollingUpdate.MaxSurge,
+				},
+			},
+		},
+	}
+}
+
+// podSecurityContext returns the pod security context meeting the restricted Pod Security Standard, unless relaxed
+func podSecurityContext(relaxed bool, runAsUser *int64) *corev1.PodSecurityContext {
+	if relaxed {
+		return nil
+	}
+	nonRoot := true
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:   &nonRoot,
+		RunAsUser:      runAsUser,
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// containerSecurityContext returns the container security context meeting the restricted Pod Security Standard,
+// unless relaxed
+func containerSecurityContext(relaxed bool) *corev1.SecurityContext {
+	if relaxed {
+		return nil
+	}
+	escalation, readOnly := false, true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &escalation,
+		ReadOnlyRootFilesystem:   &readOnly,
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
+
+// fipsEnv returns the environment forcing an exporter built with a FIPS capable Go toolchain into FIPS mode
+func fipsEnv(fips bool) []corev1.EnvVar {
+	if !fips {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: blackboxexporter.BlackBoxExporterFIPSEnv, Value: "1"}}
+}
+
+// TemplateForBlackBoxExporterService returns a blackbox service
+func TemplateForBlackBoxExporterService(blackboxNamespacedName types.NamespacedName, servingCert bool) corev1.Service {
 	labels := blackboxexporter.GenerateBlackBoxExporterLables()
 
 	svc := corev1.Service{
@@ -242,22 +900,75 @@ func templateForBlackBoxExporterService(blackboxNamespacedName types.NamespacedN
 			}},
 		},
 	}
+	if servingCert {
+		svc.Annotations = map[string]string{blackboxexporter.ServingCertSecretAnnotation: blackboxexporter.BlackBoxExporterServingCertSecretName}
+	}
 	return svc
 }
 
-func templateForBlackBoxExporterConfigMap(blackboxNamespacedName types.NamespacedName) corev1.ConfigMap {
+// TemplateForBlackBoxExporterNetworkPolicy returns a NetworkPolicy allowing the scrapes of the exporter from the
+// Prometheus namespaces and the probes to the egress networks, denying any other traffic of the exporter pods
+func TemplateForBlackBoxExporterNetworkPolicy(blackboxNamespacedName types.NamespacedName, settings runtimeconfig.NetworkPolicy) networkingv1.NetworkPolicy {
+	labels := blackboxexporter.GenerateBlackBoxExporterLables()
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	exporterPort := intstr.FromString(blackboxexporter.BlackBoxExporterPortName)
+
+	ingress := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &exporterPort}},
+	}
+	if len(settings.PrometheusNamespaces) > 0 {
+		ingress.From = []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   settings.PrometheusNamespaces,
+				}},
+			},
+		}}
+	}
+
+	// Without egress networks every destination is allowed, which includes DNS
+	egress := []networkingv1.NetworkPolicyEgressRule{{}}
+	if len(settings.EgressCIDRs) > 0 {
+		probes := networkingv1.NetworkPolicyEgressRule{}
+		for _, cidr := range settings.EgressCIDRs {
+			probes.To = append(probes.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		// The cluster DNS listens on 53, or on 5353 as on OpenShift
+		dns := networkingv1.NetworkPolicyEgressRule{}
+		for _, port := range []int32{53, 5353} {
+			port := intstr.FromInt32(port)
+			dns.Ports = append(dns.Ports,
+				networkingv1.NetworkPolicyPort{Protocol: &udp, Port: &port},
+				networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+		}
+		egress = []networkingv1.NetworkPolicyEgressRule{probes, dns}
+	}
+
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxNamespacedName.Name,
+			Namespace: blackboxNamespacedName.Namespace,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{ingress},
+			Egress:      egress,
+		},
+	}
+}
+
+// TemplateForBlackBoxExporterConfigMap returns the blackbox configuration holding the modules
+func TemplateForBlackBoxExporterConfigMap(blackboxNamespacedName types.NamespacedName, modules map[string]Module, servingCert bool) (corev1.ConfigMap, error) {
 	labels := blackboxexporter.GenerateBlackBoxExporterLables()
 
-	cfg := `modules:
-  http_2xx:
-    prober: http
-    timeout: 15s
-  insecure_http_2xx:
-    prober: http
-    timeout: 15s
-    http:
-      tls_config:
-        insecure_skip_verify: true`
+	cfg, err := Config{Modules: modules}.Render()
+	if err != nil {
+		return corev1.ConfigMap{}, err
+	}
 
 	cm := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -266,10 +977,13 @@ func templateForBlackBoxExporterConfigMap(blackboxNamespacedName types.Namespace
 			Labels:    labels,
 		},
 		Data: map[string]string{
-			"blackbox.yaml": cfg,
+			blackboxexporter.BlackBoxExporterConfigFile: cfg,
 		},
 	}
-	return cm
+	if servingCert {
+		cm.Data[blackboxexporter.BlackBoxExporterWebConfigFile] = webConfig
+	}
+	return cm, nil
 }
 
 func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentAbsent() error {
@@ -293,6 +1007,23 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterDeploymentAbsent() error {
 	return nil
 }
 
+func (b *BlackBoxExporter) EnsureBlackBoxExporterDaemonSetAbsent() error {
+	resource := &appsv1.DaemonSet{}
+
+	// Does the resource already exist?
+	err := b.Client.Get(b.Ctx, b.NamespacedName, resource)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			// return unexpectedly
+			return err
+		}
+		// Resource doesn't exist, nothing to do
+		return nil
+	}
+	return b.Client.Delete(b.Ctx, resource)
+}
+
 func (b *BlackBoxExporter) EnsureBlackBoxExporterServiceAbsent() error {
 	resource := &corev1.Service{}
 
@@ -335,27 +1066,91 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterConfigMapAbsent() error {
 	return nil
 }
 
+func (b *BlackBoxExporter) EnsureBlackBoxExporterNetworkPolicyAbsent() error {
+	resource := &networkingv1.NetworkPolicy{}
+
+	// Does the resource already exist?
+	err := b.Client.Get(b.Ctx, b.NamespacedName, resource)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			// return unexpectedly
+			return err
+		}
+		// Resource doesn't exist, nothing to do
+		return nil
+	}
+	return b.Client.Delete(b.Ctx, resource)
+}
+
 func (b *BlackBoxExporter) EnsureBlackBoxExporterResourcesAbsent() error {
-	b.Log.V(2).Info("Entering EnsureBlackBoxExporterServiceAbsent")
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterServiceAbsent")
 	if err := b.EnsureBlackBoxExporterServiceAbsent(); err != nil {
 		return err
 	}
-	b.Log.V(2).Info("Entering EnsureBlackBoxExporterDeploymentAbsent")
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterDeploymentAbsent")
 	if err := b.EnsureBlackBoxExporterDeploymentAbsent(); err != nil {
 		return err
 	}
-	b.Log.V(2).Info("Entering EnsureBlackBoxExporterConfigMapAbsent")
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterDaemonSetAbsent")
+	if err := b.EnsureBlackBoxExporterDaemonSetAbsent(); err != nil {
+		return err
+	}
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterConfigMapAbsent")
 	if err := b.EnsureBlackBoxExporterConfigMapAbsent(); err != nil {
 		return err
 	}
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterNetworkPolicyAbsent")
+	if err := b.EnsureBlackBoxExporterNetworkPolicyAbsent(); err != nil {
+		return err
+	}
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterTrustedCABundleAbsent")
+	if err := b.EnsureBlackBoxExporterTrustedCABundleAbsent(); err != nil {
+		return err
+	}
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterHealthMonitoringAbsent")
+	if err := b.EnsureBlackBoxExporterHealthMonitoringAbsent(); err != nil {
+		return err
+	}
+	b.Log.V(logging.DebugVerbosity).Info("Entering EnsureBlackBoxExporterConfigAbsent")
+	if err := b.EnsureBlackBoxExporterConfigAbsent(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// ensureBlackBoxExporterPods runs the exporter as a deployment or as a daemonset, and removes the other one once the
+// exporter was switched between them
+func (b *BlackBoxExporter) ensureBlackBoxExporterPods(configHash string) error {
+	if b.deploymentSettings().DaemonSet {
+		if err := b.EnsureBlackBoxExporterDaemonSetExists(configHash); err != nil {
+			return err
+		}
+		return b.EnsureBlackBoxExporterDeploymentAbsent()
+	}
+	if err := b.EnsureBlackBoxExporterDeploymentExists(configHash); err != nil {
+		return err
+	}
+	return b.EnsureBlackBoxExporterDaemonSetAbsent()
+}
+
 func (b *BlackBoxExporter) EnsureBlackBoxExporterResourcesExist() error {
-	if err := b.EnsureBlackBoxExporterConfigMapExists(); err != nil {
+	modules, err := b.desiredModules()
+	if err != nil {
+		return err
+	}
+	configHash, err := b.ensureConfigMap(modules)
+	if err != nil {
+		return err
+	}
+	proxy, err := ClusterProxy(b.Ctx, b.Client)
+	if err != nil {
+		return err
+	}
+	if err := b.EnsureBlackBoxExporterTrustedCABundle(proxy); err != nil {
 		return err
 	}
-	if err := b.EnsureBlackBoxExporterDeploymentExists(); err != nil {
+	if err := b.ensureBlackBoxExporterPods(configHash); err != nil {
 		return err
 	}
 	// Creating Service after because:
@@ -364,5 +1159,14 @@ func (b *BlackBoxExporter) EnsureBlackBoxExporterResourcesExist() error {
 	if err := b.EnsureBlackBoxExporterServiceExists(); err != nil {
 		return err
 	}
+	if err := b.EnsureBlackBoxExporterNetworkPolicy(); err != nil {
+		return err
+	}
+	if err := b.EnsureBlackBoxExporterHealthMonitoring(); err != nil {
+		return err
+	}
+	if err := b.EnsureBlackBoxExporterConfigStatus(configHash, modules); err != nil {
+		return err
+	}
 	return nil
 }
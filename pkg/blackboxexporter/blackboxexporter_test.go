@@ -8,8 +8,14 @@ import (
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
 	consterror "github.com/openshift/route-monitor-operator/pkg/consts/test/error"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
 	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -185,7 +191,7 @@ var _ = Describe("Blackboxexporter", func() {
 	Describe("CreateBlackBoxExporterDeployment", func() {
 		BeforeEach(func() {
 			// Arrange
-			get.CalledTimes = 2
+			get.CalledTimes = 3
 		})
 
 		When("the resource(deployment) is Not Found", func() {
@@ -196,7 +202,7 @@ var _ = Describe("Blackboxexporter", func() {
 			})
 			It("should call `Get` successfully and `Create` the resource(deployment)", func() {
 				// Act
-				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists()
+				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists("")
 				// Assert
 				Expect(err).NotTo(HaveOccurred())
 			})
@@ -205,15 +211,31 @@ var _ = Describe("Blackboxexporter", func() {
 			// Arrange
 			BeforeEach(func() {
 				get.ErrorResponse = consterror.CustomError
+				// Reading the cluster proxy fails already
+				get.CalledTimes = 1
 			})
 			It("should return the error and not call `Create`", func() {
 				// Act
-				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists()
+				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists("")
 				// Assert
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(consterror.CustomError))
 			})
 		})
+		When("the resource(deployment) is Not Found and a field owner is set", func() {
+			// Arrange
+			BeforeEach(func() {
+				get.ErrorResponse = consterror.NotFoundErr
+				mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), client.Apply, client.FieldOwner("fake-owner"), client.ForceOwnership).Times(1)
+			})
+			It("should apply the resource(deployment) server-side instead of calling `Create`", func() {
+				// Act
+				blackboxExporter.FieldOwner = "fake-owner"
+				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists("")
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
 		When("the resource(deployment) Create fails unexpectedly", func() {
 			// Arrange
 			BeforeEach(func() {
@@ -222,7 +244,7 @@ var _ = Describe("Blackboxexporter", func() {
 			})
 			It("should call `Get` Successfully and call `Create` but return the error", func() {
 				// Act
-				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists()
+				err := blackboxExporter.EnsureBlackBoxExporterDeploymentExists("")
 				// Assert
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(consterror.CustomError))
@@ -231,12 +253,13 @@ var _ = Describe("Blackboxexporter", func() {
 	})
 	Describe("CreateBlackBoxExporterService", func() {
 
-		When("the resource(service) Exists", func() {
+		When("the resource(service) Exists without the spec hash", func() {
 			// Arrange
 			BeforeEach(func() {
 				get.CalledTimes = 1
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1)
 			})
-			It("should call `Get` and not call `Create`", func() {
+			It("should call `Get` and `Update` it rather than `Create`", func() {
 				// Act
 				err := blackboxExporter.EnsureBlackBoxExporterServiceExists()
 				// Assert
@@ -285,20 +308,161 @@ var _ = Describe("Blackboxexporter", func() {
 			})
 		})
 	})
+	Describe("EnsureBlackBoxExporterNetworkPolicy", func() {
+		When("the NetworkPolicy is disabled and doesn't exist", func() {
+			BeforeEach(func() {
+				get = helper.NotFoundErrorHappensOnce()
+			})
+			It("doesn't create it", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterNetworkPolicy()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("the NetworkPolicy is disabled but exists", func() {
+			BeforeEach(func() {
+				get.CalledTimes = 1
+				delete.CalledTimes = 1
+			})
+			It("deletes it", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterNetworkPolicy()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("the NetworkPolicy is enabled and doesn't exist", func() {
+			BeforeEach(func() {
+				get = helper.NotFoundErrorHappensOnce()
+				create.CalledTimes = 1
+			})
+			It("creates it", func() {
+				blackboxExporter.NetworkPolicy = runtimeconfig.NetworkPolicy{Enabled: true}
+				err := blackboxExporter.EnsureBlackBoxExporterNetworkPolicy()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+	Describe("EnsureBlackBoxExporterHealthMonitoring", func() {
+		When("the ServiceMonitor and the PrometheusRule don't exist", func() {
+			BeforeEach(func() {
+				get.CalledTimes = 2
+				get.ErrorResponse = consterror.NotFoundErr
+				create.CalledTimes = 2
+			})
+			It("creates them", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterHealthMonitoring()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("the exporter is deleted", func() {
+			BeforeEach(func() {
+				get.CalledTimes = 2
+				delete.CalledTimes = 2
+			})
+			It("deletes them", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterHealthMonitoringAbsent()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+	Describe("EnsureBlackBoxExporterTrustedCABundle", func() {
+		proxy := Proxy{HTTPSProxy: "http://proxy.example.com:3128"}
+		When("the cluster has no proxy and the bundle exists", func() {
+			BeforeEach(func() {
+				get.CalledTimes = 1
+				delete.CalledTimes = 1
+			})
+			It("deletes it", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterTrustedCABundle(Proxy{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("the cluster has a proxy and the bundle doesn't exist", func() {
+			BeforeEach(func() {
+				get = helper.NotFoundErrorHappensOnce()
+				create.CalledTimes = 1
+			})
+			It("creates it", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterTrustedCABundle(proxy)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("the cluster has a proxy and the bundle was injected", func() {
+			BeforeEach(func() {
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+					SetArg(2, TemplateForBlackBoxExporterTrustedCABundle(types.NamespacedName{})).Return(nil)
+			})
+			It("leaves it alone", func() {
+				err := blackboxExporter.EnsureBlackBoxExporterTrustedCABundle(proxy)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetBlackBoxExporterRollout", func() {
+		var deployment appsv1.Deployment
+		BeforeEach(func() {
+			replicas := int32(2)
+			deployment = appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			}
+		})
+		rollout := func() (string, error) {
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&appsv1.Deployment{})).
+				SetArg(2, deployment).Return(nil)
+			return blackboxExporter.GetBlackBoxExporterRollout()
+		}
+		It("is empty once every pod is up to date and available", func() {
+			Expect(rollout()).To(BeEmpty())
+		})
+		It("describes the pods that aren't up to date yet", func() {
+			deployment.Status.UpdatedReplicas = 1
+			Expect(rollout()).To(Equal("1 of 2 blackbox exporter pods are up to date"))
+		})
+		It("waits for the outdated pods to terminate", func() {
+			deployment.Status.Replicas = 3
+			Expect(rollout()).To(Equal("1 outdated blackbox exporter pods are terminating"))
+		})
+		It("waits for the up to date pods to be available", func() {
+			deployment.Status.AvailableReplicas = 1
+			Expect(rollout()).To(Equal("1 of 2 up to date blackbox exporter pods are available"))
+		})
+		It("waits for the deployment controller to see the change", func() {
+			deployment.Generation = 4
+			Expect(rollout()).To(Equal("Waiting for the rollout of the blackbox exporter to start"))
+		})
+		It("describes the rollout of the daemonset in daemonset mode", func() {
+			blackboxExporter.Deployment.DaemonSet = true
+			daemonSet := appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2}}
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DaemonSet{})).
+				SetArg(2, daemonSet).Return(nil)
+			Expect(blackboxExporter.GetBlackBoxExporterRollout()).To(Equal("2 of 3 up to date blackbox exporter pods are available"))
+		})
+	})
+
 	Describe("ShouldDeleteBlackBoxExporterResources", func() {
 		var (
-			routeMonitor       v1alpha1.RouteMonitor
-			routeMonitors      v1alpha1.RouteMonitorList
-			clusterUrlMonitors v1alpha1.ClusterUrlMonitorList
+			routeMonitor        v1alpha1.RouteMonitor
+			routeMonitors       v1alpha1.RouteMonitorList
+			clusterUrlMonitors  v1alpha1.ClusterUrlMonitorList
+			urlMonitors         v1alpha1.UrlMonitorList
+			namespaceMonitors   v1alpha1.NamespaceMonitorList
+			certificateMonitors v1alpha1.CertificateMonitorList
 		)
 		JustBeforeEach(func() {
 			gomock.InOrder(
 				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, routeMonitors).Times(1),
-				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, clusterUrlMonitors).AnyTimes(),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, clusterUrlMonitors).MaxTimes(1),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, urlMonitors).MaxTimes(1),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, namespaceMonitors).MaxTimes(1),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(list.ErrorResponse).SetArg(1, certificateMonitors).AnyTimes(),
 			)
 		})
 		BeforeEach(func() {
 			list.CalledTimes = 2
+			urlMonitors = v1alpha1.UrlMonitorList{}
+			namespaceMonitors = v1alpha1.NamespaceMonitorList{}
+			certificateMonitors = v1alpha1.CertificateMonitorList{}
 		})
 
 		JustBeforeEach(func() {
@@ -370,6 +534,233 @@ var _ = Describe("Blackboxexporter", func() {
 			})
 
 		})
+
+		When("there is just one UrlMonitor, and it's being deleted", func() {
+			BeforeEach(func() {
+				routeMonitors.Items = []v1alpha1.RouteMonitor{}
+				clusterUrlMonitors.Items = []v1alpha1.ClusterUrlMonitor{}
+				urlMonitors.Items = []v1alpha1.UrlMonitor{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "fake-url-monitor",
+							Namespace:         "fake-url-monitor-namespace",
+							DeletionTimestamp: &metav1.Time{Time: time.Unix(0, 0)},
+						},
+					},
+				}
+			})
+			It("should return 'true'", func() {
+				res, err := blackboxExporter.ShouldDeleteBlackBoxExporterResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(blackboxexporter.DeleteBlackBoxExporter))
+			})
+		})
+
+		When("there is just one NamespaceMonitor, and it's being deleted", func() {
+			BeforeEach(func() {
+				routeMonitors.Items = []v1alpha1.RouteMonitor{}
+				clusterUrlMonitors.Items = []v1alpha1.ClusterUrlMonitor{}
+				namespaceMonitors.Items = []v1alpha1.NamespaceMonitor{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "fake-namespace-monitor",
+							Namespace:         "fake-namespace-monitor-namespace",
+							DeletionTimestamp: &metav1.Time{Time: time.Unix(0, 0)},
+						},
+					},
+				}
+			})
+			It("should return 'true'", func() {
+				res, err := blackboxExporter.ShouldDeleteBlackBoxExporterResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(blackboxexporter.DeleteBlackBoxExporter))
+			})
+		})
+
+		When("there is just one CertificateMonitor, and it's being deleted", func() {
+			BeforeEach(func() {
+				routeMonitors.Items = []v1alpha1.RouteMonitor{}
+				clusterUrlMonitors.Items = []v1alpha1.ClusterUrlMonitor{}
+				certificateMonitors.Items = []v1alpha1.CertificateMonitor{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "fake-certificate-monitor",
+							Namespace:         "fake-certificate-monitor-namespace",
+							DeletionTimestamp: &metav1.Time{Time: time.Unix(0, 0)},
+						},
+					},
+				}
+			})
+			It("should return 'true'", func() {
+				res, err := blackboxExporter.ShouldDeleteBlackBoxExporterResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(blackboxexporter.DeleteBlackBoxExporter))
+			})
+		})
+
+		When("the RouteMonitor being deleted is the last one of its namespace asking for a dedicated exporter", func() {
+			BeforeEach(func() {
+				clusterUrlMonitors.Items = []v1alpha1.ClusterUrlMonitor{}
+				routeMonitors.Items = []v1alpha1.RouteMonitor{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "fake-route-monitor",
+							Namespace:         "fake-route-monitor-namespace",
+							DeletionTimestamp: &metav1.Time{Time: time.Unix(0, 0)},
+						},
+						Spec: v1alpha1.RouteMonitorSpec{DedicatedExporter: true},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "fake-shared-route-monitor", Namespace: "fake-route-monitor-namespace"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "fake-other-route-monitor", Namespace: "fake-other-namespace"},
+						Spec:       v1alpha1.RouteMonitorSpec{DedicatedExporter: true},
+					},
+				}
+			})
+			It("should delete the dedicated exporter of the namespace", func() {
+				res, err := blackboxExporter.DedicatedTo("fake-route-monitor-namespace").ShouldDeleteBlackBoxExporterResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(blackboxexporter.DeleteBlackBoxExporter))
+			})
+			It("should keep the dedicated exporters of the other namespaces", func() {
+				res, err := blackboxExporter.DedicatedTo("fake-other-namespace").ShouldDeleteBlackBoxExporterResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(blackboxexporter.KeepBlackBoxExporter))
+			})
+		})
+	})
+
+})
+
+var _ = Describe("TemplateForBlackBoxExporterDeployment", func() {
+	namespacedName := types.NamespacedName{Name: "blackbox-exporter", Namespace: "fake-namespace"}
+
+	It("runs a single pod without requests or limits by default", func() {
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{})
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{}))
+	})
+
+	It("sizes the deployment by the settings", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false,
+			DeploymentSettings{Replicas: 3, Resources: resources})
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(resources))
+	})
+
+	It("sets the priority class of the pods", func() {
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{})
+		Expect(deployment.Spec.Template.Spec.PriorityClassName).To(BeEmpty())
+
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false,
+			DeploymentSettings{PriorityClassName: "system-cluster-critical"})
+		Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("system-cluster-critical"))
+	})
+
+	It("pulls the image with the pull secrets", func() {
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{})
+		Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(BeEmpty())
+
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false,
+			DeploymentSettings{ImagePullSecrets: []string{"mirror-pull-secret"}})
+		Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "mirror-pull-secret"}}))
+	})
+
+	It("meets the restricted Pod Security Standard unless relaxed", func() {
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{})
+		pod := deployment.Spec.Template.Spec
+		Expect(*pod.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(pod.SecurityContext.RunAsUser).To(BeNil())
+		Expect(pod.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+		container := pod.Containers[0].SecurityContext
+		Expect(*container.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(*container.ReadOnlyRootFilesystem).To(BeTrue())
+		Expect(container.Capabilities.Drop).To(Equal([]corev1.Capability{"ALL"}))
+
+		uid := int64(65534)
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{RunAsUser: &uid})
+		Expect(*deployment.Spec.Template.Spec.SecurityContext.RunAsUser).To(Equal(uid))
+
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{RelaxedSecurity: true})
+		Expect(deployment.Spec.Template.Spec.SecurityContext).To(BeNil())
+		Expect(deployment.Spec.Template.Spec.Containers[0].SecurityContext).To(BeNil())
+	})
+
+	It("spreads the pods over the zones and nodes only when asked to", func() {
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{})
+		Expect(deployment.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "", InfraNodeLabel, false, DeploymentSettings{ZoneSpread: true})
+		constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0].TopologyKey).To(Equal(corev1.LabelTopologyZone))
+		Expect(constraints[1].TopologyKey).To(Equal(corev1.LabelHostname))
+		for _, constraint := range constraints {
+			Expect(constraint.WhenUnsatisfiable).To(Equal(corev1.ScheduleAnyway))
+			Expect(constraint.LabelSelector.MatchLabels).To(Equal(deployment.Spec.Selector.MatchLabels))
+		}
+	})
+
+	It("runs the pods of the deployment on every node as a daemonset", func() {
+		settings := DeploymentSettings{Replicas: 3, ZoneSpread: true, DaemonSet: true}
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		daemonSet := TemplateForBlackBoxExporterDaemonSet("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		Expect(daemonSet.ObjectMeta).To(Equal(deployment.ObjectMeta))
+		Expect(daemonSet.Spec.Selector).To(Equal(deployment.Spec.Selector))
+		Expect(daemonSet.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+		Expect(daemonSet.Spec.Template.Spec.Containers).To(Equal(deployment.Spec.Template.Spec.Containers))
+		Expect(daemonSet.Spec.Template.Annotations).To(Equal(deployment.Spec.Template.Annotations))
+	})
+
+	It("serves the probes over TLS with the serving certificate", func() {
+		settings := DeploymentSettings{Replicas: 1, ServingCert: true}
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Args).To(ContainElement("--web.config.file=/config/" + blackboxexporter.BlackBoxExporterWebConfigFile))
+		Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", "/etc/tls/private")))
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.Secret.SecretName", blackboxexporter.BlackBoxExporterServingCertSecretName)))
+
+		service := TemplateForBlackBoxExporterService(namespacedName, true)
+		Expect(service.Annotations).To(HaveKeyWithValue(blackboxexporter.ServingCertSecretAnnotation, blackboxexporter.BlackBoxExporterServingCertSecretName))
 	})
 
+	It("probes through the cluster proxy trusting its CA bundle", func() {
+		settings := DeploymentSettings{Replicas: 1, Proxy: Proxy{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: ".svc"}}
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Env).To(ConsistOf(
+			corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+			corev1.EnvVar{Name: "NO_PROXY", Value: ".svc"},
+			corev1.EnvVar{Name: "SSL_CERT_FILE", Value: "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"},
+		))
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.ConfigMap.Name", blackboxexporter.BlackBoxExporterTrustedCABundleName)))
+
+		direct := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, DeploymentSettings{Replicas: 1})
+		Expect(direct.Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+		Expect(direct.Spec.Template.Spec.Volumes).To(HaveLen(1))
+	})
+	It("opts the pods in or out of the service mesh over the user's annotations", func() {
+		settings := DeploymentSettings{Replicas: 1, PodAnnotations: map[string]string{blackboxexporter.SidecarInjectAnnotation: "true"}}
+		deployment := TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(blackboxexporter.SidecarInjectAnnotation, "true"))
+
+		settings.ServiceMesh = blackboxexporter.ServiceMeshExclude
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(blackboxexporter.SidecarInjectAnnotation, "false"))
+		serviceMonitor := TemplateForBlackBoxExporterServiceMonitor(namespacedName, settings)
+		Expect(serviceMonitor.Spec.Endpoints[0].Scheme).To(Equal("http"))
+
+		settings.ServiceMesh = blackboxexporter.ServiceMeshInject
+		deployment = TemplateForBlackBoxExporterDeployment("fake-image", namespacedName, "fake-hash", InfraNodeLabel, false, settings)
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(blackboxexporter.SidecarInjectAnnotation, "true"))
+		serviceMonitor = TemplateForBlackBoxExporterServiceMonitor(namespacedName, settings)
+		Expect(serviceMonitor.Spec.Endpoints[0].Scheme).To(Equal("https"))
+		Expect(serviceMonitor.Spec.Endpoints[0].TLSConfig.CertFile).To(Equal(blackboxexporter.MeshCertsDir + "/cert-chain.pem"))
+	})
 })
@@ -0,0 +1,96 @@
+package blackboxexporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+)
+
+const (
+	// debugProbeTimeout bounds a debug probe, the exporter's own timeout of the module applies within it
+	debugProbeTimeout = 30 * time.Second
+	// maxDebugOutput is how much of the exporter's debug output is read, which also holds the module's configuration
+	maxDebugOutput = 1 << 20
+
+	debugLogsHeader    = "Logs for the probe:"
+	debugMetricsHeader = "Metrics that would have been returned:"
+)
+
+// DebugBlackBoxExporterProbe probes the url with the module through the exporter's debug output, which logs every step
+// of the probe regardless of the exporter's log level. It returns whether the probe succeeded and its log
+func (b *BlackBoxExporter) DebugBlackBoxExporterProbe(url, module string) (bool, string, error) {
+	scheme := "http"
+	if b.deploymentSettings().ServingCert {
+		scheme = "https"
+	}
+	endpoint := neturl.URL{
+		Scheme:   scheme,
+		Host:     fmt.Sprintf("%s.%s.svc:%d", b.NamespacedName.Name, b.NamespacedName.Namespace, blackboxexporter.BlackBoxExporterPortNumber),
+		Path:     "/probe",
+		RawQuery: neturl.Values{"target": {url}, "module": {module}, "debug": {"true"}}.Encode(),
+	}
+	httpClient, err := b.httpClient()
+	if err != nil {
+		return false, "", err
+	}
+	request, err := http.NewRequestWithContext(b.Ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return false, "", err
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return false, "", err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxDebugOutput))
+	if err != nil {
+		return false, "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, "", fmt.Errorf("the blackbox exporter answered the debug probe with %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	success, transcript := ParseDebugOutput(string(body))
+	return success, transcript, nil
+}
+
+// httpClient returns the client the debug probes are sent with. With a serving certificate, the exporter is verified
+// with the service CA of the operator's pod
+func (b *BlackBoxExporter) httpClient() (*http.Client, error) {
+	if b.HTTPClient != nil {
+		return b.HTTPClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if b.deploymentSettings().ServingCert {
+		bundle, err := os.ReadFile(blackboxexporter.PodServiceCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the service CA the blackbox exporter is verified with: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificate found in %s", blackboxexporter.PodServiceCAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport, Timeout: debugProbeTimeout}, nil
+}
+
+// ParseDebugOutput parses the debug output of a probe of the exporter, made up of the log of the probe, the metrics it
+// would have returned and the configuration of the module. It returns whether the probe succeeded and its log
+func ParseDebugOutput(output string) (bool, string) {
+	logs, metrics, _ := strings.Cut(output, debugMetricsHeader)
+	logs = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(logs), debugLogsHeader))
+	for _, line := range strings.Split(metrics, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "probe_success "); ok {
+			return strings.TrimSpace(value) == "1", logs
+		}
+	}
+	return false, logs
+}
@@ -0,0 +1,96 @@
+package blackboxexporter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+)
+
+// debugOutput is the debug output of a probe that failed, as the exporter returns it
+const debugOutput = `Logs for the probe:
+ts=2026-10-15T10:00:00.000Z caller=main.go:190 module=http_2xx target=https://shop.example.com level=info msg="Beginning probe" probe=http timeout_seconds=9.5
+ts=2026-10-15T10:00:00.100Z caller=http.go:481 module=http_2xx target=https://shop.example.com level=error msg="Error for HTTP request" err="tls: failed to verify certificate"
+ts=2026-10-15T10:00:00.100Z caller=main.go:190 module=http_2xx target=https://shop.example.com level=error msg="Probe failed" duration_seconds=0.1
+
+
+
+Metrics that would have been returned:
+# HELP probe_success Displays whether or not the probe was a success
+# TYPE probe_success gauge
+probe_success 0
+
+
+
+Module configuration:
+prober: http
+`
+
+// rewriteHost sends the requests to the server instead of their host
+type rewriteHost struct {
+	server *httptest.Server
+}
+
+func (r rewriteHost) RoundTrip(request *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(r.server.URL)
+	request.URL.Scheme, request.URL.Host = target.Scheme, target.Host
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+var _ = Describe("DebugBlackBoxExporterProbe", func() {
+	var (
+		blackboxExporter *BlackBoxExporter
+		server           *httptest.Server
+		requests         []*http.Request
+		status           int
+		output           string
+	)
+	BeforeEach(func() {
+		requests, status, output = nil, http.StatusOK, debugOutput
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(output))
+		}))
+		blackboxExporter = New(fake.NewClientBuilder().WithScheme(constinit.Scheme).Build(), logr.Discard(), context.Background(), "fake-image", "fake-namespace")
+		blackboxExporter.HTTPClient = &http.Client{Transport: rewriteHost{server: server}}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the log of a failing probe", func() {
+		success, transcript, err := blackboxExporter.DebugBlackBoxExporterProbe("https://shop.example.com", "http_2xx")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(success).To(BeFalse())
+		Expect(transcript).To(HavePrefix("ts=2026-10-15T10:00:00.000Z"))
+		Expect(transcript).To(HaveSuffix(`msg="Probe failed" duration_seconds=0.1`))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Host).To(Equal("blackbox-exporter.fake-namespace.svc:9115"))
+		Expect(requests[0].URL.Path).To(Equal("/probe"))
+		Expect(requests[0].URL.Query()).To(Equal(url.Values{"target": {"https://shop.example.com"}, "module": {"http_2xx"}, "debug": {"true"}}))
+	})
+
+	It("reports a successful probe", func() {
+		output = "Logs for the probe:\nmsg=\"Probe succeeded\"\n\nMetrics that would have been returned:\nprobe_success 1\n"
+		success, transcript, err := blackboxExporter.DebugBlackBoxExporterProbe("https://shop.example.com", "http_2xx")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(success).To(BeTrue())
+		Expect(transcript).To(Equal(`msg="Probe succeeded"`))
+	})
+
+	It("fails if the exporter rejects the probe", func() {
+		status, output = http.StatusBadRequest, "Unknown module \"http_2xx\"\n"
+		_, _, err := blackboxExporter.DebugBlackBoxExporterProbe("https://shop.example.com", "http_2xx")
+		Expect(err).To(MatchError(ContainSubstring(`Unknown module "http_2xx"`)))
+	})
+})
@@ -0,0 +1,36 @@
+package blackboxexporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stampSpecHash annotates the template with a digest of what it renders and returns it, so a changed template is
+// told apart from the defaults the API server adds to the deployed resource
+func stampSpecHash(template client.Object) string {
+	// Templates are plain structs, they always marshal
+	rendered, _ := json.Marshal(template)
+	sum := sha256.Sum256(rendered)
+	hash := hex.EncodeToString(sum[:])
+
+	annotations := template.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[blackboxexporter.BlackBoxExporterSpecHashAnnotation] = hash
+	template.SetAnnotations(annotations)
+	return hash
+}
+
+// drifted reports whether the deployed resource has to be updated: its template changed since it was applied, or a
+// field set by the template was changed by hand, e.g. the image or the ports. The fields the template leaves unset
+// hold the API server's defaults and are ignored
+func drifted(deployed client.Object, deployedSpec, templateSpec interface{}, hash string) bool {
+	return deployed.GetAnnotations()[blackboxexporter.BlackBoxExporterSpecHashAnnotation] != hash ||
+		!equality.Semantic.DeepDerivative(templateSpec, deployedSpec)
+}
@@ -0,0 +1,80 @@
+package blackboxexporter_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+)
+
+var _ = Describe("Drift", func() {
+	var (
+		kclient          client.Client
+		blackboxExporter *BlackBoxExporter
+		namespacedName   = types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-namespace"}
+	)
+	BeforeEach(func() {
+		kclient = fake.NewClientBuilder().WithScheme(constinit.Scheme).Build()
+		blackboxExporter = New(kclient, logr.Discard(), context.Background(), "fake-image", namespacedName.Namespace)
+	})
+
+	It("restores a hand-edited image of the deployment", func() {
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("fake-hash")).To(Succeed())
+		deployment := appsv1.Deployment{}
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		Expect(deployment.Annotations).To(HaveKey(blackboxexporter.BlackBoxExporterSpecHashAnnotation))
+
+		deployment.Spec.Template.Spec.Containers[0].Image = "other-image"
+		Expect(kclient.Update(context.Background(), &deployment)).To(Succeed())
+
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("fake-hash")).To(Succeed())
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("fake-image"))
+	})
+
+	It("leaves the deployment alone unless it drifted", func() {
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("fake-hash")).To(Succeed())
+		deployment := appsv1.Deployment{}
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		// A default of the API server isn't drift
+		deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		Expect(kclient.Update(context.Background(), &deployment)).To(Succeed())
+		version := deployment.ResourceVersion
+
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("fake-hash")).To(Succeed())
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		Expect(deployment.ResourceVersion).To(Equal(version))
+	})
+
+	It("updates the deployment once its template changed", func() {
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("fake-hash")).To(Succeed())
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists("other-hash")).To(Succeed())
+		deployment := appsv1.Deployment{}
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(blackboxexporter.BlackBoxExporterConfigHashAnnotation, "other-hash"))
+	})
+
+	It("restores a removed port of the service and keeps its cluster IP", func() {
+		Expect(blackboxExporter.EnsureBlackBoxExporterServiceExists()).To(Succeed())
+		service := corev1.Service{}
+		Expect(kclient.Get(context.Background(), namespacedName, &service)).To(Succeed())
+		service.Spec.ClusterIP = "172.30.0.10"
+		service.Spec.Ports = nil
+		Expect(kclient.Update(context.Background(), &service)).To(Succeed())
+
+		Expect(blackboxExporter.EnsureBlackBoxExporterServiceExists()).To(Succeed())
+		Expect(kclient.Get(context.Background(), namespacedName, &service)).To(Succeed())
+		Expect(service.Spec.Ports).To(ConsistOf(HaveField("Name", blackboxexporter.BlackBoxExporterPortName)))
+		Expect(service.Spec.ClusterIP).To(Equal("172.30.0.10"))
+	})
+})
@@ -0,0 +1,41 @@
+package blackboxexporter
+
+import (
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+)
+
+// External stands in for the exporter of the monitors probed by an exporter run by the user, which has nothing to be
+// deployed, rolled out or deleted
+type External struct{}
+
+func (External) EnsureBlackBoxExporterResourcesExist() error {
+	return nil
+}
+
+func (External) EnsureBlackBoxExporterResourcesAbsent() error {
+	return nil
+}
+
+func (External) ShouldDeleteBlackBoxExporterResources() (blackboxexporter.ShouldDeleteBlackBoxExporter, error) {
+	return blackboxexporter.KeepBlackBoxExporter, nil
+}
+
+// GetBlackBoxExporterNamespace is empty, the namespace of the external exporter is part of the monitor's spec
+func (External) GetBlackBoxExporterNamespace() string {
+	return ""
+}
+
+func (External) GetBlackBoxExporterRollout() (string, error) {
+	return "", nil
+}
+
+// ServesBlackBoxExporterModule trusts the external exporter to know the module named by the monitor
+func (External) ServesBlackBoxExporterModule(module string) (bool, error) {
+	return true, nil
+}
+
+// DebugBlackBoxExporterProbe reports the probes of the external exporter as successful, they are left to the user
+// running it to debug
+func (External) DebugBlackBoxExporterProbe(url, module string) (bool, string, error) {
+	return true, "", nil
+}
@@ -0,0 +1,143 @@
+package blackboxexporter
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplateForBlackBoxExporterServiceMonitor returns the ServiceMonitor scraping the exporter's own metrics, with a job
+// of its own so they are told apart from the probes scraped through the same Service. They are scraped over https with
+// a serving certificate or the mesh's sidecar
+func TemplateForBlackBoxExporterServiceMonitor(blackboxNamespacedName types.NamespacedName, settings DeploymentSettings) monitoringv1.ServiceMonitor {
+	labels := blackboxexporter.GenerateBlackBoxExporterLables()
+	endpoint := monitoringv1.Endpoint{
+		Port:     blackboxexporter.BlackBoxExporterPortName,
+		Path:     "/metrics",
+		Scheme:   "http",
+		Interval: "30s",
+		RelabelConfigs: []*monitoringv1.RelabelConfig{
+			{
+				Replacement: blackboxexporter.BlackBoxExporterHealthName,
+				TargetLabel: "job",
+			},
+		},
+	}
+	if settings.ServingCert {
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = &monitoringv1.TLSConfig{
+			CAFile: blackboxexporter.ServiceCAFile,
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				ServerName: fmt.Sprintf("%s.%s.svc", blackboxNamespacedName.Name, blackboxNamespacedName.Namespace),
+			},
+		}
+	} else if settings.MeshSidecar() {
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = meshTLSConfig()
+	}
+	return monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxexporter.BlackBoxExporterHealthName,
+			Namespace: blackboxNamespacedName.Namespace,
+			Labels:    labels,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Endpoints: []monitoringv1.Endpoint{endpoint},
+			Selector:  metav1.LabelSelector{MatchLabels: labels},
+			NamespaceSelector: monitoringv1.NamespaceSelector{
+				MatchNames: []string{blackboxNamespacedName.Namespace},
+			},
+		},
+	}
+}
+
+// meshTLSConfig returns the TLS configuration scraping the exporter through its mesh sidecar with the mesh's
+// certificates mounted into Prometheus. The sidecar presents the workload identity of the exporter rather than a
+// certificate of its Service, so the server name isn't verified
+func meshTLSConfig() *monitoringv1.TLSConfig {
+	return &monitoringv1.TLSConfig{
+		CAFile:        blackboxexporter.MeshCertsDir + "/root-cert.pem",
+		CertFile:      blackboxexporter.MeshCertsDir + "/cert-chain.pem",
+		KeyFile:       blackboxexporter.MeshCertsDir + "/key.pem",
+		SafeTLSConfig: monitoringv1.SafeTLSConfig{InsecureSkipVerify: true},
+	}
+}
+
+// EnsureBlackBoxExporterHealthMonitoring creates or updates the ServiceMonitor scraping the exporter's own metrics and
+// the PrometheusRule alerting on them. Clusters without the CRDs of the Prometheus operator are left without
+func (b *BlackBoxExporter) EnsureBlackBoxExporterHealthMonitoring() error {
+	serviceMonitor := TemplateForBlackBoxExporterServiceMonitor(b.NamespacedName, b.deploymentSettings())
+	if err := b.ensureHealthResource(&serviceMonitor, &monitoringv1.ServiceMonitor{}, monitoringv1.SchemeGroupVersion.WithKind("ServiceMonitor"), func(deployed client.Object) bool {
+		resource := deployed.(*monitoringv1.ServiceMonitor)
+		if reflect.DeepEqual(resource.Spec, serviceMonitor.Spec) {
+			return false
+		}
+		resource.Spec = serviceMonitor.Spec
+		return true
+	}); err != nil {
+		return err
+	}
+
+	rule := alert.TemplateForBlackBoxExporterPrometheusRule(b.NamespacedName.Namespace)
+	return b.ensureHealthResource(&rule, &monitoringv1.PrometheusRule{}, monitoringv1.SchemeGroupVersion.WithKind("PrometheusRule"), func(deployed client.Object) bool {
+		resource := deployed.(*monitoringv1.PrometheusRule)
+		if reflect.DeepEqual(resource.Spec, rule.Spec) {
+			return false
+		}
+		resource.Spec = rule.Spec
+		return true
+	})
+}
+
+// ensureHealthResource creates the template, or updates the deployed resource if copySpec changed it
+func (b *BlackBoxExporter) ensureHealthResource(template, deployed client.Object, gvk schema.GroupVersionKind, copySpec func(deployed client.Object) bool) error {
+	if err := b.Client.Get(b.Ctx, client.ObjectKeyFromObject(template), deployed); err != nil {
+		if meta.IsNoMatchError(err) {
+			b.Log.V(logging.DebugVerbosity).Info("Skipped monitoring the health of the BlackBoxExporter: the CRD isn't installed", "kind", gvk.Kind)
+			return nil
+		}
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if b.FieldOwner != "" {
+			return b.apply(template, gvk)
+		}
+		return b.Client.Create(b.Ctx, template)
+	}
+
+	if !copySpec(deployed) {
+		return nil
+	}
+	if b.FieldOwner != "" {
+		return b.apply(template, gvk)
+	}
+	return b.Client.Update(b.Ctx, deployed)
+}
+
+func (b *BlackBoxExporter) EnsureBlackBoxExporterHealthMonitoringAbsent() error {
+	key := types.NamespacedName{Name: blackboxexporter.BlackBoxExporterHealthName, Namespace: b.NamespacedName.Namespace}
+	for _, resource := range []client.Object{&monitoringv1.ServiceMonitor{}, &monitoringv1.PrometheusRule{}} {
+		// Does the resource already exist?
+		if err := b.Client.Get(b.Ctx, key, resource); err != nil {
+			// Resource or its CRD doesn't exist, nothing to do
+			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		if err := b.Client.Delete(b.Ctx, resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
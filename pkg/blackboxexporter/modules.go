@@ -0,0 +1,312 @@
+package blackboxexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+
+	exporterconfig "github.com/prometheus/blackbox_exporter/config"
+	yamlv3 "gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the subset of the blackbox exporter configuration file managed by the operator
+type Config struct {
+	Modules map[string]Module `json:"modules"`
+}
+
+// Module is a single blackbox exporter module
+type Module struct {
+	Prober  string     `json:"prober"`
+	Timeout string     `json:"timeout"`
+	HTTP    *HTTPProbe `json:"http,omitempty"`
+	TCP     *TCPProbe  `json:"tcp,omitempty"`
+}
+
+// HTTPProbe holds the settings of the blackbox exporter http prober
+type HTTPProbe struct {
+	FollowRedirects        *bool             `json:"follow_redirects,omitempty"`
+	PreferredIPProtocol    string            `json:"preferred_ip_protocol,omitempty"`
+	IPProtocolFallback     *bool             `json:"ip_protocol_fallback,omitempty"`
+	ProxyURL               string            `json:"proxy_url,omitempty"`
+	ProxyFromEnvironment   bool              `json:"proxy_from_environment,omitempty"`
+	ValidHTTPVersions      []string          `json:"valid_http_versions,omitempty"`
+	BodySizeLimit          string            `json:"body_size_limit,omitempty"`
+	Compression            string            `json:"compression,omitempty"`
+	Headers                map[string]string `json:"headers,omitempty"`
+	FailIfHeaderMatches    []HeaderMatch     `json:"fail_if_header_matches,omitempty"`
+	FailIfHeaderNotMatches []HeaderMatch     `json:"fail_if_header_not_matches,omitempty"`
+	TLSConfig              *TLSConfig        `json:"tls_config,omitempty"`
+}
+
+// TCPProbe holds the settings of the blackbox exporter tcp prober
+type TCPProbe struct {
+	TLS       bool       `json:"tls,omitempty"`
+	TLSConfig *TLSConfig `json:"tls_config,omitempty"`
+}
+
+// HeaderMatch is an assertion on a response header of the http prober
+type HeaderMatch struct {
+	Header       string `json:"header"`
+	Regexp       string `json:"regexp"`
+	AllowMissing bool   `json:"allow_missing,omitempty"`
+}
+
+// TLSConfig holds the TLS settings used by a prober
+type TLSConfig struct {
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	MinVersion         string `json:"min_version,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+}
+
+// DefaultModules returns the modules that are always present in the exporter configuration
+func DefaultModules() map[string]Module {
+	return map[string]Module{
+		blackboxexporter.BlackBoxExporterDefaultModule: {
+			Prober:  "http",
+			Timeout: "15s",
+		},
+		blackboxexporter.BlackBoxExporterInsecureModule: {
+			Prober:  "http",
+			Timeout: "15s",
+			HTTP: &HTTPProbe{
+				TLSConfig: &TLSConfig{InsecureSkipVerify: true},
+			},
+		},
+	}
+}
+
+// ModuleFor returns the name and definition of the module a monitor's target has to be probed with.
+// Monitors that don't customize their probe share one of the default modules, every other
+// combination of settings gets its own module named after a hash of its definition
+func ModuleFor(insecureSkipTLSVerify bool, probe *v1alpha1.HTTPProbeSpec) (string, Module) {
+	name := blackboxexporter.BlackBoxExporterDefaultModule
+	if insecureSkipTLSVerify {
+		name = blackboxexporter.BlackBoxExporterInsecureModule
+	}
+	defaultModule := DefaultModules()[name]
+	if probe == nil {
+		return name, defaultModule
+	}
+
+	module := DefaultModules()[name]
+	http := &HTTPProbe{}
+	if module.HTTP != nil {
+		http = module.HTTP
+	}
+	if probe.FollowRedirects != nil && !*probe.FollowRedirects {
+		followRedirects := false
+		http.FollowRedirects = &followRedirects
+	}
+	// ip6 is already preferred by the exporter
+	if probe.PreferredIPProtocol == v1alpha1.IPProtocolIP4 {
+		http.PreferredIPProtocol = string(probe.PreferredIPProtocol)
+	}
+	if probe.IPProtocolFallback != nil && !*probe.IPProtocolFallback {
+		ipProtocolFallback := false
+		http.IPProtocolFallback = &ipProtocolFallback
+	}
+	http.ProxyURL = probe.ProxyURL
+	// The order of the versions doesn't matter to the exporter, sorting them lets equivalent
+	// specs share a module
+	for _, version := range probe.ValidHTTPVersions {
+		http.ValidHTTPVersions = append(http.ValidHTTPVersions, string(version))
+	}
+	sort.Strings(http.ValidHTTPVersions)
+	http.BodySizeLimit = probe.BodySizeLimit
+	http.Compression = probe.Compression
+	if len(probe.Headers) > 0 {
+		http.Headers = probe.Headers
+	}
+	http.FailIfHeaderMatches = headerMatches(probe.FailIfHeaderMatches)
+	http.FailIfHeaderNotMatches = headerMatches(probe.FailIfHeaderNotMatches)
+	if probe.TLSMinVersion != "" {
+		if http.TLSConfig == nil {
+			http.TLSConfig = &TLSConfig{}
+		}
+		http.TLSConfig.MinVersion = string(probe.TLSMinVersion)
+	}
+	if !reflect.DeepEqual(*http, HTTPProbe{}) {
+		module.HTTP = http
+	}
+
+	if reflect.DeepEqual(module, defaultModule) {
+		return name, defaultModule
+	}
+	return fmt.Sprintf("%s_%s", name, module.hash()), module
+}
+
+// CertificateModuleFor returns the name and definition of the module checking the certificate chain of a host:port
+// target in a TLS handshake. The chain is verified for the serverName, or the host of the target if it's empty
+func CertificateModuleFor(serverName string) (string, Module) {
+	module := Module{
+		Prober:  "tcp",
+		Timeout: "15s",
+		TCP:     &TCPProbe{TLS: true},
+	}
+	if serverName == "" {
+		return blackboxexporter.BlackBoxExporterTLSModule, module
+	}
+	module.TCP.TLSConfig = &TLSConfig{ServerName: serverName}
+	return fmt.Sprintf("%s_%s", blackboxexporter.BlackBoxExporterTLSModule, module.hash()), module
+}
+
+// headerMatches converts the assertions of a spec. Like the versions, they're sorted so equivalent specs share a module
+func headerMatches(assertions []v1alpha1.HeaderAssertion) []HeaderMatch {
+	var matches []HeaderMatch
+	for _, assertion := range assertions {
+		matches = append(matches, HeaderMatch{Header: assertion.Header, Regexp: assertion.Regexp, AllowMissing: assertion.AllowMissing})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Header != matches[j].Header {
+			return matches[i].Header < matches[j].Header
+		}
+		return matches[i].Regexp < matches[j].Regexp
+	})
+	return matches
+}
+
+// hash returns a short, stable digest of the module definition
+func (m Module) hash() string {
+	// Marshalling a struct of plain fields cannot fail
+	raw, _ := json.Marshal(m)
+	h := fnv.New32a()
+	_, _ = h.Write(raw)
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// Render returns the configuration in the format expected by the blackbox exporter
+func (c Config) Render() (string, error) {
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// ConfigRejectedReason is the reason of the Events reporting a configuration that wasn't rolled out
+const ConfigRejectedReason = "BlackBoxExporterConfigRejected"
+
+// InvalidConfigError is returned when a configuration would be rejected by the exporter, which
+// refuses to start with it
+type InvalidConfigError struct {
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("invalid blackbox exporter configuration: %v", e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ReportInvalidConfig records a Warning Event on the monitor whose reconcile failed and reports it in its
+// BlackBoxExporterReady condition, conditions, if the exporter configuration it required was rejected. Nothing else
+// reports it, as the configuration is shared by all monitors
+func ReportInvalidConfig(recorder record.EventRecorder, monitor client.Object, conditions *[]metav1.Condition, err error) {
+	var invalidConfig *InvalidConfigError
+	if !errors.As(err, &invalidConfig) {
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionBlackBoxExporterReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: monitor.GetGeneration(),
+		Reason:             ConfigRejectedReason,
+		Message:            invalidConfig.Error(),
+	})
+	if recorder != nil {
+		recorder.Event(monitor, corev1.EventTypeWarning, ConfigRejectedReason, invalidConfig.Error())
+	}
+}
+
+// ValidateConfig parses a rendered configuration with the exporter's own parser, as strictly as the exporter does
+// when it loads its configuration file, so a configuration it would fail to load is never rolled out
+func ValidateConfig(rendered string) error {
+	decoder := yamlv3.NewDecoder(strings.NewReader(rendered))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&exporterconfig.Config{}); err != nil {
+		return &InvalidConfigError{Err: err}
+	}
+	return nil
+}
+
+// fipsMinTLSVersion is the lowest TLS version approved by FIPS 140
+const fipsMinTLSVersion = string(v1alpha1.TLSVersion12)
+
+// WithFIPS returns the module restricted to the TLS versions approved by FIPS 140, unless it
+// already requires a higher one. Go limits the cipher suites of these versions to approved ones
+// when the exporter is built in FIPS mode
+func (m Module) WithFIPS() Module {
+	if m.Prober == "tcp" && m.TCP != nil && m.TCP.TLS {
+		tcp := *m.TCP
+		tcp.TLSConfig = withFIPSMinVersion(tcp.TLSConfig)
+		m.TCP = &tcp
+		return m
+	}
+	if m.Prober != "http" {
+		return m
+	}
+	http := HTTPProbe{}
+	if m.HTTP != nil {
+		http = *m.HTTP
+	}
+	http.TLSConfig = withFIPSMinVersion(http.TLSConfig)
+	m.HTTP = &http
+	return m
+}
+
+// withFIPSMinVersion returns a copy of the TLS settings requiring the FIPS minimal TLS version, unless they set one
+func withFIPSMinVersion(config *TLSConfig) *TLSConfig {
+	tlsConfig := TLSConfig{}
+	if config != nil {
+		tlsConfig = *config
+	}
+	if tlsConfig.MinVersion == "" {
+		tlsConfig.MinVersion = fipsMinTLSVersion
+	}
+	return &tlsConfig
+}
+
+// ValidateFIPS returns an error if the module allows TLS versions which aren't approved by FIPS 140
+func (m Module) ValidateFIPS() error {
+	if m.HTTP == nil || m.HTTP.TLSConfig == nil {
+		return nil
+	}
+	switch m.HTTP.TLSConfig.MinVersion {
+	case string(v1alpha1.TLSVersion10), string(v1alpha1.TLSVersion11):
+		return fmt.Errorf("%w: min_version '%s', FIPS mode requires %s or higher", customerrors.NonFIPSCompliantTLS, m.HTTP.TLSConfig.MinVersion, fipsMinTLSVersion)
+	}
+	return nil
+}
+
+// ValidateRequired returns an error if the module a monitor requires is left out of the exporter configuration: the
+// exporter would refuse to load it or, in FIPS mode, it isn't compliant. Each module is checked on its own, so one
+// monitor's invalid settings don't block the configuration of the others
+func (m Module) ValidateRequired(fips bool) error {
+	rendered, err := Config{Modules: map[string]Module{"required": m}}.Render()
+	if err != nil {
+		return err
+	}
+	if err := ValidateConfig(rendered); err != nil {
+		return err
+	}
+	if fips {
+		return m.ValidateFIPS()
+	}
+	return nil
+}
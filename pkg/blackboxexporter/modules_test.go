@@ -0,0 +1,410 @@
+package blackboxexporter_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	. "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+)
+
+var _ = Describe("Modules", func() {
+	var (
+		disabled = false
+		enabled  = true
+	)
+
+	Describe("ModuleFor", func() {
+		It("uses the default module when the probe isn't customized", func() {
+			name, module := ModuleFor(false, nil)
+			Expect(name).To(Equal(blackboxexporter.BlackBoxExporterDefaultModule))
+			Expect(module).To(Equal(DefaultModules()[name]))
+		})
+		It("uses the insecure module when TLS verification is skipped", func() {
+			name, _ := ModuleFor(true, nil)
+			Expect(name).To(Equal(blackboxexporter.BlackBoxExporterInsecureModule))
+		})
+		It("uses the default module when the settings match the exporter defaults", func() {
+			name, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FollowRedirects: &enabled})
+			Expect(name).To(Equal(blackboxexporter.BlackBoxExporterDefaultModule))
+		})
+		When("redirects shouldn't be followed", func() {
+			It("renders a dedicated module", func() {
+				name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FollowRedirects: &disabled})
+				Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+				Expect(module.HTTP).NotTo(BeNil())
+				Expect(*module.HTTP.FollowRedirects).To(BeFalse())
+			})
+			It("keeps the TLS settings of the insecure module", func() {
+				name, module := ModuleFor(true, &v1alpha1.HTTPProbeSpec{FollowRedirects: &disabled})
+				Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterInsecureModule + "_"))
+				Expect(module.HTTP.TLSConfig.InsecureSkipVerify).To(BeTrue())
+			})
+			It("names the module deterministically", func() {
+				first, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FollowRedirects: &disabled})
+				second, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FollowRedirects: &disabled})
+				Expect(first).To(Equal(second))
+			})
+		})
+	})
+
+	Describe("ModuleFor with IP protocol settings", func() {
+		It("uses the default module when ip6 is preferred", func() {
+			name, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{PreferredIPProtocol: v1alpha1.IPProtocolIP6, IPProtocolFallback: &enabled})
+			Expect(name).To(Equal(blackboxexporter.BlackBoxExporterDefaultModule))
+		})
+		It("renders a dedicated module when ip4 is preferred", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{PreferredIPProtocol: v1alpha1.IPProtocolIP4})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.PreferredIPProtocol).To(Equal("ip4"))
+			Expect(module.HTTP.IPProtocolFallback).To(BeNil())
+		})
+		It("renders a dedicated module when falling back is disabled", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{IPProtocolFallback: &disabled})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(*module.HTTP.IPProtocolFallback).To(BeFalse())
+		})
+		It("names ip4 and ip6 only modules differently", func() {
+			ip4, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{PreferredIPProtocol: v1alpha1.IPProtocolIP4, IPProtocolFallback: &disabled})
+			ip6, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{PreferredIPProtocol: v1alpha1.IPProtocolIP6, IPProtocolFallback: &disabled})
+			Expect(ip4).NotTo(Equal(ip6))
+		})
+	})
+
+	Describe("ModuleFor with a proxy", func() {
+		It("renders a dedicated module per proxy", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ProxyURL: "http://proxy.example.com:3128"})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.ProxyURL).To(Equal("http://proxy.example.com:3128"))
+
+			other, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ProxyURL: "http://other-proxy.example.com:3128"})
+			Expect(other).NotTo(Equal(name))
+		})
+		It("keeps its own proxy over the one of the environment", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ProxyURL: "http://proxy.example.com:3128"})
+			Expect(module.WithProxyFromEnvironment().HTTP.ProxyFromEnvironment).To(BeFalse())
+
+			module.HTTP.ProxyFromEnvironment = true
+			Expect(module.ValidateRequired(false)).To(MatchError(ContainSubstring("proxy_url must not be configured")))
+		})
+	})
+
+	Describe("ModuleFor with valid HTTP versions", func() {
+		It("renders a dedicated module", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ValidHTTPVersions: []v1alpha1.HTTPVersion{"HTTP/2.0"}})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.ValidHTTPVersions).To(Equal([]string{"HTTP/2.0"}))
+		})
+		It("ignores the order of the versions", func() {
+			first, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ValidHTTPVersions: []v1alpha1.HTTPVersion{"HTTP/2.0", "HTTP/1.1"}})
+			second, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ValidHTTPVersions: []v1alpha1.HTTPVersion{"HTTP/1.1", "HTTP/2.0"}})
+			Expect(first).To(Equal(second))
+		})
+	})
+
+	Describe("ModuleFor with a minimal TLS version", func() {
+		It("renders a dedicated module", func() {
+			name, module := ModuleFor(true, &v1alpha1.HTTPProbeSpec{TLSMinVersion: v1alpha1.TLSVersion13})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterInsecureModule + "_"))
+			Expect(module.HTTP.TLSConfig).To(Equal(&TLSConfig{InsecureSkipVerify: true, MinVersion: "TLS13"}))
+		})
+	})
+
+	Describe("ModuleFor with a body size limit and compression", func() {
+		It("renders a dedicated module", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{BodySizeLimit: "1MB", Compression: "gzip"})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.BodySizeLimit).To(Equal("1MB"))
+			Expect(module.HTTP.Compression).To(Equal("gzip"))
+			Expect(module.ValidateRequired(false)).To(Succeed())
+		})
+	})
+
+	Describe("ModuleFor with request headers", func() {
+		It("renders a dedicated module", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{Headers: map[string]string{"Accept": "application/json"}})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.Headers).To(Equal(map[string]string{"Accept": "application/json"}))
+			Expect(module.ValidateRequired(false)).To(Succeed())
+		})
+	})
+
+	Describe("ModuleFor with header assertions", func() {
+		It("renders a dedicated module", func() {
+			name, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{
+				FailIfHeaderMatches:    []v1alpha1.HeaderAssertion{{Header: "X-Cache", Regexp: "MISS", AllowMissing: true}},
+				FailIfHeaderNotMatches: []v1alpha1.HeaderAssertion{{Header: "Strict-Transport-Security", Regexp: "max-age=[0-9]+"}},
+			})
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterDefaultModule + "_"))
+			Expect(module.HTTP.FailIfHeaderMatches).To(Equal([]HeaderMatch{{Header: "X-Cache", Regexp: "MISS", AllowMissing: true}}))
+			Expect(module.HTTP.FailIfHeaderNotMatches).To(Equal([]HeaderMatch{{Header: "Strict-Transport-Security", Regexp: "max-age=[0-9]+"}}))
+			Expect(module.ValidateRequired(false)).To(Succeed())
+		})
+		It("ignores the order of the assertions", func() {
+			cache := v1alpha1.HeaderAssertion{Header: "X-Cache", Regexp: "MISS"}
+			server := v1alpha1.HeaderAssertion{Header: "Server", Regexp: "nginx"}
+			first, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FailIfHeaderMatches: []v1alpha1.HeaderAssertion{cache, server}})
+			second, _ := ModuleFor(false, &v1alpha1.HTTPProbeSpec{FailIfHeaderMatches: []v1alpha1.HeaderAssertion{server, cache}})
+			Expect(first).To(Equal(second))
+		})
+		It("rejects an invalid regexp", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{
+				FailIfHeaderNotMatches: []v1alpha1.HeaderAssertion{{Header: "Strict-Transport-Security", Regexp: "max-age=(["}},
+			})
+			Expect(module.ValidateRequired(false)).To(MatchError(ContainSubstring("Could not compile regular expression")))
+		})
+	})
+
+	Describe("CertificateModuleFor", func() {
+		It("uses the TLS module when the server name isn't overridden", func() {
+			name, module := CertificateModuleFor("")
+			Expect(name).To(Equal(blackboxexporter.BlackBoxExporterTLSModule))
+			Expect(module.Prober).To(Equal("tcp"))
+			Expect(module.TCP).To(Equal(&TCPProbe{TLS: true}))
+			Expect(module.ValidateRequired(false)).To(Succeed())
+		})
+		It("renders a dedicated module per server name", func() {
+			name, module := CertificateModuleFor("db.example.com")
+			other, _ := CertificateModuleFor("cache.example.com")
+			Expect(name).To(HavePrefix(blackboxexporter.BlackBoxExporterTLSModule + "_"))
+			Expect(name).NotTo(Equal(other))
+			Expect(module.TCP.TLSConfig).To(Equal(&TLSConfig{ServerName: "db.example.com"}))
+		})
+	})
+
+	Describe("FIPS", func() {
+		It("restricts modules to approved TLS versions", func() {
+			module := DefaultModules()[blackboxexporter.BlackBoxExporterInsecureModule].WithFIPS()
+			Expect(module.HTTP.TLSConfig).To(Equal(&TLSConfig{InsecureSkipVerify: true, MinVersion: "TLS12"}))
+			Expect(DefaultModules()[blackboxexporter.BlackBoxExporterInsecureModule].HTTP.TLSConfig.MinVersion).To(BeEmpty())
+		})
+		It("restricts TLS handshakes to approved TLS versions", func() {
+			_, module := CertificateModuleFor("db.example.com")
+			Expect(module.WithFIPS().TCP.TLSConfig).To(Equal(&TLSConfig{ServerName: "db.example.com", MinVersion: "TLS12"}))
+			Expect(module.TCP.TLSConfig.MinVersion).To(BeEmpty())
+		})
+		It("keeps a higher TLS version", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{TLSMinVersion: v1alpha1.TLSVersion13})
+			Expect(module.WithFIPS().HTTP.TLSConfig.MinVersion).To(Equal("TLS13"))
+		})
+		It("rejects TLS versions which aren't approved", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{TLSMinVersion: v1alpha1.TLSVersion11})
+			Expect(errors.Is(module.ValidateFIPS(), customerrors.NonFIPSCompliantTLS)).To(BeTrue())
+			Expect(module.WithFIPS().ValidateFIPS()).To(HaveOccurred())
+		})
+		It("accepts the default modules", func() {
+			for _, module := range DefaultModules() {
+				Expect(module.WithFIPS().ValidateFIPS()).To(Succeed())
+			}
+		})
+	})
+
+	Describe("EnsureBlackBoxExporterConfigMapExists", func() {
+		var (
+			routeMonitor     v1alpha1.RouteMonitor
+			blackboxExporter *BlackBoxExporter
+			kclient          client.Client
+		)
+		BeforeEach(func() {
+			routeMonitor = v1alpha1.RouteMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace"},
+				Spec: v1alpha1.RouteMonitorSpec{
+					HTTPProbe: &v1alpha1.HTTPProbeSpec{FollowRedirects: &disabled},
+				},
+			}
+		})
+		JustBeforeEach(func() {
+			kclient = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(&routeMonitor).Build()
+			blackboxExporter = New(kclient, logr.Discard(), context.TODO(), "fake-image", "fake-exporter-namespace")
+		})
+		It("renders the modules required by the monitors", func() {
+			hash, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).NotTo(BeEmpty())
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			name, _ := ModuleFor(false, routeMonitor.Spec.HTTPProbe)
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).To(ContainSubstring(name + ":"))
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).To(ContainSubstring("follow_redirects: false"))
+		})
+		It("updates the configuration once the modules change", func() {
+			before, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+
+			routeMonitor.Spec.HTTPProbe = nil
+			Expect(kclient.Update(context.TODO(), &routeMonitor)).To(Succeed())
+			after, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after).NotTo(Equal(before))
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			Expect(strings.Contains(cm.Data[blackboxexporter.BlackBoxExporterConfigFile], "follow_redirects")).To(BeFalse())
+		})
+		It("leaves out modules which aren't FIPS compliant in FIPS mode", func() {
+			blackboxExporter.FIPS = true
+			routeMonitor.Spec.HTTPProbe = &v1alpha1.HTTPProbeSpec{TLSMinVersion: v1alpha1.TLSVersion10}
+			Expect(kclient.Update(context.TODO(), &routeMonitor)).To(Succeed())
+			_, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).NotTo(ContainSubstring("TLS10"))
+			Expect(strings.Count(cm.Data[blackboxexporter.BlackBoxExporterConfigFile], "min_version: TLS12")).To(Equal(len(DefaultModules())))
+		})
+		It("leaves out the modules the exporter couldn't load, keeping the ones of the other monitors", func() {
+			invalid := v1alpha1.RouteMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "fake-namespace"},
+				Spec: v1alpha1.RouteMonitorSpec{
+					HTTPProbe: &v1alpha1.HTTPProbeSpec{ProxyURL: "://proxy.example.com"},
+				},
+			}
+			Expect(kclient.Create(context.TODO(), &invalid)).To(Succeed())
+			_, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).To(ContainSubstring("follow_redirects: false"))
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).NotTo(ContainSubstring("proxy_url"))
+		})
+		It("probes through the proxy of the exporter's environment on clusters with a proxy", func() {
+			proxy := configv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Status: configv1.ProxyStatus{HTTPSProxy: "http://proxy.example.com:3128"}}
+			Expect(kclient.Create(context.TODO(), &proxy)).To(Succeed())
+			proxied := v1alpha1.RouteMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "proxied", Namespace: "fake-namespace"},
+				Spec: v1alpha1.RouteMonitorSpec{
+					HTTPProbe: &v1alpha1.HTTPProbeSpec{ProxyURL: "http://other-proxy.example.com:3128"},
+				},
+			}
+			Expect(kclient.Create(context.TODO(), &proxied)).To(Succeed())
+			_, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			config := Config{}
+			Expect(yaml.Unmarshal([]byte(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]), &config)).To(Succeed())
+			proxiedModule, _ := ModuleFor(false, proxied.Spec.HTTPProbe)
+			for name, module := range config.Modules {
+				if name == proxiedModule {
+					// The proxy of the probe is kept, the exporter rejects both
+					Expect(module.HTTP.ProxyFromEnvironment).To(BeFalse())
+					continue
+				}
+				Expect(module.HTTP.ProxyFromEnvironment).To(BeTrue(), "module %s", name)
+			}
+		})
+		It("leaves out the modules of the monitors probed by an external exporter", func() {
+			routeMonitor.Spec.ExternalExporter = &v1alpha1.ExternalExporterSpec{URL: "http://blackbox.example.com:9115", Module: "http_2xx"}
+			Expect(kclient.Update(context.TODO(), &routeMonitor)).To(Succeed())
+			_, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).NotTo(ContainSubstring("follow_redirects: false"))
+		})
+		It("renders the modules of the monitors asking for a dedicated exporter into the exporter of their namespace", func() {
+			routeMonitor.Spec.DedicatedExporter = true
+			Expect(kclient.Update(context.TODO(), &routeMonitor)).To(Succeed())
+			name, _ := ModuleFor(false, routeMonitor.Spec.HTTPProbe)
+
+			_, err := blackboxExporter.EnsureBlackBoxExporterConfigMapExists()
+			Expect(err).NotTo(HaveOccurred())
+			cm := corev1.ConfigMap{}
+			Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-exporter-namespace"}, &cm)).To(Succeed())
+			Expect(cm.Data[blackboxexporter.BlackBoxExporterConfigFile]).NotTo(ContainSubstring(name + ":"))
+
+			for namespace, dedicated := range map[string]bool{"fake-namespace": true, "other-namespace": false} {
+				_, err = blackboxExporter.DedicatedTo(namespace).EnsureBlackBoxExporterConfigMapExists()
+				Expect(err).NotTo(HaveOccurred())
+				cm = corev1.ConfigMap{}
+				Expect(kclient.Get(context.TODO(), types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: namespace}, &cm)).To(Succeed())
+				Expect(strings.Contains(cm.Data[blackboxexporter.BlackBoxExporterConfigFile], name+":")).To(Equal(dedicated))
+			}
+		})
+	})
+
+	Describe("ValidateRequired", func() {
+		It("rejects a module the exporter couldn't load", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{ProxyURL: "://proxy.example.com"})
+			var invalidConfig *InvalidConfigError
+			Expect(errors.As(module.ValidateRequired(false), &invalidConfig)).To(BeTrue())
+		})
+		It("rejects a header assertion the exporter couldn't compile", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{
+				FailIfHeaderMatches: []v1alpha1.HeaderAssertion{{Header: "X-Cache", Regexp: "("}},
+			})
+			var invalidConfig *InvalidConfigError
+			Expect(errors.As(module.ValidateRequired(false), &invalidConfig)).To(BeTrue())
+		})
+		It("rejects a module which isn't FIPS compliant only in FIPS mode", func() {
+			_, module := ModuleFor(false, &v1alpha1.HTTPProbeSpec{TLSMinVersion: v1alpha1.TLSVersion11})
+			Expect(module.ValidateRequired(false)).To(Succeed())
+			Expect(errors.Is(module.ValidateRequired(true), customerrors.NonFIPSCompliantTLS)).To(BeTrue())
+		})
+	})
+
+	Describe("ReportInvalidConfig", func() {
+		It("reports a rejected configuration in the BlackBoxExporterReady condition and an Event", func() {
+			monitor := &v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace", Generation: 2}}
+			recorder := record.NewFakeRecorder(1)
+			ReportInvalidConfig(recorder, monitor, &monitor.Status.Conditions, ValidateConfig("modules:\n  m:\n    prober: http\n    retries: 3\n"))
+
+			condition := meta.FindStatusCondition(monitor.Status.Conditions, v1alpha1.ConditionBlackBoxExporterReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ConfigRejectedReason))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+			Expect(condition.Message).To(ContainSubstring("field retries not found"))
+			Expect(recorder.Events).To(Receive(ContainSubstring(ConfigRejectedReason)))
+		})
+		It("ignores other errors", func() {
+			monitor := &v1alpha1.RouteMonitor{}
+			ReportInvalidConfig(record.NewFakeRecorder(1), monitor, &monitor.Status.Conditions, errors.New("fake error"))
+			Expect(monitor.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("ValidateConfig", func() {
+		It("accepts the default modules", func() {
+			rendered, err := Config{Modules: DefaultModules()}.Render()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ValidateConfig(rendered)).To(Succeed())
+		})
+		for _, invalid := range []struct{ name, rendered, reason string }{
+			{"an unknown field", "modules:\n  m:\n    prober: http\n    retries: 3\n", "field retries not found"},
+			{"an invalid timeout", "modules:\n  m:\n    prober: http\n    timeout: soon\n", "`soon`"},
+			{"an invalid body size limit", "modules:\n  m:\n    prober: http\n    http:\n      body_size_limit: 1M\n", "unknown unit M"},
+			{"a compression contradicting the headers", "modules:\n  m:\n    prober: http\n    http:\n      compression: gzip\n      headers:\n        Accept-Encoding: br\n", `"compression: gzip"`},
+			{"an invalid tls version", "modules:\n  m:\n    prober: http\n    http:\n      tls_config:\n        min_version: SSL3\n", "unknown TLS version: SSL3"},
+		} {
+			invalid := invalid
+			It("rejects "+invalid.name, func() {
+				err := ValidateConfig(invalid.rendered)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(invalid.reason))
+			})
+		}
+	})
+})
@@ -0,0 +1,31 @@
+package blackboxexporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProbeSettingsOf returns the probe settings of a RouteMonitor: the ones of the ProbeTemplate it references, or its own
+// if it references none. A missing ProbeTemplate is a missing dependency, the RouteMonitor is probed once it's created
+func ProbeSettingsOf(ctx context.Context, c client.Client, spec v1alpha1.RouteMonitorSpec) (bool, *v1alpha1.HTTPProbeSpec, error) {
+	if spec.ProbeTemplate == "" {
+		return spec.InsecureSkipTLSVerify, spec.HTTPProbe, nil
+	}
+	template := v1alpha1.ProbeTemplate{}
+	if err := c.Get(ctx, types.NamespacedName{Name: spec.ProbeTemplate}, &template); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil, &customerrors.DependencyMissingError{
+				Dependency: "ProbeTemplate",
+				Err:        fmt.Errorf("ProbeTemplate %s not found", spec.ProbeTemplate),
+			}
+		}
+		return false, nil, err
+	}
+	return template.Spec.InsecureSkipTLSVerify, template.Spec.HTTPProbe, nil
+}
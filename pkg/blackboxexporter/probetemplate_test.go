@@ -0,0 +1,51 @@
+package blackboxexporter_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	. "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+)
+
+var _ = Describe("ProbeSettingsOf", func() {
+	var (
+		jsonAPI = &v1alpha1.ProbeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "json-api"},
+			Spec: v1alpha1.ProbeTemplateSpec{
+				InsecureSkipTLSVerify: true,
+				HTTPProbe:             &v1alpha1.HTTPProbeSpec{Headers: map[string]string{"Accept": "application/json"}},
+			},
+		}
+		spec v1alpha1.RouteMonitorSpec
+	)
+	BeforeEach(func() {
+		spec = v1alpha1.RouteMonitorSpec{HTTPProbe: &v1alpha1.HTTPProbeSpec{Compression: "gzip"}}
+	})
+
+	It("returns the settings of the monitor without a ProbeTemplate", func() {
+		insecureSkipTLSVerify, probe, err := ProbeSettingsOf(context.TODO(), fake.NewClientBuilder().WithScheme(constinit.Scheme).Build(), spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(insecureSkipTLSVerify).To(BeFalse())
+		Expect(probe).To(Equal(spec.HTTPProbe))
+	})
+	It("returns the settings of the referenced ProbeTemplate instead of the monitor's", func() {
+		spec.ProbeTemplate = jsonAPI.Name
+		insecureSkipTLSVerify, probe, err := ProbeSettingsOf(context.TODO(), fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(jsonAPI).Build(), spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(insecureSkipTLSVerify).To(BeTrue())
+		Expect(probe).To(Equal(jsonAPI.Spec.HTTPProbe))
+	})
+	It("reports a missing ProbeTemplate as a missing dependency", func() {
+		spec.ProbeTemplate = "missing"
+		_, _, err := ProbeSettingsOf(context.TODO(), fake.NewClientBuilder().WithScheme(constinit.Scheme).Build(), spec)
+		Expect(err).To(MatchError("ProbeTemplate missing not found"))
+		Expect(customerrors.ClassOf(err)).To(Equal(customerrors.ClassDependencyMissing))
+	})
+})
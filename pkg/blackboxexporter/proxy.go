@@ -0,0 +1,157 @@
+package blackboxexporter
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// trustedCABundleDir is where the trusted CA bundle of the cluster proxy is mounted
+	trustedCABundleDir = "/etc/pki/ca-trust/extracted/pem"
+	// trustedCABundleFile is the file of the trusted CA bundle within the trustedCABundleDir
+	trustedCABundleFile = "tls-ca-bundle.pem"
+)
+
+// Proxy is the cluster-wide proxy the exporter probes through, the zero value probes directly
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	// NoProxy lists the hosts which are probed directly, including the cluster's own networks
+	NoProxy string
+}
+
+// Enabled returns whether the cluster has a proxy
+func (p Proxy) Enabled() bool {
+	return p.HTTPProxy != "" || p.HTTPSProxy != ""
+}
+
+// env returns the environment routing the probes through the proxy, and trusting its CA bundle
+func (p Proxy) env() []corev1.EnvVar {
+	if !p.Enabled() {
+		return nil
+	}
+	env := []corev1.EnvVar{}
+	for _, v := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: p.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: p.HTTPSProxy},
+		{Name: "NO_PROXY", Value: p.NoProxy},
+	} {
+		if v.Value != "" {
+			env = append(env, v)
+		}
+	}
+	// The bundle holds the system CAs as well, it replaces the ones of the image
+	return append(env, corev1.EnvVar{Name: "SSL_CERT_FILE", Value: trustedCABundleDir + "/" + trustedCABundleFile})
+}
+
+// WithProxyFromEnvironment returns the http module routing its probes through the proxy of the exporter's environment,
+// unless it sets its own proxy_url. The http client of the exporter ignores HTTP_PROXY, HTTPS_PROXY and NO_PROXY otherwise
+func (m Module) WithProxyFromEnvironment() Module {
+	if m.Prober != "http" || (m.HTTP != nil && m.HTTP.ProxyURL != "") {
+		return m
+	}
+	http := HTTPProbe{}
+	if m.HTTP != nil {
+		http = *m.HTTP
+	}
+	http.ProxyFromEnvironment = true
+	m.HTTP = &http
+	return m
+}
+
+// ClusterProxy returns the proxy of the cluster, as observed by the cluster network operator. Clusters without a
+// Proxy, e.g. outside of OpenShift, have none
+func ClusterProxy(ctx context.Context, c client.Client) (Proxy, error) {
+	proxy := configv1.Proxy{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "cluster"}, &proxy); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return Proxy{}, nil
+		}
+		return Proxy{}, err
+	}
+	return Proxy{
+		HTTPProxy:  proxy.Status.HTTPProxy,
+		HTTPSProxy: proxy.Status.HTTPSProxy,
+		NoProxy:    proxy.Status.NoProxy,
+	}, nil
+}
+
+// TemplateForBlackBoxExporterTrustedCABundle returns the ConfigMap the cluster network operator injects the trusted CA
+// bundle into. It's created empty, its data is owned by the cluster network operator
+func TemplateForBlackBoxExporterTrustedCABundle(blackboxNamespacedName types.NamespacedName) corev1.ConfigMap {
+	labels := blackboxexporter.GenerateBlackBoxExporterLables()
+	labels[blackboxexporter.TrustedCABundleInjectionLabel] = "true"
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxexporter.BlackBoxExporterTrustedCABundleName,
+			Namespace: blackboxNamespacedName.Namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+// EnsureBlackBoxExporterTrustedCABundle creates the ConfigMap of the trusted CA bundle while the cluster has a proxy,
+// and deletes it otherwise
+func (b *BlackBoxExporter) EnsureBlackBoxExporterTrustedCABundle(proxy Proxy) error {
+	if !proxy.Enabled() {
+		return b.EnsureBlackBoxExporterTrustedCABundleAbsent()
+	}
+	template := TemplateForBlackBoxExporterTrustedCABundle(b.NamespacedName)
+
+	resource := corev1.ConfigMap{}
+	if err := b.Client.Get(b.Ctx, client.ObjectKeyFromObject(&template), &resource); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if b.FieldOwner != "" {
+			return b.apply(&template, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		}
+		return b.Client.Create(b.Ctx, &template)
+	}
+
+	// Only the injection is asked for, the injected data is left alone
+	if resource.Labels[blackboxexporter.TrustedCABundleInjectionLabel] == "true" {
+		return nil
+	}
+	if b.FieldOwner != "" {
+		return b.apply(&template, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	}
+	metav1.SetMetaDataLabel(&resource.ObjectMeta, blackboxexporter.TrustedCABundleInjectionLabel, "true")
+	return b.Client.Update(b.Ctx, &resource)
+}
+
+func (b *BlackBoxExporter) EnsureBlackBoxExporterTrustedCABundleAbsent() error {
+	resource := &corev1.ConfigMap{}
+
+	// Does the resource already exist?
+	err := b.Client.Get(b.Ctx, types.NamespacedName{Name: blackboxexporter.BlackBoxExporterTrustedCABundleName, Namespace: b.NamespacedName.Namespace}, resource)
+	if err != nil {
+		// If this is an unknown error
+		if !k8serrors.IsNotFound(err) {
+			// return unexpectedly
+			return err
+		}
+		// Resource doesn't exist, nothing to do
+		return nil
+	}
+	return b.Client.Delete(b.Ctx, resource)
+}
+
+// podSettings returns the deploymentSettings with the proxy of the cluster
+func (b *BlackBoxExporter) podSettings() (DeploymentSettings, error) {
+	settings := b.deploymentSettings()
+	proxy, err := ClusterProxy(b.Ctx, b.Client)
+	if err != nil {
+		return settings, err
+	}
+	settings.Proxy = proxy
+	return settings, nil
+}
@@ -0,0 +1,148 @@
+package blackboxexporter
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	reconcileCommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplateForBlackBoxExporterConfig returns the BlackBoxExporterConfig reporting the rollout of the exporter
+func TemplateForBlackBoxExporterConfig(blackboxNamespacedName types.NamespacedName) v1alpha1.BlackBoxExporterConfig {
+	return v1alpha1.BlackBoxExporterConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxNamespacedName.Name,
+			Namespace: blackboxNamespacedName.Namespace,
+			Labels:    blackboxexporter.GenerateBlackBoxExporterLables(),
+		},
+	}
+}
+
+// EnsureBlackBoxExporterConfigStatus records the rollout of the exporter in its BlackBoxExporterConfig. Once every pod
+// runs the current image and the configuration of the configHash, and passes its readiness probe, the configuration's
+// modules are recorded as rolled out. Until then, the ones of the previous rollout are kept
+func (b *BlackBoxExporter) EnsureBlackBoxExporterConfigStatus(configHash string, modules map[string]Module) error {
+	config := v1alpha1.BlackBoxExporterConfig{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &config); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		config = TemplateForBlackBoxExporterConfig(b.NamespacedName)
+		if b.FieldOwner != "" {
+			err = b.apply(&config, v1alpha1.GroupVersion.WithKind("BlackBoxExporterConfig"))
+		} else {
+			err = b.Client.Create(b.Ctx, &config)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	rollout, err := b.GetBlackBoxExporterRollout()
+	if err != nil {
+		return err
+	}
+	if rollout == "" {
+		// The exporter may have been updated moments ago, its rollout isn't reported yet
+		current, err := b.runsVersion(configHash)
+		if err != nil {
+			return err
+		}
+		if !current {
+			rollout = "Waiting for the rollout of the blackbox exporter to start"
+		}
+	}
+
+	status := config.Status.DeepCopy()
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionRolledOut,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: config.Generation,
+		Reason:             reconcileCommon.ReasonReconciled,
+	}
+	if rollout != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reconcileCommon.ReasonRollingOut
+		condition.Message = rollout
+	} else {
+		status.Image = b.image()
+		status.ConfigHash = configHash
+		status.Modules = make([]string, 0, len(modules))
+		for name := range modules {
+			status.Modules = append(status.Modules, name)
+		}
+		sort.Strings(status.Modules)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if reflect.DeepEqual(config.Status, *status) {
+		return nil
+	}
+	config.Status = *status
+	// A dry run doesn't persist the config it creates, there's no status to update then
+	return client.IgnoreNotFound(b.Client.Status().Update(b.Ctx, &config))
+}
+
+// runsVersion reports whether the exporter's deployment, or daemonset, runs the current image and the configuration
+// of the configHash
+func (b *BlackBoxExporter) runsVersion(configHash string) (bool, error) {
+	var template corev1.PodTemplateSpec
+	if b.deploymentSettings().DaemonSet {
+		daemonSet := appsv1.DaemonSet{}
+		if err := b.Client.Get(b.Ctx, b.NamespacedName, &daemonSet); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		template = daemonSet.Spec.Template
+	} else {
+		deployment := appsv1.Deployment{}
+		if err := b.Client.Get(b.Ctx, b.NamespacedName, &deployment); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		template = deployment.Spec.Template
+	}
+	if template.Annotations[blackboxexporter.BlackBoxExporterConfigHashAnnotation] != configHash {
+		return false, nil
+	}
+	for _, container := range template.Spec.Containers {
+		if container.Image != b.image() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ServesBlackBoxExporterModule reports whether the module was rolled out, so the exporter's available pods probe with
+// it rather than failing the probes of an unknown module
+func (b *BlackBoxExporter) ServesBlackBoxExporterModule(module string) (bool, error) {
+	config := v1alpha1.BlackBoxExporterConfig{}
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, &config); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return slices.Contains(config.Status.Modules, module), nil
+}
+
+func (b *BlackBoxExporter) EnsureBlackBoxExporterConfigAbsent() error {
+	resource := &v1alpha1.BlackBoxExporterConfig{}
+
+	// Does the resource already exist?
+	if err := b.Client.Get(b.Ctx, b.NamespacedName, resource); err != nil {
+		// Resource doesn't exist, nothing to do
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return b.Client.Delete(b.Ctx, resource)
+}
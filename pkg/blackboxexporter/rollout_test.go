@@ -0,0 +1,90 @@
+package blackboxexporter_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	. "github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+)
+
+var _ = Describe("Rollout", func() {
+	var (
+		kclient          client.Client
+		blackboxExporter *BlackBoxExporter
+		namespacedName   = types.NamespacedName{Name: blackboxexporter.BlackBoxExporterName, Namespace: "fake-namespace"}
+	)
+	// rollOut deploys the configHash and reports every pod as updated and available, or none if they're still rolling out
+	rollOut := func(configHash string, available bool) {
+		Expect(blackboxExporter.EnsureBlackBoxExporterDeploymentExists(configHash)).To(Succeed())
+		deployment := appsv1.Deployment{}
+		Expect(kclient.Get(context.Background(), namespacedName, &deployment)).To(Succeed())
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 1}
+		if available {
+			deployment.Status.UpdatedReplicas = 1
+			deployment.Status.AvailableReplicas = 1
+		}
+		Expect(kclient.Status().Update(context.Background(), &deployment)).To(Succeed())
+	}
+	config := func() v1alpha1.BlackBoxExporterConfig {
+		config := v1alpha1.BlackBoxExporterConfig{}
+		Expect(kclient.Get(context.Background(), namespacedName, &config)).To(Succeed())
+		return config
+	}
+	BeforeEach(func() {
+		kclient = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithStatusSubresource(&v1alpha1.BlackBoxExporterConfig{}, &appsv1.Deployment{}).Build()
+		blackboxExporter = New(kclient, logr.Discard(), context.Background(), "fake-image", namespacedName.Namespace)
+	})
+
+	It("records the modules once every pod is rolled out", func() {
+		rollOut("fake-hash", true)
+		Expect(blackboxExporter.EnsureBlackBoxExporterConfigStatus("fake-hash", map[string]Module{"http_2xx": {}})).To(Succeed())
+
+		status := config().Status
+		Expect(status.Image).To(Equal("fake-image"))
+		Expect(status.ConfigHash).To(Equal("fake-hash"))
+		Expect(status.Modules).To(Equal([]string{"http_2xx"}))
+		Expect(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionRolledOut)).To(BeTrue())
+		Expect(blackboxExporter.ServesBlackBoxExporterModule("http_2xx")).To(BeTrue())
+		Expect(blackboxExporter.ServesBlackBoxExporterModule("insecure_http_2xx")).To(BeFalse())
+	})
+
+	It("keeps the previous modules while the pods are rolling out", func() {
+		rollOut("fake-hash", true)
+		Expect(blackboxExporter.EnsureBlackBoxExporterConfigStatus("fake-hash", map[string]Module{"http_2xx": {}})).To(Succeed())
+		rollOut("other-hash", false)
+		Expect(blackboxExporter.EnsureBlackBoxExporterConfigStatus("other-hash", map[string]Module{"http_2xx": {}, "insecure_http_2xx": {}})).To(Succeed())
+
+		status := config().Status
+		Expect(status.ConfigHash).To(Equal("fake-hash"))
+		Expect(status.Modules).To(Equal([]string{"http_2xx"}))
+		condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionRolledOut)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(BeEquivalentTo("False"))
+		Expect(condition.Message).To(Equal("0 of 1 blackbox exporter pods are up to date"))
+		Expect(blackboxExporter.ServesBlackBoxExporterModule("insecure_http_2xx")).To(BeFalse())
+	})
+
+	It("waits for the deployment to be updated with the configuration", func() {
+		rollOut("fake-hash", true)
+		Expect(blackboxExporter.EnsureBlackBoxExporterConfigStatus("other-hash", map[string]Module{"insecure_http_2xx": {}})).To(Succeed())
+
+		status := config().Status
+		Expect(status.Modules).To(BeEmpty())
+		Expect(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.ConditionRolledOut)).To(BeTrue())
+	})
+
+	It("doesn't serve any module before the exporter is deployed", func() {
+		Expect(blackboxExporter.ServesBlackBoxExporterModule("http_2xx")).To(BeFalse())
+	})
+})
@@ -0,0 +1,144 @@
+// Package clusteridentity resolves the ID of the cluster that probe results are attributed to
+package clusteridentity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Sources the ID of a cluster that isn't hosted can be resolved from
+	SourceClusterVersion = "clusterversion"
+	SourceEnv            = "env"
+	SourceMetadata       = "metadata"
+
+	// EnvVariable holds the cluster ID when the env source is used
+	EnvVariable = "CLUSTER_ID"
+)
+
+// Provider resolves the ID of the cluster a monitor belongs to
+type Provider interface {
+	// ClusterID returns the ID of the cluster a monitor in the given namespace belongs to
+	ClusterID(ctx context.Context, namespace string) (string, error)
+}
+
+// ClusterVersionProvider returns the ID stored in the ClusterVersion of an OpenShift cluster
+type ClusterVersionProvider struct {
+	Client client.Client
+}
+
+func (p *ClusterVersionProvider) ClusterID(ctx context.Context, _ string) (string, error) {
+	var version configv1.ClusterVersion
+	err := p.Client.Get(ctx, client.ObjectKey{Name: "version"}, &version)
+	if err != nil {
+		return "", err
+	}
+	return string(version.Spec.ClusterID), nil
+}
+
+// HostedControlPlaneProvider returns the ID of the hosted cluster whose HostedControlPlane resides in
+// the monitor's namespace. If more than one HostedControlPlane exists in the namespace, an error is returned. If none
+// exists, the error wraps customerrors.NoHostedControlPlane
+type HostedControlPlaneProvider struct {
+	Client client.Client
+}
+
+func (p *HostedControlPlaneProvider) ClusterID(ctx context.Context, namespace string) (string, error) {
+	hcpList := hypershiftv1beta1.HostedControlPlaneList{}
+	err := p.Client.List(ctx, &hcpList, client.InNamespace(namespace))
+	if err != nil {
+		return "", err
+	}
+	if len(hcpList.Items) == 0 {
+		return "", fmt.Errorf("failed to retrieve the HostedControlPlane of namespace '%s': %w", namespace, customerrors.NoHostedControlPlane)
+	}
+	if len(hcpList.Items) != 1 {
+		return "", fmt.Errorf("invalid number of HostedControlPlanes detected in namespace '%s': expected 1, got %d", namespace, len(hcpList.Items))
+	}
+	return hcpList.Items[0].Spec.ClusterID, nil
+}
+
+// StaticProvider returns the same ID for every monitor
+type StaticProvider string
+
+func (p StaticProvider) ClusterID(_ context.Context, _ string) (string, error) {
+	return string(p), nil
+}
+
+// EnvProvider returns the ID stored in an environment variable
+type EnvProvider struct {
+	Variable string
+}
+
+func (p *EnvProvider) ClusterID(_ context.Context, _ string) (string, error) {
+	id, ok := os.LookupEnv(p.Variable)
+	if !ok || id == "" {
+		return "", fmt.Errorf("environment variable '%s' holding the cluster ID is not set", p.Variable)
+	}
+	return id, nil
+}
+
+// MetadataServiceProvider returns the ID served as plain text by an external metadata service,
+// for environments where the cluster doesn't know its own ID
+type MetadataServiceProvider struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewMetadataServiceProvider returns a MetadataServiceProvider with a bounded request timeout
+func NewMetadataServiceProvider(url string) *MetadataServiceProvider {
+	return &MetadataServiceProvider{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *MetadataServiceProvider) ClusterID(ctx context.Context, _ string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata service '%s' responded with status %d", p.URL, resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(string(raw))
+	if id == "" {
+		return "", fmt.Errorf("metadata service '%s' returned an empty cluster ID", p.URL)
+	}
+	return id, nil
+}
+
+// ForSource returns the Provider resolving the ID of a cluster that isn't hosted from the given source
+func ForSource(c client.Client, source, metadataURL string) (Provider, error) {
+	switch source {
+	case SourceClusterVersion, "":
+		return &ClusterVersionProvider{Client: c}, nil
+	case SourceEnv:
+		return &EnvProvider{Variable: EnvVariable}, nil
+	case SourceMetadata:
+		if metadataURL == "" {
+			return nil, fmt.Errorf("a metadata service URL is required for the '%s' cluster ID source", SourceMetadata)
+		}
+		return NewMetadataServiceProvider(metadataURL), nil
+	}
+	return nil, fmt.Errorf("unknown cluster ID source '%s'", source)
+}
@@ -0,0 +1,13 @@
+package clusteridentity_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterIdentity(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cluster Identity Suite")
+}
@@ -0,0 +1,140 @@
+package clusteridentity_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/pkg/clusteridentity"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+)
+
+var _ = Describe("ClusterIdentity", func() {
+	ctx := context.Background()
+
+	Describe("ClusterVersionProvider", func() {
+		It("returns the ID stored in the ClusterVersion", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(&configv1.ClusterVersion{
+				ObjectMeta: metav1.ObjectMeta{Name: "version"},
+				Spec:       configv1.ClusterVersionSpec{ClusterID: "fake-id"},
+			}).Build()
+			id, err := (&clusteridentity.ClusterVersionProvider{Client: c}).ClusterID(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("fake-id"))
+		})
+		It("fails without a ClusterVersion", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).Build()
+			_, err := (&clusteridentity.ClusterVersionProvider{Client: c}).ClusterID(ctx, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("HostedControlPlaneProvider", func() {
+		hcp := func(name string) *hypershiftv1beta1.HostedControlPlane {
+			return &hypershiftv1beta1.HostedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fake-namespace"},
+				Spec:       hypershiftv1beta1.HostedControlPlaneSpec{ClusterID: name + "-id"},
+			}
+		}
+		It("returns the ID of the HostedControlPlane in the namespace", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(hcp("fake")).Build()
+			id, err := (&clusteridentity.HostedControlPlaneProvider{Client: c}).ClusterID(ctx, "fake-namespace")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("fake-id"))
+		})
+		It("fails when the namespace holds more than one HostedControlPlane", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(hcp("fake"), hcp("other")).Build()
+			_, err := (&clusteridentity.HostedControlPlaneProvider{Client: c}).ClusterID(ctx, "fake-namespace")
+			Expect(err).To(HaveOccurred())
+		})
+		It("reports the HostedControlPlane missing when the namespace holds none", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).Build()
+			_, err := (&clusteridentity.HostedControlPlaneProvider{Client: c}).ClusterID(ctx, "fake-namespace")
+			Expect(err).To(MatchError(customerrors.NoHostedControlPlane))
+		})
+	})
+
+	Describe("StaticProvider", func() {
+		It("returns its ID", func() {
+			id, err := clusteridentity.StaticProvider("fake-id").ClusterID(ctx, "fake-namespace")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("fake-id"))
+		})
+	})
+
+	Describe("EnvProvider", func() {
+		const variable = "ROUTE_MONITOR_OPERATOR_TEST_CLUSTER_ID"
+		AfterEach(func() {
+			os.Unsetenv(variable)
+		})
+		It("returns the ID stored in the variable", func() {
+			os.Setenv(variable, "fake-id")
+			id, err := (&clusteridentity.EnvProvider{Variable: variable}).ClusterID(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("fake-id"))
+		})
+		It("fails when the variable isn't set", func() {
+			_, err := (&clusteridentity.EnvProvider{Variable: variable}).ClusterID(ctx, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("MetadataServiceProvider", func() {
+		var (
+			server *httptest.Server
+			status int
+			body   string
+		)
+		BeforeEach(func() {
+			status, body = http.StatusOK, "fake-id\n"
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+				fmt.Fprint(w, body)
+			}))
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		It("returns the ID served by the metadata service", func() {
+			id, err := clusteridentity.NewMetadataServiceProvider(server.URL).ClusterID(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("fake-id"))
+		})
+		It("fails when the metadata service responds with an error", func() {
+			status = http.StatusInternalServerError
+			_, err := clusteridentity.NewMetadataServiceProvider(server.URL).ClusterID(ctx, "")
+			Expect(err).To(HaveOccurred())
+		})
+		It("fails when the metadata service returns an empty ID", func() {
+			body = ""
+			_, err := clusteridentity.NewMetadataServiceProvider(server.URL).ClusterID(ctx, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ForSource", func() {
+		It("defaults to the ClusterVersion", func() {
+			p, err := clusteridentity.ForSource(nil, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(BeAssignableToTypeOf(&clusteridentity.ClusterVersionProvider{}))
+		})
+		It("requires a URL for the metadata source", func() {
+			_, err := clusteridentity.ForSource(nil, clusteridentity.SourceMetadata, "")
+			Expect(err).To(HaveOccurred())
+		})
+		It("rejects unknown sources", func() {
+			_, err := clusteridentity.ForSource(nil, "unknown", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
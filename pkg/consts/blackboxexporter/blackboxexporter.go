@@ -4,6 +4,54 @@ const ( // All things related BlackBoxExporter
 	BlackBoxExporterName       = "blackbox-exporter"
 	BlackBoxExporterPortName   = "blackbox"
 	BlackBoxExporterPortNumber = 9115
+	// BlackBoxExporterConfigFile is the key of the exporter configuration within its ConfigMap
+	BlackBoxExporterConfigFile = "blackbox.yaml"
+	// BlackBoxExporterConfigHashAnnotation is set on the exporter pods so they are rolled out when the configuration changes
+	BlackBoxExporterConfigHashAnnotation = "blackbox-exporter.monitoring.openshift.io/config-hash"
+	// BlackBoxExporterSpecHashAnnotation holds a digest of the template the exporter's Deployment, DaemonSet or Service
+	// was last applied from
+	BlackBoxExporterSpecHashAnnotation = "blackbox-exporter.monitoring.openshift.io/spec-hash"
+	// BlackBoxExporterWebConfigFile is the key of the exporter's web configuration within its ConfigMap, which serves the
+	// probes over TLS
+	BlackBoxExporterWebConfigFile = "web.yaml"
+	// BlackBoxExporterServingCertSecretName is the Secret the service CA issues the serving certificate of the exporter into
+	BlackBoxExporterServingCertSecretName = "blackbox-exporter-tls"
+	// ServingCertSecretAnnotation asks the service CA for a serving certificate of the annotated Service
+	ServingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// ServiceCAFile is the service CA bundle mounted into the Prometheus pods of the cluster monitoring stack
+	ServiceCAFile = "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt"
+	// PodServiceCAFile is the service CA bundle OpenShift mounts into every pod along with its service account token
+	PodServiceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+	// BlackBoxExporterTrustedCABundleName is the ConfigMap the cluster network operator injects the trusted CA bundle of
+	// the cluster proxy into
+	BlackBoxExporterTrustedCABundleName = "blackbox-exporter-trusted-ca-bundle"
+	// TrustedCABundleInjectionLabel asks the cluster network operator to inject the trusted CA bundle into the labeled ConfigMap
+	TrustedCABundleInjectionLabel = "config.openshift.io/inject-trusted-cabundle"
+	// TrustedCABundleFile is the key the trusted CA bundle is injected with
+	TrustedCABundleFile = "ca-bundle.crt"
+	// BlackBoxExporterHealthName names the ServiceMonitor scraping the exporter's own metrics and the PrometheusRule
+	// alerting on them, and is the job label of the scrapes
+	BlackBoxExporterHealthName = "blackbox-exporter-health"
+	// SidecarInjectAnnotation opts a pod in or out of the sidecar injection of an Istio service mesh
+	SidecarInjectAnnotation = "sidecar.istio.io/inject"
+	// ServiceMeshInject injects the exporter pods with the mesh's sidecar, so they probe the meshed services with mTLS
+	ServiceMeshInject = "inject"
+	// ServiceMeshExclude keeps the exporter pods out of the mesh, so they are scraped in plaintext
+	ServiceMeshExclude = "exclude"
+	// NobodyUID is the numeric user of 'nobody', which the exporter image runs as
+	NobodyUID int64 = 65534
+	// MeshCertsDir is where the mesh's certificates are written into the Prometheus pods scraping meshed workloads, as
+	// set up by the Istio documentation
+	MeshCertsDir = "/etc/prom-certs"
+	// BlackBoxExporterFIPSEnv forces an exporter built with a FIPS capable Go toolchain into FIPS mode
+	BlackBoxExporterFIPSEnv = "GOLANG_FIPS"
+
+	// BlackBoxExporterDefaultModule is the module used by monitors that don't customize their probe
+	BlackBoxExporterDefaultModule = "http_2xx"
+	// BlackBoxExporterInsecureModule is the module used by monitors that skip TLS verification
+	BlackBoxExporterInsecureModule = "insecure_http_2xx"
+	// BlackBoxExporterTLSModule is the module checking the certificate chain of a host:port target
+	BlackBoxExporterTLSModule = "tls_connect"
 )
 
 // generateBlackBoxLables creates a set of common labels to most resources
@@ -4,6 +4,9 @@ const (
 	FinalizerKey string = "routemonitor.routemonitoroperator.monitoring.openshift.io/finalizer"
 	// PrevFinalizerKey is here until migration to new key is done
 	PrevFinalizerKey string = "finalizer.routemonitor.openshift.io"
+	// ForceDeleteAnnotation, set to "true" on a deleted monitor, drops its finalizer without deleting its generated
+	// resources, e.g. when they can't be deleted anymore
+	ForceDeleteAnnotation string = "routemonitor.openshift.io/force-delete"
 )
 
 var (
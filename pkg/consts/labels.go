@@ -0,0 +1,15 @@
+package consts
+
+const (
+	// OwnerKindLabel and OwnerNameLabel identify the monitor a generated resource belongs to
+	OwnerKindLabel string = "monitoring.openshift.io/owner-kind"
+	OwnerNameLabel string = "monitoring.openshift.io/owner-name"
+)
+
+// OwnerLabels returns the labels identifying the monitor a generated resource belongs to
+func OwnerLabels(kind, name string) map[string]string {
+	return map[string]string{
+		OwnerKindLabel: kind,
+		OwnerNameLabel: name,
+	}
+}
@@ -11,5 +11,9 @@ var (
 		ErrStatus: metav1.Status{
 			Reason: metav1.StatusReasonNotFound,
 		}}
+	ConflictErr = &k8serrors.StatusError{
+		ErrStatus: metav1.Status{
+			Reason: metav1.StatusReasonConflict,
+		}}
 	CustomError = errors.New("test")
 )
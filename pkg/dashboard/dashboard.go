@@ -0,0 +1,181 @@
+// Package dashboard generates the dashboard summarizing the health of the monitors in the Observe section of
+// the OpenShift console
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+)
+
+const (
+	// Namespace is the namespace the console reads dashboards from
+	Namespace = "openshift-config-managed"
+	// Name is the name of the ConfigMap holding the dashboard
+	Name = "grafana-dashboard-route-monitor-operator"
+	// ConsoleLabel marks a ConfigMap as holding a console dashboard
+	ConsoleLabel = "console.openshift.io/dashboard"
+	// DataKey is the key of the dashboard definition in the ConfigMap
+	DataKey = "route-monitor-operator.json"
+	// ErrorBudgetWindow is the window the remaining error budget is computed over
+	ErrorBudgetWindow = "30d"
+)
+
+// Monitor is a monitor shown on the dashboard
+type Monitor struct {
+	Kind           string
+	NamespacedName types.NamespacedName
+	URLs           []string
+	// Percent is the availability target as a ratio, empty if the monitor has none
+	Percent          string
+	ClientErrorsFail bool
+}
+
+// legend names the monitor in the panels
+func (m Monitor) legend() string {
+	return fmt.Sprintf("%s %s/%s", m.Kind, m.NamespacedName.Namespace, m.NamespacedName.Name)
+}
+
+// The subset of the Grafana dashboard model rendered by the console
+type grafanaDashboard struct {
+	Title         string         `json:"title"`
+	UID           string         `json:"uid"`
+	Tags          []string       `json:"tags"`
+	Editable      bool           `json:"editable"`
+	Refresh       string         `json:"refresh"`
+	SchemaVersion int            `json:"schemaVersion"`
+	Time          timeRange      `json:"time"`
+	Panels        []panel        `json:"panels"`
+	Templating    map[string]any `json:"templating"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type panel struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	GridPos gridPos  `json:"gridPos"`
+	Format  string   `json:"format,omitempty"`
+	Yaxes   []yaxis  `json:"yaxes,omitempty"`
+	Targets []target `json:"targets"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type yaxis struct {
+	Format string `json:"format"`
+	Show   bool   `json:"show"`
+}
+
+type target struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+	RefID        string `json:"refId"`
+}
+
+// refID names the nth query of a panel like Grafana does: A, B, ..., Z, AA, AB, ...
+func refID(n int) string {
+	if n < 26 {
+		return string(rune('A' + n))
+	}
+	return refID(n/26-1) + refID(n%26)
+}
+
+// probeCount counts the urls of the monitors whose latest probe has the result
+func probeCount(selector string, success int) string {
+	return fmt.Sprintf("count(max by (%s) (probe_success{%s}) == %d) or vector(0)", servicemonitor.UrlLabelName, selector, success)
+}
+
+// TemplateForDashboardConfigMap returns the ConfigMap holding the dashboard of the monitors: how many of their
+// urls are up and down, and the availability and remaining error budget of each monitor
+func TemplateForDashboardConfigMap(monitors []Monitor) (corev1.ConfigMap, error) {
+	urls := []string{}
+	availability := []target{}
+	errorBudget := []target{}
+	for _, monitor := range monitors {
+		if len(monitor.URLs) == 0 {
+			continue
+		}
+		urls = append(urls, monitor.URLs...)
+		availability = append(availability, target{
+			Expr:         fmt.Sprintf("avg(probe_success{%s})", alert.ProbeSelector(monitor.URLs)),
+			LegendFormat: monitor.legend(),
+			RefID:        refID(len(availability)),
+		})
+		if monitor.Percent == "" {
+			continue
+		}
+		errorBudget = append(errorBudget, target{
+			Expr:         alert.ErrorBudgetRemaining(monitor.URLs, monitor.Percent, monitor.ClientErrorsFail, ErrorBudgetWindow),
+			LegendFormat: monitor.legend(),
+			RefID:        refID(len(errorBudget)),
+		})
+	}
+	selector := alert.ProbeSelector(urls)
+	if len(urls) == 0 {
+		// Matches no probe, so the counts are 0
+		selector = servicemonitor.UrlLabelName + `=""`
+	}
+
+	dashboard := grafanaDashboard{
+		Title:         "Route Monitor Operator / Synthetic Monitoring",
+		UID:           "route-monitor-operator",
+		Tags:          []string{"route-monitor-operator"},
+		Refresh:       "1m",
+		SchemaVersion: 27,
+		Time:          timeRange{From: "now-24h", To: "now"},
+		Panels: []panel{
+			{
+				ID: 1, Title: "Urls up", Type: "singlestat", Format: "none",
+				GridPos: gridPos{H: 4, W: 12, X: 0, Y: 0},
+				Targets: []target{{Expr: probeCount(selector, 1), RefID: "A"}},
+			},
+			{
+				ID: 2, Title: "Urls down", Type: "singlestat", Format: "none",
+				GridPos: gridPos{H: 4, W: 12, X: 12, Y: 0},
+				Targets: []target{{Expr: probeCount(selector, 0), RefID: "A"}},
+			},
+			{
+				ID: 3, Title: "Availability", Type: "graph",
+				GridPos: gridPos{H: 9, W: 24, X: 0, Y: 4},
+				Yaxes:   []yaxis{{Format: "percentunit", Show: true}, {Format: "short", Show: false}},
+				Targets: availability,
+			},
+			{
+				ID: 4, Title: "Error budget remaining (" + ErrorBudgetWindow + ")", Type: "graph",
+				GridPos: gridPos{H: 9, W: 24, X: 0, Y: 13},
+				Yaxes:   []yaxis{{Format: "percentunit", Show: true}, {Format: "short", Show: false}},
+				Targets: errorBudget,
+			},
+		},
+		Templating: map[string]any{"list": []any{}},
+	}
+	definition, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return corev1.ConfigMap{}, err
+	}
+
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+			Labels:    map[string]string{ConsoleLabel: "true"},
+		},
+		Data: map[string]string{DataKey: string(definition)},
+	}, nil
+}
@@ -0,0 +1,84 @@
+// Package health holds the checks behind the manager's liveness and readiness endpoints
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// checkTimeout bounds how long a check waits for the cache or the API server, well below the timeout of the probes
+	checkTimeout = time.Second
+
+	// longestRunningProcessorMetric is the age of the oldest reconcile still running, by controller, as controller-runtime
+	// registers it for the workqueues of the controllers
+	longestRunningProcessorMetric = "workqueue_longest_running_processor_seconds"
+)
+
+// CacheSyncer is the part of the manager's cache the readiness depends on
+type CacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSynced fails until the informers of the cache have synced, so the monitors aren't reconciled from a partial view
+func CacheSynced(cache CacheSyncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		if !cache.WaitForCacheSync(ctx) {
+			return fmt.Errorf("the informer caches haven't synced yet")
+		}
+		return nil
+	}
+}
+
+// NamespaceReachable fails while the namespace can't be read from the API server, e.g. the blackbox exporter's
+// namespace, without which no probe is run. The reader should bypass the cache, so it's the API server that's checked
+func NamespaceReachable(reader client.Reader, namespace string) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		if err := reader.Get(ctx, client.ObjectKey{Name: namespace}, &corev1.Namespace{}); err != nil {
+			return fmt.Errorf("the namespace %s is unreachable: %w", namespace, err)
+		}
+		return nil
+	}
+}
+
+// WorkqueueProgress fails when a reconcile of any controller has been running for longer than maxDuration, as a
+// reconcile stuck e.g. on a hanging request blocks the workqueue of its controller for good. The workqueue
+// metrics are read from the gatherer, the registry of controller-runtime's metrics
+func WorkqueueProgress(gatherer prometheus.Gatherer, maxDuration time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return err
+		}
+		for _, family := range families {
+			if family.GetName() != longestRunningProcessorMetric {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				running := time.Duration(metric.GetGauge().GetValue() * float64(time.Second))
+				if running <= maxDuration {
+					continue
+				}
+				controller := ""
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "name" {
+						controller = label.GetValue()
+					}
+				}
+				return fmt.Errorf("a reconcile of the %s controller has been running for %s", controller, running.Round(time.Second))
+			}
+		}
+		return nil
+	}
+}
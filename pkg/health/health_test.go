@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	"github.com/openshift/route-monitor-operator/pkg/health"
+)
+
+type fakeCache bool
+
+func (c fakeCache) WaitForCacheSync(_ context.Context) bool {
+	return bool(c)
+}
+
+var _ = Describe("Health", func() {
+	request := httptest.NewRequest("GET", "/readyz", nil)
+
+	Describe("CacheSynced", func() {
+		It("fails until the caches have synced", func() {
+			Expect(health.CacheSynced(fakeCache(false))(request)).To(HaveOccurred())
+			Expect(health.CacheSynced(fakeCache(true))(request)).To(Succeed())
+		})
+	})
+
+	Describe("NamespaceReachable", func() {
+		It("fails while the namespace can't be read", func() {
+			c := fake.NewClientBuilder().WithScheme(constinit.Scheme).
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fake-namespace"}}).Build()
+			Expect(health.NamespaceReachable(c, "fake-namespace")(request)).To(Succeed())
+			Expect(health.NamespaceReachable(c, "missing-namespace")(request)).To(HaveOccurred())
+		})
+	})
+
+	Describe("WorkqueueProgress", func() {
+		var (
+			registry *prometheus.Registry
+			running  *prometheus.GaugeVec
+		)
+		BeforeEach(func() {
+			registry = prometheus.NewRegistry()
+			running = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_longest_running_processor_seconds"}, []string{"name"})
+			registry.MustRegister(running)
+		})
+
+		It("succeeds while the reconciles make progress", func() {
+			running.WithLabelValues("routemonitor").Set(5)
+			Expect(health.WorkqueueProgress(registry, time.Minute)(request)).To(Succeed())
+		})
+
+		It("fails when a reconcile is stuck", func() {
+			running.WithLabelValues("routemonitor").Set(5)
+			running.WithLabelValues("clusterurlmonitor").Set(600)
+			err := health.WorkqueueProgress(registry, time.Minute)(request)
+			Expect(err).To(MatchError(ContainSubstring("clusterurlmonitor controller has been running for 10m0s")))
+		})
+	})
+})
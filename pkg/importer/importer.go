@@ -0,0 +1,323 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	blackboxexporterconsts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+)
+
+// Target is a single target probed by an existing blackbox exporter setup
+type Target struct {
+	// URL is the probed url, as passed to the exporter
+	URL string
+	// Module is the name of the blackbox exporter module the target is probed with
+	Module string
+	// Job is the scrape job or file the target was read from
+	Job string
+}
+
+// Skipped is a target that could not be converted into a monitor
+type Skipped struct {
+	Target Target
+	Reason string
+}
+
+// Options configures how targets are converted into monitors
+type Options struct {
+	// Namespace is the namespace the monitors are created in. RouteMonitors default to
+	// the namespace of their Route if it's empty
+	Namespace string
+	// ClusterDomain is the domain of the cluster, targets below it become ClusterUrlMonitors
+	ClusterDomain string
+	// Routes are the Routes of the cluster, targets served by one of them become RouteMonitors
+	Routes []routev1.Route
+	// Modules are the modules of the existing blackbox exporter configuration
+	Modules map[string]blackboxexporter.Module
+	// SloTarget is the availability target, in percent, set on every monitor
+	SloTarget string
+}
+
+type scrapeConfigFile struct {
+	ScrapeConfigs []scrapeConfig `json:"scrape_configs"`
+}
+
+type scrapeConfig struct {
+	JobName       string              `json:"job_name"`
+	MetricsPath   string              `json:"metrics_path"`
+	Params        map[string][]string `json:"params"`
+	StaticConfigs []targetGroup       `json:"static_configs"`
+}
+
+type targetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// ParseTargets reads the blackbox targets from either a Prometheus configuration file, using the
+// static_configs of its '/probe' scrape jobs, or a file_sd target file. source names the targets' job
+// for the latter
+func ParseTargets(source string, data []byte) ([]Target, error) {
+	prometheusConfig := scrapeConfigFile{}
+	if err := yaml.Unmarshal(data, &prometheusConfig); err == nil && len(prometheusConfig.ScrapeConfigs) > 0 {
+		targets := []Target{}
+		for _, job := range prometheusConfig.ScrapeConfigs {
+			if job.MetricsPath != "/probe" {
+				continue
+			}
+			module := blackboxexporterconsts.BlackBoxExporterDefaultModule
+			if modules := job.Params["module"]; len(modules) > 0 {
+				module = modules[0]
+			}
+			targets = append(targets, groupTargets(job.JobName, module, job.StaticConfigs)...)
+		}
+		return targets, nil
+	}
+
+	groups := []targetGroup{}
+	if err := yaml.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("%s is neither a Prometheus configuration nor a target file: %w", source, err)
+	}
+	return groupTargets(source, blackboxexporterconsts.BlackBoxExporterDefaultModule, groups), nil
+}
+
+// groupTargets flattens target groups, a 'module' or '__param_module' label overrides the module of the job
+func groupTargets(job, module string, groups []targetGroup) []Target {
+	targets := []Target{}
+	for _, group := range groups {
+		groupModule := module
+		for _, label := range []string{"module", "__param_module"} {
+			if m, ok := group.Labels[label]; ok {
+				groupModule = m
+			}
+		}
+		for _, target := range group.Targets {
+			targets = append(targets, Target{URL: target, Module: groupModule, Job: job})
+		}
+	}
+	return targets
+}
+
+// Convert turns targets into RouteMonitors, for targets served by a Route, and ClusterUrlMonitors,
+// for other targets below the cluster domain. Targets that can't be expressed as a monitor are
+// returned as skipped
+func Convert(targets []Target, opts Options) ([]client.Object, []Skipped) {
+	routesByHost := map[string]routev1.Route{}
+	for _, route := range opts.Routes {
+		for _, ingress := range route.Status.Ingress {
+			routesByHost[ingress.Host] = route
+		}
+		if route.Spec.Host != "" {
+			routesByHost[route.Spec.Host] = route
+		}
+	}
+
+	objects := []client.Object{}
+	skipped := []Skipped{}
+	names := map[string]int{}
+	for _, target := range targets {
+		insecure, probe, err := probeFor(target.Module, opts.Modules)
+		if err != nil {
+			skipped = append(skipped, Skipped{Target: target, Reason: err.Error()})
+			continue
+		}
+		u, err := parseTargetURL(target.URL)
+		if err != nil {
+			skipped = append(skipped, Skipped{Target: target, Reason: err.Error()})
+			continue
+		}
+		suffix := u.EscapedPath()
+		if u.RawQuery != "" {
+			suffix += "?" + u.RawQuery
+		}
+		slo := v1alpha1.SloSpec{TargetAvailabilityPercent: opts.SloTarget}
+
+		if route, ok := routesByHost[u.Hostname()]; ok {
+			namespace := opts.Namespace
+			if namespace == "" {
+				namespace = route.Namespace
+			}
+			routeMonitor := &v1alpha1.RouteMonitor{
+				TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "RouteMonitor"},
+				ObjectMeta: metav1.ObjectMeta{Name: uniqueName(names, route.Name), Namespace: namespace},
+				Spec: v1alpha1.RouteMonitorSpec{
+					Route: v1alpha1.RouteMonitorRouteSpec{
+						Name:      route.Name,
+						Namespace: route.Namespace,
+						Suffix:    suffix,
+					},
+					Slo:                   slo,
+					InsecureSkipTLSVerify: insecure,
+					HTTPProbe:             probe,
+				},
+			}
+			if u.Port() != "" {
+				// parseTargetURL only accepts numeric ports
+				routeMonitor.Spec.Route.Port, _ = strconv.ParseInt(u.Port(), 10, 64)
+			}
+			objects = append(objects, routeMonitor)
+			continue
+		}
+
+		domainSuffix := "." + strings.TrimPrefix(opts.ClusterDomain, ".")
+		if opts.ClusterDomain == "" || !strings.HasSuffix(u.Hostname(), domainSuffix) {
+			skipped = append(skipped, Skipped{Target: target, Reason: "not served by a Route or below the cluster domain"})
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+		}
+		hostPrefix := strings.TrimSuffix(u.Hostname(), domainSuffix[1:])
+		objects = append(objects, &v1alpha1.ClusterUrlMonitor{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ClusterUrlMonitor"},
+			ObjectMeta: metav1.ObjectMeta{Name: uniqueName(names, strings.TrimSuffix(hostPrefix, ".")), Namespace: opts.Namespace},
+			Spec: v1alpha1.ClusterUrlMonitorSpec{
+				Prefix:    u.Scheme + "://" + hostPrefix,
+				Port:      port,
+				Suffix:    suffix,
+				Slo:       slo,
+				HTTPProbe: probe,
+			},
+		})
+	}
+	return objects, skipped
+}
+
+// parseTargetURL parses a target the way the exporter's http prober does, defaulting to http
+func parseTargetURL(target string) (*url.URL, error) {
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("no host in target '%s'", target)
+	}
+	if u.Port() != "" {
+		if _, err := strconv.ParseInt(u.Port(), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid port '%s'", u.Port())
+		}
+	}
+	return u, nil
+}
+
+// probeFor translates a blackbox exporter module into the probe settings of a monitor
+func probeFor(name string, modules map[string]blackboxexporter.Module) (bool, *v1alpha1.HTTPProbeSpec, error) {
+	module, ok := modules[name]
+	if !ok {
+		module, ok = blackboxexporter.DefaultModules()[name]
+	}
+	if !ok {
+		return false, nil, fmt.Errorf("unknown module '%s'", name)
+	}
+	if module.Prober != "http" {
+		return false, nil, fmt.Errorf("module '%s' uses the unsupported prober '%s'", name, module.Prober)
+	}
+	if module.HTTP == nil {
+		return false, nil, nil
+	}
+
+	http := module.HTTP
+	insecure := http.TLSConfig != nil && http.TLSConfig.InsecureSkipVerify
+	probe := &v1alpha1.HTTPProbeSpec{ProxyURL: http.ProxyURL, BodySizeLimit: http.BodySizeLimit, Compression: http.Compression}
+	if http.FollowRedirects != nil && !*http.FollowRedirects {
+		probe.FollowRedirects = http.FollowRedirects
+	}
+	if http.PreferredIPProtocol == string(v1alpha1.IPProtocolIP4) {
+		probe.PreferredIPProtocol = v1alpha1.IPProtocolIP4
+	}
+	if http.IPProtocolFallback != nil && !*http.IPProtocolFallback {
+		probe.IPProtocolFallback = http.IPProtocolFallback
+	}
+	for _, version := range http.ValidHTTPVersions {
+		probe.ValidHTTPVersions = append(probe.ValidHTTPVersions, v1alpha1.HTTPVersion(version))
+	}
+	probe.FailIfHeaderMatches = headerAssertions(http.FailIfHeaderMatches)
+	probe.FailIfHeaderNotMatches = headerAssertions(http.FailIfHeaderNotMatches)
+	if probe.FollowRedirects == nil && probe.PreferredIPProtocol == "" && probe.IPProtocolFallback == nil &&
+		probe.ProxyURL == "" && len(probe.ValidHTTPVersions) == 0 && probe.BodySizeLimit == "" && probe.Compression == "" &&
+		len(probe.FailIfHeaderMatches) == 0 && len(probe.FailIfHeaderNotMatches) == 0 {
+		probe = nil
+	}
+	return insecure, probe, nil
+}
+
+// headerAssertions converts the header assertions of a module
+func headerAssertions(matches []blackboxexporter.HeaderMatch) []v1alpha1.HeaderAssertion {
+	var assertions []v1alpha1.HeaderAssertion
+	for _, match := range matches {
+		assertions = append(assertions, v1alpha1.HeaderAssertion{Header: match.Header, Regexp: match.Regexp, AllowMissing: match.AllowMissing})
+	}
+	return assertions
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// uniqueName turns base into a valid object name that hasn't been handed out yet
+func uniqueName(names map[string]int, base string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if len(name) > 57 {
+		name = strings.TrimRight(name[:57], "-")
+	}
+	if name == "" {
+		name = "imported"
+	}
+	names[name]++
+	if names[name] > 1 {
+		return fmt.Sprintf("%s-%d", name, names[name])
+	}
+	return name
+}
+
+// Render returns the objects as a multi-document YAML stream, sorted by kind and name
+func Render(objects []client.Object) ([]byte, error) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		ki, kj := objects[i].GetObjectKind().GroupVersionKind().Kind, objects[j].GetObjectKind().GroupVersionKind().Kind
+		if ki != kj {
+			return ki < kj
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+	out := []byte{}
+	for _, object := range objects {
+		raw, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		// Drop the fields the API server sets, so the output is ready to be applied
+		fields := map[string]interface{}{}
+		if err := yaml.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		delete(fields, "status")
+		if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		if raw, err = yaml.Marshal(fields); err != nil {
+			return nil, err
+		}
+		out = append(out, "---\n"...)
+		out = append(out, raw...)
+	}
+	return out, nil
+}
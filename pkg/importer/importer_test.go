@@ -0,0 +1,150 @@
+package importer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/importer"
+)
+
+var _ = Describe("Importer", func() {
+	Describe("ParseTargets", func() {
+		It("reads the static targets of the probe jobs of a Prometheus configuration", func() {
+			targets, err := importer.ParseTargets("prometheus.yml", []byte(`
+scrape_configs:
+- job_name: node
+  static_configs:
+  - targets: [node:9100]
+- job_name: blackbox
+  metrics_path: /probe
+  params:
+    module: [http_no_redirects]
+  static_configs:
+  - targets: [https://console.apps.example.com]
+  - targets: [https://api.example.com:6443/version]
+    labels:
+      module: insecure_http_2xx
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]importer.Target{
+				{URL: "https://console.apps.example.com", Module: "http_no_redirects", Job: "blackbox"},
+				{URL: "https://api.example.com:6443/version", Module: "insecure_http_2xx", Job: "blackbox"},
+			}))
+		})
+		It("reads file_sd target files", func() {
+			targets, err := importer.ParseTargets("targets.json", []byte(`[{"targets": ["console.apps.example.com/healthz"]}]`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]importer.Target{
+				{URL: "console.apps.example.com/healthz", Module: "http_2xx", Job: "targets.json"},
+			}))
+		})
+		It("rejects other files", func() {
+			_, err := importer.ParseTargets("garbage", []byte(`foo: bar`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Convert", func() {
+		var (
+			opts    importer.Options
+			objects []client.Object
+			skipped []importer.Skipped
+		)
+		BeforeEach(func() {
+			followRedirects := false
+			opts = importer.Options{
+				Namespace:     "monitors",
+				ClusterDomain: "example.com",
+				SloTarget:     "99.5",
+				Routes: []routev1.Route{{
+					ObjectMeta: metav1.ObjectMeta{Name: "console", Namespace: "openshift-console"},
+					Status: routev1.RouteStatus{Ingress: []routev1.RouteIngress{
+						{Host: "console.apps.example.com"},
+					}},
+				}},
+				Modules: map[string]blackboxexporter.Module{
+					"http_no_redirects": {Prober: "http", HTTP: &blackboxexporter.HTTPProbe{FollowRedirects: &followRedirects}},
+					"icmp":              {Prober: "icmp"},
+				},
+			}
+		})
+		It("creates RouteMonitors for targets served by a Route", func() {
+			objects, skipped = importer.Convert([]importer.Target{
+				{URL: "https://console.apps.example.com:8443/healthz", Module: "http_no_redirects"},
+			}, opts)
+			Expect(skipped).To(BeEmpty())
+			Expect(objects).To(HaveLen(1))
+			routeMonitor := objects[0].(*v1alpha1.RouteMonitor)
+			Expect(routeMonitor.Name).To(Equal("console"))
+			Expect(routeMonitor.Namespace).To(Equal("monitors"))
+			Expect(routeMonitor.Spec.Route).To(Equal(v1alpha1.RouteMonitorRouteSpec{
+				Name:      "console",
+				Namespace: "openshift-console",
+				Port:      8443,
+				Suffix:    "/healthz",
+			}))
+			Expect(routeMonitor.Spec.Slo.TargetAvailabilityPercent).To(Equal("99.5"))
+			Expect(*routeMonitor.Spec.HTTPProbe.FollowRedirects).To(BeFalse())
+		})
+		It("creates ClusterUrlMonitors for other targets below the cluster domain", func() {
+			objects, skipped = importer.Convert([]importer.Target{
+				{URL: "https://api.example.com:6443/version", Module: "insecure_http_2xx"},
+				{URL: "http://oauth.apps.example.com", Module: "http_2xx"},
+			}, opts)
+			Expect(skipped).To(BeEmpty())
+			Expect(objects).To(HaveLen(2))
+			Expect(objects[0].GetName()).To(Equal("api"))
+			Expect(objects[0].(*v1alpha1.ClusterUrlMonitor).Spec).To(Equal(v1alpha1.ClusterUrlMonitorSpec{
+				Prefix: "https://api.",
+				Port:   "6443",
+				Suffix: "/version",
+				Slo:    v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"},
+			}))
+			Expect(objects[1].GetName()).To(Equal("oauth-apps"))
+			Expect(objects[1].(*v1alpha1.ClusterUrlMonitor).Spec.Port).To(Equal("80"))
+		})
+		It("gives monitors of the same Route distinct names", func() {
+			objects, _ = importer.Convert([]importer.Target{
+				{URL: "https://console.apps.example.com", Module: "http_2xx"},
+				{URL: "https://console.apps.example.com/healthz", Module: "http_2xx"},
+			}, opts)
+			Expect(objects[0].GetName()).To(Equal("console"))
+			Expect(objects[1].GetName()).To(Equal("console-2"))
+		})
+		It("skips targets it can't express as a monitor", func() {
+			objects, skipped = importer.Convert([]importer.Target{
+				{URL: "https://example.org", Module: "http_2xx"},
+				{URL: "https://console.apps.example.com", Module: "unknown"},
+				{URL: "console.apps.example.com", Module: "icmp"},
+				{URL: "ftp://console.apps.example.com", Module: "http_2xx"},
+			}, opts)
+			Expect(objects).To(BeEmpty())
+			Expect(skipped).To(HaveLen(4))
+		})
+	})
+
+	Describe("Render", func() {
+		It("writes one document per monitor", func() {
+			out, err := importer.Render([]client.Object{
+				&v1alpha1.RouteMonitor{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "monitoring.openshift.io/v1alpha1", Kind: "RouteMonitor"},
+					ObjectMeta: metav1.ObjectMeta{Name: "b"},
+				},
+				&v1alpha1.ClusterUrlMonitor{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "monitoring.openshift.io/v1alpha1", Kind: "ClusterUrlMonitor"},
+					ObjectMeta: metav1.ObjectMeta{Name: "a"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).NotTo(ContainSubstring("status"))
+			Expect(string(out)).NotTo(ContainSubstring("creationTimestamp"))
+			Expect(string(out)).To(MatchRegexp(`(?s)^---\napiVersion: monitoring.openshift.io/v1alpha1\nkind: ClusterUrlMonitor\n.*---\n.*kind: RouteMonitor\n`))
+		})
+	})
+})
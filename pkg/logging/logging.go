@@ -0,0 +1,120 @@
+// Package logging holds the verbosities and the key/value conventions of the operator's logs, and the levels of
+// its named loggers
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The verbosities of the logs below the info level, as passed to logr's V
+const (
+	// DebugVerbosity logs the steps of the reconciles and the decisions they take
+	DebugVerbosity = 2
+	// TraceVerbosity logs the details of the steps, e.g. the values they compared
+	TraceVerbosity = 3
+)
+
+// ForReconcile returns the logger of a reconcile of a monitor, with the kind, namespace and name of the monitor as values
+func ForReconcile(log logr.Logger, kind string, monitor types.NamespacedName) logr.Logger {
+	return log.WithValues("kind", kind, "namespace", monitor.Namespace, "name", monitor.Name)
+}
+
+// EnterStep logs that a step of a reconcile is entered and returns the logger of the step, with its name as the step value
+func EnterStep(log logr.Logger, step string) logr.Logger {
+	log = log.WithValues("step", step)
+	log.V(DebugVerbosity).Info("Entering the step")
+	return log
+}
+
+// ParseLevel parses the name of a level, e.g. 'info' or 'debug', or a verbosity, e.g. '3' for the traces
+func ParseLevel(level string) (zapcore.Level, error) {
+	if verbosity, err := strconv.Atoi(level); err == nil {
+		if verbosity < 0 {
+			return 0, fmt.Errorf("invalid verbosity %d: it must not be negative", verbosity)
+		}
+		return zapcore.Level(-verbosity), nil
+	}
+	var parsed zapcore.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return 0, err
+	}
+	return parsed, nil
+}
+
+// ParseLoggerLevels parses a comma separated list of logger=level pairs, e.g. 'RouteMonitor=3,UrlMonitor=debug'
+func ParseLoggerLevels(list string) (map[string]zapcore.Level, error) {
+	levels := map[string]zapcore.Level{}
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, level, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid logger level %q: it must be logger=level", pair)
+		}
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of the logger %s: %w", name, err)
+		}
+		levels[name] = parsed
+	}
+	return levels, nil
+}
+
+// WithLoggerLevels returns a zap option logging the entries of the named loggers at their own level, and the other
+// entries at the fallback level. A logger is named if one of the dot separated parts of its name is, e.g. RouteMonitor
+// for 'controllers.RouteMonitor.Reconcile'. The core the option wraps has to enable every level, it's left to this one
+func WithLoggerLevels(levels map[string]zapcore.Level, fallback zapcore.LevelEnabler) uberzap.Option {
+	return uberzap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &loggerLevelsCore{Core: core, levels: levels, fallback: fallback}
+	})
+}
+
+// loggerLevelsCore filters the entries by the level of their logger
+type loggerLevelsCore struct {
+	zapcore.Core
+	levels   map[string]zapcore.Level
+	fallback zapcore.LevelEnabler
+}
+
+// Enabled returns whether any logger logs at the level, as the entry and thus its logger aren't known yet
+func (c *loggerLevelsCore) Enabled(level zapcore.Level) bool {
+	if c.fallback.Enabled(level) {
+		return true
+	}
+	for _, enabled := range c.levels {
+		if enabled.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *loggerLevelsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &loggerLevelsCore{Core: c.Core.With(fields), levels: c.levels, fallback: c.fallback}
+}
+
+func (c *loggerLevelsCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levelOf(entry.LoggerName).Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// levelOf returns the level of the named logger, the first of its parts with a level of their own decides
+func (c *loggerLevelsCore) levelOf(name string) zapcore.LevelEnabler {
+	for _, part := range strings.Split(name, ".") {
+		if level, ok := c.levels[part]; ok {
+			return level
+		}
+	}
+	return c.fallback
+}
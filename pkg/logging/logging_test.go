@@ -0,0 +1,68 @@
+package logging_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/zapr"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+)
+
+var _ = Describe("Logging", func() {
+	Describe("ParseLevel", func() {
+		It("parses level names and verbosities", func() {
+			Expect(logging.ParseLevel("info")).To(Equal(zapcore.InfoLevel))
+			Expect(logging.ParseLevel("debug")).To(Equal(zapcore.DebugLevel))
+			Expect(logging.ParseLevel("3")).To(Equal(zapcore.Level(-3)))
+		})
+		It("rejects unknown levels and negative verbosities", func() {
+			_, err := logging.ParseLevel("verbose")
+			Expect(err).To(HaveOccurred())
+			_, err = logging.ParseLevel("-1")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ParseLoggerLevels", func() {
+		It("parses the levels of the loggers", func() {
+			Expect(logging.ParseLoggerLevels("RouteMonitor=3, UrlMonitor=error")).To(Equal(map[string]zapcore.Level{
+				"RouteMonitor": zapcore.Level(-3),
+				"UrlMonitor":   zapcore.ErrorLevel,
+			}))
+			Expect(logging.ParseLoggerLevels("")).To(BeEmpty())
+		})
+		It("rejects pairs without a logger", func() {
+			_, err := logging.ParseLoggerLevels("=3")
+			Expect(err).To(HaveOccurred())
+			_, err = logging.ParseLoggerLevels("RouteMonitor")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("WithLoggerLevels", func() {
+		It("logs the named loggers at their own level", func() {
+			core, logs := observer.New(zapcore.Level(-5))
+			levels := map[string]zapcore.Level{"RouteMonitor": zapcore.Level(-logging.TraceVerbosity)}
+			log := zapr.NewLogger(uberzap.New(core, logging.WithLoggerLevels(levels, zapcore.InfoLevel))).WithName("controllers")
+
+			routeMonitorLog := logging.ForReconcile(log.WithName("RouteMonitor"), "RouteMonitor", types.NamespacedName{Namespace: "ns", Name: "fake"})
+			logging.EnterStep(routeMonitorLog, "EnsureServiceMonitorExists")
+			routeMonitorLog.V(4).Info("too verbose")
+			log.WithName("UrlMonitor").V(logging.DebugVerbosity).Info("filtered")
+			log.WithName("UrlMonitor").Info("kept")
+
+			Expect(logs.AllUntimed()).To(HaveLen(2))
+			step := logs.AllUntimed()[0]
+			Expect(step.LoggerName).To(Equal("controllers.RouteMonitor"))
+			Expect(step.ContextMap()).To(Equal(map[string]interface{}{
+				"kind": "RouteMonitor", "namespace": "ns", "name": "fake", "step": "EnsureServiceMonitorExists",
+			}))
+			Expect(logs.AllUntimed()[1].Message).To(Equal("kept"))
+		})
+	})
+})
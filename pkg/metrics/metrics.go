@@ -0,0 +1,37 @@
+// Package metrics holds the operator's own metrics, served on the manager's metrics endpoint
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// SloTargetChanges counts the changes of the availability target of monitors
+	SloTargetChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "route_monitor_operator_slo_target_changes_total",
+		Help: "Number of changes of the availability target of monitors, by direction of the change",
+	}, []string{"kind", "namespace", "name", "direction"})
+
+	// TargetConflicts is the number of other monitors probing the url of a monitor with a different availability target
+	TargetConflicts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "route_monitor_operator_target_conflicts",
+		Help: "Number of other monitors probing the url of a monitor with a different availability target",
+	}, []string{"kind", "namespace", "name"})
+
+	// ReconcileErrors counts the failures of reconciling monitors by their class, see pkg/util/errors
+	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "route_monitor_operator_reconcile_errors_total",
+		Help: "Number of failures of reconciling monitors, by class of the failure",
+	}, []string{"kind", "class"})
+
+	// DryRunActions counts the writes of generated resources the operator would have done, if it didn't run in dry-run mode
+	DryRunActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "route_monitor_operator_dry_run_actions_total",
+		Help: "Number of writes the operator would have done outside of dry-run mode, by kind of resource and action",
+	}, []string{"kind", "action"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(SloTargetChanges, TargetConflicts, ReconcileErrors, DryRunActions)
+}
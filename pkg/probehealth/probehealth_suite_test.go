@@ -0,0 +1,13 @@
+package probehealth_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestProbeHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Probe Health Suite")
+}
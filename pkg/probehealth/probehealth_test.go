@@ -0,0 +1,160 @@
+package probehealth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	"github.com/openshift/route-monitor-operator/pkg/probehealth"
+)
+
+// fakeQuerier answers the availability and last success queries with fixed values
+type fakeQuerier struct {
+	availability, lastSuccess float64
+	found                     bool
+}
+
+func (q fakeQuerier) Query(_ context.Context, query string) (float64, bool, error) {
+	if strings.HasPrefix(query, "max(") {
+		return q.lastSuccess, q.found, nil
+	}
+	return q.availability, q.found, nil
+}
+
+var _ = Describe("PrometheusClient", func() {
+	var (
+		server    *httptest.Server
+		response  string
+		tokenDir  string
+		tokenFile string
+	)
+	BeforeEach(func() {
+		response = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.9975"]}]}}`
+		// GinkgoT().TempDir() returns no directory with ginkgo v1, which would write the token into the package
+		var err error
+		tokenDir, err = os.MkdirTemp("", "probehealth")
+		Expect(err).NotTo(HaveOccurred())
+		tokenFile = filepath.Join(tokenDir, "token")
+		Expect(os.WriteFile(tokenFile, []byte("fake-token\n"), 0600)).To(Succeed())
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer fake-token" || r.URL.Path != "/api/v1/query" || r.URL.Query().Get("query") != "up" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"status":"error","error":"forbidden"}`))
+				return
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+	})
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(tokenDir)).To(Succeed())
+	})
+
+	It("returns the value of the first sample", func() {
+		c, err := probehealth.NewPrometheusClient(server.URL+"/", tokenFile, "")
+		Expect(err).NotTo(HaveOccurred())
+		value, found, err := c.Query(context.Background(), "up")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(value).To(Equal(0.9975))
+	})
+
+	It("reports an empty result as not found", func() {
+		response = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+		c, err := probehealth.NewPrometheusClient(server.URL, tokenFile, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, found, err := c.Query(context.Background(), "up")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("fails when the query is rejected", func() {
+		c, err := probehealth.NewPrometheusClient(server.URL, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = c.Query(context.Background(), "up")
+		Expect(err).To(MatchError(ContainSubstring("forbidden")))
+	})
+})
+
+var _ = Describe("Reporter", func() {
+	var (
+		monitor *v1alpha1.RouteMonitor
+		querier fakeQuerier
+		c       client.Client
+	)
+	BeforeEach(func() {
+		monitor = &v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"},
+			Spec:       v1alpha1.RouteMonitorSpec{Slo: v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"}},
+			Status:     v1alpha1.RouteMonitorStatus{RouteURL: "https://fake-url"},
+		}
+		querier = fakeQuerier{availability: 0.9975, lastSuccess: 1700000000, found: true}
+	})
+	JustBeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(monitor).WithStatusSubresource(monitor).Build()
+		reporter := probehealth.NewReporter(c, querier, log.Log, time.Minute,
+			func(ctx context.Context) ([]client.Object, error) {
+				list := &v1alpha1.RouteMonitorList{}
+				if err := c.List(ctx, list); err != nil {
+					return nil, err
+				}
+				return []client.Object{&list.Items[0]}, nil
+			},
+			func(o client.Object) (probehealth.Target, bool) {
+				m := o.(*v1alpha1.RouteMonitor)
+				return probehealth.Target{URLs: []string{m.Status.RouteURL}, Slo: m.Spec.Slo, Health: &m.Status.ProbeHealth}, true
+			})
+		Expect(reporter.Report(context.Background())).To(Succeed())
+	})
+	reported := func() *v1alpha1.ProbeHealth {
+		updated := &v1alpha1.RouteMonitor{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(monitor), updated)).To(Succeed())
+		return updated.Status.ProbeHealth
+	}
+
+	It("reports the availability, the error budget left and the last success", func() {
+		health := reported()
+		Expect(health).NotTo(BeNil())
+		Expect(health.AvailabilityPercent).To(Equal("99.750"))
+		Expect(health.ErrorBudgetRemainingPercent).To(Equal("50.000"))
+		Expect(health.LastSuccessTime.Unix()).To(Equal(int64(1700000000)))
+		Expect(health.ObservedTime.IsZero()).To(BeFalse())
+	})
+
+	When("the monitor has no availability target", func() {
+		BeforeEach(func() {
+			monitor.Spec.Slo = v1alpha1.SloSpec{}
+		})
+		It("doesn't report an error budget", func() {
+			health := reported()
+			Expect(health.AvailabilityPercent).To(Equal("99.750"))
+			Expect(health.ErrorBudgetRemainingPercent).To(BeEmpty())
+		})
+	})
+
+	When("there are no probes yet", func() {
+		BeforeEach(func() {
+			querier.found = false
+		})
+		It("only reports when it queried", func() {
+			health := reported()
+			Expect(health.AvailabilityPercent).To(BeEmpty())
+			Expect(health.LastSuccessTime).To(BeNil())
+			Expect(health.ObservedTime.IsZero()).To(BeFalse())
+		})
+	})
+})
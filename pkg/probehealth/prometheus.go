@@ -0,0 +1,110 @@
+// Package probehealth reports the health of the probes of the monitors in their status, as queried from Prometheus
+package probehealth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// Querier runs instant queries
+type Querier interface {
+	// Query returns the value of the query's first sample, false if it returned none or not a number
+	Query(ctx context.Context, query string) (float64, bool, error)
+}
+
+// PrometheusClient queries the HTTP API of a Prometheus or Thanos querier, e.g. the thanos-querier of
+// openshift-monitoring, which requires the bearer token of a service account allowed to view the metrics
+type PrometheusClient struct {
+	// URL is the base url of the API, e.g. https://thanos-querier.openshift-monitoring.svc:9091
+	URL        string
+	HTTPClient *http.Client
+	// TokenFile, if set, holds the bearer token sent with every query. It's read for every query, so a
+	// rotated token is picked up
+	TokenFile string
+}
+
+// NewPrometheusClient returns a PrometheusClient trusting the CAs in caFile on top of the system ones, if it's set
+func NewPrometheusClient(baseURL, tokenFile, caFile string) (*PrometheusClient, error) {
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &PrometheusClient{
+		URL:        strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		TokenFile:  tokenFile,
+	}, nil
+}
+
+// queryResponse is the body the query API answers with
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string       `json:"resultType"`
+		Result     model.Vector `json:"result"`
+	} `json:"data"`
+}
+
+func (c *PrometheusClient) Query(ctx context.Context, query string) (float64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"/api/v1/query?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if c.TokenFile != "" {
+		token, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return 0, false, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	body := queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, false, fmt.Errorf("failed to decode the response of the query, status %s: %w", resp.Status, err)
+	}
+	if body.Status != "success" {
+		return 0, false, fmt.Errorf("the query failed with status %s: %s", resp.Status, body.Error)
+	}
+	if body.Data.ResultType != model.ValVector.String() {
+		return 0, false, fmt.Errorf("the query returned a %s instead of a vector", body.Data.ResultType)
+	}
+	if len(body.Data.Result) == 0 {
+		return 0, false, nil
+	}
+	value := float64(body.Data.Result[0].Value)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
@@ -0,0 +1,130 @@
+package probehealth
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+)
+
+// Target describes the probes of a monitor whose health is reported
+type Target struct {
+	// URLs are the probed urls, the health of all of them is reported as one
+	URLs []string
+	// Slo is the effective SLO of the monitor, the error budget is only reported if it has a valid target
+	Slo v1alpha1.SloSpec
+	// Health points at the probe health in the status of the monitor
+	Health **v1alpha1.ProbeHealth
+}
+
+// Reporter periodically queries the health of the probes of the monitors and patches it into their status, so
+// it's shown by 'oc get' without opening a dashboard
+type Reporter struct {
+	Client   client.Client
+	Querier  Querier
+	Log      logr.Logger
+	Interval time.Duration
+
+	// List returns the monitors to report on
+	List func(ctx context.Context) ([]client.Object, error)
+	// Target returns the probes of a monitor, false if it has none to report on yet, e.g. its url isn't known
+	Target func(monitor client.Object) (Target, bool)
+
+	now func() time.Time
+}
+
+// NewReporter returns a Reporter reporting on the monitors every interval
+func NewReporter(c client.Client, querier Querier, log logr.Logger, interval time.Duration,
+	list func(ctx context.Context) ([]client.Object, error), target func(monitor client.Object) (Target, bool)) *Reporter {
+	return &Reporter{
+		Client:   c,
+		Querier:  querier,
+		Log:      log,
+		Interval: interval,
+		List:     list,
+		Target:   target,
+		now:      time.Now,
+	}
+}
+
+// Start reports on the monitors right away and every interval after, until the context is done
+func (r *Reporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Report(ctx); err != nil {
+			r.Log.Error(err, "failed to report the probe health")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report queries the probe health of every monitor and patches it into its status
+func (r *Reporter) Report(ctx context.Context) error {
+	monitors, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range monitors {
+		target, ok := r.Target(monitor)
+		if !ok {
+			continue
+		}
+		health, err := r.health(ctx, target)
+		if err != nil {
+			r.Log.Error(err, "failed to query the probe health", "namespace", monitor.GetNamespace(), "name", monitor.GetName())
+			continue
+		}
+		original := monitor.DeepCopyObject().(client.Object)
+		*target.Health = health
+		if reflect.DeepEqual(original, monitor) {
+			continue
+		}
+		if err := r.Client.Status().Patch(ctx, monitor, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+			r.Log.Error(err, "failed to report the probe health", "namespace", monitor.GetNamespace(), "name", monitor.GetName())
+		}
+	}
+	return nil
+}
+
+// health queries the availability and the last successful probe of the target over the SLO period
+func (r *Reporter) health(ctx context.Context, target Target) (*v1alpha1.ProbeHealth, error) {
+	health := &v1alpha1.ProbeHealth{ObservedTime: metav1.NewTime(r.now().Truncate(time.Second))}
+	availability, found, err := r.Querier.Query(ctx, alert.AvailabilityQuery(target.URLs, target.Slo.ClientErrorsFail()))
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		health.AvailabilityPercent = percent(availability)
+		if isValid, objective := target.Slo.IsValid(); isValid {
+			if objective, err := strconv.ParseFloat(objective, 64); err == nil {
+				health.ErrorBudgetRemainingPercent = percent(1 - (1-availability)/(1-objective))
+			}
+		}
+	}
+	lastSuccess, found, err := r.Querier.Query(ctx, alert.LastSuccessQuery(target.URLs))
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		lastSuccessTime := metav1.NewTime(time.Unix(int64(lastSuccess), 0))
+		health.LastSuccessTime = &lastSuccessTime
+	}
+	return health, nil
+}
+
+// percent formats the ratio in percent, precise enough to tell apart the usual availability targets
+func percent(ratio float64) string {
+	return strconv.FormatFloat(ratio*100, 'f', 3, 64)
+}
@@ -3,23 +3,42 @@ package reconcileCommon
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/openshift/route-monitor-operator/pkg/clusteridentity"
 	"github.com/openshift/route-monitor-operator/pkg/util/finalizer"
 	"github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 
-	configv1 "github.com/openshift/api/config/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
 	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 //go:generate mockgen -source $GOFILE -destination ../util/test/generated/mocks/reconcile/common.go -package $GOPACKAGE
 
+// StatusUpdateBackoff bounds how often a conflicting status write is retried in place before the
+// reconcile is requeued. Status writes are cheap compared to a full reconcile, which re-templates
+// and re-reads every dependent resource
+var StatusUpdateBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
 type ResourceComparerInterface interface {
 	DeepEqual(x, y interface{}) bool
 }
@@ -30,17 +49,151 @@ func (_ *ResourceComparer) DeepEqual(x, y interface{}) bool {
 	return reflect.DeepEqual(x, y)
 }
 
+// HasLabels returns whether all expected labels are set on an object. It's used on every
+// reconcile to decide whether generated resources need an update, so it avoids building selectors
+func HasLabels(actual, expected map[string]string) bool {
+	for k, v := range expected {
+		if value, ok := actual[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataScrubber removes labels and annotations that mustn't end up on generated resources, e.g.
+// the tracking labels of a GitOps tool, which would otherwise claim the resources as its own
+type MetadataScrubber struct {
+	// Labels and Annotations are the keys removed. A key ending in '*' matches every key with that prefix
+	Labels      []string
+	Annotations []string
+}
+
+// Scrub removes the configured labels and annotations from an object and returns whether any were removed
+func (s MetadataScrubber) Scrub(o v1.Object) bool {
+	labels, scrubbedLabels := scrubKeys(o.GetLabels(), s.Labels)
+	annotations, scrubbedAnnotations := scrubKeys(o.GetAnnotations(), s.Annotations)
+	if scrubbedLabels {
+		o.SetLabels(labels)
+	}
+	if scrubbedAnnotations {
+		o.SetAnnotations(annotations)
+	}
+	return scrubbedLabels || scrubbedAnnotations
+}
+
+// scrubKeys returns a copy of values without the matching keys, if any matched
+func scrubKeys(values map[string]string, keys []string) (map[string]string, bool) {
+	var scrubbed map[string]string
+	for k := range values {
+		if !matchesKey(k, keys) {
+			continue
+		}
+		if scrubbed == nil {
+			scrubbed = make(map[string]string, len(values))
+			for key, value := range values {
+				scrubbed[key] = value
+			}
+		}
+		delete(scrubbed, k)
+	}
+	if scrubbed == nil {
+		return values, false
+	}
+	return scrubbed, true
+}
+
+func matchesKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if key == k {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(k, "*"); ok && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// ReasonReconciled is the reason of a condition whose resource is up to date
+	ReasonReconciled = "Reconciled"
+	// ReasonReconcileFailed is the reason of a condition whose resource couldn't be reconciled
+	ReasonReconcileFailed = "ReconcileFailed"
+	// ReasonPending is the reason of a Ready condition of a monitor whose ServiceMonitor hasn't been generated yet
+	ReasonPending = "Pending"
+	// ReasonRollingOut is the reason of a condition whose resource is being rolled out
+	ReasonRollingOut = "RollingOut"
+	// ReasonConflictingSLO is the reason of a condition warning about monitors probing the same url with
+	// different availability targets
+	ReasonConflictingSLO = "ConflictingSLO"
+	// ReasonHostedControlPlaneDeleted is the reason of a ServiceMonitorReady condition of a monitor whose hosted
+	// cluster was deleted, so its ServiceMonitor was deleted as well
+	ReasonHostedControlPlaneDeleted = "HostedControlPlaneDeleted"
+	// ReasonMonitoringAPIUnavailable is the reason of a ServiceMonitorReady condition of a monitor whose ServiceMonitor
+	// can't be generated, as no monitoring API serving ServiceMonitors is installed
+	ReasonMonitoringAPIUnavailable = "MonitoringAPIUnavailable"
+)
+
+// SetArtifactCondition records whether the resource a condition reports on has been reconciled, err
+// being the reason it hasn't. It returns whether the conditions changed
+func SetArtifactCondition(conditions *[]v1.Condition, conditionType string, generation int64, err error) bool {
+	condition := v1.Condition{
+		Type:               conditionType,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             ReasonReconciled,
+	}
+	if err != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonReconcileFailed
+		condition.Message = err.Error()
+	}
+	return meta.SetStatusCondition(conditions, condition)
+}
+
+// SetHostedControlPlaneDeletedCondition reports in the conditions that the monitor's ServiceMonitor was deleted along
+// with the HostedControlPlane of its namespace. It's regenerated once a HostedControlPlane is created there again
+func SetHostedControlPlaneDeletedCondition(conditions *[]v1.Condition, generation int64, namespace string) bool {
+	return meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               v1alpha1.ConditionServiceMonitorReady,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             ReasonHostedControlPlaneDeleted,
+		Message:            fmt.Sprintf("The namespace '%s' holds no HostedControlPlane anymore, the monitor is orphaned until one is created", namespace),
+	})
+}
+
+// SetMonitoringAPIUnavailableCondition reports in the conditions that the monitor's ServiceMonitor can't be generated
+// until a monitoring API serving ServiceMonitors is installed
+func SetMonitoringAPIUnavailableCondition(conditions *[]v1.Condition, generation int64) bool {
+	return meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               v1alpha1.ConditionServiceMonitorReady,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             ReasonMonitoringAPIUnavailable,
+		Message:            customerrors.NoMonitoringAPI.Error(),
+	})
+}
+
 type MonitorResourceCommon struct {
 	Client   client.Client
 	Ctx      context.Context
 	Comparer ResourceComparerInterface
+	// ClusterIdentity resolves the ID of a cluster that isn't hosted
+	ClusterIdentity clusteridentity.Provider
+	// HostedClusterIdentity resolves the ID of a hosted cluster
+	HostedClusterIdentity clusteridentity.Provider
+	// DryRun is set if Client is a DryRunClient: the updates of the monitors aren't persisted then
+	DryRun bool
 }
 
 func NewMonitorResourceCommon(ctx context.Context, c client.Client) *MonitorResourceCommon {
 	return &MonitorResourceCommon{
-		Client:   c,
-		Ctx:      ctx,
-		Comparer: &ResourceComparer{},
+		Client:                c,
+		Ctx:                   ctx,
+		Comparer:              &ResourceComparer{},
+		ClusterIdentity:       &clusteridentity.ClusterVersionProvider{Client: c},
+		HostedClusterIdentity: &clusteridentity.HostedControlPlaneProvider{Client: c},
 	}
 }
 
@@ -81,25 +234,74 @@ func (u *MonitorResourceCommon) SetResourceReference(reference *v1alpha1.Namespa
 		*reference = desiredRef
 		return true, nil
 	}
-	if *reference != desiredRef {
+	// The recorded UID isn't part of the reference's identity
+	if reference.Name != desiredRef.Name || reference.Namespace != desiredRef.Namespace {
 		// TODO Check when this is really required
 		return false, customerrors.InvalidReferenceUpdate
 	}
 	return false, nil
 }
 
+// GeneratedResourceName names a resource generated for a monitor. The monitor's name is suffixed with a hash
+// of its kind, namespace and name, so monitors never share a resource even if their names are equal.
+// A resource the monitor already references keeps its name, which adopts the ones deployed before
+func GeneratedResourceName(reference v1alpha1.NamespacedName, kind string, monitor types.NamespacedName) types.NamespacedName {
+	if reference != (v1alpha1.NamespacedName{}) {
+		return types.NamespacedName{Name: reference.Name, Namespace: reference.Namespace}
+	}
+	hasher := fnv.New32a()
+	hasher.Write([]byte(kind + "/" + monitor.Namespace + "/" + monitor.Name))
+	suffix := "-" + rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+
+	name := monitor.Name
+	if len(name)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength-len(suffix)]
+	}
+	return types.NamespacedName{Name: name + suffix, Namespace: monitor.Namespace}
+}
+
+// ControlledByOther returns true if the deployed object is controlled by another owner than the template,
+// which means two monitors resolved to the same resource
+func ControlledByOther(deployed, template v1.Object) bool {
+	owner := v1.GetControllerOf(template)
+	deployedOwner := v1.GetControllerOf(deployed)
+	return owner != nil && deployedOwner != nil && owner.UID != deployedOwner.UID
+}
+
+// IsStale returns true if the deployed object mustn't be adopted for the template: either it's controlled by
+// a previous incarnation of the template's owner, which was deleted and recreated with the same name, or
+// it isn't the object recorded by the owner. Without a recorded UID only the former is detected
+func IsStale(deployed, template v1.Object, recordedUID types.UID) bool {
+	owner := v1.GetControllerOf(template)
+	deployedOwner := v1.GetControllerOf(deployed)
+	if owner != nil && deployedOwner != nil && owner.UID != deployedOwner.UID &&
+		owner.Kind == deployedOwner.Kind && owner.Name == deployedOwner.Name {
+		return true
+	}
+	if ControlledByOther(deployed, template) {
+		// Another monitor's resource is reported as a collision, not replaced
+		return false
+	}
+	return recordedUID != "" && deployed.GetUID() != recordedUID
+}
+
 // remove boolean
 func (u *MonitorResourceCommon) ParseMonitorSLOSpecs(routeURL string, sloSpec v1alpha1.SloSpec) (string, error) {
 	if routeURL == "" {
 		return "", customerrors.NoHost
 	}
-	if sloSpec == (v1alpha1.SloSpec{}) {
+	if reflect.DeepEqual(sloSpec, v1alpha1.SloSpec{}) {
 		return "", nil
 	}
 	isValid, parsedSlo := sloSpec.IsValid()
 	if !isValid {
 		return "", customerrors.InvalidSLO
 	}
+	if sloSpec.Latency != nil {
+		if isValid, _ := sloSpec.Latency.IsValid(); !isValid {
+			return "", customerrors.InvalidSLO
+		}
+	}
 	return parsedSlo, nil
 }
 
@@ -128,39 +330,52 @@ func (u *MonitorResourceCommon) UpdateMonitorResource(cr client.Object) (reconci
 	if err := u.Client.Update(u.Ctx, cr); err != nil {
 		return reconcile.RequeueReconcileWith(err)
 	}
+	if u.DryRun && cr.GetDeletionTimestamp() == nil {
+		// A dry-run update triggers no reconcile, so the current one goes on
+		return reconcile.ContinueReconcile()
+	}
 	// After Updating watched CR we need to requeue, to prevent that two reconcile threads are running
 	return reconcile.StopReconcile()
 }
 
-// Updates the ClusterURLMonitor and RouteMonitor CR Status in reconcile loops
+// Updates the ClusterURLMonitor and RouteMonitor CR Status in reconcile loops.
+// Conflicts are retried in place with the latest resourceVersion, as the status of the monitors is
+// only written by this operator. Any other error, or running out of retries, requeues the reconcile
 func (u *MonitorResourceCommon) UpdateMonitorResourceStatus(cr client.Object) (reconcile.Result, error) {
-	if err := u.Client.Status().Update(u.Ctx, cr); err != nil {
+	statusWriter := u.Client.Status()
+	err := retry.RetryOnConflict(StatusUpdateBackoff, func() error {
+		err := statusWriter.Update(u.Ctx, cr)
+		if !k8serrors.IsConflict(err) {
+			return err
+		}
+		latest, ok := cr.DeepCopyObject().(client.Object)
+		if !ok {
+			return err
+		}
+		if getErr := u.Client.Get(u.Ctx, client.ObjectKeyFromObject(cr), latest); getErr != nil {
+			return getErr
+		}
+		cr.SetResourceVersion(latest.GetResourceVersion())
+		return err
+	})
+	if err != nil {
 		return reconcile.RequeueReconcileWith(err)
 	}
-	// After Updating watched CR we need to requeue, to prevent that two reconcile threads are running
-	return reconcile.StopReconcile()
+	return reconcile.ContinueReconcile()
 }
 
-// GetOSDClusterID returns the ID for the cluster based on its ClusterVersion
+// GetOSDClusterID returns the ID for the cluster, by default based on its ClusterVersion
 func (u *MonitorResourceCommon) GetOSDClusterID() (string, error) {
-	var version configv1.ClusterVersion
-	err := u.Client.Get(u.Ctx, client.ObjectKey{Name: "version"}, &version)
-	if err != nil {
-		return "", err
-	}
-	return string(version.Spec.ClusterID), nil
+	return u.ClusterIdentity.ClusterID(u.Ctx, "")
 }
 
-// GetHypershiftClusterID returns the ID for a hosted cluster based on the HCP object in the same namespace as the provided ClusterURLMonitor
+// GetHypershiftClusterID returns the ID for a hosted cluster, by default based on the HCP object in the same namespace as the provided ClusterURLMonitor
 func (u *MonitorResourceCommon) GetHypershiftClusterID(ns string) (string, error) {
-	hcp, err := u.GetHCP(ns)
-	if err != nil {
-		return "", err
-	}
-	return hcp.Spec.ClusterID, nil
+	return u.HostedClusterIdentity.ClusterID(u.Ctx, ns)
 }
 
-// GetHCP returns the HostedControlPlane object in the namespace provided. If more than one HCP object exists in the same namespace, an error is returned
+// GetHCP returns the HostedControlPlane object in the namespace provided. If more than one HCP object exists in the same namespace, an error is returned.
+// If none exists, the error wraps customerrors.NoHostedControlPlane
 func (u *MonitorResourceCommon) GetHCP(ns string) (hypershiftv1beta1.HostedControlPlane, error) {
 	// Retrieve the HostedControlPlane in order to lookup the associated hostedCluster object
 	hcpList := hypershiftv1beta1.HostedControlPlaneList{}
@@ -168,6 +383,9 @@ func (u *MonitorResourceCommon) GetHCP(ns string) (hypershiftv1beta1.HostedContr
 	if err != nil {
 		return hypershiftv1beta1.HostedControlPlane{}, err
 	}
+	if len(hcpList.Items) == 0 {
+		return hypershiftv1beta1.HostedControlPlane{}, fmt.Errorf("failed to retrieve the HostedControlPlane of namespace '%s': %w", ns, customerrors.NoHostedControlPlane)
+	}
 	if len(hcpList.Items) != 1 {
 		return hypershiftv1beta1.HostedControlPlane{}, fmt.Errorf("invalid number of HostedControlPlanes detected in namespace '%s': expected 1, got %d", ns, len(hcpList.Items))
 	}
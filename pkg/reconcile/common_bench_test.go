@@ -0,0 +1,55 @@
+package reconcileCommon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+)
+
+// Allocation budgets for the helpers running on every reconcile. They are checked by
+// TestPerformanceBudget, raise them deliberately when a change has to cost more
+const (
+	parseSLOAllocsBudget  = 16
+	hasLabelsAllocsBudget = 0
+)
+
+var benchmarkSlo = v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"}
+
+func BenchmarkParseMonitorSLOSpecs(b *testing.B) {
+	rc := reconcilecommon.NewMonitorResourceCommon(context.Background(), nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.ParseMonitorSLOSpecs("https://fake-url", benchmarkSlo)
+	}
+}
+
+func BenchmarkHasLabels(b *testing.B) {
+	actual := map[string]string{"a": "1", "b": "2", "c": "3"}
+	expected := map[string]string{"a": "1", "b": "2"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reconcilecommon.HasLabels(actual, expected)
+	}
+}
+
+func TestPerformanceBudget(t *testing.T) {
+	rc := reconcilecommon.NewMonitorResourceCommon(context.Background(), nil)
+	actual := map[string]string{"a": "1", "b": "2", "c": "3"}
+	expected := map[string]string{"a": "1", "b": "2"}
+
+	budgets := []struct {
+		name   string
+		budget float64
+		run    func()
+	}{
+		{"ParseMonitorSLOSpecs", parseSLOAllocsBudget, func() { _, _ = rc.ParseMonitorSLOSpecs("https://fake-url", benchmarkSlo) }},
+		{"HasLabels", hasLabelsAllocsBudget, func() { reconcilecommon.HasLabels(actual, expected) }},
+	}
+	for _, b := range budgets {
+		if allocs := testing.AllocsPerRun(100, b.run); allocs > b.budget {
+			t.Errorf("%s allocates %v times per run, exceeding its budget of %v", b.name, allocs, b.budget)
+		}
+	}
+}
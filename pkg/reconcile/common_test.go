@@ -116,6 +116,148 @@ var _ = Describe("CR Deployment Handling", func() {
 			})
 		})
 	})
+	Describe("SetArtifactCondition", func() {
+		var (
+			conditions []metav1.Condition
+		)
+		BeforeEach(func() {
+			conditions = nil
+		})
+		When("the resource failed to reconcile", func() {
+			It("should set the condition to false with the error and return true", func() {
+				res := reconcilecommon.SetArtifactCondition(&conditions, v1alpha1.ConditionPrometheusRuleReady, 2, consterror.CustomError)
+				Expect(res).To(Equal(true))
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Status).To(Equal(metav1.ConditionFalse))
+				Expect(conditions[0].Reason).To(Equal(reconcilecommon.ReasonReconcileFailed))
+				Expect(conditions[0].Message).To(Equal(consterror.CustomError.Error()))
+				Expect(conditions[0].ObservedGeneration).To(Equal(int64(2)))
+			})
+		})
+		When("the resource was already reported as reconciled", func() {
+			BeforeEach(func() {
+				reconcilecommon.SetArtifactCondition(&conditions, v1alpha1.ConditionServiceMonitorReady, 1, nil)
+			})
+			It("should leave the conditions untouched and return false", func() {
+				res := reconcilecommon.SetArtifactCondition(&conditions, v1alpha1.ConditionServiceMonitorReady, 1, nil)
+				Expect(res).To(Equal(false))
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			})
+		})
+	})
+	Describe("MetadataScrubber", func() {
+		var (
+			scrubber reconcilecommon.MetadataScrubber
+			object   metav1.ObjectMeta
+		)
+		BeforeEach(func() {
+			scrubber = reconcilecommon.MetadataScrubber{
+				Labels:      []string{"argocd.argoproj.io/*", "cost-center"},
+				Annotations: []string{"kubectl.kubernetes.io/last-applied-configuration"},
+			}
+			object = metav1.ObjectMeta{
+				Labels: map[string]string{
+					"argocd.argoproj.io/instance": "monitoring",
+					"cost-center":                 "1234",
+					"app":                         "blackbox",
+				},
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				},
+			}
+		})
+		When("the object carries scrubbed keys", func() {
+			It("should remove exact and prefix matches and return true", func() {
+				res := scrubber.Scrub(&object)
+				Expect(res).To(Equal(true))
+				Expect(object.Labels).To(Equal(map[string]string{"app": "blackbox"}))
+				Expect(object.Annotations).To(BeEmpty())
+			})
+		})
+		When("the object carries no scrubbed keys", func() {
+			BeforeEach(func() {
+				object = metav1.ObjectMeta{Labels: map[string]string{"cost-center-name": "infra"}}
+			})
+			It("should leave the object untouched and return false", func() {
+				res := scrubber.Scrub(&object)
+				Expect(res).To(Equal(false))
+				Expect(object.Labels).To(Equal(map[string]string{"cost-center-name": "infra"}))
+			})
+		})
+	})
+	Describe("GeneratedResourceName", func() {
+		var monitor types.NamespacedName
+		BeforeEach(func() {
+			monitor = types.NamespacedName{Name: "console", Namespace: "openshift-console"}
+		})
+		When("the monitor references no resource yet", func() {
+			It("should suffix the monitor's name with a stable hash", func() {
+				res := reconcilecommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "RouteMonitor", monitor)
+				Expect(res.Namespace).To(Equal(monitor.Namespace))
+				Expect(res.Name).To(HavePrefix("console-"))
+				Expect(res).To(Equal(reconcilecommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "RouteMonitor", monitor)))
+			})
+			It("should name monitors of other kinds or namespaces differently", func() {
+				res := reconcilecommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "RouteMonitor", monitor)
+				Expect(reconcilecommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "ClusterUrlMonitor", monitor).Name).NotTo(Equal(res.Name))
+				other := types.NamespacedName{Name: monitor.Name, Namespace: "other"}
+				Expect(reconcilecommon.GeneratedResourceName(v1alpha1.NamespacedName{}, "RouteMonitor", other).Name).NotTo(Equal(res.Name))
+			})
+		})
+		When("the monitor already references a resource", func() {
+			It("should keep its name", func() {
+				ref := v1alpha1.NamespacedName{Name: "console", Namespace: "openshift-console"}
+				res := reconcilecommon.GeneratedResourceName(ref, "RouteMonitor", monitor)
+				Expect(res).To(Equal(types.NamespacedName{Name: "console", Namespace: "openshift-console"}))
+			})
+		})
+	})
+	Describe("ControlledByOther", func() {
+		var deployed, template metav1.ObjectMeta
+		BeforeEach(func() {
+			isController := true
+			deployed = metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "RouteMonitor", Name: "console", UID: "a", Controller: &isController}}}
+			template = metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "RouteMonitor", Name: "console", UID: "a", Controller: &isController}}}
+		})
+		It("should return false for the same controller", func() {
+			Expect(reconcilecommon.ControlledByOther(&deployed, &template)).To(BeFalse())
+		})
+		It("should return true for another controller", func() {
+			template.OwnerReferences[0].UID = "b"
+			Expect(reconcilecommon.ControlledByOther(&deployed, &template)).To(BeTrue())
+		})
+		It("should return false if the deployed object has no controller", func() {
+			deployed.OwnerReferences = nil
+			Expect(reconcilecommon.ControlledByOther(&deployed, &template)).To(BeFalse())
+		})
+	})
+	Describe("IsStale", func() {
+		var deployed, template metav1.ObjectMeta
+		BeforeEach(func() {
+			isController := true
+			deployed = metav1.ObjectMeta{UID: "deployed", OwnerReferences: []metav1.OwnerReference{{Kind: "RouteMonitor", Name: "console", UID: "a", Controller: &isController}}}
+			template = metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "RouteMonitor", Name: "console", UID: "a", Controller: &isController}}}
+		})
+		It("should return false for the recorded object", func() {
+			Expect(reconcilecommon.IsStale(&deployed, &template, "deployed")).To(BeFalse())
+		})
+		It("should return false without a recorded UID", func() {
+			Expect(reconcilecommon.IsStale(&deployed, &template, "")).To(BeFalse())
+		})
+		It("should return true for an object recreated since it was recorded", func() {
+			Expect(reconcilecommon.IsStale(&deployed, &template, "previous")).To(BeTrue())
+		})
+		It("should return true for an object of a previous incarnation of the owner", func() {
+			template.OwnerReferences[0].UID = "b"
+			Expect(reconcilecommon.IsStale(&deployed, &template, "")).To(BeTrue())
+		})
+		It("should return false for an object of another owner", func() {
+			template.OwnerReferences[0].Name = "downloads"
+			template.OwnerReferences[0].UID = "b"
+			Expect(reconcilecommon.IsStale(&deployed, &template, "previous")).To(BeFalse())
+		})
+	})
 	Describe("ParseMonitorSLOSpecs", func() {
 		var (
 			sloSpec v1alpha1.SloSpec
@@ -182,6 +324,24 @@ var _ = Describe("CR Deployment Handling", func() {
 				Expect(err).To(Not(HaveOccurred()))
 			})
 		})
+		When("the latency objective is valid", func() {
+			BeforeEach(func() {
+				sloSpec.Latency = &v1alpha1.LatencySloSpec{ThresholdMilliseconds: 500, TargetPercent: "95"}
+			})
+			It("should return the availability target", func() {
+				Expect(res).To(Equal("0.995"))
+				Expect(err).To(Not(HaveOccurred()))
+			})
+		})
+		When("the latency objective is invalid", func() {
+			BeforeEach(func() {
+				sloSpec.Latency = &v1alpha1.LatencySloSpec{ThresholdMilliseconds: 500, TargetPercent: "100"}
+			})
+			It("should return an empty string and an error", func() {
+				Expect(res).To(Equal(""))
+				Expect(err).To(Equal(customerrors.InvalidSLO))
+			})
+		})
 	})
 	Describe("UpdateMonitorResource", func() {
 		var (
@@ -312,11 +472,44 @@ var _ = Describe("CR Deployment Handling", func() {
 			BeforeEach(func() {
 				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1)
 			})
-			It("should stop reconciling", func() {
-				Expect(res).To(Equal(reconcile.StopOperation()))
+			It("should continue", func() {
+				Expect(res).To(Equal(reconcile.ContinueOperation()))
 				Expect(err).To(Not(HaveOccurred()))
 			})
 		})
+		When("when updating the monitor conflicts once", func() {
+			BeforeEach(func() {
+				get.CalledTimes = 1
+				gomock.InOrder(
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(consterror.ConflictErr),
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1),
+				)
+			})
+			It("should retry in place and continue", func() {
+				Expect(res).To(Equal(reconcile.ContinueOperation()))
+				Expect(err).To(Not(HaveOccurred()))
+			})
+		})
+		When("when updating the monitor keeps conflicting", func() {
+			BeforeEach(func() {
+				get.CalledTimes = reconcilecommon.StatusUpdateBackoff.Steps
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(reconcilecommon.StatusUpdateBackoff.Steps).Return(consterror.ConflictErr)
+			})
+			It("should requeue with the conflict once the retries are exhausted", func() {
+				Expect(res).To(Equal(reconcile.RequeueOperation()))
+				Expect(err).To(Equal(consterror.ConflictErr))
+			})
+		})
+		When("when refreshing the monitor after a conflict fails", func() {
+			BeforeEach(func() {
+				get = testhelper.CustomErrorHappensOnce()
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(consterror.ConflictErr)
+			})
+			It("should requeue with the particular error", func() {
+				Expect(res).To(Equal(reconcile.RequeueOperation()))
+				Expect(err).To(Equal(consterror.CustomError))
+			})
+		})
 	})
 	Describe("SetFinalizer", func() {
 		var (
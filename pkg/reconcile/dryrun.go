@@ -0,0 +1,118 @@
+package reconcileCommon
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/route-monitor-operator/pkg/metrics"
+)
+
+// DryRunClient sends every write to the API server as a dry run, so it's validated but not persisted, except the
+// writes of the monitors' status: they record what would have been written. Every write is counted in the
+// DryRunActions metric, logged, emitted as an Event on the monitor controlling the resource and kept until the
+// next call of Actions
+type DryRunClient struct {
+	client.Client
+	status   client.SubResourceWriter
+	Recorder record.EventRecorder
+	Log      logr.Logger
+
+	mu      sync.Mutex
+	actions []string
+}
+
+// NewDryRunClient returns a DryRunClient reading from and dry-running the writes through c
+func NewDryRunClient(c client.Client, recorder record.EventRecorder, log logr.Logger) *DryRunClient {
+	return &DryRunClient{
+		Client:   client.NewDryRunClient(c),
+		status:   c.Status(),
+		Recorder: recorder,
+		Log:      log,
+	}
+}
+
+// Create dry-runs the creation of the object
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj, "create")
+	return nil
+}
+
+// Update dry-runs the update of the object
+func (c *DryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj, "update")
+	return nil
+}
+
+// Patch dry-runs the patch of the object
+func (c *DryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	c.record(obj, "patch")
+	return nil
+}
+
+// Delete dry-runs the deletion of the object
+func (c *DryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj, "delete")
+	return nil
+}
+
+// Status writes the status of the monitors for real
+func (c *DryRunClient) Status() client.SubResourceWriter {
+	return c.status
+}
+
+// Actions returns the writes that were dry-run since the last call, e.g. "create ServiceMonitor namespace/name".
+// A reconciler's own client returns the actions of its current reconcile, as its reconciles run one at a time
+func (c *DryRunClient) Actions() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	actions := c.actions
+	c.actions = nil
+	return actions
+}
+
+// record keeps the dry-run write of the object
+func (c *DryRunClient) record(obj client.Object, action string) {
+	kind := reflect.TypeOf(obj).Elem().Name()
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	metrics.DryRunActions.WithLabelValues(kind, action).Inc()
+	c.Log.Info("dry run: skipped a write", "action", action, "kind", kind, "name", name)
+	eventOnController(c.Recorder, obj, DryRunReason, "Would %s %s %s", action, kind, obj.GetName())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = append(c.actions, strings.Join([]string{action, kind, name}, " "))
+}
+
+// DryRunMessage summarizes the actions of a dry-run reconcile for the DryRun condition of a monitor
+func DryRunMessage(actions []string) string {
+	if len(actions) == 0 {
+		return "Dry run: the generated resources are up to date"
+	}
+	return fmt.Sprintf("Dry run: would %s", strings.Join(actions, ", "))
+}
@@ -0,0 +1,167 @@
+package reconcileCommon
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// EnsureOptions configure how EnsureResource converges a generated resource on its template
+type EnsureOptions[T client.Object] struct {
+	Client   client.Client
+	Scrubber MetadataScrubber
+
+	// SpecEqual returns whether the spec of the deployed resource matches the one of the template
+	SpecEqual func(deployed, template T) bool
+	// CopySpec sets the spec of the template on the deployed resource
+	CopySpec func(deployed, template T)
+
+	// Adopt enables the ownership checks: a stale resource, see IsStale, is recreated and one controlled by
+	// another owner fails with a ResourceNameCollision. RecordedUID is the UID the owner recorded, if any
+	Adopt       bool
+	RecordedUID types.UID
+
+	// FieldOwner, if set, applies changed resources server-side with it as the field manager instead of
+	// updating them. Labels other managers own are kept then, even if scrubbed
+	FieldOwner string
+
+	// Recorder, if set, emits an Event on the monitor controlling the resource whenever it's created or updated
+	Recorder record.EventRecorder
+}
+
+// EnsureResource creates the template's resource or updates it if it differs from the template, and returns its UID
+func EnsureResource[T client.Object](ctx context.Context, opts EnsureOptions[T], template T) (types.UID, error) {
+	// The deployed resource is read into an empty object of the template's type
+	deployed := reflect.New(reflect.TypeOf(template).Elem()).Interface().(T)
+	err := opts.Client.Get(ctx, client.ObjectKeyFromObject(template), deployed)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return "", err
+	}
+	exists := err == nil
+	if exists && opts.Adopt && IsStale(deployed, template, opts.RecordedUID) {
+		// Never adopt a resource that was recreated behind the monitor's back, e.g. in a recycled namespace
+		if err := opts.Client.Delete(ctx, deployed); err != nil {
+			return "", err
+		}
+		exists = false
+	}
+	if !exists {
+		opts.Scrubber.Scrub(template)
+		if err := opts.Client.Create(ctx, template); err != nil {
+			return "", err
+		}
+		opts.recordChange(template, "Created")
+		return template.GetUID(), nil
+	}
+	if opts.Adopt && ControlledByOther(deployed, template) {
+		return "", customerrors.ResourceNameCollision
+	}
+	if !opts.Changed(deployed, template) {
+		return deployed.GetUID(), nil
+	}
+	if opts.FieldOwner != "" {
+		uid, err := opts.apply(ctx, template)
+		if err != nil {
+			return "", err
+		}
+		opts.recordChange(template, "Updated")
+		return uid, nil
+	}
+	if controller := v1.GetControllerOf(template); controller != nil && v1.GetControllerOf(deployed) == nil {
+		// Resources created before they were owned by their monitor are adopted, so they're garbage collected
+		deployed.SetOwnerReferences(append(deployed.GetOwnerReferences(), *controller))
+	}
+	opts.CopySpec(deployed, template)
+	deployed.SetLabels(labels.Merge(deployed.GetLabels(), template.GetLabels()))
+	if err := opts.Client.Update(ctx, deployed); err != nil {
+		return "", err
+	}
+	opts.recordChange(template, "Updated")
+	return deployed.GetUID(), nil
+}
+
+// Changed scrubs both resources and returns whether the deployed one has to be updated to match the template:
+// its spec differs, it lacks a label or the controller of the template or carried metadata that was scrubbed
+func (opts EnsureOptions[T]) Changed(deployed, template T) bool {
+	scrubbed := opts.Scrubber.Scrub(deployed)
+	opts.Scrubber.Scrub(template)
+	uncontrolled := v1.GetControllerOf(template) != nil && v1.GetControllerOf(deployed) == nil
+	return scrubbed || uncontrolled || !opts.SpecEqual(deployed, template) || !HasLabels(deployed.GetLabels(), template.GetLabels())
+}
+
+// DeleteUncollected deletes the generated resources of a monitor that's being deleted, except the ones it controls:
+// the garbage collector deletes those with the monitor. Resources that don't exist, or whose kind doesn't, are skipped
+func DeleteUncollected(ctx context.Context, c client.Client, monitor v1.Object, resources ...client.Object) error {
+	for _, resource := range resources {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(resource), resource); err != nil {
+			// Without its CRD, e.g. after prometheus-operator was uninstalled, there's no resource left to delete
+			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		if controller := v1.GetControllerOf(resource); controller != nil && controller.UID == monitor.GetUID() {
+			continue
+		}
+		if err := c.Delete(ctx, resource); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CleanupSkipReason returns why the generated resources of a monitor that's being deleted aren't deleted before its
+// finalizer is dropped, empty if they are: the deletion is forced with the ForceDeleteAnnotation, or the namespace of the
+// monitor is terminating and takes them along
+func CleanupSkipReason(ctx context.Context, c client.Reader, monitor client.Object) (string, error) {
+	if monitor.GetAnnotations()[consts.ForceDeleteAnnotation] == "true" {
+		return fmt.Sprintf("the deletion is forced with the %s annotation", consts.ForceDeleteAnnotation), nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: monitor.GetNamespace()}, namespace); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Sprintf("the namespace %s is gone", monitor.GetNamespace()), nil
+		}
+		return "", err
+	}
+	if namespace.DeletionTimestamp != nil {
+		return fmt.Sprintf("the namespace %s is terminating", monitor.GetNamespace()), nil
+	}
+	return "", nil
+}
+
+// recordChange emits an Event for the created or updated resource, unless the change was only dry-run: the
+// DryRunClient emits its own Event then
+func (opts EnsureOptions[T]) recordChange(resource T, change string) {
+	if _, dryRun := opts.Client.(*DryRunClient); dryRun {
+		return
+	}
+	recordChange(opts.Recorder, resource, change)
+}
+
+// apply patches the template server-side, which requires the kind of the resource to be set
+func (opts EnsureOptions[T]) apply(ctx context.Context, template T) (types.UID, error) {
+	gvk, err := apiutil.GVKForObject(template, opts.Client.Scheme())
+	if err != nil {
+		return "", err
+	}
+	template.GetObjectKind().SetGroupVersionKind(gvk)
+	template.SetResourceVersion("")
+	template.SetManagedFields(nil)
+	if err := opts.Client.Patch(ctx, template, client.Apply, client.FieldOwner(opts.FieldOwner), client.ForceOwnership); err != nil {
+		return "", err
+	}
+	return template.GetUID(), nil
+}
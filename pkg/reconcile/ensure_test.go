@@ -0,0 +1,290 @@
+package reconcileCommon_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+)
+
+var _ = Describe("EnsureResource", func() {
+	var (
+		ctx      context.Context
+		objects  []client.Object
+		opts     reconcilecommon.EnsureOptions[*monitoringv1.ServiceMonitor]
+		template *monitoringv1.ServiceMonitor
+		patches  []client.Patch
+		c        client.Client
+		recorder *record.FakeRecorder
+		uid      types.UID
+		err      error
+	)
+	owner := func(uid types.UID) []metav1.OwnerReference {
+		controller := true
+		return []metav1.OwnerReference{{Kind: "RouteMonitor", Name: "fake-name", UID: uid, Controller: &controller}}
+	}
+	serviceMonitor := func(uid types.UID, path string) *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace", OwnerReferences: owner(uid), Labels: map[string]string{"team": "shop"}},
+			Spec:       monitoringv1.ServiceMonitorSpec{Endpoints: []monitoringv1.Endpoint{{Path: path}}},
+		}
+	}
+	get := func() *monitoringv1.ServiceMonitor {
+		deployed := &monitoringv1.ServiceMonitor{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(template), deployed)).To(Succeed())
+		return deployed
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = nil
+		patches = nil
+		template = serviceMonitor("monitor-uid", "/probe")
+		opts = reconcilecommon.EnsureOptions[*monitoringv1.ServiceMonitor]{
+			SpecEqual: func(deployed, template *monitoringv1.ServiceMonitor) bool {
+				return (&reconcilecommon.ResourceComparer{}).DeepEqual(deployed.Spec, template.Spec)
+			},
+			CopySpec: func(deployed, template *monitoringv1.ServiceMonitor) { deployed.Spec = template.Spec },
+			Adopt:    true,
+		}
+		recorder = record.NewFakeRecorder(10)
+		opts.Recorder = recorder
+	})
+	JustBeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+			// The fake client doesn't support server-side apply
+			Patch: func(ctx context.Context, client client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches = append(patches, patch)
+				return nil
+			},
+		}).Build()
+		opts.Client = c
+		uid, err = reconcilecommon.EnsureResource(ctx, opts, template)
+	})
+
+	When("the resource doesn't exist", func() {
+		It("creates it", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get().Spec.Endpoints[0].Path).To(Equal("/probe"))
+			Expect(uid).To(Equal(get().UID))
+			Expect(recorder.Events).To(Receive(Equal("Normal ServiceMonitorCreated Created ServiceMonitor fake-name")))
+		})
+	})
+
+	When("the resource differs from the template", func() {
+		BeforeEach(func() {
+			deployed := serviceMonitor("monitor-uid", "/metrics")
+			deployed.Labels = map[string]string{"argocd": "app"}
+			objects = []client.Object{deployed}
+		})
+		It("updates its spec and merges the labels", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get().Spec.Endpoints[0].Path).To(Equal("/probe"))
+			Expect(get().Labels).To(Equal(map[string]string{"argocd": "app", "team": "shop"}))
+			Expect(recorder.Events).To(Receive(Equal("Normal ServiceMonitorUpdated Updated ServiceMonitor fake-name")))
+		})
+		When("it's applied server-side", func() {
+			BeforeEach(func() {
+				opts.FieldOwner = "route-monitor-operator"
+			})
+			It("patches it instead of updating", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patches).To(Equal([]client.Patch{client.Apply}))
+				Expect(template.Kind).To(Equal("ServiceMonitor"))
+				Expect(get().Spec.Endpoints[0].Path).To(Equal("/metrics"))
+			})
+		})
+	})
+
+	When("the resource matches the template", func() {
+		BeforeEach(func() {
+			objects = []client.Object{serviceMonitor("monitor-uid", "/probe")}
+			opts.FieldOwner = "route-monitor-operator"
+		})
+		It("leaves it be", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(BeEmpty())
+			Expect(get().ResourceVersion).To(Equal("999"))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	When("the resource is controlled by another monitor", func() {
+		BeforeEach(func() {
+			deployed := serviceMonitor("other-uid", "/metrics")
+			deployed.OwnerReferences[0].Name = "other-name"
+			objects = []client.Object{deployed}
+		})
+		It("reports a collision", func() {
+			Expect(err).To(Equal(customerrors.ResourceNameCollision))
+			Expect(get().Spec.Endpoints[0].Path).To(Equal("/metrics"))
+		})
+		When("ownership isn't checked", func() {
+			BeforeEach(func() {
+				opts.Adopt = false
+			})
+			It("updates it", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(get().Spec.Endpoints[0].Path).To(Equal("/probe"))
+			})
+		})
+	})
+
+	When("the resource was created without a controller", func() {
+		BeforeEach(func() {
+			deployed := serviceMonitor("monitor-uid", "/probe")
+			deployed.OwnerReferences = nil
+			objects = []client.Object{deployed}
+		})
+		It("adopts it", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get().OwnerReferences).To(Equal(owner("monitor-uid")))
+		})
+	})
+
+	When("the resource belongs to a deleted monitor of the same name", func() {
+		BeforeEach(func() {
+			objects = []client.Object{serviceMonitor("previous-uid", "/probe")}
+		})
+		It("recreates it", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get().OwnerReferences[0].UID).To(Equal(types.UID("monitor-uid")))
+		})
+	})
+})
+
+var _ = Describe("EnsureOptions.Changed", func() {
+	opts := reconcilecommon.EnsureOptions[*monitoringv1.ServiceMonitor]{
+		Scrubber: reconcilecommon.MetadataScrubber{Labels: []string{"argocd.argoproj.io/*"}},
+		SpecEqual: func(deployed, template *monitoringv1.ServiceMonitor) bool {
+			return deployed.Spec.JobLabel == template.Spec.JobLabel
+		},
+	}
+	for _, tc := range []struct {
+		name     string
+		deployed *monitoringv1.ServiceMonitor
+		changed  bool
+	}{
+		{"an equal resource", &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "shop", "extra": "kept"}}}, false},
+		{"a different spec", &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "shop"}}, Spec: monitoringv1.ServiceMonitorSpec{JobLabel: "job"}}, true},
+		{"a missing label", &monitoringv1.ServiceMonitor{}, true},
+		{"a scrubbed label", &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "shop", "argocd.argoproj.io/instance": "app"}}}, true},
+	} {
+		tc := tc
+		It("detects "+tc.name, func() {
+			template := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "shop"}}}
+			Expect(opts.Changed(tc.deployed, template)).To(Equal(tc.changed))
+		})
+	}
+})
+
+var _ = Describe("DeleteUncollected", func() {
+	var (
+		ctx     context.Context
+		monitor *metav1.ObjectMeta
+		c       client.Client
+	)
+	serviceMonitor := func(name string, controller types.UID) *monitoringv1.ServiceMonitor {
+		resource := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fake-namespace"}}
+		if controller != "" {
+			resource.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&metav1.ObjectMeta{Name: "fake-name", UID: controller}, monitoringv1.SchemeGroupVersion.WithKind("RouteMonitor"))}
+		}
+		return resource
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		monitor = &metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace", UID: "monitor-uid"}
+		c = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(
+			serviceMonitor("controlled", "monitor-uid"),
+			serviceMonitor("uncontrolled", ""),
+			serviceMonitor("other", "other-uid"),
+		).Build()
+	})
+	It("leaves the resources the monitor controls to the garbage collector", func() {
+		Expect(reconcilecommon.DeleteUncollected(ctx, c, monitor,
+			serviceMonitor("controlled", ""), serviceMonitor("uncontrolled", ""), serviceMonitor("other", ""), serviceMonitor("missing", ""),
+		)).To(Succeed())
+		list := &monitoringv1.ServiceMonitorList{}
+		Expect(c.List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Name).To(Equal("controlled"))
+	})
+	It("skips the resources whose CRD isn't installed", func() {
+		c = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return &meta.NoKindMatchError{GroupKind: monitoringv1.SchemeGroupVersion.WithKind("ServiceMonitor").GroupKind()}
+			},
+		})
+		Expect(reconcilecommon.DeleteUncollected(ctx, c, monitor, serviceMonitor("uncontrolled", ""))).To(Succeed())
+	})
+})
+
+var _ = Describe("CleanupSkipReason", func() {
+	var (
+		ctx       context.Context
+		monitor   *monitoringv1.ServiceMonitor
+		namespace *corev1.Namespace
+		objects   []client.Object
+		reason    string
+		err       error
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		monitor = &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace"}}
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fake-namespace"}}
+		objects = []client.Object{namespace}
+	})
+	JustBeforeEach(func() {
+		c := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(objects...).Build()
+		reason, err = reconcilecommon.CleanupSkipReason(ctx, c, monitor)
+	})
+	When("the namespace of the monitor is alive", func() {
+		It("doesn't skip the cleanup", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(BeEmpty())
+		})
+	})
+	When("the deletion is forced", func() {
+		BeforeEach(func() {
+			monitor.Annotations = map[string]string{consts.ForceDeleteAnnotation: "true"}
+		})
+		It("skips the cleanup", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(ContainSubstring(consts.ForceDeleteAnnotation))
+		})
+	})
+	When("the namespace of the monitor is terminating", func() {
+		BeforeEach(func() {
+			namespace.DeletionTimestamp = &metav1.Time{Time: time.Unix(0, 0)}
+			namespace.Finalizers = []string{"kubernetes"}
+		})
+		It("skips the cleanup", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal("the namespace fake-namespace is terminating"))
+		})
+	})
+	When("the namespace of the monitor is gone", func() {
+		BeforeEach(func() {
+			objects = nil
+		})
+		It("skips the cleanup", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal("the namespace fake-namespace is gone"))
+		})
+	})
+})
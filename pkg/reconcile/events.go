@@ -0,0 +1,64 @@
+package reconcileCommon
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The reasons of the Events emitted on the monitors for the outcome of their reconciles
+const (
+	// RouteURLResolvedReason is emitted when the url probed for a RouteMonitor was read from its Route
+	RouteURLResolvedReason = "RouteURLResolved"
+	// InvalidSloReason is emitted when no PrometheusRule can be generated from the monitor's SLO
+	InvalidSloReason = "InvalidSlo"
+	// DeletionBlockedReason is emitted when the finalizer of a deleted monitor is kept because its generated
+	// resources couldn't be removed
+	DeletionBlockedReason = "DeletionBlocked"
+	// CleanupSkippedReason is emitted when the finalizer of a deleted monitor is dropped without deleting its generated
+	// resources, see CleanupSkipReason
+	CleanupSkippedReason = "CleanupSkipped"
+	// DryRunReason is emitted in dry-run mode for every write of a generated resource that was skipped
+	DryRunReason = "DryRun"
+	// ProbeFailedReason is emitted with the transcript of a failing probe of a monitor in debug mode
+	ProbeFailedReason = "ProbeFailed"
+	// DebugProbeErrorReason is emitted when a monitor in debug mode couldn't be probed with the exporter's debug output
+	DebugProbeErrorReason = "DebugProbeError"
+)
+
+// Eventf emits an Event on the monitor, if there's a recorder
+func Eventf(recorder record.EventRecorder, monitor runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(monitor, eventType, reason, messageFmt, args...)
+}
+
+// recordChange emits an Event on the monitor controlling a generated resource that was created or updated,
+// e.g. with the reason 'ServiceMonitorCreated'. Resources without a controller are skipped
+func recordChange(recorder record.EventRecorder, resource client.Object, change string) {
+	kind := reflect.TypeOf(resource).Elem().Name()
+	eventOnController(recorder, resource, kind+change, "%s %s %s", change, kind, resource.GetName())
+}
+
+// eventOnController emits an Event on the monitor controlling a generated resource. Resources without a controller
+// are skipped
+func eventOnController(recorder record.EventRecorder, resource client.Object, reason, messageFmt string, args ...interface{}) {
+	owner := metav1.GetControllerOf(resource)
+	if recorder == nil || owner == nil {
+		return
+	}
+	// The generated resources live in the namespace of their monitor
+	monitor := &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		Namespace:  resource.GetNamespace(),
+		UID:        owner.UID,
+	}
+	recorder.Eventf(monitor, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
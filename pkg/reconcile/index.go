@@ -0,0 +1,37 @@
+package reconcileCommon
+
+import (
+	"context"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteIndex indexes the RouteMonitors by the namespace/name of the Route they read the url from
+const RouteIndex = "spec.route"
+
+// IndexRouteMonitors registers RouteIndex, so the RouteMonitors of a Route are looked up without listing all of them.
+// It has to be registered once per manager, before the controllers using it are started
+func IndexRouteMonitors(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &v1alpha1.RouteMonitor{}, RouteIndex, indexRoute)
+}
+
+// indexRoute returns the Route a RouteMonitor reads its url from, if any
+func indexRoute(o client.Object) []string {
+	routeMonitor, ok := o.(*v1alpha1.RouteMonitor)
+	if !ok || routeMonitor.Spec.RouteURLOverride != "" || routeMonitor.Spec.Route.Name == "" {
+		return nil
+	}
+	return []string{types.NamespacedName{Name: routeMonitor.Spec.Route.Name, Namespace: routeMonitor.Spec.Route.Namespace}.String()}
+}
+
+// RouteMonitorsOfRoute lists the RouteMonitors reading their url from the Route. RouteMonitors overriding the url
+// aren't listed
+func RouteMonitorsOfRoute(ctx context.Context, c client.Reader, route types.NamespacedName) ([]v1alpha1.RouteMonitor, error) {
+	routeMonitors := v1alpha1.RouteMonitorList{}
+	if err := c.List(ctx, &routeMonitors, client.MatchingFields{RouteIndex: route.String()}); err != nil {
+		return nil, err
+	}
+	return routeMonitors.Items, nil
+}
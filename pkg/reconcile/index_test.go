@@ -0,0 +1,51 @@
+package reconcileCommon_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+)
+
+// builderIndexer registers the indexes with the fake client being built
+type builderIndexer struct {
+	builder *fake.ClientBuilder
+}
+
+func (i builderIndexer) IndexField(_ context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	i.builder.WithIndex(obj, field, extractValue)
+	return nil
+}
+
+var _ = Describe("RouteMonitorsOfRoute", func() {
+	routeMonitor := func(name string, route v1alpha1.RouteMonitorRouteSpec, override string) client.Object {
+		return &v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fake-namespace"},
+			Spec:       v1alpha1.RouteMonitorSpec{Route: route, RouteURLOverride: override},
+		}
+	}
+
+	It("lists only the RouteMonitors reading their url from the Route", func() {
+		builder := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(
+			routeMonitor("of-route", v1alpha1.RouteMonitorRouteSpec{Name: "fake-route", Namespace: "fake-namespace"}, ""),
+			routeMonitor("of-other-route", v1alpha1.RouteMonitorRouteSpec{Name: "other-route", Namespace: "fake-namespace"}, ""),
+			routeMonitor("of-other-namespace", v1alpha1.RouteMonitorRouteSpec{Name: "fake-route", Namespace: "other-namespace"}, ""),
+			routeMonitor("overridden", v1alpha1.RouteMonitorRouteSpec{Name: "fake-route", Namespace: "fake-namespace"}, "https://fake-url"),
+		)
+		Expect(reconcilecommon.IndexRouteMonitors(context.Background(), builderIndexer{builder})).To(Succeed())
+
+		routeMonitors, err := reconcilecommon.RouteMonitorsOfRoute(context.Background(), builder.Build(), types.NamespacedName{Name: "fake-route", Namespace: "fake-namespace"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routeMonitors).To(HaveLen(1))
+		Expect(routeMonitors[0].Name).To(Equal("of-route"))
+	})
+})
@@ -0,0 +1,116 @@
+package reconcileCommon
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+)
+
+// OrphanSweeper deletes the generated resources labelled with a monitor that no longer exists. The garbage
+// collector takes care of the resources the monitors control, but not of the ones lacking the owner reference
+// when the monitor was force-deleted with its finalizer stripped. Resources without the owner labels are left be
+type OrphanSweeper struct {
+	Client client.Client
+	// Reader reads the resources and their monitors bypassing the cache, so no resource is deleted because
+	// the cache hasn't seen its monitor yet
+	Reader   client.Reader
+	Log      logr.Logger
+	Interval time.Duration
+
+	// Lists are the kinds of generated resources to sweep
+	Lists []client.ObjectList
+}
+
+// NewOrphanSweeper returns an OrphanSweeper sweeping the given kinds of resources every interval
+func NewOrphanSweeper(c client.Client, reader client.Reader, log logr.Logger, interval time.Duration, lists ...client.ObjectList) *OrphanSweeper {
+	return &OrphanSweeper{
+		Client:   c,
+		Reader:   reader,
+		Log:      log,
+		Interval: interval,
+		Lists:    lists,
+	}
+}
+
+// Start sweeps the resources right away and every interval after, until the context is done
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sweep(ctx); err != nil {
+			s.Log.Error(err, "failed to sweep the orphaned resources")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep deletes every labelled resource whose monitor doesn't exist
+func (s *OrphanSweeper) Sweep(ctx context.Context) error {
+	for _, list := range s.Lists {
+		err := s.Reader.List(ctx, list, client.HasLabels{consts.OwnerKindLabel, consts.OwnerNameLabel})
+		if meta.IsNoMatchError(err) {
+			s.Log.V(logging.DebugVerbosity).Info("skipped sweeping resources: their CRD isn't installed", "error", err.Error())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			resource, ok := item.(client.Object)
+			if !ok || resource.GetDeletionTimestamp() != nil {
+				continue
+			}
+			orphaned, err := s.orphaned(ctx, resource)
+			if err != nil {
+				s.Log.Error(err, "failed to look up the monitor of a resource", "namespace", resource.GetNamespace(), "name", resource.GetName())
+				continue
+			}
+			if !orphaned {
+				continue
+			}
+			if err := s.Client.Delete(ctx, resource); client.IgnoreNotFound(err) != nil {
+				s.Log.Error(err, "failed to delete an orphaned resource", "namespace", resource.GetNamespace(), "name", resource.GetName())
+				continue
+			}
+			s.Log.Info("deleted a resource whose monitor doesn't exist", "namespace", resource.GetNamespace(), "name", resource.GetName(),
+				"ownerKind", resource.GetLabels()[consts.OwnerKindLabel], "ownerName", resource.GetLabels()[consts.OwnerNameLabel])
+		}
+	}
+	return nil
+}
+
+// orphaned returns true if the monitor named by the labels of the resource doesn't exist in its namespace.
+// Resources labelled with a kind that isn't a monitor aren't orphaned
+func (s *OrphanSweeper) orphaned(ctx context.Context, resource client.Object) (bool, error) {
+	labels := resource.GetLabels()
+	object, err := s.Client.Scheme().New(v1alpha1.GroupVersion.WithKind(labels[consts.OwnerKindLabel]))
+	if err != nil {
+		return false, nil
+	}
+	monitor, ok := object.(client.Object)
+	if !ok {
+		return false, nil
+	}
+	err = s.Reader.Get(ctx, types.NamespacedName{Name: labels[consts.OwnerNameLabel], Namespace: resource.GetNamespace()}, monitor)
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
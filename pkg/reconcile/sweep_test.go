@@ -0,0 +1,68 @@
+package reconcileCommon_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+)
+
+var _ = Describe("OrphanSweeper", func() {
+	var (
+		objects []client.Object
+		c       client.Client
+		err     error
+	)
+	serviceMonitor := func(name string, labels map[string]string) *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fake-namespace", Labels: labels}}
+	}
+	exists := func(name string) bool {
+		return c.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "fake-namespace"}, &monitoringv1.ServiceMonitor{}) == nil
+	}
+	BeforeEach(func() {
+		objects = []client.Object{
+			&v1alpha1.RouteMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-monitor", Namespace: "fake-namespace"}},
+			serviceMonitor("owned", consts.OwnerLabels("RouteMonitor", "fake-monitor")),
+			serviceMonitor("unlabelled", nil),
+			serviceMonitor("foreign", consts.OwnerLabels("Deployment", "fake-deployment")),
+		}
+	})
+	JustBeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(objects...).Build()
+		sweeper := reconcilecommon.NewOrphanSweeper(c, c, log.Log, 0, &monitoringv1.ServiceMonitorList{}, &monitoringv1.PrometheusRuleList{})
+		err = sweeper.Sweep(context.Background())
+	})
+
+	When("the monitors of the labelled resources exist", func() {
+		It("leaves the resources be", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("owned")).To(BeTrue())
+			Expect(exists("unlabelled")).To(BeTrue())
+			Expect(exists("foreign")).To(BeTrue())
+		})
+	})
+
+	When("the monitor of a labelled resource was deleted", func() {
+		BeforeEach(func() {
+			objects = append(objects, serviceMonitor("orphaned", consts.OwnerLabels("ClusterUrlMonitor", "deleted-monitor")))
+		})
+		It("deletes the orphaned resource only", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("orphaned")).To(BeFalse())
+			Expect(exists("owned")).To(BeTrue())
+			Expect(exists("unlabelled")).To(BeTrue())
+			Expect(exists("foreign")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package reconcileCommon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openshift/route-monitor-operator/pkg/logging"
+)
+
+// ResourceMissingReason is the reason of the Event emitted for a monitor whose generated resource is gone
+const ResourceMissingReason = "GeneratedResourceMissing"
+
+// ResourceVerifier periodically checks that the resources referenced in the status of the monitors exist.
+// They vanish without the monitors noticing when e.g. the CRDs of the prometheus-operator are reinstalled
+// during a cluster repair. Monitors missing a resource are reconciled through the Source, which regenerates it
+type ResourceVerifier struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	Interval time.Duration
+
+	// List returns the monitors to verify
+	List func(ctx context.Context) ([]client.Object, error)
+	// References returns the resources referenced by the status of a monitor, with their name and namespace set
+	References func(monitor client.Object) []client.Object
+
+	events chan event.GenericEvent
+}
+
+// NewResourceVerifier returns a ResourceVerifier verifying the monitors every interval
+func NewResourceVerifier(c client.Client, recorder record.EventRecorder, log logr.Logger, interval time.Duration,
+	list func(ctx context.Context) ([]client.Object, error), references func(monitor client.Object) []client.Object) *ResourceVerifier {
+	return &ResourceVerifier{
+		Client:     c,
+		Recorder:   recorder,
+		Log:        log,
+		Interval:   interval,
+		List:       list,
+		References: references,
+		events:     make(chan event.GenericEvent),
+	}
+}
+
+// Source emits the monitors missing a resource, to be watched by their controller
+func (v *ResourceVerifier) Source() source.Source {
+	return &source.Channel{Source: v.events}
+}
+
+// Start verifies the monitors every interval until the context is done
+func (v *ResourceVerifier) Start(ctx context.Context) error {
+	ticker := time.NewTicker(v.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := v.Verify(ctx); err != nil {
+				v.Log.Error(err, "failed to verify the generated resources")
+			}
+		}
+	}
+}
+
+// Verify reconciles every monitor missing a resource referenced in its status
+func (v *ResourceVerifier) Verify(ctx context.Context) error {
+	monitors, err := v.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range monitors {
+		missing, err := v.missingResources(ctx, monitor)
+		if err != nil {
+			v.Log.Error(err, "failed to verify the generated resources", "namespace", monitor.GetNamespace(), "name", monitor.GetName())
+			continue
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		v.Recorder.Eventf(monitor, corev1.EventTypeWarning, ResourceMissingReason,
+			"Regenerating %s referenced in the status: not found, e.g. because its CRD was reinstalled", strings.Join(missing, ", "))
+		select {
+		case v.events <- event.GenericEvent{Object: monitor}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// missingResources returns the kind, namespace and name of every referenced resource that doesn't exist
+func (v *ResourceVerifier) missingResources(ctx context.Context, monitor client.Object) ([]string, error) {
+	missing := []string{}
+	for _, reference := range v.References(monitor) {
+		err := v.Client.Get(ctx, client.ObjectKeyFromObject(reference), reference)
+		if err == nil {
+			continue
+		}
+		if meta.IsNoMatchError(err) {
+			// Until the CRD is installed again, the resource can't be regenerated
+			v.Log.V(logging.DebugVerbosity).Info("skipped verifying a resource: its CRD isn't installed", "error", err.Error())
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		kind := "resource"
+		if gvk, err := apiutil.GVKForObject(reference, v.Client.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+		missing = append(missing, fmt.Sprintf("%s %s/%s", kind, reference.GetNamespace(), reference.GetName()))
+	}
+	return missing, nil
+}
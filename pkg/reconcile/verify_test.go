@@ -0,0 +1,83 @@
+package reconcileCommon_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+)
+
+var _ = Describe("ResourceVerifier", func() {
+	var (
+		monitor  *v1alpha1.RouteMonitor
+		objects  []client.Object
+		recorder *record.FakeRecorder
+		queue    workqueue.RateLimitingInterface
+		err      error
+	)
+	BeforeEach(func() {
+		monitor = &v1alpha1.RouteMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace"},
+			Status: v1alpha1.RouteMonitorStatus{
+				ServiceMonitorRef: v1alpha1.NamespacedName{Name: "fake-name-abcdef", Namespace: "fake-namespace"},
+			},
+		}
+		objects = nil
+		recorder = record.NewFakeRecorder(1)
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	})
+	AfterEach(func() {
+		queue.ShutDown()
+	})
+	JustBeforeEach(func() {
+		c := fake.NewClientBuilder().WithScheme(constinit.Scheme).WithObjects(objects...).Build()
+		verifier := reconcilecommon.NewResourceVerifier(c, recorder, log.Log, 0,
+			func(context.Context) ([]client.Object, error) { return []client.Object{monitor}, nil },
+			func(client.Object) []client.Object {
+				return []client.Object{&monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-name-abcdef", Namespace: "fake-namespace"}}}
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		Expect(verifier.Source().Start(ctx, &handler.EnqueueRequestForObject{}, queue)).To(Succeed())
+		err = verifier.Verify(ctx)
+	})
+
+	When("the referenced resources exist", func() {
+		BeforeEach(func() {
+			objects = []client.Object{&monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "fake-name-abcdef", Namespace: "fake-namespace"}}}
+		})
+		It("leaves the monitor be", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+			Expect(queue.Len()).To(BeZero())
+		})
+	})
+
+	When("a referenced resource is missing", func() {
+		It("reconciles the monitor and explains why", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring(reconcilecommon.ResourceMissingReason),
+				ContainSubstring("ServiceMonitor fake-namespace/fake-name-abcdef"),
+			)))
+			Eventually(queue.Len).Should(Equal(1))
+			item, _ := queue.Get()
+			Expect(item).To(Equal(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(monitor)}))
+		})
+	})
+})
@@ -0,0 +1,350 @@
+// Package runtimeconfig holds the operator settings that can be changed without restarting it
+package runtimeconfig
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultConfigMapName is the name of the ConfigMap in the operator's namespace the settings are read from
+	DefaultConfigMapName = "route-monitor-operator-config"
+
+	// Keys of the settings within the ConfigMap
+	LogLevelKey                       = "logLevel"
+	BlackBoxExporterImageKey          = "blackboxImage"
+	BlackBoxExporterReplicasKey       = "blackboxReplicas"
+	BlackBoxExporterResourcesKey      = "blackboxResources"
+	BlackBoxExporterNetworkPolicyKey  = "blackboxNetworkPolicy"
+	BlackBoxExporterLogLevelKey       = "blackboxLogLevel"
+	BlackBoxExporterPodLabelsKey      = "blackboxPodLabels"
+	BlackBoxExporterPodAnnotationsKey = "blackboxPodAnnotations"
+	SloChangeAlertDurationKey         = "sloChangeAlertDuration"
+	DefaultSloKey                     = "defaultSlo"
+	BackoffBaseDelayKey               = "backoffBaseDelay"
+	BackoffMaxDelayKey                = "backoffMaxDelay"
+)
+
+// digestPattern matches the digest an image is pinned to
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Settings are the values of the runtime tunable settings
+type Settings struct {
+	// LogLevel is the level of the operator's logger
+	LogLevel zapcore.Level
+	// BlackBoxExporterImage is the image of the blackbox-exporter deployment
+	BlackBoxExporterImage string
+	// BlackBoxExporterReplicas is the number of blackbox-exporter pods
+	BlackBoxExporterReplicas int32
+	// BlackBoxExporterResources are the requests and limits of the blackbox-exporter container
+	BlackBoxExporterResources corev1.ResourceRequirements
+	// BlackBoxExporterNetworkPolicy restricts the traffic of the blackbox-exporter pods
+	BlackBoxExporterNetworkPolicy NetworkPolicy
+	// BlackBoxExporterLogLevel is the level of the blackbox-exporter's logs, the exporter's default if empty
+	BlackBoxExporterLogLevel string
+	// BlackBoxExporterPodLabels are added to the labels of the blackbox-exporter pods
+	BlackBoxExporterPodLabels map[string]string
+	// BlackBoxExporterPodAnnotations are added to the annotations of the blackbox-exporter pods
+	BlackBoxExporterPodAnnotations map[string]string
+	// SloChangeAlertDuration is how long an informational alert fires after an availability target was lowered
+	SloChangeAlertDuration time.Duration
+	// DefaultSlo is the SLO of the monitors that don't set one, empty if they go without
+	DefaultSlo v1alpha1.SloSpec
+	// BackoffBaseDelay is how long the first retry of a monitor's failed reconcile waits, every further failure
+	// doubles the delay up to BackoffMaxDelay
+	BackoffBaseDelay time.Duration
+	BackoffMaxDelay  time.Duration
+}
+
+// NetworkPolicy restricts the traffic of the blackbox-exporter pods to the scrapes of Prometheus and the probes
+type NetworkPolicy struct {
+	// Enabled creates the NetworkPolicy, it's deleted otherwise
+	Enabled bool `json:"enabled"`
+	// PrometheusNamespaces are the namespaces allowed to scrape the exporter, any namespace if empty. Other traffic
+	// into the exporter is denied either way
+	PrometheusNamespaces []string `json:"prometheusNamespaces,omitempty"`
+	// EgressCIDRs are the networks the exporter is allowed to probe, any if empty. DNS is allowed either way
+	EgressCIDRs []string `json:"egressCIDRs,omitempty"`
+}
+
+// SloFor returns the SLO a monitor is held to: its own, or the default if it doesn't set one
+func (s Settings) SloFor(slo v1alpha1.SloSpec) v1alpha1.SloSpec {
+	if reflect.DeepEqual(slo, v1alpha1.SloSpec{}) {
+		return s.DefaultSlo
+	}
+	return slo
+}
+
+// monitorSettings returns the settings the monitors and their exporters are reconciled with, leaving out the log
+// level and the backoff, which take effect without reconciling them
+func (s Settings) monitorSettings() Settings {
+	s.LogLevel = 0
+	s.BackoffBaseDelay = 0
+	s.BackoffMaxDelay = 0
+	return s
+}
+
+// Config holds the settings currently in effect. Those missing from the ConfigMap fall back to the defaults,
+// which are set by the operator's flags
+type Config struct {
+	mu       sync.RWMutex
+	defaults Settings
+	current  Settings
+	level    zap.AtomicLevel
+	// changes are the channels of the Sources, notified whenever the monitor settings change
+	changes []chan event.GenericEvent
+}
+
+// New returns a Config applying the defaults. The log level is changed through the given level,
+// which has to be the one of the operator's logger, its current level is the default
+func New(defaults Settings, level zap.AtomicLevel) *Config {
+	defaults.LogLevel = level.Level()
+	return &Config{defaults: defaults, current: defaults, level: level}
+}
+
+// Get returns the settings currently in effect
+func (c *Config) Get() Settings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+// Source emits an event whenever the settings the monitors and their exporters are reconciled with change, so
+// their controllers can put them into effect right away rather than with the next reconcile of each monitor.
+// The event's object is a placeholder, every call returns a new Source
+func (c *Config) Source() source.Source {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changes := make(chan event.GenericEvent, 1)
+	c.changes = append(c.changes, changes)
+	return &source.Channel{Source: changes}
+}
+
+// Apply puts the settings of the ConfigMap's data into effect, nil restores the defaults.
+// Invalid data is rejected as a whole, keeping the current settings
+func (c *Config) Apply(data map[string]string) (Settings, error) {
+	settings, err := c.parse(data)
+	if err != nil {
+		return c.Get(), err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := !reflect.DeepEqual(c.current.monitorSettings(), settings.monitorSettings())
+	c.current = settings
+	c.level.SetLevel(settings.LogLevel)
+	if changed {
+		for _, changes := range c.changes {
+			// A pending event already reconciles everything with the latest settings
+			select {
+			case changes <- event.GenericEvent{Object: &corev1.ConfigMap{}}:
+			default:
+			}
+		}
+	}
+	return settings, nil
+}
+
+// parse returns the defaults overridden by the data
+func (c *Config) parse(data map[string]string) (Settings, error) {
+	settings := c.defaults
+	for key, value := range data {
+		value = strings.TrimSpace(value)
+		switch key {
+		case LogLevelKey:
+			level, err := ParseLogLevel(value)
+			if err != nil {
+				return settings, err
+			}
+			settings.LogLevel = level
+		case BlackBoxExporterImageKey:
+			if err := ValidateImage(value); err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings.BlackBoxExporterImage = value
+		case BlackBoxExporterReplicasKey:
+			replicas, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || replicas < 1 {
+				return settings, fmt.Errorf("invalid %s '%s': it must be a positive number", key, value)
+			}
+			settings.BlackBoxExporterReplicas = int32(replicas)
+		case BlackBoxExporterResourcesKey:
+			resources, err := ParseResources(value)
+			if err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings.BlackBoxExporterResources = resources
+		case BlackBoxExporterNetworkPolicyKey:
+			policy, err := ParseNetworkPolicy(value)
+			if err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings.BlackBoxExporterNetworkPolicy = policy
+		case BlackBoxExporterLogLevelKey:
+			if err := ValidateExporterLogLevel(value); err != nil {
+				return settings, err
+			}
+			settings.BlackBoxExporterLogLevel = value
+		case BlackBoxExporterPodLabelsKey:
+			labels, err := ParsePodLabels(value)
+			if err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings.BlackBoxExporterPodLabels = labels
+		case BlackBoxExporterPodAnnotationsKey:
+			annotations, err := ParsePodAnnotations(value)
+			if err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings.BlackBoxExporterPodAnnotations = annotations
+		case SloChangeAlertDurationKey:
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return settings, fmt.Errorf("invalid %s '%s'", key, value)
+			}
+			settings.SloChangeAlertDuration = duration
+		case DefaultSloKey:
+			slo := v1alpha1.SloSpec{}
+			if err := yaml.UnmarshalStrict([]byte(value), &slo); err != nil {
+				return settings, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			if valid, _ := slo.IsValid(); !valid {
+				return settings, fmt.Errorf("invalid %s: targetAvailabilityPercent '%s' isn't a valid availability target", key, slo.TargetAvailabilityPercent)
+			}
+			settings.DefaultSlo = slo
+		case BackoffBaseDelayKey, BackoffMaxDelayKey:
+			delay, err := time.ParseDuration(value)
+			if err != nil || delay <= 0 {
+				return settings, fmt.Errorf("invalid %s '%s': it must be a positive duration", key, value)
+			}
+			if key == BackoffBaseDelayKey {
+				settings.BackoffBaseDelay = delay
+			} else {
+				settings.BackoffMaxDelay = delay
+			}
+		default:
+			return settings, fmt.Errorf("unknown setting '%s'", key)
+		}
+	}
+	if settings.BackoffMaxDelay < settings.BackoffBaseDelay {
+		return settings, fmt.Errorf("invalid %s '%s': it must be at least the %s '%s'", BackoffMaxDelayKey,
+			settings.BackoffMaxDelay, BackoffBaseDelayKey, settings.BackoffBaseDelay)
+	}
+	return settings, nil
+}
+
+// ParseLogLevel parses a log level as the --zap-log-level flag does: 'debug', 'info' or 'error', or a
+// verbosity greater than 0
+func ParseLogLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	verbosity, err := strconv.Atoi(level)
+	if err != nil || verbosity <= 0 || verbosity > 127 {
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level '%s'", level)
+	}
+	return zapcore.Level(int8(-verbosity)), nil
+}
+
+// ValidateExporterLogLevel checks a level of the exporter's --log.level flag: 'debug', 'info', 'warn' or 'error'
+func ValidateExporterLogLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+		return nil
+	}
+	return fmt.Errorf("invalid blackbox exporter log level '%s': it must be one of debug, info, warn or error", level)
+}
+
+// ValidateImage checks an image reference, either by tag, e.g. 'quay.io/prometheus/blackbox-exporter:v0.25.0', or pinned
+// to a digest, e.g. 'mirror.example.com/prometheus/blackbox-exporter@sha256:...', as mirror registries require
+func ValidateImage(image string) error {
+	if image == "" || strings.ContainsAny(image, " \t\n") {
+		return fmt.Errorf("invalid image '%s'", image)
+	}
+	if name, digest, pinned := strings.Cut(image, "@"); pinned {
+		if name == "" || !digestPattern.MatchString(digest) {
+			return fmt.Errorf("invalid image '%s': the digest must be 'sha256:' followed by 64 hex digits", image)
+		}
+	}
+	return nil
+}
+
+// ParseResources parses the requests and limits of a container, in YAML or JSON, e.g. '{"requests": {"cpu": "100m"}}'.
+// A request above its limit is rejected, as the API server would reject the deployment
+func ParseResources(value string) (corev1.ResourceRequirements, error) {
+	resources := corev1.ResourceRequirements{}
+	if err := yaml.UnmarshalStrict([]byte(value), &resources); err != nil {
+		return resources, err
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return resources, fmt.Errorf("the %s request %s exceeds its limit %s", name, request.String(), limit.String())
+		}
+	}
+	return resources, nil
+}
+
+// ParseNetworkPolicy parses the NetworkPolicy of the exporter, in YAML or JSON, e.g.
+// '{"enabled": true, "prometheusNamespaces": ["openshift-monitoring"]}'
+func ParseNetworkPolicy(value string) (NetworkPolicy, error) {
+	policy := NetworkPolicy{}
+	if err := yaml.UnmarshalStrict([]byte(value), &policy); err != nil {
+		return policy, err
+	}
+	for _, cidr := range policy.EgressCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return policy, fmt.Errorf("invalid egress CIDR '%s'", cidr)
+		}
+	}
+	return policy, nil
+}
+
+// ParsePodLabels parses the labels of the exporter pods, in YAML or JSON, e.g. '{"cost-center": "monitoring"}'. Keys
+// and values are checked as the API server would
+func ParsePodLabels(value string) (map[string]string, error) {
+	return parseMetadata(value, "label", validation.IsValidLabelValue)
+}
+
+// ParsePodAnnotations parses the annotations of the exporter pods, in YAML or JSON, e.g.
+// '{"sidecar.istio.io/inject": "false"}'. Their keys are checked as the API server would
+func ParsePodAnnotations(value string) (map[string]string, error) {
+	return parseMetadata(value, "annotation", func(string) []string { return nil })
+}
+
+// parseMetadata parses a map of labels or annotations, whose values are checked by validateValue
+func parseMetadata(value, kind string, validateValue func(string) []string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if err := yaml.UnmarshalStrict([]byte(value), &metadata); err != nil {
+		return nil, err
+	}
+	for key, value := range metadata {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid %s key '%s': %s", kind, key, strings.Join(errs, ", "))
+		}
+		if errs := validateValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid %s value '%s': %s", kind, value, strings.Join(errs, ", "))
+		}
+	}
+	return metadata, nil
+}
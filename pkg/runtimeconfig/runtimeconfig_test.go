@@ -0,0 +1,170 @@
+package runtimeconfig_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		level    zap.AtomicLevel
+		config   *runtimeconfig.Config
+		defaults runtimeconfig.Settings
+	)
+	BeforeEach(func() {
+		level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		defaults = runtimeconfig.Settings{
+			LogLevel:               zapcore.InfoLevel,
+			BlackBoxExporterImage:  "fake-image",
+			SloChangeAlertDuration: time.Hour,
+			BackoffBaseDelay:       time.Second,
+			BackoffMaxDelay:        10 * time.Minute,
+		}
+		config = runtimeconfig.New(defaults, level)
+	})
+
+	It("starts with the defaults", func() {
+		Expect(config.Get()).To(Equal(defaults))
+	})
+
+	It("applies the settings of the data", func() {
+		settings, err := config.Apply(map[string]string{
+			runtimeconfig.LogLevelKey:                       "3",
+			runtimeconfig.BlackBoxExporterImageKey:          "other-image",
+			runtimeconfig.BlackBoxExporterReplicasKey:       "3",
+			runtimeconfig.BlackBoxExporterResourcesKey:      "requests:\n  cpu: 100m\nlimits:\n  memory: 128Mi",
+			runtimeconfig.BlackBoxExporterNetworkPolicyKey:  `{"enabled": true, "prometheusNamespaces": ["openshift-monitoring"], "egressCIDRs": ["10.0.0.0/8"]}`,
+			runtimeconfig.BlackBoxExporterLogLevelKey:       "debug",
+			runtimeconfig.BlackBoxExporterPodLabelsKey:      `{"cost-center": "monitoring"}`,
+			runtimeconfig.BlackBoxExporterPodAnnotationsKey: "sidecar.istio.io/inject: \"false\"",
+			runtimeconfig.SloChangeAlertDurationKey:         "30m",
+			runtimeconfig.DefaultSloKey:                     "targetAvailabilityPercent: \"99.5\"\ngracePeriod: 1h",
+			runtimeconfig.BackoffBaseDelayKey:               "5s",
+			runtimeconfig.BackoffMaxDelayKey:                "30m",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(Equal(runtimeconfig.Settings{
+			LogLevel:                 zapcore.Level(-3),
+			BlackBoxExporterImage:    "other-image",
+			BlackBoxExporterReplicas: 3,
+			BlackBoxExporterResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+			BlackBoxExporterNetworkPolicy: runtimeconfig.NetworkPolicy{
+				Enabled:              true,
+				PrometheusNamespaces: []string{"openshift-monitoring"},
+				EgressCIDRs:          []string{"10.0.0.0/8"},
+			},
+			BlackBoxExporterLogLevel:       "debug",
+			BlackBoxExporterPodLabels:      map[string]string{"cost-center": "monitoring"},
+			BlackBoxExporterPodAnnotations: map[string]string{"sidecar.istio.io/inject": "false"},
+			SloChangeAlertDuration:         30 * time.Minute,
+			DefaultSlo:                     v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5", GracePeriod: "1h"},
+			BackoffBaseDelay:               5 * time.Second,
+			BackoffMaxDelay:                30 * time.Minute,
+		}))
+		Expect(config.Get()).To(Equal(settings))
+		Expect(level.Level()).To(Equal(zapcore.Level(-3)))
+	})
+
+	It("restores the defaults of the settings missing from the data", func() {
+		_, err := config.Apply(map[string]string{runtimeconfig.LogLevelKey: "debug", runtimeconfig.BlackBoxExporterImageKey: "other-image"})
+		Expect(err).NotTo(HaveOccurred())
+		settings, err := config.Apply(map[string]string{runtimeconfig.BlackBoxExporterImageKey: "other-image"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.LogLevel).To(Equal(zapcore.InfoLevel))
+		Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+
+		settings, err = config.Apply(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(Equal(defaults))
+	})
+
+	It("keeps the current settings if the data is invalid", func() {
+		applied, err := config.Apply(map[string]string{runtimeconfig.BlackBoxExporterImageKey: "other-image"})
+		Expect(err).NotTo(HaveOccurred())
+		for _, data := range []map[string]string{
+			{runtimeconfig.LogLevelKey: "verbose"},
+			{runtimeconfig.LogLevelKey: "0"},
+			{runtimeconfig.BlackBoxExporterImageKey: " "},
+			{runtimeconfig.BlackBoxExporterImageKey: "quay.io/prometheus/blackbox-exporter@sha256:b04a"},
+			{runtimeconfig.BlackBoxExporterReplicasKey: "0"},
+			{runtimeconfig.BlackBoxExporterResourcesKey: "requests:\n  cpu: lots"},
+			{runtimeconfig.BlackBoxExporterResourcesKey: "requests:\n  cpu: \"2\"\nlimits:\n  cpu: \"1\""},
+			{runtimeconfig.BlackBoxExporterNetworkPolicyKey: "enabled: true\negressCIDRs: [10.0.0.0]"},
+			{runtimeconfig.BlackBoxExporterNetworkPolicyKey: "enable: true"},
+			{runtimeconfig.BlackBoxExporterLogLevelKey: "verbose"},
+			{runtimeconfig.BlackBoxExporterPodLabelsKey: `{"cost center": "monitoring"}`},
+			{runtimeconfig.BlackBoxExporterPodLabelsKey: `{"team": "shop/frontend"}`},
+			{runtimeconfig.BlackBoxExporterPodAnnotationsKey: `{"sidecar.istio.io/inject/": "false"}`},
+			{runtimeconfig.SloChangeAlertDurationKey: "-1h"},
+			{runtimeconfig.DefaultSloKey: "targetAvailabilityPercent: \"100\""},
+			{runtimeconfig.DefaultSloKey: "target: \"99.5\""},
+			{runtimeconfig.BackoffBaseDelayKey: "0s"},
+			{runtimeconfig.BackoffBaseDelayKey: "1m", runtimeconfig.BackoffMaxDelayKey: "30s"},
+			{"requeueInterval": "1m"},
+		} {
+			_, err := config.Apply(data)
+			Expect(err).To(HaveOccurred())
+			Expect(config.Get()).To(Equal(applied))
+		}
+	})
+
+	It("notifies the Sources when the settings of the monitors change", func() {
+		events := config.Source().(*source.Channel).Source
+
+		_, err := config.Apply(map[string]string{runtimeconfig.LogLevelKey: "debug", runtimeconfig.BackoffBaseDelayKey: "5s"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).NotTo(Receive())
+
+		_, err = config.Apply(map[string]string{runtimeconfig.BlackBoxExporterImageKey: "other-image"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = config.Apply(map[string]string{runtimeconfig.SloChangeAlertDurationKey: "30m"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Receive())
+		Expect(events).NotTo(Receive())
+
+		_, err = config.Apply(map[string]string{runtimeconfig.SloChangeAlertDurationKey: "30m"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).NotTo(Receive())
+	})
+})
+
+var _ = Describe("ValidateImage", func() {
+	It("accepts images by tag or pinned to a digest", func() {
+		Expect(runtimeconfig.ValidateImage("quay.io/prometheus/blackbox-exporter:master")).To(Succeed())
+		Expect(runtimeconfig.ValidateImage("mirror.example.com:5000/prometheus/blackbox-exporter@sha256:b04a9fef4fa086a02fc7fcd8dcdbc4b7b35cc30cdee860fdc6a19dd8b208d63e")).To(Succeed())
+	})
+
+	It("rejects empty images and malformed digests", func() {
+		Expect(runtimeconfig.ValidateImage("")).NotTo(Succeed())
+		Expect(runtimeconfig.ValidateImage("quay.io/prometheus/blackbox exporter")).NotTo(Succeed())
+		Expect(runtimeconfig.ValidateImage("quay.io/prometheus/blackbox-exporter@sha256:B04A")).NotTo(Succeed())
+		Expect(runtimeconfig.ValidateImage("@sha256:b04a9fef4fa086a02fc7fcd8dcdbc4b7b35cc30cdee860fdc6a19dd8b208d63e")).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Settings.SloFor", func() {
+	settings := runtimeconfig.Settings{DefaultSlo: v1alpha1.SloSpec{TargetAvailabilityPercent: "99.5"}}
+
+	It("applies the default SLO to monitors without one", func() {
+		Expect(settings.SloFor(v1alpha1.SloSpec{})).To(Equal(settings.DefaultSlo))
+	})
+
+	It("keeps the SLO of monitors setting one", func() {
+		slo := v1alpha1.SloSpec{TargetAvailabilityPercent: "99.9"}
+		Expect(settings.SloFor(slo)).To(Equal(slo))
+	})
+})
@@ -0,0 +1,82 @@
+// Package scrapeconfig mirrors the ScrapeConfig of the monitoring.coreos.com v1alpha1 API group, which the
+// prometheus-operator API the operator builds against predates. Only the fields the operator sets are declared,
+// named like upstream's, so the objects round-trip through the CRD installed by a newer prometheus-operator
+// +kubebuilder:object:generate=true
+// +groupName=monitoring.coreos.com
+package scrapeconfig
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1alpha1"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// ScrapeConfigSpec defines the scrape jobs Prometheus generates from a ScrapeConfig
+type ScrapeConfigSpec struct {
+	// StaticConfigs list the targets to scrape
+	StaticConfigs []StaticConfig `json:"staticConfigs,omitempty"`
+	// RelabelConfigs are applied to the targets before scraping them
+	RelabelConfigs []*monitoringv1.RelabelConfig `json:"relabelings,omitempty"`
+	// MetricsPath is the HTTP path the targets are scraped at
+	MetricsPath *string `json:"metricsPath,omitempty"`
+	// ScrapeInterval is how often the targets are scraped
+	ScrapeInterval *monitoringv1.Duration `json:"scrapeInterval,omitempty"`
+	// ScrapeTimeout is how long a scrape may take
+	ScrapeTimeout *monitoringv1.Duration `json:"scrapeTimeout,omitempty"`
+	// Params are the HTTP URL parameters of the scrapes
+	Params map[string][]string `json:"params,omitempty"`
+	// Scheme is the protocol the targets are scraped with
+	Scheme *string `json:"scheme,omitempty"`
+	// SampleLimit is the number of samples a scrape may return
+	SampleLimit *uint64 `json:"sampleLimit,omitempty"`
+	// TargetLimit is the number of targets that may be scraped
+	TargetLimit *uint64 `json:"targetLimit,omitempty"`
+	// MetricRelabelConfigs are applied to the scraped samples
+	MetricRelabelConfigs []*monitoringv1.RelabelConfig `json:"metricRelabelings,omitempty"`
+}
+
+// StaticConfig is a list of targets sharing labels
+type StaticConfig struct {
+	// Targets are the addresses of the scraped targets
+	Targets []Target `json:"targets,omitempty"`
+	// Labels are set on every series scraped from the targets
+	Labels map[monitoringv1.LabelName]string `json:"labels,omitempty"`
+}
+
+// Target is the address of a scraped target
+type Target string
+
+// +kubebuilder:object:root=true
+
+// ScrapeConfig configures scrape jobs of Prometheus, also in agent mode
+type ScrapeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScrapeConfigSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScrapeConfigList contains a list of ScrapeConfig
+type ScrapeConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScrapeConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScrapeConfig{}, &ScrapeConfigList{})
+}
@@ -0,0 +1,88 @@
+package servicemonitor
+
+import (
+	"sync"
+	"time"
+
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// APIDiscoveryInterval is how long the discovered monitoring APIs are cached, so the APIs installed or removed while
+// the operator runs are picked up after it
+const APIDiscoveryInterval = time.Minute
+
+// MonitoringAPIs discovers which of the monitoring.rhobs and monitoring.coreos.com APIs serve ServiceMonitors, so the
+// monitors are scraped through the Prometheus installed rather than the one a boot flag names. A nil MonitoringAPIs
+// assumes both are installed
+type MonitoringAPIs struct {
+	Discovery discovery.DiscoveryInterface
+
+	mu         sync.Mutex
+	discovered time.Time
+	rhobs      bool
+	coreos     bool
+}
+
+// NewMonitoringAPIs creates MonitoringAPIs discovering the APIs with the discovery client
+func NewMonitoringAPIs(d discovery.DiscoveryInterface) *MonitoringAPIs {
+	return &MonitoringAPIs{Discovery: d}
+}
+
+// ServiceMonitorAPI returns whether a monitor's ServiceMonitor is generated with the monitoring.rhobs API rather than the
+// monitoring.coreos.com one. The preferred API is used while it's installed, the other one otherwise. If neither is
+// installed, the error wraps customerrors.NoMonitoringAPI
+func (a *MonitoringAPIs) ServiceMonitorAPI(preferRHOBS bool) (bool, error) {
+	if a == nil {
+		return preferRHOBS, nil
+	}
+	rhobs, coreos, err := a.discover()
+	if err != nil {
+		return false, err
+	}
+	if rhobs && (preferRHOBS || !coreos) {
+		return true, nil
+	}
+	if coreos {
+		return false, nil
+	}
+	return false, customerrors.NoMonitoringAPI
+}
+
+// discover returns which of the APIs serve ServiceMonitors, discovering them again once APIDiscoveryInterval passed
+func (a *MonitoringAPIs) discover() (rhobs, coreos bool, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.discovered.IsZero() && time.Since(a.discovered) < APIDiscoveryInterval {
+		return a.rhobs, a.coreos, nil
+	}
+	if rhobs, err = a.servesServiceMonitors(rhobsv1.SchemeGroupVersion); err != nil {
+		return false, false, err
+	}
+	if coreos, err = a.servesServiceMonitors(monitoringv1.SchemeGroupVersion); err != nil {
+		return false, false, err
+	}
+	a.rhobs, a.coreos, a.discovered = rhobs, coreos, time.Now()
+	return rhobs, coreos, nil
+}
+
+// servesServiceMonitors reports whether the group version is installed with its ServiceMonitors
+func (a *MonitoringAPIs) servesServiceMonitors(gv schema.GroupVersion) (bool, error) {
+	resources, err := a.Discovery.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "servicemonitors" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package servicemonitor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("MonitoringAPIs", func() {
+	var (
+		resources []*metav1.APIResourceList
+		apis      *servicemonitor.MonitoringAPIs
+	)
+	serviceMonitors := func(groupVersion string) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			GroupVersion: groupVersion,
+			APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: "ServiceMonitor"}},
+		}
+	}
+	BeforeEach(func() {
+		resources = nil
+	})
+	JustBeforeEach(func() {
+		apis = servicemonitor.NewMonitoringAPIs(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}})
+	})
+
+	When("both APIs are installed", func() {
+		BeforeEach(func() {
+			resources = []*metav1.APIResourceList{serviceMonitors("monitoring.rhobs/v1"), serviceMonitors("monitoring.coreos.com/v1")}
+		})
+		It("uses the preferred API", func() {
+			Expect(apis.ServiceMonitorAPI(true)).To(BeTrue())
+			Expect(apis.ServiceMonitorAPI(false)).To(BeFalse())
+		})
+	})
+	When("only the monitoring.coreos.com API is installed", func() {
+		BeforeEach(func() {
+			resources = []*metav1.APIResourceList{serviceMonitors("monitoring.coreos.com/v1")}
+		})
+		It("uses it even for hosted control planes", func() {
+			Expect(apis.ServiceMonitorAPI(true)).To(BeFalse())
+		})
+	})
+	When("only the monitoring.rhobs API is installed", func() {
+		BeforeEach(func() {
+			resources = []*metav1.APIResourceList{serviceMonitors("monitoring.rhobs/v1")}
+		})
+		It("uses it for every monitor", func() {
+			Expect(apis.ServiceMonitorAPI(false)).To(BeTrue())
+		})
+	})
+	When("a group version is installed without ServiceMonitors", func() {
+		BeforeEach(func() {
+			resources = []*metav1.APIResourceList{{GroupVersion: "monitoring.coreos.com/v1", APIResources: []metav1.APIResource{{Name: "prometheusrules"}}}}
+		})
+		It("reports no monitoring API", func() {
+			_, err := apis.ServiceMonitorAPI(false)
+			Expect(err).To(MatchError(customerrors.NoMonitoringAPI))
+		})
+	})
+	When("neither API is installed", func() {
+		It("reports no monitoring API", func() {
+			_, err := apis.ServiceMonitorAPI(true)
+			Expect(err).To(MatchError(customerrors.NoMonitoringAPI))
+		})
+	})
+	When("the APIs aren't discovered", func() {
+		It("assumes the preferred one is installed", func() {
+			var apis *servicemonitor.MonitoringAPIs
+			Expect(apis.ServiceMonitorAPI(true)).To(BeTrue())
+			Expect(apis.ServiceMonitorAPI(false)).To(BeFalse())
+		})
+	})
+})
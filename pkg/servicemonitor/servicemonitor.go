@@ -2,15 +2,27 @@ package servicemonitor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
 	"github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
 	util "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,6 +30,34 @@ type ServiceMonitor struct {
 	Client   client.Client
 	Ctx      context.Context
 	Comparer util.ResourceComparerInterface
+	// LabelOwnedResources labels the ServiceMonitors with the monitor they belong to
+	LabelOwnedResources bool
+	// Scrubber removes labels and annotations the ServiceMonitors mustn't carry
+	Scrubber util.MetadataScrubber
+	// FieldOwner, if set, applies the ServiceMonitors server-side with it as the field manager
+	FieldOwner string
+	// ProbeResources generates Probes addressing the exporter directly instead of ServiceMonitors selecting its
+	// Service, for all but Hypershift monitors
+	ProbeResources bool
+	// ScrapeConfigResources generates ScrapeConfigs instead of ServiceMonitors for all but Hypershift monitors, so
+	// Prometheus agents that don't evaluate ServiceMonitors probe the urls
+	ScrapeConfigResources bool
+	// ZoneLabels labels the series of the ServiceMonitors with the zone of the exporter pod that probed, so the outage of
+	// a zone shows as a partial failure of the probes. Prometheus needs to be allowed to get the Nodes
+	ZoneLabels bool
+	// NodeLabels labels the series of the ServiceMonitors with the node of the exporter pod that probed, so the broken
+	// network of a node shows as a partial failure of the probes
+	NodeLabels bool
+	// TLS scrapes the operator's exporter over https, verifying its serving certificate with the service CA bundle
+	// mounted into the Prometheus of the cluster monitoring stack. The RHOBS Prometheus scraping the ServiceMonitors of
+	// hosted control planes has no such bundle, so they're always scraped over http
+	TLS bool
+	// MeshTLS scrapes the operator's exporter over https through its service mesh sidecar, with the mesh's certificates
+	// mounted into the Prometheus of the cluster monitoring stack. The ServiceMonitors of hosted control planes are
+	// scraped over http, as for TLS
+	MeshTLS bool
+	// Recorder, if set, emits Events on the monitors whose ServiceMonitors, Probes or ScrapeConfigs were created or updated
+	Recorder record.EventRecorder
 }
 
 func NewServiceMonitor(ctx context.Context, c client.Client) *ServiceMonitor {
@@ -31,95 +71,497 @@ func NewServiceMonitor(ctx context.Context, c client.Client) *ServiceMonitor {
 const (
 	ServiceMonitorPeriod string = "30s"
 	UrlLabelName         string = "probe_url"
+	ZoneLabelName        string = "zone"
+	NodeLabelName        string = "node"
+
+	// The kinds, qualified with their API group, the scrapes of a monitor are generated as
+	ServiceMonitorKind      string = "ServiceMonitor.monitoring.coreos.com"
+	RHOBSServiceMonitorKind string = "ServiceMonitor.monitoring.rhobs"
+	ProbeKind               string = "Probe.monitoring.coreos.com"
+	ScrapeConfigKind        string = "ScrapeConfig.monitoring.coreos.com"
+
+	// zoneMetaLabel is the zone label of the node of a scraped pod, with the nodes' metadata attached to the targets
+	zoneMetaLabel = "__meta_kubernetes_node_label_topology_kubernetes_io_zone"
 )
 
-func (u *ServiceMonitor) TemplateAndUpdateServiceMonitorDeployment(routeURL, blackBoxExporterNamespace string, namespacedName types.NamespacedName, clusterID string, isHCPMonitor bool, useInsecure bool, owner *metav1.OwnerReference) error {
-	module := "http_2xx"
-	if useInsecure {
-		module = "insecure_http_2xx"
+func (u *ServiceMonitor) TemplateAndUpdateServiceMonitorDeployment(urls []string, exporter Exporter, namespacedName types.NamespacedName, clusterID string, isHCPMonitor bool, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference, recordedUID types.UID) (types.UID, error) {
+	if err := exporter.validate(isHCPMonitor || (!u.ScrapeConfigResources && !u.ProbeResources), namespacedName.Namespace); err != nil {
+		return "", err
+	}
+	if isHCPMonitor {
+		s := u.HyperShiftTemplateForServiceMonitorDeployment(urls, exporter, namespacedName, clusterID, module, metricLabels, relabelings, sampleLimit, targetLimit, owner)
+		uid, err := u.HypershiftUpdateServiceMonitorDeployment(s, recordedUID)
+		if err != nil {
+			return "", err
+		}
+		return uid, u.deleteOtherKinds(&rhobsv1.ServiceMonitor{}, namespacedName)
 	}
+	if u.ScrapeConfigResources {
+		c := u.TemplateForScrapeConfigDeployment(urls, exporter, namespacedName, clusterID, module, metricLabels, relabelings, sampleLimit, targetLimit, owner)
+		uid, err := u.UpdateScrapeConfigDeployment(c, recordedUID)
+		if err != nil {
+			return "", err
+		}
+		return uid, u.deleteOtherKinds(&scrapeconfig.ScrapeConfig{}, namespacedName)
+	}
+	if u.ProbeResources {
+		p := u.TemplateForProbeDeployment(urls, exporter, namespacedName, clusterID, module, metricLabels, relabelings, sampleLimit, targetLimit, owner)
+		uid, err := u.UpdateProbeDeployment(p, recordedUID)
+		if err != nil {
+			return "", err
+		}
+		return uid, u.deleteOtherKinds(&monitoringv1.Probe{}, namespacedName)
+	}
+	s := u.TemplateForServiceMonitorDeployment(urls, exporter, namespacedName, clusterID, module, metricLabels, relabelings, sampleLimit, targetLimit, owner)
+	uid, err := u.UpdateServiceMonitorDeployment(s, recordedUID)
+	if err != nil {
+		return "", err
+	}
+	return uid, u.deleteOtherKinds(&monitoringv1.ServiceMonitor{}, namespacedName)
+}
 
-	params := map[string][]string{
-		"module": {module},
-		"target": {routeURL},
+// GeneratedKind returns the kind TemplateAndUpdateServiceMonitorDeployment generates the scrapes of a monitor as, so
+// the monitor can record it in its status
+func GeneratedKind(isHCPMonitor, scrapeConfigResources, probeResources bool) string {
+	switch {
+	case isHCPMonitor:
+		return RHOBSServiceMonitorKind
+	case scrapeConfigResources:
+		return ScrapeConfigKind
+	case probeResources:
+		return ProbeKind
 	}
+	return ServiceMonitorKind
+}
 
-	if isHCPMonitor {
-		s := u.HyperShiftTemplateForServiceMonitorResource(routeURL, blackBoxExporterNamespace, params, namespacedName, clusterID, owner)
-		return u.HypershiftUpdateServiceMonitorDeployment(s)
+// GeneratedResource returns an empty resource of a kind recorded by GeneratedKind, nil for an unknown one, e.g. none
+// recorded by a monitor reconciled before the kinds were
+func GeneratedResource(kind string) client.Object {
+	switch kind {
+	case ServiceMonitorKind:
+		return &monitoringv1.ServiceMonitor{}
+	case RHOBSServiceMonitorKind:
+		return &rhobsv1.ServiceMonitor{}
+	case ProbeKind:
+		return &monitoringv1.Probe{}
+	case ScrapeConfigKind:
+		return &scrapeconfig.ScrapeConfig{}
 	}
-	s := u.TemplateForServiceMonitorResource(routeURL, blackBoxExporterNamespace, params, namespacedName, clusterID, owner)
-	return u.UpdateServiceMonitorDeployment(s)
+	return nil
 }
 
-// Creates or Updates Service Monitor Deployment according to the template
+// TemplateForServiceMonitorDeployment returns the ServiceMonitor probing the urls with the module, with one
+// endpoint per url. Zero limits are unlimited
+func (u *ServiceMonitor) TemplateForServiceMonitorDeployment(urls []string, exporter Exporter, namespacedName types.NamespacedName, clusterID string, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference) monitoringv1.ServiceMonitor {
+	s := u.TemplateForServiceMonitorResource(urls[0], exporter, probeParams(urls[0], module), namespacedName, clusterID, owner)
+	s.Spec.Endpoints[0].MetricRelabelConfigs = append(s.Spec.Endpoints[0].MetricRelabelConfigs, metricRelabelConfigs(metricLabels, relabelings)...)
+	if u.ZoneLabels {
+		s.Spec.AttachMetadata = &monitoringv1.AttachMetadata{Node: true}
+		s.Spec.Endpoints[0].RelabelConfigs = append(s.Spec.Endpoints[0].RelabelConfigs, &monitoringv1.RelabelConfig{
+			SourceLabels: []monitoringv1.LabelName{zoneMetaLabel},
+			TargetLabel:  ZoneLabelName,
+		})
+	}
+	if u.NodeLabels {
+		s.Spec.Endpoints[0].RelabelConfigs = append(s.Spec.Endpoints[0].RelabelConfigs, &monitoringv1.RelabelConfig{
+			SourceLabels: []monitoringv1.LabelName{"__meta_kubernetes_pod_node_name"},
+			TargetLabel:  NodeLabelName,
+		})
+	}
+	for _, url := range urls[1:] {
+		endpoint := *s.Spec.Endpoints[0].DeepCopy()
+		endpoint.Params = probeParams(url, module)
+		endpoint.MetricRelabelConfigs[0].Replacement = url
+		s.Spec.Endpoints = append(s.Spec.Endpoints, endpoint)
+	}
+	s.Spec.SampleLimit = sampleLimit
+	s.Spec.TargetLimit = targetLimit
+	s.Labels = u.ownerLabels(owner)
+	return s
+}
 
-func (u *ServiceMonitor) UpdateServiceMonitorDeployment(template monitoringv1.ServiceMonitor) error {
-	namespacedName := types.NamespacedName{Name: template.Name, Namespace: template.Namespace}
-	deployedServiceMonitor := &monitoringv1.ServiceMonitor{}
-	err := u.Client.Get(u.Ctx, namespacedName, deployedServiceMonitor)
-	if err != nil {
-		// No similar ServiceMonitor exists
-		if !k8serrors.IsNotFound(err) {
-			return err
-		}
-		return u.Client.Create(u.Ctx, &template)
+// TemplateForProbeDeployment returns the Probe of the urls with the module, addressing the exporter in its
+// namespace directly. Zero limits are unlimited
+func (u *ServiceMonitor) TemplateForProbeDeployment(urls []string, exporter Exporter, namespacedName types.NamespacedName, clusterID string, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference) monitoringv1.Probe {
+	scheme, host, path := exporter.address()
+	return monitoringv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            namespacedName.Name,
+			Namespace:       namespacedName.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+			Labels:          u.ownerLabels(owner),
+		},
+		Spec: monitoringv1.ProbeSpec{
+			ProberSpec: monitoringv1.ProberSpec{
+				URL:    host,
+				Scheme: scheme,
+				Path:   path,
+			},
+			Module: module,
+			Targets: monitoringv1.ProbeTargets{
+				StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+					Targets: urls,
+					Labels:  map[string]string{"_id": clusterID},
+					// Every series of a probe is labeled with its url, like the ones of a ServiceMonitor
+					RelabelConfigs: []*monitoringv1.RelabelConfig{
+						{
+							SourceLabels: []monitoringv1.LabelName{"__param_target"},
+							TargetLabel:  UrlLabelName,
+						},
+					},
+				},
+			},
+			Interval: monitoringv1.Duration(ServiceMonitorPeriod),
+			// Timeout has to be smaller than probe interval
+			ScrapeTimeout:        "15s",
+			MetricRelabelConfigs: metricRelabelConfigs(metricLabels, relabelings),
+			SampleLimit:          sampleLimit,
+			TargetLimit:          targetLimit,
+		},
 	}
-	if !u.Comparer.DeepEqual(deployedServiceMonitor.Spec, template.Spec) {
-		// Update existing ServiceMonitor for the case that the template changed
-		deployedServiceMonitor.Spec = template.Spec
-		return u.Client.Update(u.Ctx, deployedServiceMonitor)
+}
+
+// TemplateForScrapeConfigDeployment returns the ScrapeConfig of the urls with the module, addressing the exporter
+// in its namespace directly like a Probe. Zero limits are unlimited
+func (u *ServiceMonitor) TemplateForScrapeConfigDeployment(urls []string, exporter Exporter, namespacedName types.NamespacedName, clusterID string, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference) scrapeconfig.ScrapeConfig {
+	targets := make([]scrapeconfig.Target, 0, len(urls))
+	for _, url := range urls {
+		targets = append(targets, scrapeconfig.Target(url))
+	}
+	scheme, host, metricsPath := exporter.address()
+	scheme = strings.ToUpper(scheme)
+	// Timeout has to be smaller than probe interval
+	interval, timeout := monitoringv1.Duration(ServiceMonitorPeriod), monitoringv1.Duration("15s")
+	c := scrapeconfig.ScrapeConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            namespacedName.Name,
+			Namespace:       namespacedName.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+			Labels:          u.ownerLabels(owner),
+		},
+		Spec: scrapeconfig.ScrapeConfigSpec{
+			StaticConfigs: []scrapeconfig.StaticConfig{
+				{
+					Targets: targets,
+					Labels:  map[monitoringv1.LabelName]string{"_id": clusterID},
+				},
+			},
+			// The urls are passed to the exporter as the target to probe, the relabelings a Probe is generated with
+			RelabelConfigs: []*monitoringv1.RelabelConfig{
+				{
+					SourceLabels: []monitoringv1.LabelName{"__address__"},
+					TargetLabel:  "__param_target",
+				},
+				{
+					SourceLabels: []monitoringv1.LabelName{"__param_target"},
+					TargetLabel:  "instance",
+				},
+				{
+					SourceLabels: []monitoringv1.LabelName{"__param_target"},
+					TargetLabel:  UrlLabelName,
+				},
+				{
+					Replacement: host,
+					TargetLabel: "__address__",
+				},
+			},
+			MetricsPath:          &metricsPath,
+			ScrapeInterval:       &interval,
+			ScrapeTimeout:        &timeout,
+			Params:               map[string][]string{"module": {module}},
+			Scheme:               &scheme,
+			MetricRelabelConfigs: metricRelabelConfigs(metricLabels, relabelings),
+		},
+	}
+	if sampleLimit > 0 {
+		c.Spec.SampleLimit = &sampleLimit
+	}
+	if targetLimit > 0 {
+		c.Spec.TargetLimit = &targetLimit
+	}
+	return c
+}
+
+// Exporter addresses the blackbox exporter probing the urls of a monitor
+type Exporter struct {
+	// Namespace is the namespace of the exporter deployed by the operator
+	Namespace string
+	// External, if set, is the exporter run by the user, which probes the urls instead
+	External *v1alpha1.ExternalExporterSpec
+}
+
+// validate returns an error if the generated resources can't reach the external exporter: ServiceMonitors select its
+// Service, while Probes and ScrapeConfigs address its url. The Service has to be in the namespace of the monitor, which
+// the generated resources share, so a monitor can't make Prometheus scrape the Services of other namespaces
+func (e Exporter) validate(serviceMonitor bool, namespace string) error {
+	if e.External == nil {
+		return nil
+	}
+	switch {
+	case (e.External.Service == nil) == (e.External.URL == ""):
+		return customerrors.Validation("externalExporter", errors.New("Invalid External Exporter: exactly one of service and url has to be set"))
+	case serviceMonitor && e.External.Service == nil:
+		return customerrors.Validation("externalExporter.url", errors.New("Invalid External Exporter: ServiceMonitors can only select the service of an exporter"))
+	case !serviceMonitor && e.External.URL == "":
+		return customerrors.Validation("externalExporter.service", errors.New("Invalid External Exporter: Probes and ScrapeConfigs can only address the url of an exporter"))
+	case e.External.Service != nil && e.External.Service.Namespace != namespace:
+		return customerrors.Validation("externalExporter.service.namespace", fmt.Errorf("Invalid External Exporter: the service has to be in the namespace of the monitor, %s", namespace))
+	}
+	if e.External.URL != "" {
+		if _, err := neturl.Parse(e.External.URL); err != nil {
+			return customerrors.Validation("externalExporter.url", fmt.Errorf("Invalid External Exporter: %w", err))
+		}
 	}
 	return nil
 }
 
-// Creates or Updates Service Monitor Deployment according to the template if enable of the hypershift
-func (u *ServiceMonitor) HypershiftUpdateServiceMonitorDeployment(template rhobsv1.ServiceMonitor) error {
-	namespacedName := types.NamespacedName{Name: template.Name, Namespace: template.Namespace}
-	deployedServiceMonitor := &rhobsv1.ServiceMonitor{}
-	err := u.Client.Get(u.Ctx, namespacedName, deployedServiceMonitor)
-	if err != nil {
-		// No similar ServiceMonitor exists
-		if !k8serrors.IsNotFound(err) {
-			return err
+// address returns the scheme, the host and the path Probes and ScrapeConfigs address the exporter with, which is the
+// Service of the operator's exporter unless the url of an external exporter is set
+func (e Exporter) address() (string, string, string) {
+	if e.External != nil && e.External.URL != "" {
+		if u, err := neturl.Parse(e.External.URL); err == nil {
+			return u.Scheme, u.Host, strings.TrimSuffix(u.Path, "/") + "/probe"
 		}
-		return u.Client.Create(u.Ctx, &template)
 	}
-	if !u.Comparer.DeepEqual(deployedServiceMonitor.Spec, template.Spec) {
-		// Update existing ServiceMonitor for the case that the template changed
-		deployedServiceMonitor.Spec = template.Spec
-		return u.Client.Update(u.Ctx, deployedServiceMonitor)
+	return "http", fmt.Sprintf("%s.%s.svc:%d", blackboxexporter.BlackBoxExporterName, e.Namespace, blackboxexporter.BlackBoxExporterPortNumber), "/probe"
+}
+
+// service returns the selector, the namespace and the port of the exporter's Service. Services can't be selected by
+// name, so the one of an external exporter is kept among the Services of its namespace by the serviceNameRegex
+func (e Exporter) service() (metav1.LabelSelector, string, string) {
+	if e.External != nil && e.External.Service != nil {
+		return metav1.LabelSelector{}, e.External.Service.Namespace, e.External.Service.Port
 	}
-	return nil
+	return metav1.LabelSelector{MatchLabels: blackboxexporter.GenerateBlackBoxExporterLables()}, e.Namespace, blackboxexporter.BlackBoxExporterPortName
+}
+
+// serverName returns the name the serving certificate of the operator's exporter is issued for
+func (e Exporter) serverName() string {
+	return fmt.Sprintf("%s.%s.svc", blackboxexporter.BlackBoxExporterName, e.Namespace)
+}
+
+// serviceNameRegex returns the regex the Service of an external exporter is kept with, empty for the operator's exporter
+func (e Exporter) serviceNameRegex() string {
+	if e.External != nil && e.External.Service != nil {
+		return regexp.QuoteMeta(e.External.Service.Name)
+	}
+	return ""
+}
+
+// HyperShiftTemplateForServiceMonitorDeployment returns the Hypershift ServiceMonitor probing the urls with the
+// module, with one endpoint per url. Zero limits are unlimited
+func (u *ServiceMonitor) HyperShiftTemplateForServiceMonitorDeployment(urls []string, exporter Exporter, namespacedName types.NamespacedName, clusterID string, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *metav1.OwnerReference) rhobsv1.ServiceMonitor {
+	s := u.HyperShiftTemplateForServiceMonitorResource(urls[0], exporter, probeParams(urls[0], module), namespacedName, clusterID, owner)
+	for _, key := range metricLabelKeys(metricLabels) {
+		s.Spec.Endpoints[0].MetricRelabelConfigs = append(s.Spec.Endpoints[0].MetricRelabelConfigs, &rhobsv1.RelabelConfig{
+			Replacement: metricLabels[key],
+			TargetLabel: key,
+		})
+	}
+	for _, relabeling := range relabelings {
+		s.Spec.Endpoints[0].MetricRelabelConfigs = append(s.Spec.Endpoints[0].MetricRelabelConfigs, &rhobsv1.RelabelConfig{
+			SourceLabels: rhobsLabelNames(relabeling.SourceLabels),
+			Separator:    relabeling.Separator,
+			TargetLabel:  relabeling.TargetLabel,
+			Regex:        relabeling.Regex,
+			Modulus:      relabeling.Modulus,
+			Replacement:  relabeling.Replacement,
+			Action:       relabeling.Action,
+		})
+	}
+	for _, url := range urls[1:] {
+		endpoint := *s.Spec.Endpoints[0].DeepCopy()
+		endpoint.Params = probeParams(url, module)
+		endpoint.MetricRelabelConfigs[0].Replacement = url
+		s.Spec.Endpoints = append(s.Spec.Endpoints, endpoint)
+	}
+	s.Spec.SampleLimit = sampleLimit
+	s.Spec.TargetLimit = targetLimit
+	s.Labels = u.ownerLabels(owner)
+	return s
+}
+
+// metricRelabelConfigs returns the relabelings setting the metric labels, followed by the relabelings of a monitor
+func metricRelabelConfigs(metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig) []*monitoringv1.RelabelConfig {
+	var configs []*monitoringv1.RelabelConfig
+	for _, key := range metricLabelKeys(metricLabels) {
+		configs = append(configs, &monitoringv1.RelabelConfig{
+			Replacement: metricLabels[key],
+			TargetLabel: key,
+		})
+	}
+	for _, relabeling := range relabelings {
+		configs = append(configs, &monitoringv1.RelabelConfig{
+			SourceLabels: labelNames(relabeling.SourceLabels),
+			Separator:    relabeling.Separator,
+			TargetLabel:  relabeling.TargetLabel,
+			Regex:        relabeling.Regex,
+			Modulus:      relabeling.Modulus,
+			Replacement:  relabeling.Replacement,
+			Action:       relabeling.Action,
+		})
+	}
+	return configs
+}
+
+// metricLabelKeys returns the keys of the metric labels in order, so the generated relabelings are stable.
+// The labels set by the operator can't be overridden
+func metricLabelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if key == UrlLabelName || key == "_id" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelNames converts the source labels of a relabeling
+func labelNames(names []string) []monitoringv1.LabelName {
+	var converted []monitoringv1.LabelName
+	for _, name := range names {
+		converted = append(converted, monitoringv1.LabelName(name))
+	}
+	return converted
+}
+
+// rhobsLabelNames converts the source labels of a relabeling for a RHOBS ServiceMonitor
+func rhobsLabelNames(names []string) []rhobsv1.LabelName {
+	var converted []rhobsv1.LabelName
+	for _, name := range names {
+		converted = append(converted, rhobsv1.LabelName(name))
+	}
+	return converted
+}
+
+// probeParams returns the parameters of a probe of the url with the given module
+func probeParams(url, module string) map[string][]string {
+	return map[string][]string{
+		"module": {module},
+		"target": {url},
+	}
+}
+
+// ownerLabels returns the labels identifying the owner of a ServiceMonitor, if enabled
+func (u *ServiceMonitor) ownerLabels(owner *metav1.OwnerReference) map[string]string {
+	if !u.LabelOwnedResources || owner == nil {
+		return nil
+	}
+	return consts.OwnerLabels(owner.Kind, owner.Name)
+}
+
+// Creates or Updates Service Monitor Deployment according to the template
+func (u *ServiceMonitor) UpdateServiceMonitorDeployment(template monitoringv1.ServiceMonitor, recordedUID types.UID) (types.UID, error) {
+	return util.EnsureResource(u.Ctx, util.EnsureOptions[*monitoringv1.ServiceMonitor]{
+		Client:   u.Client,
+		Scrubber: u.Scrubber,
+		SpecEqual: func(deployed, template *monitoringv1.ServiceMonitor) bool {
+			return u.Comparer.DeepEqual(deployed.Spec, template.Spec)
+		},
+		CopySpec:    func(deployed, template *monitoringv1.ServiceMonitor) { deployed.Spec = template.Spec },
+		Adopt:       true,
+		RecordedUID: recordedUID,
+		FieldOwner:  u.FieldOwner,
+		Recorder:    u.Recorder,
+	}, &template)
+}
+
+// Creates or Updates the Probe according to the template
+func (u *ServiceMonitor) UpdateProbeDeployment(template monitoringv1.Probe, recordedUID types.UID) (types.UID, error) {
+	return util.EnsureResource(u.Ctx, util.EnsureOptions[*monitoringv1.Probe]{
+		Client:   u.Client,
+		Scrubber: u.Scrubber,
+		SpecEqual: func(deployed, template *monitoringv1.Probe) bool {
+			return u.Comparer.DeepEqual(deployed.Spec, template.Spec)
+		},
+		CopySpec:    func(deployed, template *monitoringv1.Probe) { deployed.Spec = template.Spec },
+		Adopt:       true,
+		RecordedUID: recordedUID,
+		FieldOwner:  u.FieldOwner,
+		Recorder:    u.Recorder,
+	}, &template)
+}
+
+// Creates or Updates the ScrapeConfig according to the template
+func (u *ServiceMonitor) UpdateScrapeConfigDeployment(template scrapeconfig.ScrapeConfig, recordedUID types.UID) (types.UID, error) {
+	return util.EnsureResource(u.Ctx, util.EnsureOptions[*scrapeconfig.ScrapeConfig]{
+		Client:   u.Client,
+		Scrubber: u.Scrubber,
+		SpecEqual: func(deployed, template *scrapeconfig.ScrapeConfig) bool {
+			return u.Comparer.DeepEqual(deployed.Spec, template.Spec)
+		},
+		CopySpec:    func(deployed, template *scrapeconfig.ScrapeConfig) { deployed.Spec = template.Spec },
+		Adopt:       true,
+		RecordedUID: recordedUID,
+		FieldOwner:  u.FieldOwner,
+		Recorder:    u.Recorder,
+	}, &template)
 }
 
-// Deletes the ServiceMonitor Deployment
-func (u *ServiceMonitor) DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName, isHCPMonitor bool) error {
+// Creates or Updates Service Monitor Deployment according to the template if enable of the hypershift
+func (u *ServiceMonitor) HypershiftUpdateServiceMonitorDeployment(template rhobsv1.ServiceMonitor, recordedUID types.UID) (types.UID, error) {
+	return util.EnsureResource(u.Ctx, util.EnsureOptions[*rhobsv1.ServiceMonitor]{
+		Client:   u.Client,
+		Scrubber: u.Scrubber,
+		SpecEqual: func(deployed, template *rhobsv1.ServiceMonitor) bool {
+			return u.Comparer.DeepEqual(deployed.Spec, template.Spec)
+		},
+		CopySpec:    func(deployed, template *rhobsv1.ServiceMonitor) { deployed.Spec = template.Spec },
+		Adopt:       true,
+		RecordedUID: recordedUID,
+		FieldOwner:  u.FieldOwner,
+		Recorder:    u.Recorder,
+	}, &template)
+}
+
+// DeleteServiceMonitorDeployment deletes the resources generated for a monitor, of whichever kind they were generated as
+func (u *ServiceMonitor) DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName) error {
 	if serviceMonitorRef == (v1alpha1.NamespacedName{}) {
 		return nil
 	}
 	namespacedName := types.NamespacedName{Name: serviceMonitorRef.Name, Namespace: serviceMonitorRef.Namespace}
-
-	if isHCPMonitor {
-		resource := &rhobsv1.ServiceMonitor{}
-		// Does the resource already exist?
-		err := u.Client.Get(u.Ctx, namespacedName, resource)
-		if err != nil {
-			if !k8serrors.IsNotFound(err) {
-				// If this is an unknown error
-				return err
-			}
-			// Resource doesn't exist, nothing to do
-			return nil
+	// Only the recorded kind is left, the other ones are deleted whenever the kind changes. Without one, e.g. a ref recorded before
+	// its kind was, any kind may be left, so all of them are deleted
+	kinds := generatedKinds()
+	if resource := GeneratedResource(serviceMonitorRef.Kind); resource != nil {
+		kinds = []client.Object{resource}
+	}
+	for _, resource := range kinds {
+		if err := deleteIfExists(u.Ctx, u.Client, resource, namespacedName); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		return u.Client.Delete(u.Ctx, resource)
+// generatedKinds returns an empty resource of each kind the scrapes of a monitor are generated as
+func generatedKinds() []client.Object {
+	return []client.Object{&monitoringv1.ServiceMonitor{}, &monitoringv1.Probe{}, &scrapeconfig.ScrapeConfig{}, &rhobsv1.ServiceMonitor{}}
+}
+
+// deleteOtherKinds deletes the resources generated for a monitor as another kind than the kept one's, e.g. before
+// switching to Probes or the monitor becoming hosted, which would scrape its urls twice
+func (u *ServiceMonitor) deleteOtherKinds(kept client.Object, namespacedName types.NamespacedName) error {
+	for _, resource := range generatedKinds() {
+		if reflect.TypeOf(resource) == reflect.TypeOf(kept) {
+			continue
+		}
+		if err := deleteIfExists(u.Ctx, u.Client, resource, namespacedName); err != nil {
+			return err
+		}
 	}
-	resource := &monitoringv1.ServiceMonitor{}
+	return nil
+}
+
+// deleteIfExists deletes the resource with the namespaced name, if there is one
+func deleteIfExists(ctx context.Context, c client.Client, resource client.Object, namespacedName types.NamespacedName) error {
 	// Does the resource already exist?
-	err := u.Client.Get(u.Ctx, namespacedName, resource)
+	err := c.Get(ctx, namespacedName, resource)
 	if err != nil {
-		if !k8serrors.IsNotFound(err) {
+		// Without its CRD, e.g. the RHOBS one on a cluster that isn't hosting control planes, there's nothing to delete
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 			// If this is an unknown error
 			return err
 		}
@@ -127,12 +569,13 @@ func (u *ServiceMonitor) DeleteServiceMonitorDeployment(serviceMonitorRef v1alph
 		return nil
 	}
 
-	return u.Client.Delete(u.Ctx, resource)
+	return c.Delete(ctx, resource)
 }
 
 // TemplateForServiceMonitorResource returns a ServiceMonitor
-func (u *ServiceMonitor) TemplateForServiceMonitorResource(routeURL, blackBoxExporterNamespace string, params map[string][]string, namespacedName types.NamespacedName, clusterID string, owner *metav1.OwnerReference) monitoringv1.ServiceMonitor {
-	return monitoringv1.ServiceMonitor{
+func (u *ServiceMonitor) TemplateForServiceMonitorResource(routeURL string, exporter Exporter, params map[string][]string, namespacedName types.NamespacedName, clusterID string, owner *metav1.OwnerReference) monitoringv1.ServiceMonitor {
+	selector, namespace, port := exporter.service()
+	s := monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            namespacedName.Name,
 			Namespace:       namespacedName.Namespace,
@@ -141,7 +584,7 @@ func (u *ServiceMonitor) TemplateForServiceMonitorResource(routeURL, blackBoxExp
 		Spec: monitoringv1.ServiceMonitorSpec{
 			Endpoints: []monitoringv1.Endpoint{
 				{
-					Port: blackboxexporter.BlackBoxExporterPortName,
+					Port: port,
 					// Probe every 30s
 					Interval: monitoringv1.Duration(ServiceMonitorPeriod),
 					// Timeout has to be smaller than probe interval
@@ -160,21 +603,45 @@ func (u *ServiceMonitor) TemplateForServiceMonitorResource(routeURL, blackBoxExp
 						},
 					},
 				}},
-			Selector: metav1.LabelSelector{
-				MatchLabels: blackboxexporter.GenerateBlackBoxExporterLables(),
-			},
+			Selector: selector,
 			NamespaceSelector: monitoringv1.NamespaceSelector{
 				MatchNames: []string{
-					blackBoxExporterNamespace,
+					namespace,
 				},
 			},
 		},
 	}
+	if regex := exporter.serviceNameRegex(); regex != "" {
+		s.Spec.Endpoints[0].RelabelConfigs = []*monitoringv1.RelabelConfig{
+			{
+				Action:       "keep",
+				SourceLabels: []monitoringv1.LabelName{"__meta_kubernetes_service_name"},
+				Regex:        regex,
+			},
+		}
+	} else if u.TLS {
+		s.Spec.Endpoints[0].Scheme = "https"
+		s.Spec.Endpoints[0].TLSConfig = &monitoringv1.TLSConfig{
+			CAFile:        blackboxexporter.ServiceCAFile,
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{ServerName: exporter.serverName()},
+		}
+	} else if u.MeshTLS {
+		// The sidecar presents the exporter's workload identity rather than a certificate of its Service
+		s.Spec.Endpoints[0].Scheme = "https"
+		s.Spec.Endpoints[0].TLSConfig = &monitoringv1.TLSConfig{
+			CAFile:        blackboxexporter.MeshCertsDir + "/root-cert.pem",
+			CertFile:      blackboxexporter.MeshCertsDir + "/cert-chain.pem",
+			KeyFile:       blackboxexporter.MeshCertsDir + "/key.pem",
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{InsecureSkipVerify: true},
+		}
+	}
+	return s
 }
 
 // HyperShiftTemplateForServiceMonitorResource returns a ServiceMonitor for Hypershift
-func (u *ServiceMonitor) HyperShiftTemplateForServiceMonitorResource(routeURL, blackBoxExporterNamespace string, params map[string][]string, namespacedName types.NamespacedName, clusterID string, owner *metav1.OwnerReference) rhobsv1.ServiceMonitor {
-	return rhobsv1.ServiceMonitor{
+func (u *ServiceMonitor) HyperShiftTemplateForServiceMonitorResource(routeURL string, exporter Exporter, params map[string][]string, namespacedName types.NamespacedName, clusterID string, owner *metav1.OwnerReference) rhobsv1.ServiceMonitor {
+	selector, namespace, port := exporter.service()
+	s := rhobsv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            namespacedName.Name,
 			Namespace:       namespacedName.Namespace,
@@ -183,7 +650,7 @@ func (u *ServiceMonitor) HyperShiftTemplateForServiceMonitorResource(routeURL, b
 		Spec: rhobsv1.ServiceMonitorSpec{
 			Endpoints: []rhobsv1.Endpoint{
 				{
-					Port: blackboxexporter.BlackBoxExporterPortName,
+					Port: port,
 					// Probe every 30s
 					Interval: rhobsv1.Duration(ServiceMonitorPeriod),
 					// Timeout has to be smaller than probe interval
@@ -202,14 +669,22 @@ func (u *ServiceMonitor) HyperShiftTemplateForServiceMonitorResource(routeURL, b
 						},
 					},
 				}},
-			Selector: metav1.LabelSelector{
-				MatchLabels: blackboxexporter.GenerateBlackBoxExporterLables(),
-			},
+			Selector: selector,
 			NamespaceSelector: rhobsv1.NamespaceSelector{
 				MatchNames: []string{
-					blackBoxExporterNamespace,
+					namespace,
 				},
 			},
 		},
 	}
+	if regex := exporter.serviceNameRegex(); regex != "" {
+		s.Spec.Endpoints[0].RelabelConfigs = []*rhobsv1.RelabelConfig{
+			{
+				Action:       "keep",
+				SourceLabels: []rhobsv1.LabelName{"__meta_kubernetes_service_name"},
+				Regex:        regex,
+			},
+		}
+	}
+	return s
 }
@@ -0,0 +1,92 @@
+package servicemonitor_test
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	constinit "github.com/openshift/route-monitor-operator/pkg/consts/test/init"
+	util "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+)
+
+// Allocation budgets for the templating and comparison running on every reconcile. They are
+// checked by TestPerformanceBudget, raise them deliberately when a change has to cost more
+const (
+	templateAllocsBudget  = 10
+	deepEqualAllocsBudget = 10
+)
+
+var (
+	benchmarkNamespacedName = types.NamespacedName{Name: "fake-name", Namespace: "fake-namespace"}
+	benchmarkOwner          = &metav1.OwnerReference{Kind: "RouteMonitor", Name: "fake-name"}
+	benchmarkParams         = map[string][]string{"module": {"http_2xx"}, "target": {"https://fake-url"}}
+)
+
+func benchmarkTemplate(sm *servicemonitor.ServiceMonitor) monitoringv1.ServiceMonitor {
+	return sm.TemplateForServiceMonitorResource("https://fake-url", servicemonitor.Exporter{Namespace: "fake-exporter-namespace"}, benchmarkParams, benchmarkNamespacedName, "fake-id", benchmarkOwner)
+}
+
+func BenchmarkTemplateForServiceMonitorResource(b *testing.B) {
+	sm := &servicemonitor.ServiceMonitor{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkTemplate(sm)
+	}
+}
+
+func BenchmarkServiceMonitorSpecDeepEqual(b *testing.B) {
+	sm := &servicemonitor.ServiceMonitor{}
+	comparer := &util.ResourceComparer{}
+	deployed, template := benchmarkTemplate(sm), benchmarkTemplate(sm)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		comparer.DeepEqual(deployed.Spec, template.Spec)
+	}
+}
+
+// BenchmarkTemplateAndUpdateServiceMonitorDeployment measures the steady state, in which the
+// deployed ServiceMonitor already matches the template
+func BenchmarkTemplateAndUpdateServiceMonitorDeployment(b *testing.B) {
+	ctx := context.Background()
+	sm := servicemonitor.NewServiceMonitor(ctx, fake.NewClientBuilder().WithScheme(constinit.Scheme).Build())
+	var uid types.UID
+	update := func() (err error) {
+		uid, err = sm.TemplateAndUpdateServiceMonitorDeployment([]string{"https://fake-url"}, servicemonitor.Exporter{Namespace: "fake-exporter-namespace"}, benchmarkNamespacedName, "fake-id", false, "http_2xx", nil, nil, 0, 0, benchmarkOwner, uid)
+		return err
+	}
+	if err := update(); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := update(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPerformanceBudget(t *testing.T) {
+	sm := &servicemonitor.ServiceMonitor{}
+	comparer := &util.ResourceComparer{}
+	deployed, template := benchmarkTemplate(sm), benchmarkTemplate(sm)
+
+	budgets := []struct {
+		name   string
+		budget float64
+		run    func()
+	}{
+		{"TemplateForServiceMonitorResource", templateAllocsBudget, func() { benchmarkTemplate(sm) }},
+		{"ServiceMonitorSpecDeepEqual", deepEqualAllocsBudget, func() { comparer.DeepEqual(deployed.Spec, template.Spec) }},
+	}
+	for _, b := range budgets {
+		if allocs := testing.AllocsPerRun(100, b.run); allocs > b.budget {
+			t.Errorf("%s allocates %v times per run, exceeding its budget of %v", b.name, allocs, b.budget)
+		}
+	}
+}
@@ -8,13 +8,20 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/consts"
 	consterror "github.com/openshift/route-monitor-operator/pkg/consts/test/error"
+	reconcilecommon "github.com/openshift/route-monitor-operator/pkg/reconcile"
+	"github.com/openshift/route-monitor-operator/pkg/scrapeconfig"
 	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
 	clientmocks "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/client"
 	utilmock "github.com/openshift/route-monitor-operator/pkg/util/test/generated/mocks/reconcile"
 	testhelper "github.com/openshift/route-monitor-operator/pkg/util/test/helper"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ResourceComparerMockHelper struct {
@@ -36,6 +43,7 @@ var _ = Describe("CR Deployment Handling", func() {
 
 		serviceMonitorRef v1alpha1.NamespacedName
 		serviceMonitor    monitoringv1.ServiceMonitor
+		recordedUID       types.UID
 		sm                servicemonitor.ServiceMonitor
 		err               error
 	)
@@ -52,6 +60,7 @@ var _ = Describe("CR Deployment Handling", func() {
 
 		serviceMonitorRef = v1alpha1.NamespacedName{}
 		serviceMonitor = monitoringv1.ServiceMonitor{}
+		recordedUID = ""
 
 		sm = servicemonitor.ServiceMonitor{
 			Client:   mockClient,
@@ -90,7 +99,7 @@ var _ = Describe("CR Deployment Handling", func() {
 			get.CalledTimes = 1
 		})
 		JustBeforeEach(func() {
-			err = sm.UpdateServiceMonitorDeployment(serviceMonitor)
+			_, err = sm.UpdateServiceMonitorDeployment(serviceMonitor, recordedUID)
 		})
 		When("The Client failed to fetch existing deployments", func() {
 			BeforeEach(func() {
@@ -146,11 +155,334 @@ var _ = Describe("CR Deployment Handling", func() {
 					Expect(err).NotTo(HaveOccurred())
 				})
 			})
+			When("the template adds labels to the existing ServiceMonitor", func() {
+				BeforeEach(func() {
+					deepEqual.ReturnValue = true
+					serviceMonitor.Labels = consts.OwnerLabels("RouteMonitor", "test")
+					update.CalledTimes = 1
+				})
+				It("updates the existing deployment", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+			When("it isn't the ServiceMonitor recorded by the monitor", func() {
+				BeforeEach(func() {
+					deepEqual.CalledTimes = 0
+					recordedUID = "recorded-uid"
+					delete.CalledTimes = 1
+					create.CalledTimes = 1
+				})
+				It("recreates it instead of adopting it", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+			When("the template carries scrubbed labels", func() {
+				BeforeEach(func() {
+					deepEqual.ReturnValue = true
+					serviceMonitor.Labels = map[string]string{"argocd.argoproj.io/instance": "monitoring"}
+					sm.Scrubber = reconcilecommon.MetadataScrubber{Labels: []string{"argocd.argoproj.io/*"}}
+				})
+				It("doesn't propagate them to the existing deployment", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+	})
+	Describe("TemplateAndUpdateServiceMonitorDeployment", func() {
+		var (
+			owner        metav1.OwnerReference
+			urls         []string
+			metricLabels map[string]string
+			relabelings  []v1alpha1.RelabelConfig
+			sampleLimit  uint64
+			targetLimit  uint64
+			exporter     servicemonitor.Exporter
+		)
+		BeforeEach(func() {
+			owner = metav1.OwnerReference{Kind: "RouteMonitor", Name: "test"}
+			urls = []string{"https://fake-url"}
+			exporter = servicemonitor.Exporter{Namespace: "fake-namespace"}
+			metricLabels = nil
+			relabelings = nil
+			sampleLimit = 0
+			targetLimit = 0
+			// the generated resource and the ones of the other kinds, e.g. left over from before switching to Probes,
+			// are looked up
+			get.CalledTimes = 4
+			get.ErrorResponse = consterror.NotFoundErr
+		})
+		JustBeforeEach(func() {
+			_, err = sm.TemplateAndUpdateServiceMonitorDeployment(urls, exporter, types.NamespacedName{Name: "test", Namespace: "test"}, "fake-id", false, "http_2xx", metricLabels, relabelings, sampleLimit, targetLimit, &owner, "")
+		})
+		When("owned resources should be labeled", func() {
+			BeforeEach(func() {
+				sm.LabelOwnedResources = true
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					Expect(o.GetLabels()).To(Equal(consts.OwnerLabels(owner.Kind, owner.Name)))
+					return nil
+				})
+			})
+			It("labels the ServiceMonitor with its owner", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("owned resources shouldn't be labeled", func() {
+			BeforeEach(func() {
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					Expect(o.GetLabels()).To(BeEmpty())
+					return nil
+				})
+			})
+			It("doesn't label the ServiceMonitor", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		When("several urls are probed", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				urls = []string{"https://default-url", "https://sharded-url"}
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("probes every url with its own endpoint and probe_url label", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.Endpoints).To(HaveLen(2))
+				for i, url := range urls {
+					Expect(created.Spec.Endpoints[i].Params["target"]).To(Equal([]string{url}))
+					Expect(created.Spec.Endpoints[i].MetricRelabelConfigs[0].TargetLabel).To(Equal(servicemonitor.UrlLabelName))
+					Expect(created.Spec.Endpoints[i].MetricRelabelConfigs[0].Replacement).To(Equal(url))
+				}
+			})
+		})
+		When("the probes should be labeled with the zone", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				sm.ZoneLabels = true
+				urls = []string{"https://default-url", "https://sharded-url"}
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("attaches the nodes' metadata and relabels their zone on every endpoint", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.AttachMetadata).To(Equal(&monitoringv1.AttachMetadata{Node: true}))
+				for _, endpoint := range created.Spec.Endpoints {
+					zone := endpoint.RelabelConfigs[len(endpoint.RelabelConfigs)-1]
+					Expect(zone.TargetLabel).To(Equal(servicemonitor.ZoneLabelName))
+					Expect(zone.SourceLabels).To(Equal([]monitoringv1.LabelName{"__meta_kubernetes_node_label_topology_kubernetes_io_zone"}))
+				}
+			})
+		})
+		When("the probes should be labeled with the node", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				sm.NodeLabels = true
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("relabels the node of the exporter pod", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.AttachMetadata).To(BeNil())
+				relabelings := created.Spec.Endpoints[0].RelabelConfigs
+				node := relabelings[len(relabelings)-1]
+				Expect(node.TargetLabel).To(Equal(servicemonitor.NodeLabelName))
+				Expect(node.SourceLabels).To(Equal([]monitoringv1.LabelName{"__meta_kubernetes_pod_node_name"}))
+			})
+		})
+		When("metric labels are set", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				urls = []string{"https://default-url", "https://sharded-url"}
+				metricLabels = map[string]string{"telemetry": "fleet", "retention": "short", servicemonitor.UrlLabelName: "https://overridden"}
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("adds them to the series of every endpoint without overriding the operator's labels", func() {
+				Expect(err).NotTo(HaveOccurred())
+				for i, url := range urls {
+					relabelings := created.Spec.Endpoints[i].MetricRelabelConfigs
+					Expect(relabelings).To(HaveLen(4))
+					Expect(relabelings[0].Replacement).To(Equal(url))
+					Expect(*relabelings[2]).To(Equal(monitoringv1.RelabelConfig{TargetLabel: "retention", Replacement: "short"}))
+					Expect(*relabelings[3]).To(Equal(monitoringv1.RelabelConfig{TargetLabel: "telemetry", Replacement: "fleet"}))
+				}
+			})
+		})
+		When("metric relabelings are set", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				metricLabels = map[string]string{"tier": "frontend"}
+				relabelings = []v1alpha1.RelabelConfig{
+					{SourceLabels: []string{"__name__"}, Regex: "probe_dns_.*", Action: "drop"},
+					{SourceLabels: []string{"tier"}, TargetLabel: "team", Replacement: "shop-$1"},
+				}
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("appends them after the operator's relabelings", func() {
+				Expect(err).NotTo(HaveOccurred())
+				relabelings := created.Spec.Endpoints[0].MetricRelabelConfigs
+				Expect(relabelings).To(HaveLen(5))
+				Expect(*relabelings[2]).To(Equal(monitoringv1.RelabelConfig{TargetLabel: "tier", Replacement: "frontend"}))
+				Expect(*relabelings[3]).To(Equal(monitoringv1.RelabelConfig{SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "probe_dns_.*", Action: "drop"}))
+				Expect(*relabelings[4]).To(Equal(monitoringv1.RelabelConfig{SourceLabels: []monitoringv1.LabelName{"tier"}, TargetLabel: "team", Replacement: "shop-$1"}))
+			})
+		})
+		When("scrape limits are set", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				sampleLimit = 200
+				targetLimit = 1
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("sets them on the ServiceMonitor", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.SampleLimit).To(Equal(uint64(200)))
+				Expect(created.Spec.TargetLimit).To(Equal(uint64(1)))
+			})
+		})
+		When("Probes are generated", func() {
+			var created *monitoringv1.Probe
+			BeforeEach(func() {
+				sm.ProbeResources = true
+				urls = []string{"https://default-url", "https://sharded-url"}
+				metricLabels = map[string]string{"tier": "frontend"}
+				sampleLimit = 200
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.Probe)
+					return nil
+				})
+			})
+			It("probes every url through the exporter's Service and labels the series with the url", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.ProberSpec).To(Equal(monitoringv1.ProberSpec{URL: "blackbox-exporter.fake-namespace.svc:9115", Scheme: "http", Path: "/probe"}))
+				Expect(created.Spec.Module).To(Equal("http_2xx"))
+				Expect(created.Spec.Targets.StaticConfig.Targets).To(Equal(urls))
+				Expect(created.Spec.Targets.StaticConfig.Labels).To(Equal(map[string]string{"_id": "fake-id"}))
+				Expect(created.Spec.Targets.StaticConfig.RelabelConfigs[0].TargetLabel).To(Equal(servicemonitor.UrlLabelName))
+				Expect(created.Spec.MetricRelabelConfigs).To(Equal([]*monitoringv1.RelabelConfig{{TargetLabel: "tier", Replacement: "frontend"}}))
+				Expect(created.Spec.SampleLimit).To(Equal(uint64(200)))
+			})
+		})
+		When("a ServiceMonitor was generated before switching to Probes", func() {
+			var deleted client.Object
+			BeforeEach(func() {
+				sm.ProbeResources = true
+				get.CalledTimes = 0
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ types.NamespacedName, o client.Object, _ ...client.GetOption) error {
+					if _, ok := o.(*monitoringv1.ServiceMonitor); ok {
+						return nil
+					}
+					return consterror.NotFoundErr
+				}).Times(4)
+				create.CalledTimes = 1
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.DeleteOption) error {
+					deleted = o
+					return nil
+				})
+			})
+			It("deletes the ServiceMonitor, which would scrape the urls twice", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deleted).To(BeAssignableToTypeOf(&monitoringv1.ServiceMonitor{}))
+			})
+		})
+		When("ScrapeConfigs are generated", func() {
+			var created *scrapeconfig.ScrapeConfig
+			BeforeEach(func() {
+				sm.ScrapeConfigResources = true
+				urls = []string{"https://default-url", "https://sharded-url"}
+				targetLimit = 2
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*scrapeconfig.ScrapeConfig)
+					return nil
+				})
+			})
+			It("probes every url through the exporter's Service and labels the series with the url", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.StaticConfigs).To(Equal([]scrapeconfig.StaticConfig{{
+					Targets: []scrapeconfig.Target{"https://default-url", "https://sharded-url"},
+					Labels:  map[monitoringv1.LabelName]string{"_id": "fake-id"},
+				}}))
+				Expect(created.Spec.Params).To(Equal(map[string][]string{"module": {"http_2xx"}}))
+				Expect(*created.Spec.MetricsPath).To(Equal("/probe"))
+				relabelings := created.Spec.RelabelConfigs
+				Expect(relabelings[2].TargetLabel).To(Equal(servicemonitor.UrlLabelName))
+				Expect(*relabelings[3]).To(Equal(monitoringv1.RelabelConfig{TargetLabel: "__address__", Replacement: "blackbox-exporter.fake-namespace.svc:9115"}))
+				Expect(created.Spec.SampleLimit).To(BeNil())
+				Expect(*created.Spec.TargetLimit).To(Equal(uint64(2)))
+			})
+		})
+		When("the Service of an external exporter is set", func() {
+			var created *monitoringv1.ServiceMonitor
+			BeforeEach(func() {
+				exporter.External = &v1alpha1.ExternalExporterSpec{Service: &v1alpha1.ExternalExporterServiceSpec{Name: "blackbox", Namespace: "test", Port: "http"}}
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+					created = o.(*monitoringv1.ServiceMonitor)
+					return nil
+				})
+			})
+			It("selects the Service by its name in its namespace", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.Spec.Selector).To(Equal(metav1.LabelSelector{}))
+				Expect(created.Spec.NamespaceSelector.MatchNames).To(Equal([]string{"test"}))
+				Expect(created.Spec.Endpoints[0].Port).To(Equal("http"))
+				Expect(created.Spec.Endpoints[0].RelabelConfigs).To(Equal([]*monitoringv1.RelabelConfig{
+					{Action: "keep", SourceLabels: []monitoringv1.LabelName{"__meta_kubernetes_service_name"}, Regex: "blackbox"},
+				}))
+			})
+		})
+		When("the Service of an external exporter is in another namespace", func() {
+			BeforeEach(func() {
+				exporter.External = &v1alpha1.ExternalExporterSpec{Service: &v1alpha1.ExternalExporterServiceSpec{Name: "blackbox", Namespace: "kube-system", Port: "http"}}
+				get.CalledTimes = 0
+			})
+			It("fails without generating a ServiceMonitor", func() {
+				Expect(customerrors.ClassOf(err)).To(Equal(customerrors.ClassValidation))
+			})
+		})
+		When("the url of an external exporter is set", func() {
+			BeforeEach(func() {
+				exporter.External = &v1alpha1.ExternalExporterSpec{URL: "https://blackbox.example.com:9115/exporter/"}
+			})
+			When("Probes are generated", func() {
+				var created *monitoringv1.Probe
+				BeforeEach(func() {
+					sm.ProbeResources = true
+					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+						created = o.(*monitoringv1.Probe)
+						return nil
+					})
+				})
+				It("probes the urls through the external exporter", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(created.Spec.ProberSpec).To(Equal(monitoringv1.ProberSpec{URL: "blackbox.example.com:9115", Scheme: "https", Path: "/exporter/probe"}))
+				})
+			})
+			When("ServiceMonitors are generated", func() {
+				BeforeEach(func() {
+					get.CalledTimes = 0
+				})
+				It("fails as they can only select a Service", func() {
+					Expect(customerrors.ClassOf(err)).To(Equal(customerrors.ClassValidation))
+				})
+			})
 		})
 	})
 	Describe("DeleteServiceMonitorDeployment", func() {
 		JustBeforeEach(func() {
-			err = sm.DeleteServiceMonitorDeployment(serviceMonitorRef, false)
+			err = sm.DeleteServiceMonitorDeployment(serviceMonitorRef)
 		})
 		When("The ServiceMonitorRef is not set", func() {
 			BeforeEach(func() {
@@ -175,15 +507,36 @@ var _ = Describe("CR Deployment Handling", func() {
 			})
 			When("the ServiceMonitorDeployment doesnt exist", func() {
 				BeforeEach(func() {
+					// a ServiceMonitor, Probe, ScrapeConfig and RHOBS ServiceMonitor are looked up
+					get.CalledTimes = 4
 					get.ErrorResponse = consterror.NotFoundErr
 				})
 				It("does nothing", func() {
 					Expect(err).NotTo(HaveOccurred())
 				})
 			})
+			When("resources of every kind exist", func() {
+				BeforeEach(func() {
+					get.CalledTimes = 4
+					delete.CalledTimes = 4
+				})
+				It("deletes them all, whichever kind the monitor was generated as before", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+			When("the kind of the ServiceMonitor is recorded", func() {
+				BeforeEach(func() {
+					serviceMonitorRef.Kind = servicemonitor.RHOBSServiceMonitorKind
+					delete.CalledTimes = 1
+				})
+				It("deletes only the resource of that kind", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
 			When("the ServiceMonitorDeployment exists", func() {
 				BeforeEach(func() {
 					delete.CalledTimes = 1
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(consterror.NotFoundErr).Times(3)
 				})
 				It("deletes the Deployment", func() {
 					Expect(err).NotTo(HaveOccurred())
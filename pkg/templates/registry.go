@@ -0,0 +1,193 @@
+// Package templates registers every variant of the resources the operator generates, so all of them can be
+// rendered from the same inputs and their output kept under review as golden files
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kind is the kind of a generated resource
+type Kind string
+
+const (
+	ServiceMonitor             Kind = "ServiceMonitor"
+	PrometheusRule             Kind = "PrometheusRule"
+	BlackBoxExporterDeployment Kind = "BlackBoxExporterDeployment"
+	BlackBoxExporterDaemonSet  Kind = "BlackBoxExporterDaemonSet"
+	BlackBoxExporterService    Kind = "BlackBoxExporterService"
+	BlackBoxExporterConfigMap  Kind = "BlackBoxExporterConfigMap"
+	// BlackBoxExporterNetworkPolicy is only rendered while the NetworkPolicy is enabled
+	BlackBoxExporterNetworkPolicy Kind = "BlackBoxExporterNetworkPolicy"
+	// BlackBoxExporterTrustedCABundle is only rendered while the cluster has a proxy
+	BlackBoxExporterTrustedCABundle Kind = "BlackBoxExporterTrustedCABundle"
+	// BlackBoxExporterServiceMonitor and BlackBoxExporterPrometheusRule monitor the health of the exporter itself
+	BlackBoxExporterServiceMonitor Kind = "BlackBoxExporterServiceMonitor"
+	BlackBoxExporterPrometheusRule Kind = "BlackBoxExporterPrometheusRule"
+	// BlackBoxExporterConfig reports the rollout of the exporter
+	BlackBoxExporterConfig Kind = "BlackBoxExporterConfig"
+	ConsoleDashboard       Kind = "ConsoleDashboard"
+)
+
+// Feature toggles a variant of a template
+type Feature string
+
+const (
+	// HCP renders the resources of a HostedControlPlane monitor
+	HCP Feature = "hcp"
+	// InsecureTLS probes without verifying the certificate of the target
+	InsecureTLS Feature = "insecure-tls"
+	// CustomModule probes with the HTTPProbe of the Params instead of the default module
+	CustomModule Feature = "custom-module"
+	// FIPS restricts the exporter to FIPS approved settings
+	FIPS Feature = "fips"
+	// OwnerLabels labels the resources with the monitor they belong to
+	OwnerLabels Feature = "owner-labels"
+	// PrivateNLB schedules the exporter as in clusters with a private NLB
+	PrivateNLB Feature = "private-nlb"
+	// Latency alerts on the latency objective of the Slo
+	Latency Feature = "latency"
+	// MaintenanceWindows suppresses the alerts during the MaintenanceWindows
+	MaintenanceWindows Feature = "maintenance-windows"
+	// GracePeriod suppresses the alerts during the grace period of the Slo
+	GracePeriod Feature = "grace-period"
+	// ZoneSpread spreads the exporter pods over the zones
+	ZoneSpread Feature = "zone-spread"
+	// ZoneLabels labels the probes with the zone of the exporter pod
+	ZoneLabels Feature = "zone-labels"
+	// NodeLabels labels the probes with the node of the exporter pod
+	NodeLabels Feature = "node-labels"
+	// RelaxedSecurity runs the exporter without the restricted security context
+	RelaxedSecurity Feature = "relaxed-security"
+	// RestrictedNetwork restricts the exporter's traffic to the Prometheus namespaces and egress networks of the Params
+	RestrictedNetwork Feature = "restricted-network"
+	// ServingCert serves the probes of the exporter over TLS and scrapes them over https
+	ServingCert Feature = "serving-cert"
+	// ClusterProxy probes through the cluster-wide proxy of the Params
+	ClusterProxy Feature = "cluster-proxy"
+	// DebugLogs runs the exporter at the debug log level
+	DebugLogs Feature = "debug-logs"
+	// PodMetadata adds the pod labels and annotations of the Params to the exporter pods
+	PodMetadata Feature = "pod-metadata"
+	// ServiceMesh injects the exporter pods with the sidecar of a service mesh and scrapes them with its certificates
+	ServiceMesh Feature = "service-mesh"
+)
+
+// Features are the features a variant is rendered with, in any order
+type Features []Feature
+
+// Has returns whether the feature is enabled
+func (f Features) Has(feature Feature) bool {
+	for _, enabled := range f {
+		if enabled == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the features in order, joined by '+', or "default" if there are none
+func (f Features) String() string {
+	if len(f) == 0 {
+		return "default"
+	}
+	names := make([]string, 0, len(f))
+	for _, feature := range f {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return strings.Join(names, "+")
+}
+
+// Params are the inputs of the templates, the settings of a monitor and of the operator
+type Params struct {
+	URLs           []string
+	NamespacedName types.NamespacedName
+	Owner          metav1.OwnerReference
+	Created        time.Time
+	ClusterID      string
+
+	Slo                v1alpha1.SloSpec
+	HTTPProbe          *v1alpha1.HTTPProbeSpec
+	MetricLabels       map[string]string
+	MetricRelabelings  []v1alpha1.RelabelConfig
+	SampleLimit        uint64
+	TargetLimit        uint64
+	MaintenanceWindows []v1alpha1.MaintenanceWindow
+
+	BlackBoxExporter      types.NamespacedName
+	BlackBoxExporterImage string
+	// BlackBoxExporterDeployment sizes the exporter deployment, the zero value runs a single pod
+	BlackBoxExporterDeployment blackboxexporter.DeploymentSettings
+	// BlackBoxExporterNetworkPolicy lists the Prometheus namespaces and egress networks of the exporter NetworkPolicy
+	BlackBoxExporterNetworkPolicy runtimeconfig.NetworkPolicy
+	// ClusterProxy is the cluster-wide proxy the exporter probes through
+	ClusterProxy blackboxexporter.Proxy
+	// BlackBoxExporterPodLabels and BlackBoxExporterPodAnnotations are added to the exporter pods
+	BlackBoxExporterPodLabels      map[string]string
+	BlackBoxExporterPodAnnotations map[string]string
+}
+
+// RenderFunc renders a resource of a kind from the params with the features enabled
+type RenderFunc func(params Params, features Features) (client.Object, error)
+
+// Template is a variant of a generated resource
+type Template struct {
+	Kind     Kind
+	Features Features
+	// Version of the template of the kind, bumped with every change of what it renders
+	Version int
+
+	render RenderFunc
+}
+
+// Render returns the resource of the variant generated from the params
+func (t Template) Render(params Params) (client.Object, error) {
+	return t.render(params, t.Features)
+}
+
+// String names the variant by its kind and features, e.g. 'ServiceMonitor/hcp+insecure-tls'
+func (t Template) String() string {
+	return string(t.Kind) + "/" + t.Features.String()
+}
+
+var registry = map[string]Template{}
+
+// Register adds the variants of a kind, rendered by the render func, to the registry. Registering
+// a variant twice panics
+func Register(kind Kind, version int, render RenderFunc, variants ...Features) {
+	for _, features := range variants {
+		template := Template{Kind: kind, Features: features, Version: version, render: render}
+		if _, ok := registry[template.String()]; ok {
+			panic(fmt.Sprintf("template %s is already registered", template))
+		}
+		registry[template.String()] = template
+	}
+}
+
+// Lookup returns the variant of the kind with exactly the features, false is returned if it isn't registered
+func Lookup(kind Kind, features ...Feature) (Template, bool) {
+	template, ok := registry[Template{Kind: kind, Features: features}.String()]
+	return template, ok
+}
+
+// All returns every registered variant, ordered by kind and features
+func All() []Template {
+	templates := make([]Template, 0, len(registry))
+	for _, template := range registry {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].String() < templates[j].String()
+	})
+	return templates
+}
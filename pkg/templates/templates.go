@@ -0,0 +1,250 @@
+package templates
+
+import (
+	"github.com/openshift/route-monitor-operator/pkg/alert"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	consts "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/dashboard"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/servicemonitor"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The variants of the generated resources. Bump the version of a kind whenever what it renders changes
+func init() {
+	Register(ServiceMonitor, 4, renderServiceMonitor,
+		nil,
+		Features{HCP},
+		Features{InsecureTLS},
+		Features{CustomModule},
+		Features{HCP, CustomModule},
+		Features{OwnerLabels},
+		Features{ZoneLabels},
+		Features{NodeLabels},
+		Features{ServingCert},
+		Features{HCP, ServingCert},
+		Features{ServiceMesh},
+		Features{HCP, ServiceMesh},
+	)
+	Register(PrometheusRule, 3, renderPrometheusRule,
+		nil,
+		Features{Latency},
+		Features{MaintenanceWindows},
+		Features{GracePeriod},
+	)
+	Register(BlackBoxExporterDeployment, 4, renderBlackBoxExporterDeployment,
+		nil,
+		Features{FIPS},
+		Features{PrivateNLB},
+		Features{ZoneSpread},
+		Features{RelaxedSecurity},
+		Features{ServingCert},
+		Features{ClusterProxy},
+		Features{DebugLogs},
+		Features{PodMetadata},
+		Features{ServiceMesh},
+	)
+	Register(BlackBoxExporterDaemonSet, 2, renderBlackBoxExporterDaemonSet,
+		nil,
+	)
+	Register(BlackBoxExporterService, 1, renderBlackBoxExporterService,
+		nil,
+		Features{ServingCert},
+	)
+	Register(BlackBoxExporterConfigMap, 1, renderBlackBoxExporterConfigMap,
+		nil,
+		Features{InsecureTLS},
+		Features{CustomModule},
+		Features{FIPS},
+		Features{ServingCert},
+		Features{ClusterProxy},
+	)
+	Register(BlackBoxExporterNetworkPolicy, 1, renderBlackBoxExporterNetworkPolicy,
+		nil,
+		Features{RestrictedNetwork},
+	)
+	Register(BlackBoxExporterTrustedCABundle, 1, renderBlackBoxExporterTrustedCABundle,
+		nil,
+	)
+	Register(BlackBoxExporterServiceMonitor, 1, renderBlackBoxExporterServiceMonitor,
+		nil,
+		Features{ServingCert},
+		Features{ServiceMesh},
+	)
+	Register(BlackBoxExporterPrometheusRule, 1, renderBlackBoxExporterPrometheusRule,
+		nil,
+	)
+	Register(BlackBoxExporterConfig, 1, renderBlackBoxExporterConfig,
+		nil,
+	)
+	Register(ConsoleDashboard, 1, renderConsoleDashboard,
+		nil,
+	)
+}
+
+// module returns the name and definition of the module probing with the features
+func module(params Params, features Features) (string, blackboxexporter.Module) {
+	probe := params.HTTPProbe
+	if !features.Has(CustomModule) {
+		probe = nil
+	}
+	return blackboxexporter.ModuleFor(features.Has(InsecureTLS), probe)
+}
+
+func renderServiceMonitor(params Params, features Features) (client.Object, error) {
+	u := &servicemonitor.ServiceMonitor{
+		LabelOwnedResources: features.Has(OwnerLabels),
+		ZoneLabels:          features.Has(ZoneLabels),
+		NodeLabels:          features.Has(NodeLabels),
+		TLS:                 features.Has(ServingCert),
+		MeshTLS:             features.Has(ServiceMesh),
+	}
+	name, _ := module(params, features)
+	if features.Has(HCP) {
+		s := u.HyperShiftTemplateForServiceMonitorDeployment(params.URLs, servicemonitor.Exporter{Namespace: params.BlackBoxExporter.Namespace}, params.NamespacedName, params.ClusterID, name, params.MetricLabels, params.MetricRelabelings, params.SampleLimit, params.TargetLimit, &params.Owner)
+		return &s, nil
+	}
+	s := u.TemplateForServiceMonitorDeployment(params.URLs, servicemonitor.Exporter{Namespace: params.BlackBoxExporter.Namespace}, params.NamespacedName, params.ClusterID, name, params.MetricLabels, params.MetricRelabelings, params.SampleLimit, params.TargetLimit, &params.Owner)
+	return &s, nil
+}
+
+func renderPrometheusRule(params Params, features Features) (client.Object, error) {
+	slo := params.Slo
+	if !features.Has(Latency) {
+		slo.Latency = nil
+	}
+	if !features.Has(GracePeriod) {
+		slo.GracePeriod = ""
+	}
+	windows := params.MaintenanceWindows
+	if !features.Has(MaintenanceWindows) {
+		windows = nil
+	}
+	_, percent := slo.IsValid()
+	rule, err := alert.TemplateForMonitorPrometheusRule(alert.MonitorRuleOptions{
+		URL:                params.URLs[0],
+		Percent:            percent,
+		Slo:                slo,
+		NamespacedName:     params.NamespacedName,
+		Created:            params.Created,
+		MaintenanceWindows: windows,
+		Owner:              &params.Owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func renderBlackBoxExporterDeployment(params Params, features Features) (client.Object, error) {
+	nodeLabel := blackboxexporter.InfraNodeLabel
+	if features.Has(PrivateNLB) {
+		nodeLabel = blackboxexporter.MasterNodeLabel
+	}
+	config, err := exporterConfig(params, features)
+	if err != nil {
+		return nil, err
+	}
+	settings := params.BlackBoxExporterDeployment
+	settings.ZoneSpread = features.Has(ZoneSpread)
+	settings.RelaxedSecurity = features.Has(RelaxedSecurity)
+	settings.ServingCert = features.Has(ServingCert)
+	if features.Has(ClusterProxy) {
+		settings.Proxy = params.ClusterProxy
+	}
+	if features.Has(DebugLogs) {
+		settings.LogLevel = "debug"
+	}
+	if features.Has(PodMetadata) {
+		settings.PodLabels = params.BlackBoxExporterPodLabels
+		settings.PodAnnotations = params.BlackBoxExporterPodAnnotations
+	}
+	if features.Has(ServiceMesh) {
+		settings.ServiceMesh = consts.ServiceMeshInject
+	}
+	deployment := blackboxexporter.TemplateForBlackBoxExporterDeployment(params.BlackBoxExporterImage, params.BlackBoxExporter, blackboxexporter.HashConfigMap(config), nodeLabel, features.Has(FIPS), settings)
+	return &deployment, nil
+}
+
+func renderBlackBoxExporterDaemonSet(params Params, features Features) (client.Object, error) {
+	config, err := exporterConfig(params, features)
+	if err != nil {
+		return nil, err
+	}
+	daemonSet := blackboxexporter.TemplateForBlackBoxExporterDaemonSet(params.BlackBoxExporterImage, params.BlackBoxExporter, blackboxexporter.HashConfigMap(config), blackboxexporter.InfraNodeLabel, false, params.BlackBoxExporterDeployment)
+	return &daemonSet, nil
+}
+
+func renderBlackBoxExporterService(params Params, features Features) (client.Object, error) {
+	service := blackboxexporter.TemplateForBlackBoxExporterService(params.BlackBoxExporter, features.Has(ServingCert))
+	return &service, nil
+}
+
+func renderBlackBoxExporterNetworkPolicy(params Params, features Features) (client.Object, error) {
+	settings := runtimeconfig.NetworkPolicy{Enabled: true}
+	if features.Has(RestrictedNetwork) {
+		settings.PrometheusNamespaces = params.BlackBoxExporterNetworkPolicy.PrometheusNamespaces
+		settings.EgressCIDRs = params.BlackBoxExporterNetworkPolicy.EgressCIDRs
+	}
+	policy := blackboxexporter.TemplateForBlackBoxExporterNetworkPolicy(params.BlackBoxExporter, settings)
+	return &policy, nil
+}
+
+func renderBlackBoxExporterTrustedCABundle(params Params, features Features) (client.Object, error) {
+	bundle := blackboxexporter.TemplateForBlackBoxExporterTrustedCABundle(params.BlackBoxExporter)
+	return &bundle, nil
+}
+
+func renderBlackBoxExporterServiceMonitor(params Params, features Features) (client.Object, error) {
+	settings := blackboxexporter.DeploymentSettings{ServingCert: features.Has(ServingCert)}
+	if features.Has(ServiceMesh) {
+		settings.ServiceMesh = consts.ServiceMeshInject
+	}
+	serviceMonitor := blackboxexporter.TemplateForBlackBoxExporterServiceMonitor(params.BlackBoxExporter, settings)
+	return &serviceMonitor, nil
+}
+
+func renderBlackBoxExporterPrometheusRule(params Params, features Features) (client.Object, error) {
+	rule := alert.TemplateForBlackBoxExporterPrometheusRule(params.BlackBoxExporter.Namespace)
+	return &rule, nil
+}
+
+func renderBlackBoxExporterConfig(params Params, features Features) (client.Object, error) {
+	config := blackboxexporter.TemplateForBlackBoxExporterConfig(params.BlackBoxExporter)
+	return &config, nil
+}
+
+func renderBlackBoxExporterConfigMap(params Params, features Features) (client.Object, error) {
+	config, err := exporterConfig(params, features)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// exporterConfig returns the configuration of an exporter probing a monitor with the features
+func exporterConfig(params Params, features Features) (corev1.ConfigMap, error) {
+	name, definition := module(params, features)
+	proxy := blackboxexporter.Proxy{}
+	if features.Has(ClusterProxy) {
+		proxy = params.ClusterProxy
+	}
+	modules := blackboxexporter.ExporterModules(map[string]blackboxexporter.Module{name: definition}, features.Has(FIPS), proxy)
+	return blackboxexporter.TemplateForBlackBoxExporterConfigMap(params.BlackBoxExporter, modules, features.Has(ServingCert))
+}
+
+func renderConsoleDashboard(params Params, features Features) (client.Object, error) {
+	_, percent := params.Slo.IsValid()
+	config, err := dashboard.TemplateForDashboardConfigMap([]dashboard.Monitor{{
+		Kind:             params.Owner.Kind,
+		NamespacedName:   params.NamespacedName,
+		URLs:             params.URLs,
+		Percent:          percent,
+		ClientErrorsFail: params.Slo.ClientErrorsFail(),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,150 @@
+package templates_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/blackboxexporter"
+	"github.com/openshift/route-monitor-operator/pkg/runtimeconfig"
+	"github.com/openshift/route-monitor-operator/pkg/templates"
+)
+
+// Regenerate the golden files with: go test ./pkg/templates/ -update
+var update = flag.Bool("update", false, "rewrite the golden files of the templates")
+
+// params are the inputs every variant is rendered from
+func params() templates.Params {
+	controller := true
+	followRedirects := false
+	countClientErrors := true
+	windowStart := metav1.NewTime(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC))
+	windowEnd := metav1.NewTime(time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC))
+	return templates.Params{
+		URLs:           []string{"https://shop.apps.example.com/healthz", "https://shop.apps.internal.example.com/healthz"},
+		NamespacedName: types.NamespacedName{Name: "shop", Namespace: "shop-namespace"},
+		Owner:          metav1.OwnerReference{APIVersion: "monitoring.openshift.io/v1alpha1", Kind: "RouteMonitor", Name: "shop", UID: "shop-uid", Controller: &controller},
+		Created:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ClusterID:      "cluster-id",
+		Slo: v1alpha1.SloSpec{
+			TargetAvailabilityPercent:  "99.5",
+			Latency:                    &v1alpha1.LatencySloSpec{ThresholdMilliseconds: 250, TargetPercent: "99"},
+			AlertLabels:                map[string]string{"team": "shop"},
+			AlertAnnotations:           map[string]string{"runbook_url": "https://runbooks.example.com/shop"},
+			Severities:                 &v1alpha1.AlertSeveritySpec{SlowBurn: "info"},
+			CountClientErrorsAsFailure: &countClientErrors,
+			GracePeriod:                "1h",
+		},
+		HTTPProbe: &v1alpha1.HTTPProbeSpec{
+			FollowRedirects:     &followRedirects,
+			PreferredIPProtocol: v1alpha1.IPProtocolIP6,
+			TLSMinVersion:       v1alpha1.TLSVersion12,
+			FailIfHeaderMatches: []v1alpha1.HeaderAssertion{{Header: "X-Maintenance", Regexp: "true"}},
+		},
+		MetricLabels:      map[string]string{"team": "shop", "tier": "frontend"},
+		MetricRelabelings: []v1alpha1.RelabelConfig{{SourceLabels: []string{"instance"}, TargetLabel: "host", Action: "replace"}},
+		SampleLimit:       100,
+		TargetLimit:       2,
+		MaintenanceWindows: []v1alpha1.MaintenanceWindow{
+			{Schedule: "0 2 * * 0", Duration: "2h"},
+			{Start: &windowStart, End: &windowEnd},
+		},
+		BlackBoxExporter:      types.NamespacedName{Name: "blackbox-exporter", Namespace: "openshift-route-monitor-operator"},
+		BlackBoxExporterImage: "quay.io/prometheus/blackbox-exporter@sha256:b04a9fef4fa086a02fc7fcd8dcdbc4b7b35cc30cdee860fdc6a19dd8b208d63e",
+		BlackBoxExporterNetworkPolicy: runtimeconfig.NetworkPolicy{
+			PrometheusNamespaces: []string{"openshift-monitoring", "openshift-user-workload-monitoring"},
+			EgressCIDRs:          []string{"10.0.0.0/8", "172.30.0.0/16"},
+		},
+		ClusterProxy: blackboxexporter.Proxy{
+			HTTPProxy:  "http://proxy.example.com:3128",
+			HTTPSProxy: "http://proxy.example.com:3128",
+			NoProxy:    ".cluster.local,.svc,10.0.0.0/8,172.30.0.0/16,localhost",
+		},
+		BlackBoxExporterPodLabels:      map[string]string{"cost-center": "monitoring", "app": "not-the-exporter"},
+		BlackBoxExporterPodAnnotations: map[string]string{"sidecar.istio.io/inject": "false"},
+	}
+}
+
+// goldenFile returns the path of the golden file of the template
+func goldenFile(template templates.Template) string {
+	return filepath.Join("testdata", string(template.Kind), template.Features.String()+".yaml")
+}
+
+// header identifies the template a golden file was rendered from
+func header(template templates.Template) string {
+	return fmt.Sprintf("# %s v%d\n", template, template.Version)
+}
+
+var _ = Describe("Templates", func() {
+	for _, template := range templates.All() {
+		template := template
+		It("renders "+template.String()+" as its golden file", func() {
+			resource, err := template.Render(params())
+			Expect(err).NotTo(HaveOccurred())
+			rendered, err := yaml.Marshal(resource)
+			Expect(err).NotTo(HaveOccurred())
+			golden := header(template) + string(rendered)
+
+			path := goldenFile(template)
+			existing, err := os.ReadFile(path)
+			if *update {
+				if err == nil && string(existing) != golden && strings.HasPrefix(string(existing), header(template)) {
+					Fail(fmt.Sprintf("%s renders differently, bump the version of %s", template, template.Kind))
+				}
+				Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+				Expect(os.WriteFile(path, []byte(golden), 0o644)).To(Succeed())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred(), "missing golden file, run go test ./pkg/templates/ -update")
+			Expect(golden).To(Equal(string(existing)), "run go test ./pkg/templates/ -update if the change is intended")
+		})
+	}
+
+	It("has no golden files of unregistered templates", func() {
+		registered := map[string]bool{}
+		for _, template := range templates.All() {
+			registered[goldenFile(template)] = true
+		}
+		files, err := filepath.Glob(filepath.Join("testdata", "*", "*.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, file := range files {
+			Expect(registered).To(HaveKey(file))
+		}
+	})
+})
+
+var _ = Describe("Lookup", func() {
+	It("finds a variant by its features in any order", func() {
+		template, ok := templates.Lookup(templates.ServiceMonitor, templates.CustomModule, templates.HCP)
+		Expect(ok).To(BeTrue())
+		Expect(template.String()).To(Equal("ServiceMonitor/custom-module+hcp"))
+	})
+
+	It("finds the default variant", func() {
+		template, ok := templates.Lookup(templates.PrometheusRule)
+		Expect(ok).To(BeTrue())
+		Expect(template.Features).To(BeEmpty())
+	})
+
+	It("doesn't find unregistered variants", func() {
+		_, ok := templates.Lookup(templates.PrometheusRule, templates.HCP)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("refuses to register a variant twice", func() {
+		Expect(func() {
+			templates.Register(templates.BlackBoxExporterService, 1, nil, nil)
+		}).To(Panic())
+	})
+})
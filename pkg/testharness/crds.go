@@ -0,0 +1,68 @@
+package testharness
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// externalResource describes a resource the operator reads or writes, but whose CRD it doesn't own
+type externalResource struct {
+	group    string
+	version  string
+	kind     string
+	plural   string
+	scope    apiextensionsv1.ResourceScope
+	noStatus bool
+}
+
+var externalResources = []externalResource{
+	{group: "monitoring.coreos.com", version: "v1", kind: "ServiceMonitor", plural: "servicemonitors", scope: apiextensionsv1.NamespaceScoped, noStatus: true},
+	{group: "monitoring.coreos.com", version: "v1", kind: "PrometheusRule", plural: "prometheusrules", scope: apiextensionsv1.NamespaceScoped, noStatus: true},
+	{group: "monitoring.rhobs", version: "v1", kind: "ServiceMonitor", plural: "servicemonitors", scope: apiextensionsv1.NamespaceScoped, noStatus: true},
+	{group: "route.openshift.io", version: "v1", kind: "Route", plural: "routes", scope: apiextensionsv1.NamespaceScoped},
+	{group: "config.openshift.io", version: "v1", kind: "ClusterVersion", plural: "clusterversions", scope: apiextensionsv1.ClusterScoped},
+	{group: "config.openshift.io", version: "v1", kind: "Infrastructure", plural: "infrastructures", scope: apiextensionsv1.ClusterScoped},
+	{group: "config.openshift.io", version: "v1", kind: "Proxy", plural: "proxies", scope: apiextensionsv1.ClusterScoped},
+	{group: "operator.openshift.io", version: "v1", kind: "IngressController", plural: "ingresscontrollers", scope: apiextensionsv1.NamespaceScoped},
+	{group: "hypershift.openshift.io", version: "v1beta1", kind: "HostedControlPlane", plural: "hostedcontrolplanes", scope: apiextensionsv1.NamespaceScoped},
+	{group: "hypershift.openshift.io", version: "v1beta1", kind: "HostedCluster", plural: "hostedclusters", scope: apiextensionsv1.NamespaceScoped},
+}
+
+// externalCRDs returns schemaless CRDs for the resources owned by other operators, so the
+// controllers can run against a control plane that only knows about the operator's own CRDs
+func externalCRDs() []*apiextensionsv1.CustomResourceDefinition {
+	preserveUnknownFields := true
+	crds := []*apiextensionsv1.CustomResourceDefinition{}
+	for _, r := range externalResources {
+		version := apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    r.version,
+			Served:  true,
+			Storage: true,
+			Schema: &apiextensionsv1.CustomResourceValidation{
+				OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:                   "object",
+					XPreserveUnknownFields: &preserveUnknownFields,
+				},
+			},
+		}
+		if !r.noStatus {
+			version.Subresources = &apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			}
+		}
+		crds = append(crds, &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: r.plural + "." + r.group},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: r.group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     r.kind,
+					ListKind: r.kind + "List",
+					Plural:   r.plural,
+				},
+				Scope:    r.scope,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{version},
+			},
+		})
+	}
+	return crds
+}
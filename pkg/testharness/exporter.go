@@ -0,0 +1,76 @@
+package testharness
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// FakeExporter is a stub HTTP server answering like the blackbox exporter's /probe endpoint.
+// Every target is reported as up, unless it was marked otherwise with SetTargetUp
+type FakeExporter struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	targets map[string]bool
+	probes  map[string]int
+}
+
+// NewFakeExporter starts a FakeExporter, it has to be closed once it's no longer needed
+func NewFakeExporter() *FakeExporter {
+	e := &FakeExporter{
+		targets: map[string]bool{},
+		probes:  map[string]int{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/probe", e.probe)
+	e.server = httptest.NewServer(mux)
+	return e
+}
+
+// URL returns the base URL of the exporter
+func (e *FakeExporter) URL() string {
+	return e.server.URL
+}
+
+// Close shuts the exporter down
+func (e *FakeExporter) Close() {
+	e.server.Close()
+}
+
+// SetTargetUp defines the probe result reported for the target
+func (e *FakeExporter) SetTargetUp(target string, up bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.targets[target] = up
+}
+
+// Probes returns how often the target has been probed
+func (e *FakeExporter) Probes(target string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.probes[target]
+}
+
+func (e *FakeExporter) probe(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "Target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	e.mu.Lock()
+	up, ok := e.targets[target]
+	e.probes[target]++
+	e.mu.Unlock()
+
+	success := 1
+	if ok && !up {
+		success = 0
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP probe_success Displays whether or not the probe was a success\n")
+	fmt.Fprintf(w, "# TYPE probe_success gauge\n")
+	fmt.Fprintf(w, "probe_success %d\n", success)
+}
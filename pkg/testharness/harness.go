@@ -0,0 +1,240 @@
+// Package testharness runs the operator's controllers against a local control plane started by
+// envtest, so reconcile scenarios can be tested without a cluster. The kube-apiserver and etcd
+// binaries are looked up by envtest, e.g. through the KUBEBUILDER_ASSETS environment variable
+package testharness
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	rhobsv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/controllers"
+	"github.com/openshift/route-monitor-operator/controllers/certificatemonitor"
+	"github.com/openshift/route-monitor-operator/controllers/clusterurlmonitor"
+	"github.com/openshift/route-monitor-operator/controllers/namespacemonitor"
+	"github.com/openshift/route-monitor-operator/controllers/routemonitor"
+	"github.com/openshift/route-monitor-operator/controllers/urlmonitor"
+	"github.com/openshift/route-monitor-operator/pkg/clusteridentity"
+)
+
+const (
+	DefaultClusterID                 = "fake-cluster-id"
+	DefaultAPIServerURL              = "https://api.fake-cluster.example.com:6443"
+	DefaultBlackBoxExporterImage     = "quay.io/prometheus/blackbox-exporter:master"
+	DefaultBlackBoxExporterNamespace = "openshift-route-monitor-operator"
+)
+
+// Options customize the environment started by the harness
+type Options struct {
+	// CRDDirectoryPaths overrides the directories the operator's CRDs are installed from.
+	// Defaults to the CRDs shipped with this module
+	CRDDirectoryPaths []string
+
+	// ClusterID is the ID of the cluster stored in the seeded ClusterVersion
+	ClusterID string
+
+	// ClusterIdentity overrides how the controllers resolve the cluster ID, e.g. with a
+	// clusteridentity.StaticProvider
+	ClusterIdentity clusteridentity.Provider
+
+	// APIServerURL is the API URL stored in the seeded Infrastructure, ClusterUrlMonitors derive
+	// the cluster domain from it
+	APIServerURL string
+
+	BlackBoxExporterImage     string
+	BlackBoxExporterNamespace string
+	EnableHypershift          bool
+	LabelOwnedResources       bool
+}
+
+// Harness is a running control plane with the operator's controllers registered against it
+type Harness struct {
+	Env      *envtest.Environment
+	Config   *rest.Config
+	Client   client.Client
+	Scheme   *k8sruntime.Scheme
+	Exporter *FakeExporter
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// NewScheme returns a scheme holding every type the operator works with
+func NewScheme() *k8sruntime.Scheme {
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(monitoringv1.AddToScheme(scheme))
+	utilruntime.Must(rhobsv1.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1.AddToScheme(scheme))
+	utilruntime.Must(hypershiftv1beta1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	return scheme
+}
+
+// DefaultCRDDirectoryPaths returns the location of the CRDs shipped with this module
+func DefaultCRDDirectoryPaths() []string {
+	_, file, _, _ := runtime.Caller(0)
+	return []string{filepath.Join(filepath.Dir(file), "..", "..", "deploy", "crds")}
+}
+
+func (o *Options) setDefaults() {
+	if len(o.CRDDirectoryPaths) == 0 {
+		o.CRDDirectoryPaths = DefaultCRDDirectoryPaths()
+	}
+	if o.ClusterID == "" {
+		o.ClusterID = DefaultClusterID
+	}
+	if o.APIServerURL == "" {
+		o.APIServerURL = DefaultAPIServerURL
+	}
+	if o.BlackBoxExporterImage == "" {
+		o.BlackBoxExporterImage = DefaultBlackBoxExporterImage
+	}
+	if o.BlackBoxExporterNamespace == "" {
+		o.BlackBoxExporterNamespace = DefaultBlackBoxExporterNamespace
+	}
+}
+
+// Start brings up the control plane, seeds the cluster-scoped objects the controllers depend on
+// and starts the controllers. The returned Harness has to be stopped by the caller
+func Start(opts Options) (*Harness, error) {
+	opts.setDefaults()
+	scheme := NewScheme()
+
+	h := &Harness{
+		Env: &envtest.Environment{
+			CRDDirectoryPaths:     opts.CRDDirectoryPaths,
+			CRDs:                  externalCRDs(),
+			ErrorIfCRDPathMissing: true,
+			Scheme:                scheme,
+		},
+		Scheme: scheme,
+	}
+
+	cfg, err := h.Env.Start()
+	if err != nil {
+		return nil, err
+	}
+	h.Config = cfg
+
+	if err := h.run(opts); err != nil {
+		return nil, errors.Join(err, h.Stop())
+	}
+	return h, nil
+}
+
+func (h *Harness) run(opts Options) error {
+	c, err := client.New(h.Config, client.Options{Scheme: h.Scheme})
+	if err != nil {
+		return err
+	}
+	h.Client = c
+
+	if err := h.seed(opts); err != nil {
+		return err
+	}
+
+	mgr, err := ctrl.NewManager(h.Config, ctrl.Options{
+		Scheme:                 h.Scheme,
+		Metrics:                server.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		return err
+	}
+	reconcilerOptions := controllers.ReconcilerOptions{
+		BlackBoxExporterImage:     opts.BlackBoxExporterImage,
+		BlackBoxExporterNamespace: opts.BlackBoxExporterNamespace,
+		EnableHypershift:          opts.EnableHypershift,
+		LabelOwnedResources:       opts.LabelOwnedResources,
+		ClusterIdentity:           opts.ClusterIdentity,
+	}
+	if err := routemonitor.NewReconciler(mgr, reconcilerOptions).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := clusterurlmonitor.NewReconciler(mgr, reconcilerOptions).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := urlmonitor.NewReconciler(mgr, reconcilerOptions).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := namespacemonitor.NewReconciler(mgr, reconcilerOptions).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := certificatemonitor.NewReconciler(mgr, reconcilerOptions).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	h.Exporter = NewFakeExporter()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan error, 1)
+	go func() {
+		h.done <- mgr.Start(ctx)
+	}()
+	return nil
+}
+
+// seed creates the objects every reconcile depends on, but which aren't part of a scenario
+func (h *Harness) seed(opts Options) error {
+	ctx := context.Background()
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.BlackBoxExporterNamespace}},
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: configv1.ClusterID(opts.ClusterID)},
+		},
+		&configv1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+	}
+	for _, o := range objects {
+		if err := h.Client.Create(ctx, o); err != nil {
+			return err
+		}
+	}
+
+	// The API URL is part of the status, which is ignored on creation
+	infra := &configv1.Infrastructure{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Name: "cluster"}, infra); err != nil {
+		return err
+	}
+	infra.Status.APIServerURL = opts.APIServerURL
+	return h.Client.Status().Update(ctx, infra)
+}
+
+// Stop shuts the controllers, the fake exporter and the control plane down
+func (h *Harness) Stop() error {
+	var errs []error
+	if h.cancel != nil {
+		h.cancel()
+		errs = append(errs, <-h.done)
+	}
+	if h.Exporter != nil {
+		h.Exporter.Close()
+	}
+	errs = append(errs, h.Env.Stop())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,13 @@
+package testharness_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTestHarness(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test Harness Suite")
+}
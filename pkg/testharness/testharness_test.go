@@ -0,0 +1,87 @@
+package testharness_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/route-monitor-operator/api/v1alpha1"
+	"github.com/openshift/route-monitor-operator/pkg/testharness"
+)
+
+var _ = Describe("FakeExporter", func() {
+	var exporter *testharness.FakeExporter
+
+	probe := func(target string) string {
+		resp, err := http.Get(exporter.URL() + "/probe?" + url.Values{"target": {target}}.Encode())
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		exporter = testharness.NewFakeExporter()
+	})
+	AfterEach(func() {
+		exporter.Close()
+	})
+	It("reports unknown targets as up", func() {
+		Expect(probe("https://fake-url")).To(ContainSubstring("probe_success 1"))
+		Expect(exporter.Probes("https://fake-url")).To(Equal(1))
+	})
+	It("reports targets marked as down", func() {
+		exporter.SetTargetUp("https://fake-url", false)
+		Expect(probe("https://fake-url")).To(ContainSubstring("probe_success 0"))
+	})
+	It("rejects probes without a target", func() {
+		resp, err := http.Get(exporter.URL() + "/probe")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("Harness", func() {
+	var h *testharness.Harness
+
+	BeforeEach(func() {
+		if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+			Skip("KUBEBUILDER_ASSETS isn't set, the control plane can't be started")
+		}
+		var err error
+		h, err = testharness.Start(testharness.Options{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		if h != nil {
+			Expect(h.Stop()).To(Succeed())
+		}
+	})
+	It("reconciles ClusterUrlMonitors into ServiceMonitors", func() {
+		ctx := context.Background()
+		Expect(h.Client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fake-namespace"}})).To(Succeed())
+		Expect(h.Client.Create(ctx, &v1alpha1.ClusterUrlMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "fake-namespace"},
+			Spec: v1alpha1.ClusterUrlMonitorSpec{
+				Prefix: "https://api.",
+				Port:   "6443",
+				Suffix: "/livez",
+			},
+		})).To(Succeed())
+
+		Eventually(func() error {
+			return h.Client.Get(ctx, client.ObjectKey{Name: "api", Namespace: "fake-namespace"}, &monitoringv1.ServiceMonitor{})
+		}, 30*time.Second, time.Second).Should(Succeed())
+	})
+})
@@ -2,12 +2,122 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
-	NoHost     = errors.New("No Host: extracted RouteURL is empty")
-	InvalidSLO = errors.New("Invalid RawSlo: string cannot be parsed " +
-		"or is not in correct range, or type is not supported")
-	InvalidReferenceUpdate = errors.New("Invalid Reference Update: currently the reference cannot be changed in flight, " +
-		"please delete the parent resource and create it in the new name")
+	NoHost     = &DependencyMissingError{Dependency: "Route host", Err: errors.New("No Host: extracted RouteURL is empty")}
+	InvalidSLO = &ValidationError{Field: "slo", Err: errors.New("Invalid RawSlo: string cannot be parsed " +
+		"or is not in correct range, or type is not supported")}
+	InvalidReferenceUpdate = &TerminalError{Err: errors.New("Invalid Reference Update: currently the reference cannot be changed in flight, " +
+		"please delete the parent resource and create it in the new name")}
+	ResourceNameCollision = &RetryableError{Err: errors.New("Resource Name Collision: the resource is controlled by another monitor")}
+	NonFIPSCompliantTLS   = &ValidationError{Field: "httpProbe.tlsMinVersion", Err: errors.New("Non FIPS Compliant TLS: the probe allows TLS versions which aren't approved in FIPS mode")}
+	NoMonitoringAPI       = &DependencyMissingError{Dependency: "monitoring API", Err: errors.New("No Monitoring API: neither the monitoring.rhobs nor the monitoring.coreos.com API serves ServiceMonitors")}
+	NoHostedControlPlane  = &DependencyMissingError{Dependency: "HostedControlPlane", Err: errors.New("No HostedControlPlane: the namespace holds no HostedControlPlane, its hosted cluster was deleted")}
 )
+
+// Class classifies a failure, so the requeue policy and metrics don't depend on error messages
+type Class string
+
+const (
+	// ClassRetryable failures may pass when retried, e.g. a conflicting update. Unclassified errors are retryable
+	ClassRetryable Class = "retryable"
+	// ClassDependencyMissing failures wait for something the monitor depends on, e.g. the host of a Route
+	ClassDependencyMissing Class = "dependency_missing"
+	// ClassValidation failures are caused by the spec of a monitor, only changing it fixes them
+	ClassValidation Class = "validation"
+	// ClassTerminal failures can't be fixed by retrying
+	ClassTerminal Class = "terminal"
+)
+
+// RetryableError is a failure which may pass when retried
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// TerminalError is a failure which retrying can't fix
+type TerminalError struct {
+	Err error
+}
+
+func (e *TerminalError) Error() string { return e.Err.Error() }
+func (e *TerminalError) Unwrap() error { return e.Err }
+
+// DependencyMissingError is a failure caused by a missing dependency of the monitor, retrying passes once it exists
+type DependencyMissingError struct {
+	// Dependency names what is missing
+	Dependency string
+	Err        error
+}
+
+func (e *DependencyMissingError) Error() string { return e.Err.Error() }
+func (e *DependencyMissingError) Unwrap() error { return e.Err }
+
+// ValidationError is a failure caused by an invalid field of a monitor's spec
+type ValidationError struct {
+	// Field is the path of the invalid field within the spec
+	Field string
+	Err   error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Retryable marks the error as retryable, nil stays nil
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err}
+}
+
+// Terminal marks the error as terminal, nil stays nil
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TerminalError{Err: err}
+}
+
+// DependencyMissing returns an error for the missing dependency
+func DependencyMissing(dependency string, err error) error {
+	if err == nil {
+		err = fmt.Errorf("%s is missing", dependency)
+	}
+	return &DependencyMissingError{Dependency: dependency, Err: err}
+}
+
+// Validation marks the error as caused by the field of the spec, nil stays nil
+func Validation(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ValidationError{Field: field, Err: err}
+}
+
+// ClassOf returns the class of the outermost classified error in the chain. Unclassified errors are retryable
+func ClassOf(err error) Class {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case *TerminalError:
+			return ClassTerminal
+		case *ValidationError:
+			return ClassValidation
+		case *DependencyMissingError:
+			return ClassDependencyMissing
+		case *RetryableError:
+			return ClassRetryable
+		}
+	}
+	return ClassRetryable
+}
+
+// IsTerminal returns whether retrying can't fix the error, as it's terminal or caused by the spec
+func IsTerminal(err error) bool {
+	class := ClassOf(err)
+	return err != nil && (class == ClassTerminal || class == ClassValidation)
+}
@@ -0,0 +1,81 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+	utilreconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+)
+
+var _ = Describe("Errors", func() {
+	cause := errors.New("cause")
+
+	Describe("ClassOf", func() {
+		for _, tc := range []struct {
+			name  string
+			err   error
+			class customerrors.Class
+		}{
+			{"an unclassified error", cause, customerrors.ClassRetryable},
+			{"a retryable error", customerrors.Retryable(cause), customerrors.ClassRetryable},
+			{"a terminal error", customerrors.Terminal(cause), customerrors.ClassTerminal},
+			{"a missing dependency", customerrors.DependencyMissing("Route", cause), customerrors.ClassDependencyMissing},
+			{"a validation error", customerrors.Validation("slo", cause), customerrors.ClassValidation},
+			{"a wrapped validation error", fmt.Errorf("module 'fips': %w", customerrors.NonFIPSCompliantTLS), customerrors.ClassValidation},
+			{"an error classified twice", customerrors.Retryable(customerrors.Terminal(cause)), customerrors.ClassRetryable},
+			{"the missing host", customerrors.NoHost, customerrors.ClassDependencyMissing},
+			{"an invalid SLO", customerrors.InvalidSLO, customerrors.ClassValidation},
+			{"an invalid reference update", customerrors.InvalidReferenceUpdate, customerrors.ClassTerminal},
+			{"a name collision", customerrors.ResourceNameCollision, customerrors.ClassRetryable},
+		} {
+			tc := tc
+			It("classifies "+tc.name, func() {
+				Expect(customerrors.ClassOf(tc.err)).To(Equal(tc.class))
+			})
+		}
+	})
+
+	Describe("wrapping", func() {
+		It("keeps the message and the cause", func() {
+			err := customerrors.Validation("slo.gracePeriod", cause)
+			Expect(err).To(MatchError("cause"))
+			Expect(errors.Is(err, cause)).To(BeTrue())
+		})
+		It("exposes the details through errors.As", func() {
+			var validationErr *customerrors.ValidationError
+			Expect(errors.As(fmt.Errorf("wrapped: %w", customerrors.InvalidSLO), &validationErr)).To(BeTrue())
+			Expect(validationErr.Field).To(Equal("slo"))
+
+			var dependencyErr *customerrors.DependencyMissingError
+			Expect(errors.As(customerrors.DependencyMissing("Route", nil), &dependencyErr)).To(BeTrue())
+			Expect(dependencyErr).To(MatchError("Route is missing"))
+		})
+		It("keeps nil", func() {
+			Expect(customerrors.Retryable(nil)).To(BeNil())
+			Expect(customerrors.Terminal(nil)).To(BeNil())
+			Expect(customerrors.Validation("slo", nil)).To(BeNil())
+			Expect(customerrors.IsTerminal(nil)).To(BeFalse())
+		})
+	})
+
+	Describe("FailReconcileWith", func() {
+		It("stops on errors retrying can't fix", func() {
+			for _, err := range []error{customerrors.InvalidReferenceUpdate, customerrors.Validation("slo", cause)} {
+				res, resErr := utilreconcile.FailReconcileWith(err)
+				Expect(resErr).To(BeNil())
+				Expect(res).To(Equal(utilreconcile.StopOperation()))
+			}
+		})
+		It("requeues on any other error", func() {
+			for _, err := range []error{cause, customerrors.NoHost, customerrors.ResourceNameCollision} {
+				res, resErr := utilreconcile.FailReconcileWith(err)
+				Expect(resErr).To(Equal(err))
+				Expect(res).To(Equal(utilreconcile.RequeueOperation()))
+			}
+		})
+	})
+})
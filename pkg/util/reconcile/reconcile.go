@@ -1,16 +1,36 @@
 package reconcile
 
-import "time"
+import (
+	"time"
+
+	customerrors "github.com/openshift/route-monitor-operator/pkg/util/errors"
+)
 
 type Result struct {
 	Requeue bool
 	// Continue is used mostly by ShouldStop() and it's named this way so the empty Result will stop proccesing
 	Continue     bool
 	RequeueAfter time.Duration
+	// Reason explains why the operation stops or requeues, it's logged when converting to a 'ctrl.Result'
+	Reason string
+}
+
+// RequeueAfter stops the operation and requeues it once the duration passed, e.g. when waiting on
+// something that doesn't trigger a reconcile by itself
+func RequeueAfter(d time.Duration) Result {
+	return Result{
+		RequeueAfter: d,
+	}
+}
+
+// Because records the reason of the Result
+func (r Result) Because(reason string) Result {
+	r.Reason = reason
+	return r
 }
 
 func (r Result) RequeueOrStop() bool {
-	return r.Requeue || !r.Continue
+	return r.Requeue || r.RequeueAfter > 0 || !r.Continue
 }
 
 func (r Result) ShouldStop() bool {
@@ -53,6 +73,15 @@ func RequeueReconcileWith(errIn error) (result Result, err error) {
 	return
 }
 
+// FailReconcileWith stops on errors retrying can't fix, as they're terminal or caused by the spec of the monitor,
+// and requeues on any other. The next change of the monitor triggers a reconcile after a stop
+func FailReconcileWith(errIn error) (result Result, err error) {
+	if customerrors.IsTerminal(errIn) {
+		return StopReconcile()
+	}
+	return RequeueReconcileWith(errIn)
+}
+
 func ContinueReconcile() (result Result, err error) {
 	result = ContinueOperation()
 	return
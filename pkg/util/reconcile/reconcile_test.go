@@ -0,0 +1,38 @@
+package reconcile_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openshift/route-monitor-operator/pkg/util/reconcile"
+)
+
+var _ = Describe("Result", func() {
+	Describe("RequeueAfter", func() {
+		It("stops the operation and requeues it after the duration", func() {
+			res := reconcile.RequeueAfter(time.Minute).Because("waiting for the Route to be admitted")
+			Expect(res.ShouldStop()).To(BeTrue())
+			Expect(res.RequeueOrStop()).To(BeTrue())
+			Expect(res.Reason).To(Equal("waiting for the Route to be admitted"))
+			Expect(res.Convert()).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+	})
+
+	Describe("Because", func() {
+		It("keeps the rest of the Result", func() {
+			Expect(reconcile.ContinueOperation().Because("nothing changed")).To(Equal(reconcile.Result{Continue: true, Reason: "nothing changed"}))
+			Expect(reconcile.RequeueOperation().Because("conflict").Convert()).To(Equal(ctrl.Result{Requeue: true}))
+		})
+	})
+
+	Describe("ReturnWith", func() {
+		It("converts a stop to an empty ctrl.Result", func() {
+			res, err := reconcile.StopOperation().ReturnWith(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(ctrl.Result{}))
+		})
+	})
+})
@@ -2,15 +2,17 @@ package reconcile
 
 import (
 	"github.com/go-logr/logr"
+	"github.com/openshift/route-monitor-operator/pkg/logging"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 var Log logr.Logger = ctrl.Log.WithName("ReconcileOperation")
 
-// Convert converts the ReconcileOperation to a 'ctrl.Result'
+// Convert converts the ReconcileOperation to a 'ctrl.Result'. Continue has no equivalent, the controllers
+// convert the Result of the operation that stopped the reconcile
 func (r Result) Convert() ctrl.Result {
-	if !r.Continue {
-		Log.V(1).Info("Info loss: converting 'ReconcileOperation' to 'ctrl.Result' with 'Continue'  as 'false'")
+	if r.Reason != "" {
+		Log.V(logging.DebugVerbosity).Info("Stopping reconcile", "reason", r.Reason, "requeue", r.Requeue, "requeueAfter", r.RequeueAfter.String())
 	}
 	return ctrl.Result{
 		Requeue:      r.Requeue,
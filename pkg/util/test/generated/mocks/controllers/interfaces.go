@@ -15,6 +15,7 @@ import (
 	v1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	v1alpha1 "github.com/openshift/route-monitor-operator/api/v1alpha1"
 	blackboxexporter "github.com/openshift/route-monitor-operator/pkg/consts/blackboxexporter"
+	servicemonitor "github.com/openshift/route-monitor-operator/pkg/servicemonitor"
 	reconcile "github.com/openshift/route-monitor-operator/pkg/util/reconcile"
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v10 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
@@ -218,59 +219,62 @@ func (m *MockServiceMonitorHandler) EXPECT() *MockServiceMonitorHandlerMockRecor
 }
 
 // DeleteServiceMonitorDeployment mocks base method.
-func (m *MockServiceMonitorHandler) DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName, hcp bool) error {
+func (m *MockServiceMonitorHandler) DeleteServiceMonitorDeployment(serviceMonitorRef v1alpha1.NamespacedName) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteServiceMonitorDeployment", serviceMonitorRef, hcp)
+	ret := m.ctrl.Call(m, "DeleteServiceMonitorDeployment", serviceMonitorRef)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteServiceMonitorDeployment indicates an expected call of DeleteServiceMonitorDeployment.
-func (mr *MockServiceMonitorHandlerMockRecorder) DeleteServiceMonitorDeployment(serviceMonitorRef, hcp any) *gomock.Call {
+func (mr *MockServiceMonitorHandlerMockRecorder) DeleteServiceMonitorDeployment(serviceMonitorRef any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).DeleteServiceMonitorDeployment), serviceMonitorRef, hcp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).DeleteServiceMonitorDeployment), serviceMonitorRef)
 }
 
 // HypershiftUpdateServiceMonitorDeployment mocks base method.
-func (m *MockServiceMonitorHandler) HypershiftUpdateServiceMonitorDeployment(template v10.ServiceMonitor) error {
+func (m *MockServiceMonitorHandler) HypershiftUpdateServiceMonitorDeployment(template v10.ServiceMonitor, recordedUID types.UID) (types.UID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HypershiftUpdateServiceMonitorDeployment", template)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "HypershiftUpdateServiceMonitorDeployment", template, recordedUID)
+	ret0, _ := ret[0].(types.UID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // HypershiftUpdateServiceMonitorDeployment indicates an expected call of HypershiftUpdateServiceMonitorDeployment.
-func (mr *MockServiceMonitorHandlerMockRecorder) HypershiftUpdateServiceMonitorDeployment(template any) *gomock.Call {
+func (mr *MockServiceMonitorHandlerMockRecorder) HypershiftUpdateServiceMonitorDeployment(template, recordedUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HypershiftUpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).HypershiftUpdateServiceMonitorDeployment), template)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HypershiftUpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).HypershiftUpdateServiceMonitorDeployment), template, recordedUID)
 }
 
 // TemplateAndUpdateServiceMonitorDeployment mocks base method.
-func (m *MockServiceMonitorHandler) TemplateAndUpdateServiceMonitorDeployment(url, blackBoxExporterNamespace string, namespacedName types.NamespacedName, clusterID string, hcp, useInsecure bool, owner *v11.OwnerReference) error {
+func (m *MockServiceMonitorHandler) TemplateAndUpdateServiceMonitorDeployment(urls []string, exporter servicemonitor.Exporter, namespacedName types.NamespacedName, clusterID string, hcp bool, module string, metricLabels map[string]string, relabelings []v1alpha1.RelabelConfig, sampleLimit, targetLimit uint64, owner *v11.OwnerReference, recordedUID types.UID) (types.UID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TemplateAndUpdateServiceMonitorDeployment", url, blackBoxExporterNamespace, namespacedName, clusterID, hcp, useInsecure, owner)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "TemplateAndUpdateServiceMonitorDeployment", urls, exporter, namespacedName, clusterID, hcp, module, metricLabels, relabelings, sampleLimit, targetLimit, owner, recordedUID)
+	ret0, _ := ret[0].(types.UID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // TemplateAndUpdateServiceMonitorDeployment indicates an expected call of TemplateAndUpdateServiceMonitorDeployment.
-func (mr *MockServiceMonitorHandlerMockRecorder) TemplateAndUpdateServiceMonitorDeployment(url, blackBoxExporterNamespace, namespacedName, clusterID, hcp, useInsecure, owner any) *gomock.Call {
+func (mr *MockServiceMonitorHandlerMockRecorder) TemplateAndUpdateServiceMonitorDeployment(urls, exporter, namespacedName, clusterID, hcp, module, metricLabels, relabelings, sampleLimit, targetLimit, owner, recordedUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateAndUpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).TemplateAndUpdateServiceMonitorDeployment), url, blackBoxExporterNamespace, namespacedName, clusterID, hcp, useInsecure, owner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateAndUpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).TemplateAndUpdateServiceMonitorDeployment), urls, exporter, namespacedName, clusterID, hcp, module, metricLabels, relabelings, sampleLimit, targetLimit, owner, recordedUID)
 }
 
 // UpdateServiceMonitorDeployment mocks base method.
-func (m *MockServiceMonitorHandler) UpdateServiceMonitorDeployment(template v1.ServiceMonitor) error {
+func (m *MockServiceMonitorHandler) UpdateServiceMonitorDeployment(template v1.ServiceMonitor, recordedUID types.UID) (types.UID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateServiceMonitorDeployment", template)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateServiceMonitorDeployment", template, recordedUID)
+	ret0, _ := ret[0].(types.UID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateServiceMonitorDeployment indicates an expected call of UpdateServiceMonitorDeployment.
-func (mr *MockServiceMonitorHandlerMockRecorder) UpdateServiceMonitorDeployment(template any) *gomock.Call {
+func (mr *MockServiceMonitorHandlerMockRecorder) UpdateServiceMonitorDeployment(template, recordedUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).UpdateServiceMonitorDeployment), template)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceMonitorDeployment", reflect.TypeOf((*MockServiceMonitorHandler)(nil).UpdateServiceMonitorDeployment), template, recordedUID)
 }
 
 // MockPrometheusRuleHandler is a mock of PrometheusRuleHandler interface.
@@ -347,6 +351,22 @@ func (m *MockBlackBoxExporterHandler) EXPECT() *MockBlackBoxExporterHandlerMockR
 	return m.recorder
 }
 
+// DebugBlackBoxExporterProbe mocks base method.
+func (m *MockBlackBoxExporterHandler) DebugBlackBoxExporterProbe(url, module string) (bool, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebugBlackBoxExporterProbe", url, module)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DebugBlackBoxExporterProbe indicates an expected call of DebugBlackBoxExporterProbe.
+func (mr *MockBlackBoxExporterHandlerMockRecorder) DebugBlackBoxExporterProbe(url, module any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugBlackBoxExporterProbe", reflect.TypeOf((*MockBlackBoxExporterHandler)(nil).DebugBlackBoxExporterProbe), url, module)
+}
+
 // EnsureBlackBoxExporterResourcesAbsent mocks base method.
 func (m *MockBlackBoxExporterHandler) EnsureBlackBoxExporterResourcesAbsent() error {
 	m.ctrl.T.Helper()
@@ -389,6 +409,36 @@ func (mr *MockBlackBoxExporterHandlerMockRecorder) GetBlackBoxExporterNamespace(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlackBoxExporterNamespace", reflect.TypeOf((*MockBlackBoxExporterHandler)(nil).GetBlackBoxExporterNamespace))
 }
 
+// GetBlackBoxExporterRollout mocks base method.
+func (m *MockBlackBoxExporterHandler) GetBlackBoxExporterRollout() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlackBoxExporterRollout")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlackBoxExporterRollout indicates an expected call of GetBlackBoxExporterRollout.
+func (mr *MockBlackBoxExporterHandlerMockRecorder) GetBlackBoxExporterRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlackBoxExporterRollout", reflect.TypeOf((*MockBlackBoxExporterHandler)(nil).GetBlackBoxExporterRollout))
+}
+
+// ServesBlackBoxExporterModule mocks base method.
+func (m *MockBlackBoxExporterHandler) ServesBlackBoxExporterModule(module string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServesBlackBoxExporterModule", module)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServesBlackBoxExporterModule indicates an expected call of ServesBlackBoxExporterModule.
+func (mr *MockBlackBoxExporterHandlerMockRecorder) ServesBlackBoxExporterModule(module any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServesBlackBoxExporterModule", reflect.TypeOf((*MockBlackBoxExporterHandler)(nil).ServesBlackBoxExporterModule), module)
+}
+
 // ShouldDeleteBlackBoxExporterResources mocks base method.
 func (m *MockBlackBoxExporterHandler) ShouldDeleteBlackBoxExporterResources() (blackboxexporter.ShouldDeleteBlackBoxExporter, error) {
 	m.ctrl.T.Helper()